@@ -1,14 +1,53 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	// _ "github.com/lib/pq"
+	"github.com/alfathaulia/ca_ecommerce_api/carrier"
+	_cartDelivery "github.com/alfathaulia/ca_ecommerce_api/cart/delivery/http"
+	_cartRepo "github.com/alfathaulia/ca_ecommerce_api/cart/repository/mysql"
+	_cartUcase "github.com/alfathaulia/ca_ecommerce_api/cart/usecase"
+	_categoryDelivery "github.com/alfathaulia/ca_ecommerce_api/category/delivery/http"
+	_categoryRepo "github.com/alfathaulia/ca_ecommerce_api/category/repository/mysql"
+	_categoryUcase "github.com/alfathaulia/ca_ecommerce_api/category/usecase"
+	_currencyDelivery "github.com/alfathaulia/ca_ecommerce_api/currency/delivery/http"
+	_currencyFile "github.com/alfathaulia/ca_ecommerce_api/currency/repository/file"
+	_currencyRepo "github.com/alfathaulia/ca_ecommerce_api/currency/repository/mysql"
+	_currencyUcase "github.com/alfathaulia/ca_ecommerce_api/currency/usecase"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/gateway"
+	"github.com/alfathaulia/ca_ecommerce_api/idempotency"
+	"github.com/alfathaulia/ca_ecommerce_api/limiter"
+	"github.com/alfathaulia/ca_ecommerce_api/mailer"
+	"github.com/alfathaulia/ca_ecommerce_api/mfa"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	_orderDelivery "github.com/alfathaulia/ca_ecommerce_api/order/delivery/http"
+	_orderRepo "github.com/alfathaulia/ca_ecommerce_api/order/repository/mysql"
+	_orderUcase "github.com/alfathaulia/ca_ecommerce_api/order/usecase"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	_paymentDelivery "github.com/alfathaulia/ca_ecommerce_api/payment/delivery/http"
+	_paymentRepo "github.com/alfathaulia/ca_ecommerce_api/payment/repository/mysql"
+	_paymentUcase "github.com/alfathaulia/ca_ecommerce_api/payment/usecase"
+	_productDelivery "github.com/alfathaulia/ca_ecommerce_api/product/delivery/http"
+	_productRepo "github.com/alfathaulia/ca_ecommerce_api/product/repository/mysql"
+	_productUcase "github.com/alfathaulia/ca_ecommerce_api/product/usecase"
+	_shippingDelivery "github.com/alfathaulia/ca_ecommerce_api/shipping/delivery/http"
+	_shippingUcase "github.com/alfathaulia/ca_ecommerce_api/shipping/usecase"
+	_taxDelivery "github.com/alfathaulia/ca_ecommerce_api/tax/delivery/http"
+	_taxRepo "github.com/alfathaulia/ca_ecommerce_api/tax/repository/mysql"
+	_taxUcase "github.com/alfathaulia/ca_ecommerce_api/tax/usecase"
+	"github.com/alfathaulia/ca_ecommerce_api/token"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
 	_userDelivery "github.com/alfathaulia/ca_ecommerce_api/user/delivery/http"
 	_userRepo "github.com/alfathaulia/ca_ecommerce_api/user/repository/mysql"
 	_userUcase "github.com/alfathaulia/ca_ecommerce_api/user/usecase"
@@ -60,13 +99,205 @@ func main() {
 	}()
 
 	e := echo.New()
+	e.IPExtractor = newIPExtractor()
 
 	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+	accessTokenDuration := time.Duration(viper.GetInt("token.access_token_duration")) * time.Second
+	tokenMaker, err := token.NewJWTMaker(viper.GetString("token.secret_key"), accessTokenDuration)
+	if err != nil {
+		log.Fatal(err)
+	}
+	permissions := domain.DefaultRolePermissions()
+	if configured := viper.GetStringMapStringSlice("permissions"); len(configured) > 0 {
+		permissions, err = domain.NewRolePermissions(configured)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	idempotencyStore := idempotency.NewMysqlStore(dbConn)
+	go cleanupIdempotencyKeys(idempotencyStore, time.Duration(viper.GetInt("idempotency.cleanup_interval"))*time.Second)
+	middL := middleware.InitMiddleware(tokenMaker, permissions, idempotencyStore, time.Duration(viper.GetInt("idempotency.ttl"))*time.Second)
+
+	paginator := pagination.NewPaginator(
+		viper.GetString("pagination.secret_key"),
+		viper.GetInt64("pagination.default_size"),
+		viper.GetInt64("pagination.max_size"),
+	)
+
+	userMailer := mailer.NewUserMailer(newMailer(), viper.GetString("server.base_url"))
+
 	userRepo := _userRepo.NewMysqlUserRepo(dbConn)
-	userUcase := _userUcase.NewUserUsecase(userRepo, timeoutContext)
+	refreshTokenRepo := _userRepo.NewMysqlRefreshTokenRepo(dbConn)
+	verificationRepo := _userRepo.NewMysqlEmailVerificationRepo(dbConn)
+	passwordResetRepo := _userRepo.NewMysqlPasswordResetRepo(dbConn)
+	loginAttemptRepo := _userRepo.NewMysqlLoginAttemptRepo(dbConn)
+	loginLimiter := limiter.NewLoginLimiter(limiter.NewMemoryStore(), loginAttemptRepo, limiter.Config{
+		FreeAttempts:       viper.GetInt("login_throttle.free_attempts"),
+		AccountMaxAttempts: viper.GetInt("login_throttle.account_max_attempts"),
+		IPMaxAttempts:      viper.GetInt("login_throttle.ip_max_attempts"),
+		BaseDelay:          time.Duration(viper.GetInt("login_throttle.base_delay")) * time.Second,
+		MaxDelay:           time.Duration(viper.GetInt("login_throttle.max_delay")) * time.Second,
+		LockoutDuration:    time.Duration(viper.GetInt("login_throttle.lockout_duration")) * time.Second,
+		Window:             time.Duration(viper.GetInt("login_throttle.window")) * time.Second,
+	})
+	var mfaRoles []domain.RolesType
+	for _, role := range viper.GetStringSlice("mfa.required_roles") {
+		mfaRoles = append(mfaRoles, domain.RolesType(role))
+	}
+	mfaAuthenticator := mfa.NewTOTPAuthenticator(_userRepo.NewMysqlMFARepo(dbConn), viper.GetString("mfa.issuer"), mfaRoles)
+	userUcase := _userUcase.NewUserUsecase(userRepo, refreshTokenRepo, verificationRepo, passwordResetRepo, loginLimiter, mfaAuthenticator, userMailer, tokenMaker, timeoutContext)
+
+	_userDelivery.NewUserHandler(e, userUcase, paginator, middL)
 
-	_userDelivery.NewUserHandler(e, userUcase)
+	transactor := transaction.NewMysqlTransactor(dbConn)
+	exchangeRateRepo := _currencyRepo.NewMysqlExchangeRateRepo(dbConn)
+	exchangeRateUcase := _currencyUcase.NewExchangeRateUsecase(exchangeRateRepo, transactor, timeoutContext)
+	if path := viper.GetString("exchange_rates.file"); path != "" {
+		importExchangeRates(exchangeRateUcase, path)
+	}
+	_currencyDelivery.NewExchangeRateHandler(e, exchangeRateUcase, middL)
+
+	categoryRepo := _categoryRepo.NewMysqlCategoryRepo(dbConn)
+	categoryUcase := _categoryUcase.NewCategoryUsecase(categoryRepo, transactor, timeoutContext)
+	_categoryDelivery.NewCategoryHandler(e, categoryUcase, middL)
+
+	productRepo := _productRepo.NewMysqlProductRepo(dbConn)
+	var priceBounds []int64
+	for _, b := range viper.GetIntSlice("search.price_buckets") {
+		priceBounds = append(priceBounds, int64(b))
+	}
+	productOptionRepo := _productRepo.NewMysqlProductOptionRepo(dbConn)
+	variantRepo := _productRepo.NewMysqlVariantRepo(dbConn)
+	productUcase := _productUcase.NewProductUsecase(productRepo, categoryRepo, productOptionRepo, variantRepo, transactor, priceBounds, timeoutContext)
+	_productDelivery.NewProductHandler(e, productUcase, exchangeRateUcase, paginator, middL)
+	variantUcase := _productUcase.NewVariantUsecase(productRepo, productOptionRepo, variantRepo, transactor, timeoutContext)
+	_productDelivery.NewVariantHandler(e, variantUcase, middL)
+
+	orderRepo := _orderRepo.NewMysqlOrderRepo(dbConn)
+	orderItemRepo := _orderRepo.NewMysqlOrderItemRepo(dbConn)
+	shippingAddressRepo := _orderRepo.NewMysqlShippingAddressRepo(dbConn)
+	orderHistoryRepo := _orderRepo.NewMysqlOrderStatusHistoryRepo(dbConn)
+	orderUcase := _orderUcase.NewOrderUsecase(orderRepo, orderItemRepo, shippingAddressRepo, orderHistoryRepo, productRepo, variantRepo, transactor, timeoutContext)
+	_orderDelivery.NewOrderHandler(e, orderUcase, paginator, middL)
+
+	taxRuleRepo := _taxRepo.NewMysqlTaxRuleRepo(dbConn)
+	taxUcase := _taxUcase.NewTaxUsecase(taxRuleRepo, timeoutContext)
+	_taxDelivery.NewTaxHandler(e, taxUcase, middL)
+
+	shippingUcase := _shippingUcase.NewShippingUsecase(productRepo, variantRepo, newShippingCarrier(), timeoutContext)
+	_shippingDelivery.NewShippingHandler(e, shippingUcase)
+
+	cartRepo := _cartRepo.NewMysqlCartRepo(dbConn)
+	cartUcase := _cartUcase.NewCartUsecase(cartRepo, productRepo, variantRepo, orderRepo, orderItemRepo, shippingAddressRepo, exchangeRateUcase, taxUcase, shippingUcase, transactor, timeoutContext)
+	_cartDelivery.NewCartHandler(e, cartUcase, exchangeRateUcase, middL)
+
+	paymentRepo := _paymentRepo.NewMysqlPaymentRepo(dbConn)
+	paymentProvider := newPaymentProvider()
+	paymentUcase := _paymentUcase.NewPaymentUsecase(paymentRepo, orderUcase, paymentProvider, transactor, timeoutContext)
+	_paymentDelivery.NewPaymentHandler(e, paymentUcase, middL)
+
+	refundRepo := _paymentRepo.NewMysqlRefundRepo(dbConn)
+	refundUcase := _paymentUcase.NewRefundUsecase(refundRepo, paymentRepo, orderRepo, productRepo, variantRepo, orderUcase, paymentProvider, transactor, timeoutContext)
+	_paymentDelivery.NewRefundHandler(e, refundUcase, middL)
 
 	log.Fatal(e.Start(viper.GetString("server.address")))
 
 }
+
+// cleanupIdempotencyKeys drops expired idempotency records every interval
+func cleanupIdempotencyKeys(store domain.IdempotencyStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		deleted, err := store.DeleteExpired(context.Background(), time.Now())
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if deleted > 0 && viper.GetBool(`debug`) {
+			log.Printf("removed %d expired idempotency keys", deleted)
+		}
+	}
+}
+
+// importExchangeRates saves the rates of a local JSON or CSV file at startup
+func importExchangeRates(ucase domain.ExchangeRateUsecase, path string) {
+	rates, err := _currencyFile.LoadExchangeRates(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = ucase.Import(context.Background(), rates); err != nil {
+		log.Fatal(err)
+	}
+	if viper.GetBool(`debug`) {
+		log.Printf("imported %d exchange rates from %s", len(rates), path)
+	}
+}
+
+// newIPExtractor takes the client IP from the connection, the forwarded
+// headers are only read when the request comes through a configured proxy
+func newIPExtractor() echo.IPExtractor {
+	proxies := viper.GetStringSlice("server.trusted_proxies")
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range proxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+func newPaymentProvider() domain.PaymentProvider {
+	switch driver := viper.GetString("payment.provider"); driver {
+	case "fake":
+		return gateway.NewFakeProvider(viper.GetString("payment.webhook_secret"))
+	default:
+		log.Fatalf("unknown payment provider %q", driver)
+		return nil
+	}
+}
+
+func newShippingCarrier() domain.ShippingCarrier {
+	switch driver := viper.GetString("shipping.carrier"); driver {
+	case "table":
+		table, err := carrier.LoadTable(viper.GetString("shipping.table_file"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return carrier.NewTableCarrier(table)
+	default:
+		log.Fatalf("unknown shipping carrier %q", driver)
+		return nil
+	}
+}
+
+func newMailer() domain.Mailer {
+	if viper.GetString("mailer.driver") == "smtp" {
+		return mailer.NewSMTPMailer(
+			viper.GetString("mailer.smtp.host"),
+			viper.GetString("mailer.smtp.port"),
+			viper.GetString("mailer.smtp.user"),
+			viper.GetString("mailer.smtp.pass"),
+			viper.GetString("mailer.from"),
+		)
+	}
+
+	var out io.Writer = os.Stdout
+	if path := viper.GetString("mailer.log_file"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out = f
+	}
+	return mailer.NewLogMailer(out)
+}
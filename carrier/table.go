@@ -0,0 +1,221 @@
+package carrier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+const (
+	tableCarrierName = "local"
+	// defaultVolumetricDivisor turns cubic millimetres into grams, it is the
+	// usual 5000 cm³ per kilogram of the couriers
+	defaultVolumetricDivisor = 5000
+	gramsPerKg               = 1000
+)
+
+// PostalRange is an inclusive range of postal codes of the same length, e.g.
+// 10000 to 14999
+type PostalRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (r PostalRange) contains(code string) bool {
+	return len(code) == len(r.From) && len(code) == len(r.To) && r.From <= code && code <= r.To
+}
+
+// Zone is a country or a part of it. A zone without PostalRanges covers the
+// whole country.
+type Zone struct {
+	Name         string        `json:"name"`
+	Country      string        `json:"country"`
+	PostalRanges []PostalRange `json:"postal_ranges"`
+}
+
+func (z Zone) matches(address domain.ShippingAddress) bool {
+	if !strings.EqualFold(strings.TrimSpace(z.Country), strings.TrimSpace(address.Country)) {
+		return false
+	}
+	if len(z.PostalRanges) == 0 {
+		return true
+	}
+	code := normalizePostalCode(address.PostalCode)
+	for _, r := range z.PostalRanges {
+		if r.contains(code) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizePostalCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
+
+// Rate prices one method in one zone. The price is Flat plus PerKg for every
+// started kilogram of the chargeable weight, which is the larger of the actual
+// and the volumetric weight. A parcel heavier than MaxWeightGrams or with a
+// side longer than MaxLengthMM is not accepted, zero means no limit, so
+// several rates of a method act as weight bands. Shipping is free when the
+// value of the parcel reaches FreeAbove.
+type Rate struct {
+	Zone           string                `json:"zone"`
+	Method         domain.ShippingMethod `json:"method"`
+	Service        string                `json:"service"`
+	Flat           domain.Money          `json:"flat"`
+	PerKg          domain.Money          `json:"per_kg"`
+	MaxWeightGrams int64                 `json:"max_weight_grams"`
+	MaxLengthMM    int64                 `json:"max_length_mm"`
+	FreeAbove      *domain.Money         `json:"free_above,omitempty"`
+	EstimatedDays  int                   `json:"estimated_days"`
+}
+
+func (r Rate) accepts(p domain.Parcel, chargeable int64) bool {
+	return r.Flat.Currency == p.Value.Currency &&
+		(r.MaxWeightGrams == 0 || chargeable <= r.MaxWeightGrams) &&
+		(r.MaxLengthMM == 0 || p.LongestMM <= r.MaxLengthMM)
+}
+
+func (r Rate) price(p domain.Parcel, chargeable int64) (domain.Money, error) {
+	if r.FreeAbove != nil {
+		c, err := p.Value.Cmp(*r.FreeAbove)
+		if err != nil {
+			return domain.Money{}, err
+		}
+		if c >= 0 {
+			return domain.NewMoney(0, r.Flat.Currency), nil
+		}
+	}
+	if r.PerKg.IsZero() {
+		return r.Flat, nil
+	}
+	kgs := (chargeable + gramsPerKg - 1) / gramsPerKg
+	return r.Flat.Add(r.PerKg.Mul(kgs))
+}
+
+// Table is the rate card of the local carrier. Zones are tried in order and
+// the first one matching the address is used, so narrow zones are listed
+// before the country wide ones. Within the zone the first rate of each method
+// accepting the parcel is quoted.
+type Table struct {
+	Name string `json:"name"`
+	// VolumetricDivisor is the number of cubic millimetres charged as one
+	// gram, 5000 when not set
+	VolumetricDivisor int64  `json:"volumetric_divisor"`
+	Zones             []Zone `json:"zones"`
+	Rates             []Rate `json:"rates"`
+}
+
+// Validate reports the first inconsistency of t
+func (t Table) Validate() error {
+	zones := map[string]bool{}
+	for _, z := range t.Zones {
+		if z.Name == "" || strings.TrimSpace(z.Country) == "" {
+			return fmt.Errorf("shipping zone %q needs a name and a country", z.Name)
+		}
+		for _, r := range z.PostalRanges {
+			if len(r.From) != len(r.To) || r.From > r.To {
+				return fmt.Errorf("shipping zone %q: bad postal range %s-%s", z.Name, r.From, r.To)
+			}
+		}
+		zones[z.Name] = true
+	}
+	for i, r := range t.Rates {
+		switch {
+		case !zones[r.Zone]:
+			return fmt.Errorf("shipping rate %d: unknown zone %q", i, r.Zone)
+		case !r.Method.IsValid():
+			return fmt.Errorf("shipping rate %d: unknown method %q", i, r.Method)
+		case !r.Flat.Currency.IsValid() || r.Flat.IsNegative():
+			return fmt.Errorf("shipping rate %d: flat must be a price", i)
+		case !r.PerKg.IsZero() && (r.PerKg.Currency != r.Flat.Currency || r.PerKg.IsNegative()):
+			return fmt.Errorf("shipping rate %d: per_kg must be a price in %s", i, r.Flat.Currency)
+		case r.FreeAbove != nil && r.FreeAbove.Currency != r.Flat.Currency:
+			return fmt.Errorf("shipping rate %d: free_above must be in %s", i, r.Flat.Currency)
+		}
+	}
+	return nil
+}
+
+// LoadTable reads a Table from a JSON file
+func LoadTable(path string) (Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Table{}, err
+	}
+	defer f.Close()
+
+	var res Table
+	if err = json.NewDecoder(f).Decode(&res); err != nil {
+		return Table{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err = res.Validate(); err != nil {
+		return Table{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return res, nil
+}
+
+// TableCarrier is a domain.ShippingCarrier pricing parcels from a Table, it
+// needs no integration and is what the shop ships with by default
+type TableCarrier struct {
+	table Table
+}
+
+// NewTableCarrier will create a TableCarrier quoting from t
+func NewTableCarrier(t Table) *TableCarrier {
+	if t.Name == "" {
+		t.Name = tableCarrierName
+	}
+	if t.VolumetricDivisor <= 0 {
+		t.VolumetricDivisor = defaultVolumetricDivisor
+	}
+	return &TableCarrier{table: t}
+}
+
+// Quote implements domain.ShippingCarrier
+func (c *TableCarrier) Quote(ctx context.Context, address domain.ShippingAddress, parcel domain.Parcel) ([]domain.ShippingQuote, error) {
+	zone, ok := c.zone(address)
+	if !ok {
+		return []domain.ShippingQuote{}, nil
+	}
+
+	chargeable := parcel.WeightGrams
+	if volumetric := parcel.VolumeMM3 / c.table.VolumetricDivisor; volumetric > chargeable {
+		chargeable = volumetric
+	}
+
+	res := make([]domain.ShippingQuote, 0)
+	quoted := map[domain.ShippingMethod]bool{}
+	for _, r := range c.table.Rates {
+		if r.Zone != zone.Name || quoted[r.Method] || !r.accepts(parcel, chargeable) {
+			continue
+		}
+		price, err := r.price(parcel, chargeable)
+		if err != nil {
+			return nil, err
+		}
+		quoted[r.Method] = true
+		res = append(res, domain.ShippingQuote{
+			Carrier:       c.table.Name,
+			Method:        r.Method,
+			Service:       r.Service,
+			Price:         price,
+			EstimatedDays: r.EstimatedDays,
+		})
+	}
+	return res, nil
+}
+
+func (c *TableCarrier) zone(address domain.ShippingAddress) (Zone, bool) {
+	for _, z := range c.table.Zones {
+		if z.matches(address) {
+			return z, true
+		}
+	}
+	return Zone{}, false
+}
@@ -0,0 +1,119 @@
+package carrier_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/carrier"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func idr(amount int64) domain.Money {
+	return domain.NewMoney(amount, "IDR")
+}
+
+var table = carrier.Table{
+	Zones: []carrier.Zone{
+		{Name: "Jakarta", Country: "Indonesia", PostalRanges: []carrier.PostalRange{{From: "10000", To: "14999"}}},
+		{Name: "Indonesia", Country: "Indonesia"},
+	},
+	Rates: []carrier.Rate{
+		{Zone: "Jakarta", Method: domain.ShippingMethodPickup, Service: "Store pickup", Flat: idr(0)},
+		{Zone: "Jakarta", Method: domain.ShippingMethodStandard, Service: "Regular", Flat: idr(900000), PerKg: idr(500000), MaxWeightGrams: 30000, EstimatedDays: 2},
+		{Zone: "Indonesia", Method: domain.ShippingMethodStandard, Service: "Light", Flat: idr(1000000), MaxWeightGrams: 1000, EstimatedDays: 5},
+		{Zone: "Indonesia", Method: domain.ShippingMethodStandard, Service: "Regular", Flat: idr(1500000), PerKg: idr(1000000), FreeAbove: &domain.Money{Amount: 100000000, Currency: "IDR"}, EstimatedDays: 5},
+		{Zone: "Indonesia", Method: domain.ShippingMethodExpress, Service: "Next day", Flat: idr(3000000), MaxLengthMM: 1000, EstimatedDays: 1},
+	},
+}
+
+func TestTableCarrierQuote(t *testing.T) {
+	c := carrier.NewTableCarrier(table)
+
+	tests := []struct {
+		name    string
+		address domain.ShippingAddress
+		parcel  domain.Parcel
+		want    []domain.ShippingQuote
+	}{
+		{
+			name:    "postal-range",
+			address: domain.ShippingAddress{Country: "indonesia", PostalCode: "12 190"},
+			parcel:  domain.Parcel{WeightGrams: 2500, Value: idr(20000000)},
+			want: []domain.ShippingQuote{
+				{Carrier: "local", Method: domain.ShippingMethodPickup, Service: "Store pickup", Price: idr(0)},
+				{Carrier: "local", Method: domain.ShippingMethodStandard, Service: "Regular", Price: idr(2400000), EstimatedDays: 2},
+			},
+		},
+		{
+			name:    "weight-band",
+			address: domain.ShippingAddress{Country: "Indonesia", PostalCode: "40111"},
+			parcel:  domain.Parcel{WeightGrams: 800, LongestMM: 300, Value: idr(20000000)},
+			want: []domain.ShippingQuote{
+				{Carrier: "local", Method: domain.ShippingMethodStandard, Service: "Light", Price: idr(1000000), EstimatedDays: 5},
+				{Carrier: "local", Method: domain.ShippingMethodExpress, Service: "Next day", Price: idr(3000000), EstimatedDays: 1},
+			},
+		},
+		{
+			name:    "volumetric-and-oversized",
+			address: domain.ShippingAddress{Country: "Indonesia", PostalCode: "40111"},
+			parcel:  domain.Parcel{WeightGrams: 800, VolumeMM3: 1200 * 400 * 20, LongestMM: 1200, Value: idr(20000000)},
+			want: []domain.ShippingQuote{
+				{Carrier: "local", Method: domain.ShippingMethodStandard, Service: "Regular", Price: idr(3500000), EstimatedDays: 5},
+			},
+		},
+		{
+			name:    "free-above",
+			address: domain.ShippingAddress{Country: "Indonesia", PostalCode: "40111"},
+			parcel:  domain.Parcel{WeightGrams: 5000, Value: idr(100000000)},
+			want: []domain.ShippingQuote{
+				{Carrier: "local", Method: domain.ShippingMethodStandard, Service: "Regular", Price: idr(0), EstimatedDays: 5},
+				{Carrier: "local", Method: domain.ShippingMethodExpress, Service: "Next day", Price: idr(3000000), EstimatedDays: 1},
+			},
+		},
+		{
+			name:    "other-currency",
+			address: domain.ShippingAddress{Country: "Indonesia", PostalCode: "40111"},
+			parcel:  domain.Parcel{WeightGrams: 500, Value: domain.NewMoney(1000, "USD")},
+			want:    []domain.ShippingQuote{},
+		},
+		{
+			name:    "no-zone",
+			address: domain.ShippingAddress{Country: "Malaysia", PostalCode: "50000"},
+			parcel:  domain.Parcel{WeightGrams: 500, Value: idr(20000000)},
+			want:    []domain.ShippingQuote{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotes, err := c.Quote(context.TODO(), tt.address, tt.parcel)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, quotes)
+		})
+	}
+}
+
+func TestLoadTable(t *testing.T) {
+	t.Run("shipped-table", func(t *testing.T) {
+		res, err := carrier.LoadTable(filepath.Join("..", "shipping.json"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Rates)
+	})
+
+	t.Run("unknown-zone", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "shipping.json")
+		content := `{"zones":[{"name":"Indonesia","country":"Indonesia"}],"rates":[{"zone":"Java","method":"standard","flat":{"amount":0,"currency":"IDR"}}]}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err := carrier.LoadTable(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown-method", func(t *testing.T) {
+		bad := table
+		bad.Rates = []carrier.Rate{{Zone: "Indonesia", Method: "drone", Flat: idr(0)}}
+		assert.Error(t, bad.Validate())
+	})
+}
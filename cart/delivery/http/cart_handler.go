@@ -0,0 +1,362 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+const (
+	// cartCookieName holds the token of a guest cart
+	cartCookieName   = "cart_token"
+	cartCookieMaxAge = time.Hour * 24 * 30
+	// acceptCurrencyHeaderKey asks for prices in a currency when the currency query param is not set
+	acceptCurrencyHeaderKey = "Accept-Currency"
+	// variantParam picks the line of a variant when the product is in the cart in several variants
+	variantParam = "variant_id"
+)
+
+var validate = validator.New()
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// addItemRequest needs a variant_id when the product is sold in variants
+type addItemRequest struct {
+	ProductID int64 `json:"product_id" validate:"required"`
+	VariantID int64 `json:"variant_id" validate:"gte=0"`
+	Qty       int   `json:"qty" validate:"required,min=1"`
+}
+
+func (r addItemRequest) line() domain.CartLine {
+	return domain.CartLine{ProductID: r.ProductID, VariantID: r.VariantID}
+}
+
+type updateItemRequest struct {
+	Qty int `json:"qty" validate:"required,min=1"`
+}
+
+type shippingAddressRequest struct {
+	Address    string `json:"address" validate:"required,max=255"`
+	City       string `json:"city" validate:"required,max=128"`
+	PostalCode string `json:"postal_code" validate:"required,max=32"`
+	Country    string `json:"country" validate:"required,max=128"`
+}
+
+// checkoutRequest has no shipping price, it is computed from the method, the
+// address and the items. The method defaults to standard.
+type checkoutRequest struct {
+	PayMethod       string                 `json:"paymethod" validate:"required,max=64"`
+	ShippingMethod  string                 `json:"shipping_method" validate:"omitempty,oneof=standard express pickup"`
+	ShippingAddress shippingAddressRequest `json:"shipping_address" validate:"required"`
+}
+
+func (r checkoutRequest) toCheckoutRequest(display domain.Currency) domain.CheckoutRequest {
+	return domain.CheckoutRequest{
+		PayMethod:       r.PayMethod,
+		DisplayCurrency: display,
+		ShippingAddress: domain.ShippingAddress{
+			Address:        r.ShippingAddress.Address,
+			City:           r.ShippingAddress.City,
+			PostalCode:     r.ShippingAddress.PostalCode,
+			Country:        r.ShippingAddress.Country,
+			ShippingMethod: domain.ShippingMethod(r.ShippingMethod),
+		},
+	}
+}
+
+// stockErrorResponse tells which lines of the cart could not be fulfilled
+type stockErrorResponse struct {
+	Message string                 `json:"message"`
+	Lines   []domain.StockShortage `json:"lines"`
+}
+
+type CartHandler struct {
+	CUsecase domain.CartUsecase
+	RUsecase domain.ExchangeRateUsecase
+}
+
+func NewCartHandler(e *echo.Echo, cucase domain.CartUsecase, rucase domain.ExchangeRateUsecase, mw *middleware.GoMiddleware) {
+	handler := &CartHandler{
+		CUsecase: cucase,
+		RUsecase: rucase,
+	}
+	e.GET("/cart", handler.Get, mw.AuthOptional)
+	e.POST("/cart/items", handler.AddItem, mw.AuthOptional)
+	// the line of a variant is picked with the variant_id query param
+	e.PUT("/cart/items/:product_id", handler.UpdateItem, mw.AuthOptional)
+	e.DELETE("/cart/items/:product_id", handler.RemoveItem, mw.AuthOptional)
+	e.POST("/cart/checkout", handler.Checkout, mw.Auth, mw.RequireVerified, mw.Idempotent)
+}
+
+// owner resolves the cart of the request. A signed in user still holding a
+// guest cart cookie gets the guest cart merged into their own, which is how
+// the cart picked before logging in follows the user.
+func (h *CartHandler) owner(c echo.Context) (domain.CartOwner, error) {
+	var token string
+	if cookie, err := c.Cookie(cartCookieName); err == nil {
+		token = cookie.Value
+	}
+
+	auth, ok := domain.AuthFromContext(c.Request().Context())
+	if !ok {
+		return domain.CartOwner{Token: token}, nil
+	}
+	if token != "" {
+		if err := h.CUsecase.Merge(c.Request().Context(), auth.UserID, token); err != nil {
+			return domain.CartOwner{}, err
+		}
+		c.SetCookie(&http.Cookie{Name: cartCookieName, Path: "/", MaxAge: -1, HttpOnly: true})
+	}
+	return domain.CartOwner{UserID: auth.UserID}, nil
+}
+
+// cartLine is the line named by the product_id path param and the
+// variant_id query param
+func cartLine(c echo.Context) (line domain.CartLine, err error) {
+	line.ProductID, err = strconv.ParseInt(c.Param("product_id"), 10, 64)
+	if err != nil {
+		return
+	}
+	if v := c.QueryParam(variantParam); v != "" {
+		line.VariantID, err = strconv.ParseInt(v, 10, 64)
+	}
+	return
+}
+
+// Get will show the cart with subtotals computed from the current prices
+func (h *CartHandler) Get(c echo.Context) error {
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	owner, err := h.owner(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	cart, err := h.CUsecase.Get(ctx, owner)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if err = h.displayPrices(ctx, &cart, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, cart)
+}
+
+// AddItem will put a product, or a variant of it, in the cart, creating a guest cart when needed
+func (h *CartHandler) AddItem(c echo.Context) (err error) {
+	var req addItemRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	owner, err := h.owner(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	cart, err := h.CUsecase.AddItem(ctx, owner, req.line(), req.Qty)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if err = h.displayPrices(ctx, &cart, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if cart.Token != "" {
+		c.SetCookie(&http.Cookie{
+			Name:     cartCookieName,
+			Value:    cart.Token,
+			Path:     "/",
+			MaxAge:   int(cartCookieMaxAge.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	return c.JSON(http.StatusOK, cart)
+}
+
+// UpdateItem will change the quantity of a line of the cart
+func (h *CartHandler) UpdateItem(c echo.Context) (err error) {
+	line, err := cartLine(c)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req updateItemRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	owner, err := h.owner(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	cart, err := h.CUsecase.UpdateItem(ctx, owner, line, req.Qty)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if err = h.displayPrices(ctx, &cart, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, cart)
+}
+
+// RemoveItem will take a line out of the cart
+func (h *CartHandler) RemoveItem(c echo.Context) error {
+	line, err := cartLine(c)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	owner, err := h.owner(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	cart, err := h.CUsecase.RemoveItem(ctx, owner, line)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if err = h.displayPrices(ctx, &cart, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, cart)
+}
+
+// Checkout will place an order with the content of the cart of the authenticated user
+func (h *CartHandler) Checkout(c echo.Context) (err error) {
+	var req checkoutRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	owner, err := h.owner(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	order, err := h.CUsecase.Checkout(ctx, owner.UserID, req.toCheckoutRequest(currency))
+	var stockErr *domain.StockError
+	if errors.As(err, &stockErr) {
+		return c.JSON(http.StatusConflict, stockErrorResponse{
+			Message: domain.ErrInsufficientStock.Error(),
+			Lines:   stockErr.Shortages,
+		})
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+// displayCurrency is the currency asked for by the currency query param or
+// the Accept-Currency header, empty when prices are shown as stored
+func displayCurrency(c echo.Context) (domain.Currency, error) {
+	code := c.QueryParam("currency")
+	if code == "" {
+		code = c.Request().Header.Get(acceptCurrencyHeaderKey)
+	}
+	if code == "" {
+		return "", nil
+	}
+	currency := domain.Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !currency.IsValid() {
+		return "", domain.ErrInvalidCurrency
+	}
+	return currency, nil
+}
+
+// displayPrices fills the display amounts of the cart converted to currency
+func (h *CartHandler) displayPrices(ctx context.Context, cart *domain.Cart, currency domain.Currency) error {
+	if currency == "" || len(cart.Items) == 0 {
+		return nil
+	}
+	rate, err := h.RUsecase.Rate(ctx, cart.Subtotal.Currency, currency)
+	if err != nil {
+		return err
+	}
+	for i := range cart.Items {
+		price := rate.Convert(cart.Items[i].Price, currency)
+		subtotal := rate.Convert(cart.Items[i].Subtotal, currency)
+		cart.Items[i].DisplayPrice = &price
+		cart.Items[i].DisplaySubtotal = &subtotal
+	}
+	subtotal := rate.Convert(cart.Subtotal, currency)
+	cart.DisplaySubtotal = &subtotal
+	return nil
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict, domain.ErrInsufficientStock:
+		return http.StatusConflict
+	case domain.ErrBadParamInput, domain.ErrEmptyCart, domain.ErrCurrencyMismatch, domain.ErrInvalidCurrency, domain.ErrNoExchangeRate, domain.ErrShippingUnavailable, domain.ErrVariantRequired:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden, domain.ErrUnverified:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,236 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cartHttp "github.com/alfathaulia/ca_ecommerce_api/cart/delivery/http"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddItemGuest(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+	mockUcase.On("AddItem", mock.Anything, domain.CartOwner{}, domain.CartLine{ProductID: 4}, 2).
+		Return(domain.Cart{ID: 2, Token: "new-token", Items: []domain.CartItem{{ProductID: 4, Qty: 2}}}, nil).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/cart/items", strings.NewReader(`{"product_id":4,"qty":2}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.AddItem(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "cart_token=new-token")
+	assert.NotContains(t, w.Body.String(), "new-token")
+	mockUcase.AssertExpectations(t)
+}
+
+func TestAddItemVariantRequired(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+	mockUcase.On("AddItem", mock.Anything, domain.CartOwner{}, domain.CartLine{ProductID: 4}, 1).
+		Return(domain.Cart{}, domain.ErrVariantRequired).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/cart/items", strings.NewReader(`{"product_id":4,"qty":1}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.AddItem(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUcase.AssertExpectations(t)
+}
+
+func TestRemoveVariantItem(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+	mockUcase.On("RemoveItem", mock.Anything, domain.CartOwner{Token: "guest"}, domain.CartLine{ProductID: 4, VariantID: 7}).
+		Return(domain.Cart{ID: 2, Items: []domain.CartItem{}}, nil).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.DELETE, "/cart/items/4?variant_id=7", strings.NewReader(""))
+	assert.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "cart_token", Value: "guest"})
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	c.SetPath("/cart/items/:product_id")
+	c.SetParamNames("product_id")
+	c.SetParamValues("4")
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.RemoveItem(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUcase.AssertExpectations(t)
+}
+
+func TestGetDisplayCurrency(t *testing.T) {
+	price := domain.NewMoney(16250000, "IDR")
+	cart := domain.Cart{ID: 1, UserID: 3, Items: []domain.CartItem{{ProductID: 4, Qty: 2, Price: price, Subtotal: price.Mul(2)}}, Subtotal: price.Mul(2)}
+	mockUcase := new(mocks.CartUsecase)
+	mockUcase.On("Get", mock.Anything, domain.CartOwner{UserID: 3}).Return(cart, nil).Once()
+	mockRateUcase := new(mocks.ExchangeRateUsecase)
+	mockRateUcase.On("Rate", mock.Anything, domain.Currency("IDR"), domain.Currency("USD")).Return(domain.Rate(6154), nil).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/cart?currency=USD", strings.NewReader(""))
+	assert.NoError(t, err)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+		RUsecase: mockRateUcase,
+	}
+
+	err = handler.Get(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"display_price":{"amount":1000,"currency":"USD"},"display_subtotal":{"amount":2000,"currency":"USD"}`)
+	assert.Contains(t, w.Body.String(), `"subtotal":{"amount":32500000,"currency":"IDR"},"display_subtotal":{"amount":2000,"currency":"USD"}`)
+	mockRateUcase.AssertExpectations(t)
+}
+
+func TestGetMergesGuestCart(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+	mockUcase.On("Merge", mock.Anything, int64(3), "guest-token").Return(nil).Once()
+	mockUcase.On("Get", mock.Anything, domain.CartOwner{UserID: 3}).Return(domain.Cart{ID: 1, UserID: 3}, nil).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/cart", strings.NewReader(""))
+	assert.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "cart_token", Value: "guest-token"})
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.Get(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "Max-Age=0")
+	mockUcase.AssertExpectations(t)
+}
+
+const checkoutBody = `{"paymethod":"transfer","shipping_address":{"address":"Jl. Merdeka 1","city":"Bandung","postal_code":"40111","country":"Indonesia"}}`
+
+func TestCheckoutEmptyCart(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+	mockUcase.On("Checkout", mock.Anything, int64(3), mock.MatchedBy(func(r domain.CheckoutRequest) bool {
+		return r.PayMethod == "transfer" && r.ShippingAddress.City == "Bandung"
+	})).Return(domain.Order{}, domain.ErrEmptyCart).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/cart/checkout", strings.NewReader(checkoutBody))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, IsVerified: true}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.Checkout(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUcase.AssertExpectations(t)
+}
+
+func TestCheckoutInsufficientStock(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+	stockErr := &domain.StockError{Shortages: []domain.StockShortage{{ProductID: 4, Name: "Shirt", Requested: 3, Available: 1}}}
+	mockUcase.On("Checkout", mock.Anything, int64(3), mock.AnythingOfType("domain.CheckoutRequest")).Return(domain.Order{}, stockErr).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/cart/checkout", strings.NewReader(checkoutBody))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, IsVerified: true}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.Checkout(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"message":"insufficient stock","lines":[{"product_id":4,"name":"Shirt","requested":3,"available":1}]}`, w.Body.String())
+	mockUcase.AssertExpectations(t)
+}
+
+func TestCheckoutRequiresShippingAddress(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/cart/checkout", strings.NewReader(`{"paymethod":"transfer"}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, IsVerified: true}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.Checkout(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUcase.AssertNotCalled(t, "Checkout", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckoutShippingUnavailable(t *testing.T) {
+	mockUcase := new(mocks.CartUsecase)
+	mockUcase.On("Checkout", mock.Anything, int64(3), mock.MatchedBy(func(r domain.CheckoutRequest) bool {
+		return r.ShippingAddress.ShippingMethod == domain.ShippingMethodExpress
+	})).Return(domain.Order{}, domain.ErrShippingUnavailable).Once()
+
+	e := echo.New()
+	body := `{"paymethod":"transfer","shipping_method":"express","shipping_address":{"address":"Jl. Merdeka 1","city":"Bandung","postal_code":"40111","country":"Indonesia"}}`
+	req, err := http.NewRequest(echo.POST, "/cart/checkout", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, IsVerified: true}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := cartHttp.CartHandler{
+		CUsecase: mockUcase,
+	}
+
+	err = handler.Checkout(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUcase.AssertExpectations(t)
+}
@@ -0,0 +1,221 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlCartRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlCartRepo will create an object that represent the domain.CartRepository interface
+func NewMysqlCartRepo(DB *sql.DB) domain.CartRepository {
+	return &mysqlCartRepo{DB: DB}
+}
+
+func (m *mysqlCartRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Cart, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Cart, 0)
+	for rows.Next() {
+		t := domain.Cart{}
+		var userID sql.NullInt64
+		var tokenHash sql.NullString
+		err = rows.Scan(
+			&t.ID,
+			&userID,
+			&tokenHash,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.UserID = userID.Int64
+		t.TokenHash = tokenHash.String
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlCartRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Cart, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlCartRepo) GetByUserID(ctx context.Context, userID int64) (domain.Cart, error) {
+	query := `SELECT id, user_id, token_hash, updated_at, created_at FROM cart WHERE user_id = ?`
+	return m.getOne(ctx, query, userID)
+}
+
+func (m *mysqlCartRepo) GetByTokenHash(ctx context.Context, tokenHash string) (domain.Cart, error) {
+	query := `SELECT id, user_id, token_hash, updated_at, created_at FROM cart WHERE token_hash = ?`
+	return m.getOne(ctx, query, tokenHash)
+}
+
+// Store inserts the cart, user carts are stored without a token and guest carts without a user
+func (m *mysqlCartRepo) Store(ctx context.Context, c *domain.Cart) (err error) {
+	query := `INSERT  cart SET user_id=? , token_hash=? , updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	userID := sql.NullInt64{Int64: c.UserID, Valid: c.UserID != 0}
+	tokenHash := sql.NullString{String: c.TokenHash, Valid: c.TokenHash != ""}
+	res, err := stmt.ExecContext(ctx, userID, tokenHash, c.UpdatedAt, c.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	c.ID = lastID
+	return
+}
+
+// AssignUser turns a guest cart into the cart of userID, the guest token stops working
+func (m *mysqlCartRepo) AssignUser(ctx context.Context, id int64, userID int64) (err error) {
+	query := `UPDATE  cart SET user_id=? , token_hash=NULL , updated_at=? WHERE id=? AND user_id IS NULL`
+	return m.execOne(ctx, query, userID, time.Now(), id)
+}
+
+func (m *mysqlCartRepo) Delete(ctx context.Context, id int64) (err error) {
+	if err = m.ClearItems(ctx, id); err != nil {
+		return
+	}
+	query := "DELETE FROM cart WHERE id = ?"
+	return m.execOne(ctx, query, id)
+}
+
+func (m *mysqlCartRepo) FetchItems(ctx context.Context, cartID int64) (result []domain.CartItem, err error) {
+	query := `SELECT product_id, variant_id, qty FROM cart_item WHERE cart_id = ? ORDER BY created_at, product_id, variant_id`
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, cartID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.CartItem, 0)
+	for rows.Next() {
+		t := domain.CartItem{}
+		err = rows.Scan(&t.ProductID, &t.VariantID, &t.Qty)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// AddItem adds qty to the line, creating the line when the product, or its variant, is not in the cart yet
+func (m *mysqlCartRepo) AddItem(ctx context.Context, cartID int64, line domain.CartLine, qty int) (err error) {
+	query := `INSERT  cart_item SET cart_id=? , product_id=? , variant_id=? , qty=? , created_at=? ON DUPLICATE KEY UPDATE qty = qty + VALUES(qty)`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, cartID, line.ProductID, line.VariantID, qty, time.Now())
+	return
+}
+
+// UpdateItem sets the quantity of a line, the caller makes sure the line exists
+func (m *mysqlCartRepo) UpdateItem(ctx context.Context, cartID int64, line domain.CartLine, qty int) (err error) {
+	query := `UPDATE  cart_item SET qty=? WHERE cart_id=? AND product_id=? AND variant_id=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, qty, cartID, line.ProductID, line.VariantID)
+	return
+}
+
+func (m *mysqlCartRepo) RemoveItem(ctx context.Context, cartID int64, line domain.CartLine) (err error) {
+	query := "DELETE FROM cart_item WHERE cart_id = ? AND product_id = ? AND variant_id = ?"
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, cartID, line.ProductID, line.VariantID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlCartRepo) ClearItems(ctx context.Context, cartID int64) (err error) {
+	query := "DELETE FROM cart_item WHERE cart_id = ?"
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, cartID)
+	return
+}
+
+func (m *mysqlCartRepo) execOne(ctx context.Context, query string, args ...interface{}) (err error) {
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	cartMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/cart/repository/mysql"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func TestGetByUserID(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash", "updated_at", "created_at"}).
+		AddRow(1, 3, nil, now, now)
+
+	query := `SELECT id, user_id, token_hash, updated_at, created_at FROM cart WHERE user_id = \?`
+	mock.ExpectQuery(query).WithArgs(int64(3)).WillReturnRows(rows)
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	res, err := a.GetByUserID(context.TODO(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), res.UserID)
+	assert.Empty(t, res.TokenHash)
+}
+
+func TestGetByTokenHashNotFound(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `SELECT id, user_id, token_hash, updated_at, created_at FROM cart WHERE token_hash = \?`
+	mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "token_hash", "updated_at", "created_at"}))
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	_, err := a.GetByTokenHash(context.TODO(), "hash")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestStore(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  cart SET user_id=\\? , token_hash=\\? , updated_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(nil, "hash", now, now).WillReturnResult(sqlmock.NewResult(2, 1))
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	c := &domain.Cart{TokenHash: "hash", UpdatedAt: now, CreatedAt: now}
+	err := a.Store(context.TODO(), c)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), c.ID)
+}
+
+func TestAssignUser(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  cart SET user_id=\\? , token_hash=NULL , updated_at=\\? WHERE id=\\? AND user_id IS NULL"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(int64(3), sqlmock.AnyArg(), int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	err := a.AssignUser(context.TODO(), 2, 3)
+	assert.NoError(t, err)
+}
+
+func TestFetchItems(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"product_id", "variant_id", "qty"}).AddRow(4, 0, 2).AddRow(5, 7, 1)
+
+	query := `SELECT product_id, variant_id, qty FROM cart_item WHERE cart_id = \? ORDER BY created_at, product_id, variant_id`
+	mock.ExpectQuery(query).WithArgs(int64(1)).WillReturnRows(rows)
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	list, err := a.FetchItems(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.CartItem{{ProductID: 4, Qty: 2}, {ProductID: 5, VariantID: 7, Qty: 1}}, list)
+}
+
+func TestAddItem(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  cart_item SET cart_id=\\? , product_id=\\? , variant_id=\\? , qty=\\? , created_at=\\? ON DUPLICATE KEY UPDATE qty = qty \\+ VALUES\\(qty\\)"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(int64(1), int64(4), int64(7), 2, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	err := a.AddItem(context.TODO(), 1, domain.CartLine{ProductID: 4, VariantID: 7}, 2)
+	assert.NoError(t, err)
+}
+
+func TestRemoveItemNotFound(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "DELETE FROM cart_item WHERE cart_id = \\? AND product_id = \\? AND variant_id = \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(int64(1), int64(4), int64(0)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	err := a.RemoveItem(context.TODO(), 1, domain.CartLine{ProductID: 4})
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock := NewMock()
+
+	mock.ExpectPrepare("DELETE FROM cart_item WHERE cart_id = \\?").ExpectExec().WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectPrepare("DELETE FROM cart WHERE id = \\?").ExpectExec().WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := cartMysqlRepo.NewMysqlCartRepo(db)
+	err := a.Delete(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
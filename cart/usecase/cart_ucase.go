@@ -0,0 +1,509 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	util "github.com/alfathaulia/ca_ecommerce_api/user/repository/utils"
+)
+
+const cartTokenSize = 32
+
+type cartUsecase struct {
+	cartRepo       domain.CartRepository
+	productRepo    domain.ProductRepository
+	variantRepo    domain.VariantRepository
+	orderRepo      domain.OrderRepository
+	orderItemRepo  domain.OrderItemRepository
+	addressRepo    domain.ShippingAddressRepository
+	rateUsecase    domain.ExchangeRateUsecase
+	taxUsecase     domain.TaxUsecase
+	shipUsecase    domain.ShippingUsecase
+	transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewCartUsecase will create an object that represent the domain.CartUsecase interface
+func NewCartUsecase(c domain.CartRepository, p domain.ProductRepository, v domain.VariantRepository, o domain.OrderRepository, oi domain.OrderItemRepository, sa domain.ShippingAddressRepository, r domain.ExchangeRateUsecase, t domain.TaxUsecase, s domain.ShippingUsecase, tx domain.Transactor, timeout time.Duration) domain.CartUsecase {
+	return &cartUsecase{
+		cartRepo:       c,
+		productRepo:    p,
+		variantRepo:    v,
+		orderRepo:      o,
+		orderItemRepo:  oi,
+		addressRepo:    sa,
+		rateUsecase:    r,
+		taxUsecase:     t,
+		shipUsecase:    s,
+		transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (m *cartUsecase) find(ctx context.Context, owner domain.CartOwner) (domain.Cart, error) {
+	if owner.UserID != 0 {
+		return m.cartRepo.GetByUserID(ctx, owner.UserID)
+	}
+	if owner.Token != "" {
+		return m.cartRepo.GetByTokenHash(ctx, util.HashToken(owner.Token))
+	}
+	return domain.Cart{}, domain.ErrNotFound
+}
+
+// findOrCreate returns the cart of owner, creating it on first use. A new
+// guest cart gets a fresh token which is returned in Cart.Token.
+func (m *cartUsecase) findOrCreate(ctx context.Context, owner domain.CartOwner) (domain.Cart, error) {
+	res, err := m.find(ctx, owner)
+	if err != domain.ErrNotFound {
+		return res, err
+	}
+
+	now := time.Now()
+	res = domain.Cart{UserID: owner.UserID, UpdatedAt: now, CreatedAt: now}
+	if owner.UserID == 0 {
+		res.Token, err = util.RandomToken(cartTokenSize)
+		if err != nil {
+			return domain.Cart{}, err
+		}
+		res.TokenHash = util.HashToken(res.Token)
+	}
+	if err = m.cartRepo.Store(ctx, &res); err != nil {
+		return domain.Cart{}, err
+	}
+	return res, nil
+}
+
+// getVariant returns the variant of the line, ErrNotFound when it is gone or
+// belongs to another product
+func (m *cartUsecase) getVariant(ctx context.Context, line domain.CartLine) (domain.Variant, error) {
+	variant, err := m.variantRepo.GetByID(ctx, line.VariantID)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+	if variant.ProductID != line.ProductID {
+		return domain.Variant{}, domain.ErrNotFound
+	}
+	return variant, nil
+}
+
+// price fills the name, image, price and SKU of the line from its product,
+// or its variant when the line has one
+func (m *cartUsecase) price(ctx context.Context, item *domain.CartItem) error {
+	product, err := m.productRepo.GetByID(ctx, item.ProductID)
+	if err != nil {
+		return err
+	}
+	item.Name = product.Name
+	item.Image = product.Image
+	item.Price = product.Price
+	if item.VariantID != 0 {
+		variant, err := m.getVariant(ctx, item.Line())
+		if err != nil {
+			return err
+		}
+		item.Name = variant.ItemName(product.Name)
+		if variant.Image != "" {
+			item.Image = variant.Image
+		}
+		item.Price = variant.Price
+		item.SKU = variant.SKU
+	}
+	return nil
+}
+
+// load fills the lines of the cart priced from the current products and
+// variants. Lines of products or variants removed from the catalog are
+// dropped.
+func (m *cartUsecase) load(ctx context.Context, c domain.Cart) (domain.Cart, error) {
+	items, err := m.cartRepo.FetchItems(ctx, c.ID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	c.Items = make([]domain.CartItem, 0, len(items))
+	c.Subtotal = domain.Money{}
+	for _, item := range items {
+		err := m.price(ctx, &item)
+		if err == domain.ErrNotFound {
+			if err = m.cartRepo.RemoveItem(ctx, c.ID, item.Line()); err != nil {
+				return domain.Cart{}, err
+			}
+			continue
+		}
+		if err != nil {
+			return domain.Cart{}, err
+		}
+		item.Subtotal = item.Price.Mul(int64(item.Qty))
+		if len(c.Items) == 0 {
+			c.Subtotal = item.Subtotal
+		} else if c.Subtotal, err = c.Subtotal.Add(item.Subtotal); err != nil {
+			return domain.Cart{}, err
+		}
+		c.Items = append(c.Items, item)
+	}
+	return c, nil
+}
+
+func (m *cartUsecase) Get(ctx context.Context, owner domain.CartOwner) (domain.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.find(ctx, owner)
+	if err == domain.ErrNotFound {
+		return domain.Cart{Items: []domain.CartItem{}}, nil
+	}
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	return m.load(ctx, res)
+}
+
+// checkLine makes sure the product of the line exists and that the line
+// names one of its variants exactly when it has some, and returns the price
+// of the line
+func (m *cartUsecase) checkLine(ctx context.Context, line domain.CartLine) (domain.Money, error) {
+	product, err := m.productRepo.GetByID(ctx, line.ProductID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if line.VariantID != 0 {
+		variant, err := m.getVariant(ctx, line)
+		return variant.Price, err
+	}
+	variants, err := m.variantRepo.FetchByProduct(ctx, line.ProductID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if len(variants) > 0 {
+		return domain.Money{}, domain.ErrVariantRequired
+	}
+	return product.Price, nil
+}
+
+func (m *cartUsecase) AddItem(ctx context.Context, owner domain.CartOwner, line domain.CartLine, qty int) (domain.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if qty < 1 {
+		return domain.Cart{}, domain.ErrBadParamInput
+	}
+	price, err := m.checkLine(ctx, line)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	res, err := m.findOrCreate(ctx, owner)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	// a cart is priced in one currency, its subtotal could not add up otherwise
+	current, err := m.load(ctx, res)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	if len(current.Items) > 0 && current.Subtotal.Currency != price.Currency {
+		return domain.Cart{}, domain.ErrCurrencyMismatch
+	}
+	if err = m.cartRepo.AddItem(ctx, res.ID, line, qty); err != nil {
+		return domain.Cart{}, err
+	}
+	return m.load(ctx, res)
+}
+
+func (m *cartUsecase) UpdateItem(ctx context.Context, owner domain.CartOwner, line domain.CartLine, qty int) (domain.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if qty < 1 {
+		return domain.Cart{}, domain.ErrBadParamInput
+	}
+	res, err := m.find(ctx, owner)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+
+	items, err := m.cartRepo.FetchItems(ctx, res.ID)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	if !hasLine(items, line) {
+		return domain.Cart{}, domain.ErrNotFound
+	}
+	if err = m.cartRepo.UpdateItem(ctx, res.ID, line, qty); err != nil {
+		return domain.Cart{}, err
+	}
+	return m.load(ctx, res)
+}
+
+func hasLine(items []domain.CartItem, line domain.CartLine) bool {
+	for _, item := range items {
+		if item.Line() == line {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *cartUsecase) RemoveItem(ctx context.Context, owner domain.CartOwner, line domain.CartLine) (domain.Cart, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.find(ctx, owner)
+	if err != nil {
+		return domain.Cart{}, err
+	}
+	if err = m.cartRepo.RemoveItem(ctx, res.ID, line); err != nil {
+		return domain.Cart{}, err
+	}
+	return m.load(ctx, res)
+}
+
+// Merge moves the guest cart into the cart of the user. When the user has no
+// cart yet the guest cart is simply handed over.
+// The lines of the guest cart in another currency than the lines of the
+// user cart are dropped.
+func (m *cartUsecase) Merge(ctx context.Context, userID int64, guestToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	guest, err := m.cartRepo.GetByTokenHash(ctx, util.HashToken(guestToken))
+	if err == domain.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	userCart, err := m.cartRepo.GetByUserID(ctx, userID)
+	if err == domain.ErrNotFound {
+		return m.cartRepo.AssignUser(ctx, guest.ID, userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	current, err := m.load(ctx, userCart)
+	if err != nil {
+		return err
+	}
+	items, err := m.cartRepo.FetchItems(ctx, guest.ID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		// lines priced in another currency than the cart of the user are left out
+		if len(current.Items) > 0 {
+			err = m.price(ctx, &item)
+			if err == domain.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if item.Price.Currency != current.Subtotal.Currency {
+				continue
+			}
+		}
+		if err = m.cartRepo.AddItem(ctx, userCart.ID, item.Line(), item.Qty); err != nil {
+			return err
+		}
+	}
+	return m.cartRepo.Delete(ctx, guest.ID)
+}
+
+// Checkout turns the cart of the user into an order priced from the current
+// products, taxed for the shipping address and shipped with the requested
+// method (standard when none is given), takes the ordered quantities
+// out of the stock, of the variant for a line that has one, and empties the
+// cart. Everything happens in one transaction: the product and variant rows
+// are locked in id order so concurrent checkouts can neither oversell nor
+// deadlock, and when any line is short nothing is written and a
+// *domain.StockError lists every short line.
+func (m *cartUsecase) Checkout(ctx context.Context, userID int64, req domain.CheckoutRequest) (res domain.Order, err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	err = m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		res, err = m.checkout(ctx, userID, req)
+		return err
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return res, nil
+}
+
+func (m *cartUsecase) checkout(ctx context.Context, userID int64, req domain.CheckoutRequest) (domain.Order, error) {
+	c, err := m.cartRepo.GetByUserID(ctx, userID)
+	if err == domain.ErrNotFound {
+		return domain.Order{}, domain.ErrEmptyCart
+	}
+	if err != nil {
+		return domain.Order{}, err
+	}
+	lines, err := m.cartRepo.FetchItems(ctx, c.ID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if len(lines) == 0 {
+		return domain.Order{}, domain.ErrEmptyCart
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].ProductID != lines[j].ProductID {
+			return lines[i].ProductID < lines[j].ProductID
+		}
+		return lines[i].VariantID < lines[j].VariantID
+	})
+
+	products := make([]domain.Product, len(lines))
+	variants := make([]domain.Variant, len(lines))
+	var shortages []domain.StockShortage
+	for i, line := range lines {
+		product, err := m.productRepo.GetByIDForUpdate(ctx, line.ProductID)
+		if err != nil && err != domain.ErrNotFound {
+			return domain.Order{}, err
+		}
+		name, available := product.Name, product.CountInStock
+		if err == nil && line.VariantID != 0 {
+			variants[i], err = m.variantRepo.GetByIDForUpdate(ctx, line.VariantID)
+			if err != nil && err != domain.ErrNotFound {
+				return domain.Order{}, err
+			}
+			if err == nil && variants[i].ProductID != line.ProductID {
+				err = domain.ErrNotFound
+			}
+			name, available = variants[i].ItemName(product.Name), variants[i].CountInStock
+		} else if err == nil {
+			// a line added before the product got variants has no stock of its own
+			others, err := m.variantRepo.FetchByProduct(ctx, line.ProductID)
+			if err != nil {
+				return domain.Order{}, err
+			}
+			if len(others) > 0 {
+				return domain.Order{}, domain.ErrVariantRequired
+			}
+		}
+		if err == domain.ErrNotFound {
+			available = 0
+		}
+		if err == domain.ErrNotFound || available < line.Qty {
+			shortages = append(shortages, domain.StockShortage{
+				ProductID: line.ProductID,
+				VariantID: line.VariantID,
+				Name:      name,
+				Requested: line.Qty,
+				Available: available,
+			})
+		}
+		products[i] = product
+	}
+	if len(shortages) > 0 {
+		return domain.Order{}, &domain.StockError{Shortages: shortages}
+	}
+
+	now := time.Now()
+	currency := products[0].Price.Currency
+	order := domain.Order{
+		UserID:        userID,
+		PayMethod:     req.PayMethod,
+		TaxPrice:      domain.NewMoney(0, currency),
+		ShippingPrice: domain.NewMoney(0, currency),
+		TotalPrice:    domain.NewMoney(0, currency),
+		Status:        domain.OrderStatusPending,
+		UpdatedAt:     now,
+		CreatedAt:     now,
+	}
+	items := make([]domain.OrderItem, len(lines))
+	for i, line := range lines {
+		items[i] = domain.OrderItem{
+			ProductID:   line.ProductID,
+			Name:        products[i].Name,
+			Qty:         line.Qty,
+			Price:       products[i].Price,
+			Image:       products[i].Image,
+			TaxCategory: products[i].TaxCategory,
+		}
+		if line.VariantID != 0 {
+			items[i].VariantID = line.VariantID
+			items[i].SKU = variants[i].SKU
+			items[i].Name = variants[i].ItemName(products[i].Name)
+			items[i].Price = variants[i].Price
+			if variants[i].Image != "" {
+				items[i].Image = variants[i].Image
+			}
+		}
+	}
+	if err = m.taxUsecase.Apply(ctx, req.ShippingAddress, items); err != nil {
+		return domain.Order{}, err
+	}
+	for _, item := range items {
+		if order.TotalPrice, err = order.TotalPrice.Add(item.Charged(item.Qty)); err != nil {
+			return domain.Order{}, err
+		}
+		if order.TaxPrice, err = order.TaxPrice.Add(item.TaxAmount); err != nil {
+			return domain.Order{}, err
+		}
+	}
+
+	address := req.ShippingAddress
+	if address.ShippingMethod == "" {
+		address.ShippingMethod = domain.ShippingMethodStandard
+	}
+	var parcel domain.Parcel
+	for i, line := range lines {
+		if err = parcel.Add(products[i], items[i].Price, line.Qty); err != nil {
+			return domain.Order{}, err
+		}
+	}
+	quote, err := m.shipUsecase.Price(ctx, address, parcel, address.ShippingMethod)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	order.ShippingPrice = quote.Price
+	if order.TotalPrice, err = order.TotalPrice.Add(quote.Price); err != nil {
+		return domain.Order{}, err
+	}
+
+	if req.DisplayCurrency != "" && req.DisplayCurrency != currency {
+		rate, err := m.rateUsecase.Rate(ctx, currency, req.DisplayCurrency)
+		if err != nil {
+			return domain.Order{}, err
+		}
+		order.LockExchangeRate(req.DisplayCurrency, rate)
+	}
+	for _, line := range lines {
+		if line.VariantID != 0 {
+			if err = m.variantRepo.DecrementStock(ctx, line.VariantID, line.Qty); err != nil {
+				return domain.Order{}, err
+			}
+		}
+		if err = m.productRepo.DecrementStock(ctx, line.ProductID, line.Qty); err != nil {
+			return domain.Order{}, err
+		}
+	}
+	if err = m.orderRepo.Store(ctx, &order); err != nil {
+		return domain.Order{}, err
+	}
+
+	for i := range items {
+		items[i].OrderID = order.ID
+		if err = m.orderItemRepo.Store(ctx, &items[i]); err != nil {
+			return domain.Order{}, err
+		}
+	}
+	order.Items = items
+
+	address.OrderID = order.ID
+	address.ShippingPrice = order.ShippingPrice
+	if err = m.addressRepo.Store(ctx, &address); err != nil {
+		return domain.Order{}, err
+	}
+	order.ShippingAddress = &address
+
+	if err = m.cartRepo.ClearItems(ctx, c.ID); err != nil {
+		return domain.Order{}, err
+	}
+	return order, nil
+}
@@ -0,0 +1,509 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ucase "github.com/alfathaulia/ca_ecommerce_api/cart/usecase"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	util "github.com/alfathaulia/ca_ecommerce_api/user/repository/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var shirt = domain.Product{ID: 4, Name: "Shirt", Image: "/images/shirt.jpg", Price: domain.NewMoney(15000000, "IDR"), CountInStock: 5}
+
+func TestGet(t *testing.T) {
+	mockCartRepo := new(mocks.CartRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockRateUcase := new(mocks.ExchangeRateUsecase)
+	mockTaxUcase := new(mocks.TaxUsecase)
+	mockShipUcase := new(mocks.ShippingUsecase)
+	mockTransactor := new(mocks.Transactor)
+	userCart := domain.Cart{ID: 1, UserID: 3}
+
+	t.Run("computes-subtotals", func(t *testing.T) {
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 2}}, nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		res, err := u.Get(context.TODO(), domain.CartOwner{UserID: userCart.UserID})
+		assert.NoError(t, err)
+		assert.Len(t, res.Items, 1)
+		assert.Equal(t, shirt.Name, res.Items[0].Name)
+		assert.Equal(t, domain.NewMoney(30000000, "IDR"), res.Items[0].Subtotal)
+		assert.Equal(t, domain.NewMoney(30000000, "IDR"), res.Subtotal)
+		mockCartRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("drops-deleted-products", func(t *testing.T) {
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: 9, Qty: 1}}, nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Product{}, domain.ErrNotFound).Once()
+		mockCartRepo.On("RemoveItem", mock.Anything, userCart.ID, domain.CartLine{ProductID: 9}).Return(nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		res, err := u.Get(context.TODO(), domain.CartOwner{UserID: userCart.UserID})
+		assert.NoError(t, err)
+		assert.Empty(t, res.Items)
+		mockCartRepo.AssertExpectations(t)
+	})
+
+	t.Run("no-cart-yet", func(t *testing.T) {
+		mockCartRepo.On("GetByTokenHash", mock.Anything, util.HashToken("guest")).Return(domain.Cart{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		res, err := u.Get(context.TODO(), domain.CartOwner{Token: "guest"})
+		assert.NoError(t, err)
+		assert.Empty(t, res.Items)
+		mockCartRepo.AssertExpectations(t)
+	})
+}
+
+func TestAddItem(t *testing.T) {
+	mockCartRepo := new(mocks.CartRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockRateUcase := new(mocks.ExchangeRateUsecase)
+	mockTaxUcase := new(mocks.TaxUsecase)
+	mockShipUcase := new(mocks.ShippingUsecase)
+	mockTransactor := new(mocks.Transactor)
+
+	t.Run("creates-guest-cart", func(t *testing.T) {
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Twice()
+		mockCartRepo.On("Store", mock.Anything, mock.MatchedBy(func(c *domain.Cart) bool {
+			return c.UserID == 0 && c.TokenHash != "" && c.TokenHash == util.HashToken(c.Token)
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Cart).ID = 2
+		}).Once()
+		mockVariantRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.Variant{}, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, int64(2)).Return([]domain.CartItem{}, nil).Once()
+		mockCartRepo.On("AddItem", mock.Anything, int64(2), domain.CartLine{ProductID: shirt.ID}, 1).Return(nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, int64(2)).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 1}}, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		res, err := u.AddItem(context.TODO(), domain.CartOwner{}, domain.CartLine{ProductID: shirt.ID}, 1)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Token)
+		assert.Equal(t, shirt.Price, res.Subtotal)
+		mockCartRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-product", func(t *testing.T) {
+		mockProductRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Product{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.AddItem(context.TODO(), domain.CartOwner{UserID: 3}, domain.CartLine{ProductID: 9}, 1)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("variant-required", func(t *testing.T) {
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockVariantRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.Variant{{ID: 7, ProductID: shirt.ID}}, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.AddItem(context.TODO(), domain.CartOwner{UserID: 3}, domain.CartLine{ProductID: shirt.ID}, 1)
+		assert.Equal(t, domain.ErrVariantRequired, err)
+		mockVariantRepo.AssertExpectations(t)
+	})
+
+	t.Run("variant-priced", func(t *testing.T) {
+		userCart := domain.Cart{ID: 1, UserID: 3}
+		line := domain.CartLine{ProductID: shirt.ID, VariantID: 7}
+		shirtXL := domain.Variant{ID: 7, ProductID: shirt.ID, SKU: "SHIRT-XL", Price: domain.NewMoney(17500000, "IDR"), Image: "/images/shirt-xl.jpg",
+			Options: []domain.OptionValue{{ID: 5, Value: "XL"}}}
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Twice()
+		mockVariantRepo.On("GetByID", mock.Anything, shirtXL.ID).Return(shirtXL, nil).Twice()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{}, nil).Once()
+		mockCartRepo.On("AddItem", mock.Anything, userCart.ID, line, 2).Return(nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, VariantID: shirtXL.ID, Qty: 2}}, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		res, err := u.AddItem(context.TODO(), domain.CartOwner{UserID: userCart.UserID}, line, 2)
+		assert.NoError(t, err)
+		if assert.Len(t, res.Items, 1) {
+			assert.Equal(t, "Shirt (XL)", res.Items[0].Name)
+			assert.Equal(t, "SHIRT-XL", res.Items[0].SKU)
+			assert.Equal(t, shirtXL.Image, res.Items[0].Image)
+		}
+		assert.Equal(t, domain.NewMoney(35000000, "IDR"), res.Subtotal)
+		mockVariantRepo.AssertExpectations(t)
+	})
+
+	t.Run("other-currency-than-cart", func(t *testing.T) {
+		mockCartRepo := new(mocks.CartRepository)
+		mockProductRepo := new(mocks.ProductRepository)
+		mockVariantRepo := new(mocks.VariantRepository)
+		userCart := domain.Cart{ID: 1, UserID: 3}
+		hat := domain.Product{ID: 8, Name: "Hat", Price: domain.NewMoney(2000, "USD")}
+		mockProductRepo.On("GetByID", mock.Anything, hat.ID).Return(hat, nil).Once()
+		mockVariantRepo.On("FetchByProduct", mock.Anything, hat.ID).Return([]domain.Variant{}, nil).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 1}}, nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.AddItem(context.TODO(), domain.CartOwner{UserID: userCart.UserID}, domain.CartLine{ProductID: hat.ID}, 1)
+		assert.Equal(t, domain.ErrCurrencyMismatch, err)
+		mockCartRepo.AssertNotCalled(t, "AddItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid-qty", func(t *testing.T) {
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.AddItem(context.TODO(), domain.CartOwner{UserID: 3}, domain.CartLine{ProductID: shirt.ID}, 0)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestUpdateItem(t *testing.T) {
+	mockCartRepo := new(mocks.CartRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockRateUcase := new(mocks.ExchangeRateUsecase)
+	mockTaxUcase := new(mocks.TaxUsecase)
+	mockShipUcase := new(mocks.ShippingUsecase)
+	mockTransactor := new(mocks.Transactor)
+	userCart := domain.Cart{ID: 1, UserID: 3}
+
+	t.Run("item-not-in-cart", func(t *testing.T) {
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{}, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.UpdateItem(context.TODO(), domain.CartOwner{UserID: userCart.UserID}, domain.CartLine{ProductID: shirt.ID}, 3)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockCartRepo.AssertExpectations(t)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	mockCartRepo := new(mocks.CartRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockRateUcase := new(mocks.ExchangeRateUsecase)
+	mockTaxUcase := new(mocks.TaxUsecase)
+	mockShipUcase := new(mocks.ShippingUsecase)
+	mockTransactor := new(mocks.Transactor)
+	guestCart := domain.Cart{ID: 2, TokenHash: util.HashToken("guest")}
+
+	t.Run("hand-over", func(t *testing.T) {
+		mockCartRepo.On("GetByTokenHash", mock.Anything, guestCart.TokenHash).Return(guestCart, nil).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, int64(3)).Return(domain.Cart{}, domain.ErrNotFound).Once()
+		mockCartRepo.On("AssignUser", mock.Anything, guestCart.ID, int64(3)).Return(nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		err := u.Merge(context.TODO(), 3, "guest")
+		assert.NoError(t, err)
+		mockCartRepo.AssertExpectations(t)
+	})
+
+	t.Run("into-existing-cart", func(t *testing.T) {
+		userCart := domain.Cart{ID: 1, UserID: 3}
+		mockCartRepo.On("GetByTokenHash", mock.Anything, guestCart.TokenHash).Return(guestCart, nil).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, int64(3)).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{}, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, guestCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 2}}, nil).Once()
+		mockCartRepo.On("AddItem", mock.Anything, userCart.ID, domain.CartLine{ProductID: shirt.ID}, 2).Return(nil).Once()
+		mockCartRepo.On("Delete", mock.Anything, guestCart.ID).Return(nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		err := u.Merge(context.TODO(), 3, "guest")
+		assert.NoError(t, err)
+		mockCartRepo.AssertExpectations(t)
+	})
+
+	t.Run("drops-lines-in-other-currency", func(t *testing.T) {
+		mockCartRepo := new(mocks.CartRepository)
+		mockProductRepo := new(mocks.ProductRepository)
+		userCart := domain.Cart{ID: 1, UserID: 3}
+		hat := domain.Product{ID: 8, Name: "Hat", Price: domain.NewMoney(2000, "USD")}
+		mockCartRepo.On("GetByTokenHash", mock.Anything, guestCart.TokenHash).Return(guestCart, nil).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, int64(3)).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 1}}, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, guestCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 2}, {ProductID: hat.ID, Qty: 1}}, nil).Once()
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Twice()
+		mockProductRepo.On("GetByID", mock.Anything, hat.ID).Return(hat, nil).Once()
+		mockCartRepo.On("AddItem", mock.Anything, userCart.ID, domain.CartLine{ProductID: shirt.ID}, 2).Return(nil).Once()
+		mockCartRepo.On("Delete", mock.Anything, guestCart.ID).Return(nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		err := u.Merge(context.TODO(), 3, "guest")
+		assert.NoError(t, err)
+		mockCartRepo.AssertExpectations(t)
+		mockCartRepo.AssertNotCalled(t, "AddItem", mock.Anything, userCart.ID, domain.CartLine{ProductID: hat.ID}, 1)
+	})
+
+	t.Run("unknown-guest-cart", func(t *testing.T) {
+		mockCartRepo.On("GetByTokenHash", mock.Anything, util.HashToken("gone")).Return(domain.Cart{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		err := u.Merge(context.TODO(), 3, "gone")
+		assert.NoError(t, err)
+		mockCartRepo.AssertExpectations(t)
+	})
+}
+
+// runInTransaction makes the Transactor mock call the unit of work directly
+func runInTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+// applyTax makes the TaxUsecase mock tax every item with an exclusive rate
+func applyTax(rate int64) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		rule := domain.TaxRule{Rate: rate}
+		items := args.Get(2).([]domain.OrderItem)
+		for i := range items {
+			items[i].TaxRate = rate
+			items[i].TaxAmount = rule.Tax(items[i].Subtotal())
+		}
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	mockCartRepo := new(mocks.CartRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockRateUcase := new(mocks.ExchangeRateUsecase)
+	mockTaxUcase := new(mocks.TaxUsecase)
+	mockShipUcase := new(mocks.ShippingUsecase)
+	mockTransactor := new(mocks.Transactor)
+	userCart := domain.Cart{ID: 1, UserID: 3}
+	pants := domain.Product{ID: 2, Name: "Pants", Image: "/images/pants.jpg", Price: domain.NewMoney(20000000, "IDR"), CountInStock: 1}
+	req := domain.CheckoutRequest{
+		PayMethod:       "transfer",
+		ShippingAddress: domain.ShippingAddress{Address: "Jl. Merdeka 1", City: "Bandung", PostalCode: "40111", Country: "Indonesia"},
+	}
+	// none of the products of the cart are sold in variants
+	mockVariantRepo.On("FetchByProduct", mock.Anything, mock.Anything).Return([]domain.Variant{}, nil)
+
+	t.Run("success", func(t *testing.T) {
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 2}, {ProductID: pants.ID, Qty: 1}}, nil).Once()
+		var locked []int64
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, pants.ID).Return(pants, nil).Run(func(args mock.Arguments) {
+			locked = append(locked, args.Get(1).(int64))
+		}).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Run(func(args mock.Arguments) {
+			locked = append(locked, args.Get(1).(int64))
+		}).Once()
+		mockTaxUcase.On("Apply", mock.Anything, req.ShippingAddress, mock.AnythingOfType("[]domain.OrderItem")).Return(nil).Run(applyTax(1100)).Once()
+		mockShipUcase.On("Price", mock.Anything, mock.AnythingOfType("domain.ShippingAddress"), mock.MatchedBy(func(p domain.Parcel) bool {
+			return p.Value == domain.NewMoney(50000000, "IDR")
+		}), domain.ShippingMethodStandard).Return(domain.ShippingQuote{Method: domain.ShippingMethodStandard, Price: domain.NewMoney(1000000, "IDR")}, nil).Once()
+		mockProductRepo.On("DecrementStock", mock.Anything, pants.ID, 1).Return(nil).Once()
+		mockProductRepo.On("DecrementStock", mock.Anything, shirt.ID, 2).Return(nil).Once()
+		mockOrderRepo.On("Store", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.UserID == userCart.UserID && o.PayMethod == "transfer" &&
+				o.TaxPrice == domain.NewMoney(5500000, "IDR") && o.ShippingPrice == domain.NewMoney(1000000, "IDR") &&
+				o.TotalPrice == domain.NewMoney(56500000, "IDR")
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Order).ID = 8
+		}).Once()
+		mockOrderItemRepo.On("Store", mock.Anything, mock.MatchedBy(func(i *domain.OrderItem) bool {
+			return i.OrderID == 8 && i.ProductID == shirt.ID && i.Qty == 2 && i.Price == shirt.Price && i.TaxAmount == domain.NewMoney(3300000, "IDR")
+		})).Return(nil).Once()
+		mockOrderItemRepo.On("Store", mock.Anything, mock.MatchedBy(func(i *domain.OrderItem) bool {
+			return i.OrderID == 8 && i.ProductID == pants.ID && i.Qty == 1
+		})).Return(nil).Once()
+		mockAddressRepo.On("Store", mock.Anything, mock.MatchedBy(func(a *domain.ShippingAddress) bool {
+			return a.OrderID == 8 && a.City == "Bandung" && a.ShippingMethod == domain.ShippingMethodStandard &&
+				a.ShippingPrice == domain.NewMoney(1000000, "IDR")
+		})).Return(nil).Once()
+		mockCartRepo.On("ClearItems", mock.Anything, userCart.ID).Return(nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		res, err := u.Checkout(context.TODO(), userCart.UserID, req)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), res.ID)
+		assert.Len(t, res.Items, 2)
+		assert.NotNil(t, res.ShippingAddress)
+		assert.Equal(t, []int64{pants.ID, shirt.ID}, locked)
+		mockTransactor.AssertExpectations(t)
+		mockCartRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+		mockOrderItemRepo.AssertExpectations(t)
+		mockAddressRepo.AssertExpectations(t)
+		mockShipUcase.AssertExpectations(t)
+	})
+
+	t.Run("locks-exchange-rate", func(t *testing.T) {
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 1}}, nil).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockTaxUcase.On("Apply", mock.Anything, req.ShippingAddress, mock.AnythingOfType("[]domain.OrderItem")).Return(nil).Run(applyTax(0)).Once()
+		mockShipUcase.On("Price", mock.Anything, mock.AnythingOfType("domain.ShippingAddress"), mock.AnythingOfType("domain.Parcel"), domain.ShippingMethodStandard).Return(domain.ShippingQuote{Price: domain.NewMoney(0, "IDR")}, nil).Once()
+		mockRateUcase.On("Rate", mock.Anything, domain.Currency("IDR"), domain.Currency("USD")).Return(domain.Rate(6154), nil).Once()
+		mockProductRepo.On("DecrementStock", mock.Anything, shirt.ID, 1).Return(nil).Once()
+		mockOrderRepo.On("Store", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.TotalPrice == shirt.Price && o.DisplayCurrency == "USD" && o.ExchangeRate == 6154
+		})).Return(nil).Once()
+		mockOrderItemRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.OrderItem")).Return(nil).Once()
+		mockAddressRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.ShippingAddress")).Return(nil).Once()
+		mockCartRepo.On("ClearItems", mock.Anything, userCart.ID).Return(nil).Once()
+
+		display := req
+		display.DisplayCurrency = "USD"
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		res, err := u.Checkout(context.TODO(), userCart.UserID, display)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(923, "USD"), *res.DisplayTotalPrice)
+		mockRateUcase.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("no-exchange-rate", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 1}}, nil).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockTaxUcase.On("Apply", mock.Anything, req.ShippingAddress, mock.AnythingOfType("[]domain.OrderItem")).Return(nil).Run(applyTax(0)).Once()
+		mockShipUcase.On("Price", mock.Anything, mock.AnythingOfType("domain.ShippingAddress"), mock.AnythingOfType("domain.Parcel"), domain.ShippingMethodStandard).Return(domain.ShippingQuote{Price: domain.NewMoney(0, "IDR")}, nil).Once()
+		mockRateUcase.On("Rate", mock.Anything, domain.Currency("IDR"), domain.Currency("EUR")).Return(domain.Rate(0), domain.ErrNoExchangeRate).Once()
+
+		display := req
+		display.DisplayCurrency = "EUR"
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.Checkout(context.TODO(), userCart.UserID, display)
+		assert.Equal(t, domain.ErrNoExchangeRate, err)
+		mockProductRepo.AssertNotCalled(t, "DecrementStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("shipping-unavailable", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 1}}, nil).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockTaxUcase.On("Apply", mock.Anything, mock.Anything, mock.AnythingOfType("[]domain.OrderItem")).Return(nil).Run(applyTax(0)).Once()
+		mockShipUcase.On("Price", mock.Anything, mock.AnythingOfType("domain.ShippingAddress"), mock.AnythingOfType("domain.Parcel"), domain.ShippingMethodExpress).Return(domain.ShippingQuote{}, domain.ErrShippingUnavailable).Once()
+
+		express := req
+		express.ShippingAddress.ShippingMethod = domain.ShippingMethodExpress
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.Checkout(context.TODO(), userCart.UserID, express)
+		assert.Equal(t, domain.ErrShippingUnavailable, err)
+		mockProductRepo.AssertNotCalled(t, "DecrementStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("insufficient-stock", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, Qty: 2}, {ProductID: pants.ID, Qty: 3}, {ProductID: 9, Qty: 1}}, nil).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, pants.ID).Return(pants, nil).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(9)).Return(domain.Product{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.Checkout(context.TODO(), userCart.UserID, req)
+		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+		var stockErr *domain.StockError
+		if assert.ErrorAs(t, err, &stockErr) {
+			assert.Equal(t, []domain.StockShortage{
+				{ProductID: pants.ID, Name: pants.Name, Requested: 3, Available: 1},
+				{ProductID: 9, Requested: 1, Available: 0},
+			}, stockErr.Shortages)
+		}
+		mockProductRepo.AssertNotCalled(t, "DecrementStock", mock.Anything, mock.Anything, mock.Anything)
+		mockTransactor.AssertExpectations(t)
+		mockCartRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("variant-line", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockVariantRepo := new(mocks.VariantRepository)
+		shirtXL := domain.Variant{ID: 7, ProductID: shirt.ID, SKU: "SHIRT-XL", Price: domain.NewMoney(17500000, "IDR"), CountInStock: 3,
+			Options: []domain.OptionValue{{ID: 5, Value: "XL"}}}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, VariantID: shirtXL.ID, Qty: 2}}, nil).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockVariantRepo.On("GetByIDForUpdate", mock.Anything, shirtXL.ID).Return(shirtXL, nil).Once()
+		mockTaxUcase.On("Apply", mock.Anything, req.ShippingAddress, mock.AnythingOfType("[]domain.OrderItem")).Return(nil).Run(applyTax(0)).Once()
+		// the parcel is worth what the variant is charged, not the base price
+		mockShipUcase.On("Price", mock.Anything, mock.AnythingOfType("domain.ShippingAddress"), mock.MatchedBy(func(p domain.Parcel) bool {
+			return p.Value == domain.NewMoney(35000000, "IDR")
+		}), domain.ShippingMethodStandard).Return(domain.ShippingQuote{Price: domain.NewMoney(0, "IDR")}, nil).Once()
+		mockVariantRepo.On("DecrementStock", mock.Anything, shirtXL.ID, 2).Return(nil).Once()
+		mockProductRepo.On("DecrementStock", mock.Anything, shirt.ID, 2).Return(nil).Once()
+		mockOrderRepo.On("Store", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.TotalPrice == domain.NewMoney(35000000, "IDR")
+		})).Return(nil).Once()
+		mockOrderItemRepo.On("Store", mock.Anything, mock.MatchedBy(func(i *domain.OrderItem) bool {
+			return i.VariantID == shirtXL.ID && i.SKU == "SHIRT-XL" && i.Name == "Shirt (XL)" && i.Price == shirtXL.Price && i.Image == shirt.Image
+		})).Return(nil).Once()
+		mockAddressRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.ShippingAddress")).Return(nil).Once()
+		mockCartRepo.On("ClearItems", mock.Anything, userCart.ID).Return(nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.Checkout(context.TODO(), userCart.UserID, req)
+		assert.NoError(t, err)
+		mockProductRepo.AssertExpectations(t)
+		mockVariantRepo.AssertExpectations(t)
+		mockOrderItemRepo.AssertExpectations(t)
+		mockShipUcase.AssertExpectations(t)
+	})
+
+	t.Run("variant-out-of-stock", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockVariantRepo := new(mocks.VariantRepository)
+		shirtXL := domain.Variant{ID: 7, ProductID: shirt.ID, SKU: "SHIRT-XL", Price: shirt.Price, CountInStock: 1,
+			Options: []domain.OptionValue{{ID: 5, Value: "XL"}}}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{{ProductID: shirt.ID, VariantID: shirtXL.ID, Qty: 2}}, nil).Once()
+		// the product has stock left in other sizes
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockVariantRepo.On("GetByIDForUpdate", mock.Anything, shirtXL.ID).Return(shirtXL, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.Checkout(context.TODO(), userCart.UserID, req)
+		var stockErr *domain.StockError
+		if assert.ErrorAs(t, err, &stockErr) {
+			assert.Equal(t, []domain.StockShortage{
+				{ProductID: shirt.ID, VariantID: shirtXL.ID, Name: "Shirt (XL)", Requested: 2, Available: 1},
+			}, stockErr.Shortages)
+		}
+		mockVariantRepo.AssertNotCalled(t, "DecrementStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty-cart", func(t *testing.T) {
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCartRepo.On("GetByUserID", mock.Anything, userCart.UserID).Return(userCart, nil).Once()
+		mockCartRepo.On("FetchItems", mock.Anything, userCart.ID).Return([]domain.CartItem{}, nil).Once()
+
+		u := ucase.NewCartUsecase(mockCartRepo, mockProductRepo, mockVariantRepo, mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockRateUcase, mockTaxUcase, mockShipUcase, mockTransactor, time.Second*2)
+		_, err := u.Checkout(context.TODO(), userCart.UserID, req)
+		assert.Equal(t, domain.ErrEmptyCart, err)
+		mockCartRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+type CategoryHandler struct {
+	CUsecase domain.CategoryUsecase
+}
+
+func NewCategoryHandler(e *echo.Echo, cucase domain.CategoryUsecase, mw *middleware.GoMiddleware) {
+	handler := &CategoryHandler{
+		CUsecase: cucase,
+	}
+	e.GET("/categories", handler.Tree)
+	e.GET("/categories/:id", handler.Get)
+	e.POST("/categories", handler.Store, mw.Auth, mw.RequirePermission(domain.PermissionCategoryWrite))
+	e.PUT("/categories/:id", handler.Update, mw.Auth, mw.RequirePermission(domain.PermissionCategoryWrite))
+	e.POST("/categories/:id/move", handler.Move, mw.Auth, mw.RequirePermission(domain.PermissionCategoryWrite))
+	e.DELETE("/categories/:id", handler.Delete, mw.Auth, mw.RequirePermission(domain.PermissionCategoryWrite))
+}
+
+// Tree will list the root categories with their subcategories nested
+func (h *CategoryHandler) Tree(c echo.Context) error {
+	ctx := c.Request().Context()
+	tree, err := h.CUsecase.Tree(ctx)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, tree)
+}
+
+// Get will get the category with its subcategories by given id or slug
+func (h *CategoryHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	var category domain.Category
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err == nil {
+		category, err = h.CUsecase.GetByID(ctx, id)
+	} else {
+		category, err = h.CUsecase.GetBySlug(ctx, c.Param("id"))
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, category)
+}
+
+// Store will create a category by given request body
+func (h *CategoryHandler) Store(c echo.Context) (err error) {
+	var req createCategoryRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	category := req.toCategory()
+	ctx := c.Request().Context()
+	err = h.CUsecase.Store(ctx, &category)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusCreated, category)
+}
+
+// Update will rename or reorder the category by given param, Move changes its parent
+func (h *CategoryHandler) Update(c echo.Context) (err error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	var req categoryRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	category := req.toCategory()
+	category.ID = id
+	ctx := c.Request().Context()
+	err = h.CUsecase.Update(ctx, &category)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, category)
+}
+
+// Move will place the category and its subcategories under another parent
+func (h *CategoryHandler) Move(c echo.Context) (err error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	var req moveRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	category, err := h.CUsecase.Move(ctx, id, req.ParentID, req.Position)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, category)
+}
+
+// Delete will delete a category without subcategories nor products by given param
+func (h *CategoryHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	ctx := c.Request().Context()
+	err = h.CUsecase.Delete(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict, domain.ErrCategoryInUse:
+		return http.StatusConflict
+	case domain.ErrBadParamInput, domain.ErrCategoryCycle, domain.ErrUnknownCategory:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
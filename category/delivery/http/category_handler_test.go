@@ -0,0 +1,163 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	categoryHttp "github.com/alfathaulia/ca_ecommerce_api/category/delivery/http"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	t.Run("by-slug", func(t *testing.T) {
+		mockUcase := new(mocks.CategoryUsecase)
+		mockUcase.On("GetBySlug", mock.Anything, "running-shoes").Return(domain.Category{ID: 4, Name: "Running shoes", Slug: "running-shoes"}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/categories/running-shoes", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("categories/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("running-shoes")
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUcase,
+		}
+
+		err = handler.Get(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"slug":"running-shoes"`)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("by-id-not-found", func(t *testing.T) {
+		mockUcase := new(mocks.CategoryUsecase)
+		mockUcase.On("GetByID", mock.Anything, int64(9)).Return(domain.Category{}, domain.ErrNotFound).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/categories/9", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("categories/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("9")
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUcase,
+		}
+
+		err = handler.Get(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.CategoryUsecase)
+		mockUcase.On("Store", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool {
+			return c.Name == "Shoes" && c.ParentID != nil && *c.ParentID == 1
+		})).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/categories", strings.NewReader(`{"name":"Shoes","parent_id":1}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUcase,
+		}
+
+		err = handler.Store(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("missing-name", func(t *testing.T) {
+		mockUcase := new(mocks.CategoryUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/categories", strings.NewReader(`{"slug":"shoes"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUcase,
+		}
+
+		err = handler.Store(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMove(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		mockUcase := new(mocks.CategoryUsecase)
+		mockUcase.On("Move", mock.Anything, int64(1), mock.MatchedBy(func(parentID *int64) bool {
+			return parentID != nil && *parentID == 4
+		}), 0).Return(domain.Category{}, domain.ErrCategoryCycle).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/categories/1/move", strings.NewReader(`{"parent_id":4}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("categories/:id/move")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUcase,
+		}
+
+		err = handler.Move(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("in-use", func(t *testing.T) {
+		mockUcase := new(mocks.CategoryUsecase)
+		mockUcase.On("Delete", mock.Anything, int64(2)).Return(domain.ErrCategoryInUse).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.DELETE, "/categories/2", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("categories/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("2")
+		handler := categoryHttp.CategoryHandler{
+			CUsecase: mockUcase,
+		}
+
+		err = handler.Delete(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+}
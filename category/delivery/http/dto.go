@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var validate = validator.New()
+
+// categoryRequest is the body of update, the slug is made from the name when empty
+type categoryRequest struct {
+	Name     string `json:"name" validate:"required,max=128"`
+	Slug     string `json:"slug" validate:"max=128"`
+	Position int    `json:"position" validate:"gte=0"`
+}
+
+func (r categoryRequest) toCategory() domain.Category {
+	return domain.Category{
+		Name:     r.Name,
+		Slug:     r.Slug,
+		Position: r.Position,
+	}
+}
+
+// createCategoryRequest is the body of create, a category without parent is a root
+type createCategoryRequest struct {
+	categoryRequest
+	ParentID *int64 `json:"parent_id" validate:"omitempty,gt=0"`
+}
+
+func (r createCategoryRequest) toCategory() domain.Category {
+	res := r.categoryRequest.toCategory()
+	res.ParentID = r.ParentID
+	return res
+}
+
+// moveRequest places a category under another one, or at the root when
+// parent_id is null
+type moveRequest struct {
+	ParentID *int64 `json:"parent_id" validate:"omitempty,gt=0"`
+	Position int    `json:"position" validate:"gte=0"`
+}
@@ -0,0 +1,221 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+const selectCategory = `SELECT id, parent_id, name, slug, position, path, updated_at, created_at FROM category`
+
+type mysqlCategoryRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlCategoryRepo will create an object that represent the domain.CategoryRepository interface
+func NewMysqlCategoryRepo(DB *sql.DB) domain.CategoryRepository {
+	return &mysqlCategoryRepo{DB: DB}
+}
+
+func (m *mysqlCategoryRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Category, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Category, 0)
+	for rows.Next() {
+		t := domain.Category{}
+		var parentID sql.NullInt64
+		err = rows.Scan(
+			&t.ID,
+			&parentID,
+			&t.Name,
+			&t.Slug,
+			&t.Position,
+			&t.Path,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if parentID.Valid {
+			t.ParentID = &parentID.Int64
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlCategoryRepo) Fetch(ctx context.Context) ([]domain.Category, error) {
+	return m.fetch(ctx, selectCategory+` ORDER BY position, name, id`)
+}
+
+func (m *mysqlCategoryRepo) FetchSubtree(ctx context.Context, root domain.Category) ([]domain.Category, error) {
+	return m.fetch(ctx, selectCategory+` WHERE path LIKE ? ORDER BY position, name, id`, root.Path+"%")
+}
+
+func (m *mysqlCategoryRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Category, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlCategoryRepo) GetByID(ctx context.Context, id int64) (domain.Category, error) {
+	return m.getOne(ctx, selectCategory+` WHERE id = ?`, id)
+}
+
+// GetByIDForUpdate is GetByID holding a write lock on the row, it only makes
+// sense inside a transaction where the lock lasts until commit or rollback
+func (m *mysqlCategoryRepo) GetByIDForUpdate(ctx context.Context, id int64) (domain.Category, error) {
+	return m.getOne(ctx, selectCategory+` WHERE id = ? FOR UPDATE`, id)
+}
+
+func (m *mysqlCategoryRepo) GetBySlug(ctx context.Context, slug string) (domain.Category, error) {
+	return m.getOne(ctx, selectCategory+` WHERE slug = ?`, slug)
+}
+
+// Store needs the id of the new row for its path, so it only makes sense
+// inside a transaction
+func (m *mysqlCategoryRepo) Store(ctx context.Context, c *domain.Category) (err error) {
+	query := `INSERT  category SET parent_id=? , name=? , slug=? , position=? , path=? , updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, c.ParentID, c.Name, c.Slug, c.Position, c.Path, c.UpdatedAt, c.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	path := c.ChildPath(lastID)
+
+	query = `UPDATE  category SET path=? WHERE id=?`
+	stmt, err = transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	if _, err = stmt.ExecContext(ctx, path, lastID); err != nil {
+		return
+	}
+	c.ID = lastID
+	c.Path = path
+	return
+}
+
+func (m *mysqlCategoryRepo) Update(ctx context.Context, c *domain.Category) (err error) {
+	query := `UPDATE  category SET name=? , slug=? , position=? , updated_at=? WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, c.Name, c.Slug, c.Position, c.UpdatedAt, c.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
+
+// Move rewrites the subtree in two statements, it only makes sense inside a
+// transaction
+func (m *mysqlCategoryRepo) Move(ctx context.Context, c *domain.Category, oldPath string) (err error) {
+	query := `UPDATE  category SET parent_id=? , position=? , updated_at=? WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, c.ParentID, c.Position, c.UpdatedAt, c.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+
+	// the category itself and every descendant swap the old prefix for the new one
+	query = `UPDATE  category SET path = CONCAT(?, SUBSTRING(path, ?)) WHERE path LIKE ?`
+	stmt, err = transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, c.Path, len(oldPath)+1, oldPath+"%")
+	return
+}
+
+func (m *mysqlCategoryRepo) InUse(ctx context.Context, id int64) (inUse bool, err error) {
+	query := `SELECT EXISTS(SELECT 1 FROM category WHERE parent_id = ?) OR EXISTS(SELECT 1 FROM product WHERE category_id = ?)`
+
+	err = transaction.Conn(ctx, m.DB).QueryRowContext(ctx, query, id, id).Scan(&inUse)
+	if err != nil {
+		logrus.Error(err)
+		return false, err
+	}
+	return
+}
+
+func (m *mysqlCategoryRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM category WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", rowsAfected)
+		return
+	}
+	return
+}
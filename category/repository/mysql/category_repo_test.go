@@ -0,0 +1,156 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	categoryMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/category/repository/mysql"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+var parentID = int64(1)
+
+var category = &domain.Category{
+	ID:        2,
+	ParentID:  &parentID,
+	Name:      "Shoes",
+	Slug:      "shoes",
+	Position:  1,
+	Path:      "/1/2/",
+	UpdatedAt: now,
+	CreatedAt: now,
+}
+
+var categoryColumns = []string{"id", "parent_id", "name", "slug", "position", "path", "updated_at", "created_at"}
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func TestFetchSubtree(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows(categoryColumns).
+		AddRow(category.ID, category.ParentID, category.Name, category.Slug, category.Position, category.Path, now, now).
+		AddRow(4, 2, "Running", "running", 0, "/1/2/4/", now, now)
+
+	query := `SELECT id, parent_id, name, slug, position, path, updated_at, created_at FROM category WHERE path LIKE \? ORDER BY position, name, id`
+	mock.ExpectQuery(query).WithArgs("/1/2/%").WillReturnRows(rows)
+
+	r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+	list, err := r.FetchSubtree(context.TODO(), *category)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, *category, list[0])
+	assert.Equal(t, int64(2), *list[1].ParentID)
+}
+
+func TestGetBySlug(t *testing.T) {
+	query := `SELECT id, parent_id, name, slug, position, path, updated_at, created_at FROM category WHERE slug = \?`
+
+	t.Run("root", func(t *testing.T) {
+		db, mock := NewMock()
+		rows := sqlmock.NewRows(categoryColumns).AddRow(1, nil, "Clothing", "clothing", 0, "/1/", now, now)
+		mock.ExpectQuery(query).WithArgs("clothing").WillReturnRows(rows)
+
+		r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+		res, err := r.GetBySlug(context.TODO(), "clothing")
+		assert.NoError(t, err)
+		assert.Nil(t, res.ParentID)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		db, mock := NewMock()
+		mock.ExpectQuery(query).WithArgs("nothing").WillReturnRows(sqlmock.NewRows(categoryColumns))
+
+		r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+		_, err := r.GetBySlug(context.TODO(), "nothing")
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+}
+
+func TestGetByIDForUpdate(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows(categoryColumns).
+		AddRow(category.ID, category.ParentID, category.Name, category.Slug, category.Position, category.Path, now, now)
+
+	query := `SELECT id, parent_id, name, slug, position, path, updated_at, created_at FROM category WHERE id = \? FOR UPDATE`
+	mock.ExpectQuery(query).WithArgs(category.ID).WillReturnRows(rows)
+
+	r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+	res, err := r.GetByIDForUpdate(context.TODO(), category.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, *category, res)
+}
+
+func TestStore(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  category SET parent_id=\\? , name=\\? , slug=\\? , position=\\? , path=\\? , updated_at=\\? , created_at=\\?"
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(category.ParentID, category.Name, category.Slug, category.Position, "/1/", category.UpdatedAt, category.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectPrepare("UPDATE  category SET path=\\? WHERE id=\\?").ExpectExec().
+		WithArgs("/1/7/", int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+	tmp := *category
+	tmp.ID = 0
+	tmp.Path = "/1/"
+	err := r.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), tmp.ID)
+	assert.Equal(t, "/1/7/", tmp.Path)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMove(t *testing.T) {
+	db, mock := NewMock()
+	moved := *category
+	moved.ParentID = nil
+	moved.Path = "/2/"
+
+	mock.ExpectPrepare("UPDATE  category SET parent_id=\\? , position=\\? , updated_at=\\? WHERE id=\\?").ExpectExec().
+		WithArgs(nil, moved.Position, moved.UpdatedAt, moved.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(`UPDATE  category SET path = CONCAT\(\?, SUBSTRING\(path, \?\)\) WHERE path LIKE \?`).ExpectExec().
+		WithArgs("/2/", 6, "/1/2/%").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+	err := r.Move(context.TODO(), &moved, "/1/2/")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInUse(t *testing.T) {
+	db, mock := NewMock()
+	query := `SELECT EXISTS\(SELECT 1 FROM category WHERE parent_id = \?\) OR EXISTS\(SELECT 1 FROM product WHERE category_id = \?\)`
+	mock.ExpectQuery(query).WithArgs(category.ID, category.ID).WillReturnRows(sqlmock.NewRows([]string{"in_use"}).AddRow(true))
+
+	r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+	inUse, err := r.InUse(context.TODO(), category.ID)
+	assert.NoError(t, err)
+	assert.True(t, inUse)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock := NewMock()
+	mock.ExpectPrepare("DELETE FROM category WHERE id = \\?").ExpectExec().WithArgs(category.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := categoryMysqlRepo.NewMysqlCategoryRepo(db)
+	err := r.Delete(context.TODO(), category.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type categoryUsecase struct {
+	categoryRepo   domain.CategoryRepository
+	transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewCategoryUsecase will create an object that represent the domain.CategoryUsecase interface
+func NewCategoryUsecase(c domain.CategoryRepository, tx domain.Transactor, timeout time.Duration) domain.CategoryUsecase {
+	return &categoryUsecase{
+		categoryRepo:   c,
+		transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (m *categoryUsecase) Tree(ctx context.Context) ([]domain.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	list, err := m.categoryRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	children := childrenOf(list)
+	res := make([]domain.Category, 0, len(children[0]))
+	for _, c := range children[0] {
+		res = append(res, nest(c, children))
+	}
+	return res, nil
+}
+
+func (m *categoryUsecase) GetByID(ctx context.Context, id int64) (domain.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	c, err := m.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return m.subtree(ctx, c)
+}
+
+func (m *categoryUsecase) GetBySlug(ctx context.Context, slug string) (domain.Category, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	c, err := m.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return m.subtree(ctx, c)
+}
+
+// subtree returns root with its descendants nested as Children
+func (m *categoryUsecase) subtree(ctx context.Context, root domain.Category) (domain.Category, error) {
+	list, err := m.categoryRepo.FetchSubtree(ctx, root)
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return nest(root, childrenOf(list)), nil
+}
+
+// childrenOf groups categories by the id of their parent, the roots under 0
+func childrenOf(list []domain.Category) map[int64][]domain.Category {
+	res := map[int64][]domain.Category{}
+	for _, c := range list {
+		var parent int64
+		if c.ParentID != nil {
+			parent = *c.ParentID
+		}
+		res[parent] = append(res[parent], c)
+	}
+	return res
+}
+
+func nest(c domain.Category, children map[int64][]domain.Category) domain.Category {
+	for _, child := range children[c.ID] {
+		c.Children = append(c.Children, nest(child, children))
+	}
+	return c
+}
+
+// checkSlug makes the slug of c from its name when empty and refuses a slug
+// that is not in its canonical form or is taken by another category
+func (m *categoryUsecase) checkSlug(ctx context.Context, c *domain.Category) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return domain.ErrBadParamInput
+	}
+	if c.Slug == "" {
+		c.Slug = slugify(c.Name)
+	}
+	if c.Slug == "" || c.Slug != slugify(c.Slug) {
+		return domain.ErrBadParamInput
+	}
+	existing, err := m.categoryRepo.GetBySlug(ctx, c.Slug)
+	if err == domain.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != c.ID {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+// parentOf returns the category id is placed under, ErrUnknownCategory is
+// returned when it does not exist
+// lock reads the categories with a write lock, always in id order so that
+// concurrent moves can not deadlock
+func (m *categoryUsecase) lock(ctx context.Context, ids []int64) (map[int64]domain.Category, error) {
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	res := make(map[int64]domain.Category, len(sorted))
+	for _, id := range sorted {
+		if _, ok := res[id]; ok {
+			continue
+		}
+		c, err := m.categoryRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		res[id] = c
+	}
+	return res, nil
+}
+
+func (m *categoryUsecase) parentOf(ctx context.Context, id int64) (domain.Category, error) {
+	parent, err := m.categoryRepo.GetByID(ctx, id)
+	if err == domain.ErrNotFound {
+		return domain.Category{}, domain.ErrUnknownCategory
+	}
+	return parent, err
+}
+
+func (m *categoryUsecase) Store(ctx context.Context, c *domain.Category) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := m.checkSlug(ctx, c); err != nil {
+			return err
+		}
+		c.Path = "/"
+		if c.ParentID != nil {
+			parent, err := m.parentOf(ctx, *c.ParentID)
+			if err != nil {
+				return err
+			}
+			c.Path = parent.Path
+		}
+		c.CreatedAt = time.Now()
+		c.UpdatedAt = c.CreatedAt
+		return m.categoryRepo.Store(ctx, c)
+	})
+}
+
+func (m *categoryUsecase) Update(ctx context.Context, c *domain.Category) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	existed, err := m.categoryRepo.GetByID(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if err = m.checkSlug(ctx, c); err != nil {
+		return err
+	}
+	c.ParentID = existed.ParentID
+	c.Path = existed.Path
+	c.CreatedAt = existed.CreatedAt
+	c.UpdatedAt = time.Now()
+	return m.categoryRepo.Update(ctx, c)
+}
+
+func (m *categoryUsecase) Move(ctx context.Context, id int64, parentID *int64, position int) (res domain.Category, err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	err = m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// the moved category and the path of the new parent are locked, so
+		// that two moves that would make a cycle together run one after the
+		// other and the second one sees the first
+		ids := []int64{id}
+		var parent domain.Category
+		if parentID != nil {
+			p, err := m.parentOf(ctx, *parentID)
+			if err != nil {
+				return err
+			}
+			parent = p
+			ids = append(ids, parent.PathIDs()...)
+		}
+		locked, err := m.lock(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		c := locked[id]
+		path := (domain.Category{Path: "/"}).ChildPath(id)
+		if parentID != nil {
+			if locked[*parentID].Path != parent.Path {
+				// the parent was moved meanwhile
+				return domain.ErrConflict
+			}
+			parent = locked[*parentID]
+			if c.Contains(parent) {
+				return domain.ErrCategoryCycle
+			}
+			path = parent.ChildPath(id)
+		}
+		oldPath := c.Path
+		c.ParentID = parentID
+		c.Position = position
+		c.Path = path
+		c.UpdatedAt = time.Now()
+		if err = m.categoryRepo.Move(ctx, &c, oldPath); err != nil {
+			return err
+		}
+		res = c
+		return nil
+	})
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return
+}
+
+func (m *categoryUsecase) Delete(ctx context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	_, err = m.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	inUse, err := m.categoryRepo.InUse(ctx, id)
+	if err != nil {
+		return
+	}
+	if inUse {
+		return domain.ErrCategoryInUse
+	}
+	return m.categoryRepo.Delete(ctx, id)
+}
@@ -0,0 +1,235 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ucase "github.com/alfathaulia/ca_ecommerce_api/category/usecase"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// runInTransaction makes the Transactor mock call the unit of work directly
+func runInTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func id(v int64) *int64 {
+	return &v
+}
+
+// catalog is clothing > shoes > running, and books
+var catalog = []domain.Category{
+	{ID: 1, Name: "Clothing", Slug: "clothing", Path: "/1/"},
+	{ID: 3, Name: "Books", Slug: "books", Position: 1, Path: "/3/"},
+	{ID: 2, ParentID: id(1), Name: "Shoes", Slug: "shoes", Path: "/1/2/"},
+	{ID: 4, ParentID: id(2), Name: "Running", Slug: "running", Path: "/1/2/4/"},
+}
+
+func TestTree(t *testing.T) {
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockCategoryRepo.On("Fetch", mock.Anything).Return(catalog, nil).Once()
+
+	u := ucase.NewCategoryUsecase(mockCategoryRepo, new(mocks.Transactor), time.Second*2)
+	tree, err := u.Tree(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "clothing", tree[0].Slug)
+	assert.Equal(t, "books", tree[1].Slug)
+	assert.Empty(t, tree[1].Children)
+	require.Len(t, tree[0].Children, 1)
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, "running", tree[0].Children[0].Children[0].Slug)
+}
+
+func TestGetBySlug(t *testing.T) {
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	mockCategoryRepo.On("GetBySlug", mock.Anything, "shoes").Return(catalog[2], nil).Once()
+	mockCategoryRepo.On("FetchSubtree", mock.Anything, catalog[2]).Return(catalog[2:], nil).Once()
+
+	u := ucase.NewCategoryUsecase(mockCategoryRepo, new(mocks.Transactor), time.Second*2)
+	res, err := u.GetBySlug(context.TODO(), "shoes")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.ID)
+	require.Len(t, res.Children, 1)
+	assert.Equal(t, int64(4), res.Children[0].ID)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	t.Run("slug-from-name", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetBySlug", mock.Anything, "men-s-shoes").Return(domain.Category{}, domain.ErrNotFound).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(1)).Return(catalog[0], nil).Once()
+		mockCategoryRepo.On("Store", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool {
+			return c.Path == "/1/" && *c.ParentID == 1
+		})).Return(nil).Once()
+
+		c := domain.Category{ParentID: id(1), Name: " Men's Shoes "}
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		err := u.Store(context.TODO(), &c)
+		require.NoError(t, err)
+		assert.Equal(t, "Men's Shoes", c.Name)
+		assert.Equal(t, "men-s-shoes", c.Slug)
+		assert.False(t, c.CreatedAt.IsZero())
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("slug-taken", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetBySlug", mock.Anything, "shoes").Return(catalog[2], nil).Once()
+
+		c := domain.Category{Name: "shoes"}
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		err := u.Store(context.TODO(), &c)
+		assert.Equal(t, domain.ErrConflict, err)
+		mockCategoryRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("bad-slug", func(t *testing.T) {
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+
+		c := domain.Category{Name: "Shoes", Slug: "Shoes!"}
+		u := ucase.NewCategoryUsecase(new(mocks.CategoryRepository), mockTransactor, time.Second*2)
+		err := u.Store(context.TODO(), &c)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+
+	t.Run("unknown-parent", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetBySlug", mock.Anything, "shoes").Return(domain.Category{}, domain.ErrNotFound).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Category{}, domain.ErrNotFound).Once()
+
+		c := domain.Category{ParentID: id(9), Name: "Shoes"}
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		err := u.Store(context.TODO(), &c)
+		assert.Equal(t, domain.ErrUnknownCategory, err)
+	})
+}
+
+func TestMove(t *testing.T) {
+	t.Run("under-another-parent", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(3)).Return(catalog[1], nil).Once()
+		mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, int64(2)).Return(catalog[2], nil).Once()
+		mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, int64(3)).Return(catalog[1], nil).Once()
+		mockCategoryRepo.On("Move", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool {
+			return c.ID == 2 && *c.ParentID == 3 && c.Path == "/3/2/" && c.Position == 5
+		}), "/1/2/").Return(nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		res, err := u.Move(context.TODO(), 2, id(3), 5)
+		require.NoError(t, err)
+		assert.Equal(t, "/3/2/", res.Path)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("to-the-root", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, int64(4)).Return(catalog[3], nil).Once()
+		mockCategoryRepo.On("Move", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool {
+			return c.ParentID == nil && c.Path == "/4/"
+		}), "/1/2/4/").Return(nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		_, err := u.Move(context.TODO(), 4, nil, 0)
+		require.NoError(t, err)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("under-own-descendant", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(catalog[3], nil).Once()
+		for _, c := range []domain.Category{catalog[0], catalog[2], catalog[3]} {
+			mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, c.ID).Return(c, nil).Once()
+		}
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		_, err := u.Move(context.TODO(), 1, id(4), 0)
+		assert.Equal(t, domain.ErrCategoryCycle, err)
+		mockCategoryRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("locks-in-id-order", func(t *testing.T) {
+		// moving Books under Running locks Books and the path of Running
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(catalog[3], nil).Once()
+		locked := make([]int64, 0)
+		for _, c := range catalog {
+			c := c
+			mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, c.ID).
+				Run(func(mock.Arguments) { locked = append(locked, c.ID) }).Return(c, nil).Once()
+		}
+		mockCategoryRepo.On("Move", mock.Anything, mock.AnythingOfType("*domain.Category"), "/3/").Return(nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		res, err := u.Move(context.TODO(), 3, id(4), 0)
+		require.NoError(t, err)
+		assert.Equal(t, "/1/2/4/3/", res.Path)
+		assert.Equal(t, []int64{1, 2, 3, 4}, locked)
+	})
+
+	t.Run("parent-moved-meanwhile", func(t *testing.T) {
+		// the other move took Running under Books before the locks were held
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(catalog[3], nil).Once()
+		moved := catalog[3]
+		moved.ParentID = id(3)
+		moved.Path = "/3/4/"
+		mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, int64(1)).Return(catalog[0], nil).Once()
+		mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, int64(2)).Return(catalog[2], nil).Once()
+		mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, int64(3)).Return(catalog[1], nil).Once()
+		mockCategoryRepo.On("GetByIDForUpdate", mock.Anything, int64(4)).Return(moved, nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, mockTransactor, time.Second*2)
+		_, err := u.Move(context.TODO(), 3, id(4), 0)
+		assert.Equal(t, domain.ErrConflict, err)
+		mockCategoryRepo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(catalog[3], nil).Once()
+		mockCategoryRepo.On("InUse", mock.Anything, int64(4)).Return(false, nil).Once()
+		mockCategoryRepo.On("Delete", mock.Anything, int64(4)).Return(nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, new(mocks.Transactor), time.Second*2)
+		err := u.Delete(context.TODO(), 4)
+		assert.NoError(t, err)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("in-use", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(2)).Return(catalog[2], nil).Once()
+		mockCategoryRepo.On("InUse", mock.Anything, int64(2)).Return(true, nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, new(mocks.Transactor), time.Second*2)
+		err := u.Delete(context.TODO(), 2)
+		assert.Equal(t, domain.ErrCategoryInUse, err)
+		mockCategoryRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import "strings"
+
+// slugify lower cases s and joins its runs of ASCII letters and digits with
+// dashes, "Men's Shoes" becomes "men-s-shoes". Migration 020 makes the slugs
+// of the existing categories the same way.
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
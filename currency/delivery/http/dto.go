@@ -0,0 +1,20 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var validate = validator.New()
+
+// exchangeRateRequest is the body of saving the rate of the pair in the path
+type exchangeRateRequest struct {
+	Rate domain.Rate `json:"rate" validate:"required"`
+}
+
+// currencyParam reads a currency code of the path, case insensitive
+func currencyParam(s string) domain.Currency {
+	return domain.Currency(strings.ToUpper(s))
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+type ExchangeRateHandler struct {
+	RUsecase domain.ExchangeRateUsecase
+}
+
+func NewExchangeRateHandler(e *echo.Echo, rucase domain.ExchangeRateUsecase, mw *middleware.GoMiddleware) {
+	handler := &ExchangeRateHandler{
+		RUsecase: rucase,
+	}
+	e.GET("/exchange-rates", handler.Fetch)
+	e.PUT("/exchange-rates/:base/:quote", handler.Save, mw.Auth, mw.RequirePermission(domain.PermissionExchangeRateWrite))
+	e.DELETE("/exchange-rates/:base/:quote", handler.Delete, mw.Auth, mw.RequirePermission(domain.PermissionExchangeRateWrite))
+}
+
+// Fetch will list all exchange rates
+func (h *ExchangeRateHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+	rates, err := h.RUsecase.Fetch(ctx)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rates)
+}
+
+// Save will create or replace the rate of the pair by given param
+func (h *ExchangeRateHandler) Save(c echo.Context) (err error) {
+	var req exchangeRateRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	rate := domain.ExchangeRate{
+		Base:  currencyParam(c.Param("base")),
+		Quote: currencyParam(c.Param("quote")),
+		Rate:  req.Rate,
+	}
+	ctx := c.Request().Context()
+	err = h.RUsecase.Save(ctx, &rate)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rate)
+}
+
+// Delete will remove the rate of the pair by given param
+func (h *ExchangeRateHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	err := h.RUsecase.Delete(ctx, currencyParam(c.Param("base")), currencyParam(c.Param("quote")))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrBadParamInput, domain.ErrInvalidCurrency, domain.ErrInvalidRate:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,74 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	currencyHttp "github.com/alfathaulia/ca_ecommerce_api/currency/delivery/http"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSaveContext(e *echo.Echo, body string, w *httptest.ResponseRecorder) echo.Context {
+	req := httptest.NewRequest(echo.PUT, "/exchange-rates/usd/idr", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req, w)
+	c.SetPath("/exchange-rates/:base/:quote")
+	c.SetParamNames("base", "quote")
+	c.SetParamValues("usd", "idr")
+	return c
+}
+
+func TestSave(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.ExchangeRateUsecase)
+		mockUcase.On("Save", mock.Anything, mock.MatchedBy(func(r *domain.ExchangeRate) bool {
+			return r.Base == "USD" && r.Quote == "IDR" && r.Rate == 1625050000000
+		})).Return(nil).Once()
+
+		e := echo.New()
+		w := httptest.NewRecorder()
+		handler := currencyHttp.ExchangeRateHandler{
+			RUsecase: mockUcase,
+		}
+		err := handler.Save(newSaveContext(e, `{"rate":"16250.5"}`, w))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"rate":"16250.5"`)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("unsupported-currency", func(t *testing.T) {
+		mockUcase := new(mocks.ExchangeRateUsecase)
+		mockUcase.On("Save", mock.Anything, mock.AnythingOfType("*domain.ExchangeRate")).Return(domain.ErrInvalidCurrency).Once()
+
+		e := echo.New()
+		w := httptest.NewRecorder()
+		handler := currencyHttp.ExchangeRateHandler{
+			RUsecase: mockUcase,
+		}
+		err := handler.Save(newSaveContext(e, `{"rate":"16250.5"}`, w))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid-rate", func(t *testing.T) {
+		mockUcase := new(mocks.ExchangeRateUsecase)
+
+		e := echo.New()
+		w := httptest.NewRecorder()
+		handler := currencyHttp.ExchangeRateHandler{
+			RUsecase: mockUcase,
+		}
+		err := handler.Save(newSaveContext(e, `{"rate":"1.123456789"}`, w))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		mockUcase.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,69 @@
+package file
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+// LoadExchangeRates reads the rates of a local file, the format is chosen by
+// the extension:
+//
+//	.json: [{"base":"USD","quote":"IDR","rate":"16250.5"}]
+//	.csv:  a base,quote,rate header followed by one rate per line
+func LoadExchangeRates(path string) ([]domain.ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return readJSON(f)
+	case ".csv":
+		return readCSV(f)
+	default:
+		return nil, fmt.Errorf("exchange rates file must be .json or .csv, got %q", ext)
+	}
+}
+
+func readJSON(r io.Reader) (res []domain.ExchangeRate, err error) {
+	if err = json.NewDecoder(r).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func readCSV(r io.Reader) ([]domain.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || strings.Join(records[0], ",") != "base,quote,rate" {
+		return nil, fmt.Errorf("exchange rates csv must start with a base,quote,rate header")
+	}
+
+	res := make([]domain.ExchangeRate, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		rate, err := domain.ParseRate(rec[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+		res = append(res, domain.ExchangeRate{
+			Base:  domain.Currency(rec[0]),
+			Quote: domain.Currency(rec[1]),
+			Rate:  rate,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/currency/repository/file"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadExchangeRates(t *testing.T) {
+	want := []domain.ExchangeRate{
+		{Base: "USD", Quote: "IDR", Rate: 1625050000000},
+		{Base: "USD", Quote: "JPY", Rate: 15000000000},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		path := writeFile(t, "rates.json", `[{"base":"USD","quote":"IDR","rate":"16250.5"},{"base":"USD","quote":"JPY","rate":150}]`)
+		rates, err := file.LoadExchangeRates(path)
+		require.NoError(t, err)
+		assert.Equal(t, want, rates)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		path := writeFile(t, "rates.csv", "base,quote,rate\nUSD,IDR,16250.5\nUSD,JPY,150\n")
+		rates, err := file.LoadExchangeRates(path)
+		require.NoError(t, err)
+		assert.Equal(t, want, rates)
+	})
+
+	t.Run("csv-invalid-rate", func(t *testing.T) {
+		path := writeFile(t, "rates.csv", "base,quote,rate\nUSD,IDR,-1\n")
+		_, err := file.LoadExchangeRates(path)
+		assert.ErrorIs(t, err, domain.ErrInvalidRate)
+	})
+
+	t.Run("csv-without-header", func(t *testing.T) {
+		path := writeFile(t, "rates.csv", "USD,IDR,16250.5\n")
+		_, err := file.LoadExchangeRates(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown-extension", func(t *testing.T) {
+		path := writeFile(t, "rates.txt", "")
+		_, err := file.LoadExchangeRates(path)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,107 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+const selectExchangeRate = `SELECT base, quote, rate, updated_at FROM exchange_rate`
+
+type mysqlExchangeRateRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlExchangeRateRepo will create an object that represent the domain.ExchangeRateRepository interface
+func NewMysqlExchangeRateRepo(DB *sql.DB) domain.ExchangeRateRepository {
+	return &mysqlExchangeRateRepo{DB: DB}
+}
+
+func (m *mysqlExchangeRateRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.ExchangeRate, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.ExchangeRate, 0)
+	for rows.Next() {
+		t := domain.ExchangeRate{}
+		err = rows.Scan(
+			&t.Base,
+			&t.Quote,
+			&t.Rate,
+			&t.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlExchangeRateRepo) Fetch(ctx context.Context) ([]domain.ExchangeRate, error) {
+	return m.fetch(ctx, selectExchangeRate+` ORDER BY base, quote`)
+}
+
+func (m *mysqlExchangeRateRepo) Get(ctx context.Context, base domain.Currency, quote domain.Currency) (res domain.ExchangeRate, err error) {
+	list, err := m.fetch(ctx, selectExchangeRate+` WHERE base = ? AND quote = ?`, base, quote)
+	if err != nil {
+		return domain.ExchangeRate{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlExchangeRateRepo) Save(ctx context.Context, r *domain.ExchangeRate) (err error) {
+	query := `INSERT  exchange_rate SET base=? , quote=? , rate=? , updated_at=? ON DUPLICATE KEY UPDATE rate=VALUES(rate) , updated_at=VALUES(updated_at)`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, r.Base, r.Quote, r.Rate, r.UpdatedAt)
+	return
+}
+
+func (m *mysqlExchangeRateRepo) Delete(ctx context.Context, base domain.Currency, quote domain.Currency) (err error) {
+	query := "DELETE FROM exchange_rate WHERE base = ? AND quote = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, base, quote)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", rowsAfected)
+		return
+	}
+	return
+}
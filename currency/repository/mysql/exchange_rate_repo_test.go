@@ -0,0 +1,89 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	currencyMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/currency/repository/mysql"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+var rateColumns = []string{"base", "quote", "rate", "updated_at"}
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func TestFetch(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows(rateColumns).
+		AddRow("USD", "IDR", []byte("16250.50000000"), now).
+		AddRow("USD", "JPY", []byte("150.00000000"), now)
+
+	mock.ExpectQuery(`SELECT base, quote, rate, updated_at FROM exchange_rate ORDER BY base, quote`).WillReturnRows(rows)
+
+	r := currencyMysqlRepo.NewMysqlExchangeRateRepo(db)
+	list, err := r.Fetch(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, domain.Rate(1625050000000), list[0].Rate)
+	assert.Equal(t, domain.Currency("JPY"), list[1].Quote)
+}
+
+func TestGet(t *testing.T) {
+	query := `SELECT base, quote, rate, updated_at FROM exchange_rate WHERE base = \? AND quote = \?`
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := NewMock()
+		rows := sqlmock.NewRows(rateColumns).AddRow("USD", "IDR", []byte("16250.50000000"), now)
+		mock.ExpectQuery(query).WithArgs("USD", "IDR").WillReturnRows(rows)
+
+		r := currencyMysqlRepo.NewMysqlExchangeRateRepo(db)
+		res, err := r.Get(context.TODO(), "USD", "IDR")
+		assert.NoError(t, err)
+		assert.Equal(t, "16250.5", res.Rate.String())
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		db, mock := NewMock()
+		mock.ExpectQuery(query).WithArgs("USD", "EUR").WillReturnRows(sqlmock.NewRows(rateColumns))
+
+		r := currencyMysqlRepo.NewMysqlExchangeRateRepo(db)
+		_, err := r.Get(context.TODO(), "USD", "EUR")
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+}
+
+func TestSave(t *testing.T) {
+	db, mock := NewMock()
+	rate := domain.ExchangeRate{Base: "USD", Quote: "IDR", Rate: 1625050000000, UpdatedAt: now}
+
+	query := `INSERT  exchange_rate SET base=\? , quote=\? , rate=\? , updated_at=\? ON DUPLICATE KEY UPDATE rate=VALUES\(rate\) , updated_at=VALUES\(updated_at\)`
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("USD", "IDR", "16250.5", now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := currencyMysqlRepo.NewMysqlExchangeRateRepo(db)
+	err := r.Save(context.TODO(), &rate)
+	assert.NoError(t, err)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock := NewMock()
+	mock.ExpectPrepare(`DELETE FROM exchange_rate WHERE base = \? AND quote = \?`).ExpectExec().
+		WithArgs("USD", "IDR").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := currencyMysqlRepo.NewMysqlExchangeRateRepo(db)
+	err := r.Delete(context.TODO(), "USD", "IDR")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type exchangeRateUsecase struct {
+	rateRepo       domain.ExchangeRateRepository
+	transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewExchangeRateUsecase will create an object that represent the domain.ExchangeRateUsecase interface
+func NewExchangeRateUsecase(r domain.ExchangeRateRepository, tx domain.Transactor, timeout time.Duration) domain.ExchangeRateUsecase {
+	return &exchangeRateUsecase{
+		rateRepo:       r,
+		transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func validateRate(r domain.ExchangeRate) error {
+	if !r.Base.IsValid() || !r.Quote.IsValid() {
+		return domain.ErrInvalidCurrency
+	}
+	if r.Base == r.Quote {
+		return domain.ErrBadParamInput
+	}
+	if !r.Rate.IsValid() {
+		return domain.ErrInvalidRate
+	}
+	return nil
+}
+
+func (m *exchangeRateUsecase) Fetch(ctx context.Context) ([]domain.ExchangeRate, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.rateRepo.Fetch(ctx)
+}
+
+func (m *exchangeRateUsecase) Save(ctx context.Context, r *domain.ExchangeRate) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err := validateRate(*r); err != nil {
+		return err
+	}
+	r.UpdatedAt = time.Now()
+	return m.rateRepo.Save(ctx, r)
+}
+
+// Import refuses the whole set when one of the rates is invalid
+func (m *exchangeRateUsecase) Import(ctx context.Context, rates []domain.ExchangeRate) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	for _, r := range rates {
+		if err := validateRate(r); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range rates {
+			rates[i].UpdatedAt = now
+			if err := m.rateRepo.Save(ctx, &rates[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *exchangeRateUsecase) Delete(ctx context.Context, base domain.Currency, quote domain.Currency) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	_, err = m.rateRepo.Get(ctx, base, quote)
+	if err != nil {
+		return
+	}
+	return m.rateRepo.Delete(ctx, base, quote)
+}
+
+func (m *exchangeRateUsecase) Rate(ctx context.Context, base domain.Currency, quote domain.Currency) (domain.Rate, error) {
+	if base == quote {
+		return domain.RateScale, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.rateRepo.Get(ctx, base, quote)
+	if err == nil {
+		return res.Rate, nil
+	}
+	if err != domain.ErrNotFound {
+		return 0, err
+	}
+	res, err = m.rateRepo.Get(ctx, quote, base)
+	if err == domain.ErrNotFound {
+		return 0, domain.ErrNoExchangeRate
+	}
+	if err != nil {
+		return 0, err
+	}
+	return res.Rate.Inverse(), nil
+}
@@ -0,0 +1,155 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ucase "github.com/alfathaulia/ca_ecommerce_api/currency/usecase"
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var usdIDR = domain.ExchangeRate{Base: "USD", Quote: "IDR", Rate: 1625000000000}
+
+// runInTransaction makes the Transactor mock call the unit of work directly
+func runInTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestSave(t *testing.T) {
+	mockRateRepo := new(mocks.ExchangeRateRepository)
+	mockTransactor := new(mocks.Transactor)
+
+	t.Run("success", func(t *testing.T) {
+		mockRateRepo.On("Save", mock.Anything, mock.MatchedBy(func(r *domain.ExchangeRate) bool {
+			return !r.UpdatedAt.IsZero()
+		})).Return(nil).Once()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		r := usdIDR
+		err := u.Save(context.TODO(), &r)
+		assert.NoError(t, err)
+		mockRateRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		mockRateRepo := new(mocks.ExchangeRateRepository)
+		tests := []struct {
+			name string
+			rate domain.ExchangeRate
+			err  error
+		}{
+			{"unsupported-currency", domain.ExchangeRate{Base: "USD", Quote: "XXX", Rate: domain.RateScale}, domain.ErrInvalidCurrency},
+			{"same-currency", domain.ExchangeRate{Base: "USD", Quote: "USD", Rate: domain.RateScale}, domain.ErrBadParamInput},
+			{"zero-rate", domain.ExchangeRate{Base: "USD", Quote: "IDR"}, domain.ErrInvalidRate},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+				err := u.Save(context.TODO(), &tt.rate)
+				assert.Equal(t, tt.err, err)
+			})
+		}
+		mockRateRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestImport(t *testing.T) {
+	mockRateRepo := new(mocks.ExchangeRateRepository)
+	mockTransactor := new(mocks.Transactor)
+
+	t.Run("success", func(t *testing.T) {
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockRateRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.ExchangeRate")).Return(nil).Twice()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		err := u.Import(context.TODO(), []domain.ExchangeRate{usdIDR, {Base: "USD", Quote: "JPY", Rate: 15000000000}})
+		assert.NoError(t, err)
+		mockRateRepo.AssertExpectations(t)
+	})
+
+	t.Run("one-invalid-rate", func(t *testing.T) {
+		mockTransactor := new(mocks.Transactor)
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		err := u.Import(context.TODO(), []domain.ExchangeRate{usdIDR, {Base: "USD", Quote: "JPY"}})
+		assert.Equal(t, domain.ErrInvalidRate, err)
+		mockTransactor.AssertNotCalled(t, "WithinTransaction", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockRateRepo := new(mocks.ExchangeRateRepository)
+	mockTransactor := new(mocks.Transactor)
+
+	t.Run("success", func(t *testing.T) {
+		mockRateRepo.On("Get", mock.Anything, usdIDR.Base, usdIDR.Quote).Return(usdIDR, nil).Once()
+		mockRateRepo.On("Delete", mock.Anything, usdIDR.Base, usdIDR.Quote).Return(nil).Once()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		err := u.Delete(context.TODO(), usdIDR.Base, usdIDR.Quote)
+		assert.NoError(t, err)
+		mockRateRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockRateRepo.On("Get", mock.Anything, domain.Currency("USD"), domain.Currency("EUR")).Return(domain.ExchangeRate{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		err := u.Delete(context.TODO(), "USD", "EUR")
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockRateRepo.AssertNotCalled(t, "Delete", mock.Anything, domain.Currency("USD"), domain.Currency("EUR"))
+	})
+}
+
+func TestRate(t *testing.T) {
+	mockRateRepo := new(mocks.ExchangeRateRepository)
+	mockTransactor := new(mocks.Transactor)
+
+	t.Run("same-currency", func(t *testing.T) {
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		rate, err := u.Rate(context.TODO(), "IDR", "IDR")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Rate(domain.RateScale), rate)
+	})
+
+	t.Run("direct", func(t *testing.T) {
+		mockRateRepo.On("Get", mock.Anything, usdIDR.Base, usdIDR.Quote).Return(usdIDR, nil).Once()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		rate, err := u.Rate(context.TODO(), usdIDR.Base, usdIDR.Quote)
+		assert.NoError(t, err)
+		assert.Equal(t, usdIDR.Rate, rate)
+	})
+
+	t.Run("inverse", func(t *testing.T) {
+		mockRateRepo.On("Get", mock.Anything, usdIDR.Quote, usdIDR.Base).Return(domain.ExchangeRate{}, domain.ErrNotFound).Once()
+		mockRateRepo.On("Get", mock.Anything, usdIDR.Base, usdIDR.Quote).Return(usdIDR, nil).Once()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		rate, err := u.Rate(context.TODO(), usdIDR.Quote, usdIDR.Base)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.00006154", rate.String())
+	})
+
+	t.Run("unknown-pair", func(t *testing.T) {
+		mockRateRepo.On("Get", mock.Anything, domain.Currency("IDR"), domain.Currency("EUR")).Return(domain.ExchangeRate{}, domain.ErrNotFound).Once()
+		mockRateRepo.On("Get", mock.Anything, domain.Currency("EUR"), domain.Currency("IDR")).Return(domain.ExchangeRate{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		_, err := u.Rate(context.TODO(), "IDR", "EUR")
+		assert.Equal(t, domain.ErrNoExchangeRate, err)
+	})
+
+	t.Run("repository-error", func(t *testing.T) {
+		mockRateRepo.On("Get", mock.Anything, domain.Currency("IDR"), domain.Currency("SGD")).Return(domain.ExchangeRate{}, errors.New("Unexpected")).Once()
+
+		u := ucase.NewExchangeRateUsecase(mockRateRepo, mockTransactor, time.Second*2)
+		_, err := u.Rate(context.TODO(), "IDR", "SGD")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidToken will throw if the given token can not be verified
+	ErrInvalidToken = errors.New("token is invalid")
+	// ErrExpiredToken will throw if the given token is already expired
+	ErrExpiredToken = errors.New("token has expired")
+	// ErrUnauthorized will throw if the request is not authenticated
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+const (
+	// TokenScopeAccess grants access to the API
+	TokenScopeAccess = "access"
+	// TokenScopeMFA is only accepted to complete a login with a TOTP code
+	TokenScopeMFA = "mfa"
+	// TokenScopeMFAEnroll is only accepted by the TOTP enrollment endpoints
+	TokenScopeMFAEnroll = "mfa_enroll"
+)
+
+// TokenPayload represent the claims carried by an access token
+type TokenPayload struct {
+	UserID     int64     `json:"user_id"`
+	Username   string    `json:"username"`
+	Role       RolesType `json:"role"`
+	IsVerified bool      `json:"is_verified"`
+	Scope      string    `json:"scope"`
+	IssuedAt   time.Time `json:"issued_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
+
+// LoginResponse is returned to the client after a successful login. When
+// MFARequired is set only MFAToken is filled, and the login is completed by
+// sending it together with a TOTP code.
+type LoginResponse struct {
+	AccessToken           string    `json:"access_token,omitempty"`
+	TokenType             string    `json:"token_type,omitempty"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at,omitempty"`
+	RefreshToken          string    `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at,omitempty"`
+	MFARequired           bool      `json:"mfa_required,omitempty"`
+	MFAToken              string    `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool      `json:"mfa_enrollment_required,omitempty"`
+	User                  *User     `json:"user,omitempty"`
+}
+
+// TokenMaker represent the contract to create and verify access tokens
+type TokenMaker interface {
+	CreateToken(user User) (string, *TokenPayload, error)
+	CreateScopedToken(user User, scope string, duration time.Duration) (string, *TokenPayload, error)
+	VerifyToken(token string) (*TokenPayload, error)
+}
+
+type authContextKey struct{}
+
+// NewContextWithAuth returns a copy of ctx carrying the authenticated token payload
+func NewContextWithAuth(ctx context.Context, payload *TokenPayload) context.Context {
+	return context.WithValue(ctx, authContextKey{}, payload)
+}
+
+// AuthFromContext returns the authenticated token payload stored in ctx, if any
+func AuthFromContext(ctx context.Context) (*TokenPayload, bool) {
+	payload, ok := ctx.Value(authContextKey{}).(*TokenPayload)
+	return payload, ok
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrEmptyCart will throw if a cart without items is checked out
+var ErrEmptyCart = errors.New("cart is empty")
+
+// Cart belongs either to a user or, for guests, to the holder of the cart
+// token. Token is only filled right after a guest cart is created.
+type Cart struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id,omitempty"`
+	Token     string     `json:"-"`
+	TokenHash string     `json:"-"`
+	Items     []CartItem `json:"items"`
+	Subtotal  Money      `json:"subtotal"`
+	// DisplaySubtotal is Subtotal converted to the currency asked by the client
+	DisplaySubtotal *Money    `json:"display_subtotal,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CartItem is a line of the cart, Name, Image, Price and Subtotal are
+// computed from the current Product, or its Variant, when the cart is read.
+// The display amounts are in the currency asked by the client.
+type CartItem struct {
+	ProductID       int64  `json:"product_id"`
+	VariantID       int64  `json:"variant_id,omitempty"`
+	SKU             string `json:"sku,omitempty"`
+	Name            string `json:"name"`
+	Image           string `json:"image"`
+	Price           Money  `json:"price"`
+	Qty             int    `json:"qty"`
+	Subtotal        Money  `json:"subtotal"`
+	DisplayPrice    *Money `json:"display_price,omitempty"`
+	DisplaySubtotal *Money `json:"display_subtotal,omitempty"`
+}
+
+// Line is what the cart line of item holds
+func (i CartItem) Line() CartLine {
+	return CartLine{ProductID: i.ProductID, VariantID: i.VariantID}
+}
+
+// CartLine identifies a line of a cart, VariantID is 0 for a product without variants
+type CartLine struct {
+	ProductID int64
+	VariantID int64
+}
+
+// CartOwner identifies the cart of a request, UserID for signed in users and
+// Token for guests
+type CartOwner struct {
+	UserID int64
+	Token  string
+}
+
+// CheckoutRequest carries what the customer chooses when checking out,
+// DisplayCurrency is the currency the customer browsed in, if any
+type CheckoutRequest struct {
+	PayMethod       string
+	ShippingAddress ShippingAddress
+	DisplayCurrency Currency
+}
+
+// CartRepository represent the Cart's repository contract
+type CartRepository interface {
+	GetByUserID(ctx context.Context, userID int64) (Cart, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (Cart, error)
+	Store(ctx context.Context, c *Cart) error
+	AssignUser(ctx context.Context, id int64, userID int64) error
+	Delete(ctx context.Context, id int64) error
+	FetchItems(ctx context.Context, cartID int64) ([]CartItem, error)
+	AddItem(ctx context.Context, cartID int64, line CartLine, qty int) error
+	UpdateItem(ctx context.Context, cartID int64, line CartLine, qty int) error
+	RemoveItem(ctx context.Context, cartID int64, line CartLine) error
+	ClearItems(ctx context.Context, cartID int64) error
+}
+
+// CartUsecase represent the Cart's usecases
+type CartUsecase interface {
+	Get(ctx context.Context, owner CartOwner) (Cart, error)
+	// AddItem puts qty of the line in the cart, a product sold in variants
+	// needs one, ErrVariantRequired is returned otherwise
+	AddItem(ctx context.Context, owner CartOwner, line CartLine, qty int) (Cart, error)
+	UpdateItem(ctx context.Context, owner CartOwner, line CartLine, qty int) (Cart, error)
+	RemoveItem(ctx context.Context, owner CartOwner, line CartLine) (Cart, error)
+	Merge(ctx context.Context, userID int64, guestToken string) error
+	Checkout(ctx context.Context, userID int64, req CheckoutRequest) (Order, error)
+}
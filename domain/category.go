@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrCategoryCycle will throw if a category is moved under itself or one of its descendants
+	ErrCategoryCycle = errors.New("a category can not be moved under itself or its descendants")
+	// ErrCategoryInUse will throw if a category that still has subcategories or products is deleted
+	ErrCategoryInUse = errors.New("category still has subcategories or products")
+	// ErrUnknownCategory will throw if a product or a category refers to a category that does not exist
+	ErrUnknownCategory = errors.New("category does not exist")
+)
+
+// Category is a node of the catalog tree. Slug is unique over the whole
+// tree, Position orders the children of a parent, lowest first. Path holds
+// the ids from the root down to the category itself, e.g. "/1/4/", so that
+// a subtree is every category whose path starts with the path of its root.
+type Category struct {
+	ID        int64      `json:"id"`
+	ParentID  *int64     `json:"parent_id"`
+	Name      string     `json:"name"`
+	Slug      string     `json:"slug"`
+	Position  int        `json:"position"`
+	Path      string     `json:"-"`
+	Children  []Category `json:"children,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ChildPath returns the path of a category with id placed under c
+func (c Category) ChildPath(id int64) string {
+	return fmt.Sprintf("%s%d/", c.Path, id)
+}
+
+// PathIDs returns the ids of the path of c, from the root down to c itself
+func (c Category) PathIDs() []int64 {
+	res := make([]int64, 0)
+	for _, p := range strings.Split(strings.Trim(c.Path, "/"), "/") {
+		if id, err := strconv.ParseInt(p, 10, 64); err == nil {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// Contains reports whether o is c or one of its descendants
+func (c Category) Contains(o Category) bool {
+	return c.Path != "" && strings.HasPrefix(o.Path, c.Path)
+}
+
+// CategoryRepository represent the Category's repository contract
+type CategoryRepository interface {
+	// Fetch returns every category, ordered by parent and position
+	Fetch(ctx context.Context) ([]Category, error)
+	// FetchSubtree returns root and its descendants, ordered like Fetch
+	FetchSubtree(ctx context.Context, root Category) ([]Category, error)
+	GetByID(ctx context.Context, id int64) (Category, error)
+	// GetByIDForUpdate locks the category row until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int64) (Category, error)
+	GetBySlug(ctx context.Context, slug string) (Category, error)
+	// Store inserts c with Path holding the path of its parent, then sets
+	// Path to the path of c itself
+	Store(ctx context.Context, c *Category) error
+	// Update changes the name, slug and position of c, never its parent
+	Update(ctx context.Context, c *Category) error
+	// Move places c under its ParentID with Path, rewriting the paths of
+	// the descendants that started with oldPath
+	Move(ctx context.Context, c *Category, oldPath string) error
+	// InUse reports whether the category has subcategories or products
+	InUse(ctx context.Context, id int64) (bool, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// CategoryUsecase represent the catalog tree and its management
+type CategoryUsecase interface {
+	// Tree returns the root categories with their descendants nested as Children
+	Tree(ctx context.Context) ([]Category, error)
+	// GetByID returns the category with its descendants nested as Children
+	GetByID(ctx context.Context, id int64) (Category, error)
+	// GetBySlug is GetByID by the slug of the category
+	GetBySlug(ctx context.Context, slug string) (Category, error)
+	// Store creates c under its ParentID, the slug is made from the name
+	// when empty. ErrConflict is returned for a slug already taken.
+	Store(ctx context.Context, c *Category) error
+	Update(ctx context.Context, c *Category) error
+	// Move places the category and its subtree under parentID, at the root
+	// when nil. ErrCategoryCycle is returned for a parent within the subtree.
+	Move(ctx context.Context, id int64, parentID *int64, position int) (Category, error)
+	// Delete removes a category without subcategories nor products,
+	// ErrCategoryInUse is returned otherwise
+	Delete(ctx context.Context, id int64) error
+}
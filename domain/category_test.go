@@ -0,0 +1,13 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryPathIDs(t *testing.T) {
+	assert.Equal(t, []int64{1, 2, 4}, domain.Category{Path: "/1/2/4/"}.PathIDs())
+	assert.Empty(t, domain.Category{}.PathIDs())
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnverified will throw if the action requires a verified email address
+var ErrUnverified = errors.New("email address is not verified")
+
+// EmailVerification is a single-use token sent to verify the email of a user
+type EmailVerification struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// Email is the address the token was sent to
+	Email     string    `json:"email"`
+	TokenHash string    `json:"-"`
+	IsUsed    bool      `json:"is_used"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EmailVerificationRepository represent the EmailVerification's repository contract
+type EmailVerificationRepository interface {
+	Store(ctx context.Context, v *EmailVerification) error
+	GetByHash(ctx context.Context, tokenHash string) (EmailVerification, error)
+	MarkUsed(ctx context.Context, id int64) error
+}
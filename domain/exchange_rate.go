@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidRate will throw if an exchange rate is not a positive decimal of at most 8 digits on each side of the point
+	ErrInvalidRate = errors.New("exchange rate must be a positive decimal with at most 8 digits before and after the point")
+	// ErrNoExchangeRate will throw if prices can not be converted to the requested currency
+	ErrNoExchangeRate = errors.New("no exchange rate to the requested currency")
+)
+
+const (
+	// RateScale is the fixed point of a Rate, rates carry 8 decimals
+	RateScale    = 100000000
+	rateDecimals = 8
+	// maxRate keeps a conversion of any amount of minor units within int64
+	maxRate Rate = RateScale * RateScale
+)
+
+// Rate is how many units of a quote currency one unit of a base currency
+// buys, in 1/RateScale. It travels as a decimal string, e.g. "16250.5", and is
+// stored as DECIMAL(16,8) so that no float ever touches it.
+type Rate int64
+
+// ParseRate reads a positive decimal such as "0.0000615" or "16250"
+func ParseRate(s string) (Rate, error) {
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" || len(whole) > rateDecimals || len(frac) > rateDecimals || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidRate
+	}
+	v, err := strconv.ParseInt(whole+frac+strings.Repeat("0", rateDecimals-len(frac)), 10, 64)
+	if err != nil || v <= 0 {
+		return 0, ErrInvalidRate
+	}
+	return Rate(v), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValid reports whether r is positive and small enough to convert with
+func (r Rate) IsValid() bool {
+	return r > 0 && r < maxRate
+}
+
+// String formats r as a decimal without trailing zeros, e.g. "16250.5"
+func (r Rate) String() string {
+	digits := strconv.FormatInt(int64(r), 10)
+	if len(digits) <= rateDecimals {
+		digits = strings.Repeat("0", rateDecimals-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-rateDecimals], strings.TrimRight(digits[len(digits)-rateDecimals:], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
+
+// Inverse returns the rate of the opposite direction rounded half up
+func (r Rate) Inverse() Rate {
+	return Rate((RateScale*RateScale + int64(r)/2) / int64(r))
+}
+
+// Convert returns m in currency to, rounded half up to a whole minor unit of to
+func (r Rate) Convert(m Money, to Currency) Money {
+	num, den := int64(r), int64(RateScale)
+	for exp := to.Exponent() - m.Currency.Exponent(); exp > 0; exp-- {
+		num *= 10
+	}
+	for exp := m.Currency.Exponent() - to.Exponent(); exp > 0; exp-- {
+		den *= 10
+	}
+	res := m.MulFrac(num, den, RoundHalfUp)
+	res.Currency = to
+	return res
+}
+
+// MarshalJSON writes r as a decimal string
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+// UnmarshalJSON accepts a decimal either as a string or as a bare number
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// Value implements driver.Valuer
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan implements sql.Scanner for a DECIMAL column
+func (r *Rate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return r.scanDecimal(string(v))
+	case string:
+		return r.scanDecimal(v)
+	case int64:
+		*r = Rate(v * RateScale)
+	case nil:
+		*r = 0
+	default:
+		return fmt.Errorf("domain: can not scan %T into Rate", src)
+	}
+	return nil
+}
+
+// scanDecimal also accepts zero, which ParseRate refuses
+func (r *Rate) scanDecimal(s string) error {
+	if strings.Trim(s, "0.") == "" {
+		*r = 0
+		return nil
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+// ExchangeRate converts amounts of Base into Quote, it is managed by the
+// admins and used to show prices in the currency of the customer. Orders keep
+// being settled in the currency of the products.
+type ExchangeRate struct {
+	Base      Currency  `json:"base"`
+	Quote     Currency  `json:"quote"`
+	Rate      Rate      `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ExchangeRateRepository represent the ExchangeRate's repository contract
+type ExchangeRateRepository interface {
+	Fetch(ctx context.Context) ([]ExchangeRate, error)
+	Get(ctx context.Context, base Currency, quote Currency) (ExchangeRate, error)
+	// Save creates the rate of the pair or replaces the existing one
+	Save(ctx context.Context, r *ExchangeRate) error
+	Delete(ctx context.Context, base Currency, quote Currency) error
+}
+
+// ExchangeRateUsecase represent the ExchangeRate's usecases
+type ExchangeRateUsecase interface {
+	Fetch(ctx context.Context) ([]ExchangeRate, error)
+	Save(ctx context.Context, r *ExchangeRate) error
+	// Import saves all rates at once, e.g. the ones loaded from a file
+	Import(ctx context.Context, rates []ExchangeRate) error
+	Delete(ctx context.Context, base Currency, quote Currency) error
+	// Rate returns the rate from base to quote, using the inverse of the
+	// quote to base rate when only that one is known. ErrNoExchangeRate is
+	// returned when neither is.
+	Rate(ctx context.Context, base Currency, quote Currency) (Rate, error)
+}
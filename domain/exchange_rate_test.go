@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want domain.Rate
+		err  error
+	}{
+		{"16250.5", 1625050000000, nil},
+		{"150", 15000000000, nil},
+		{"0.00006154", 6154, nil},
+		{"0", 0, domain.ErrInvalidRate},
+		{"-1", 0, domain.ErrInvalidRate},
+		{"1.123456789", 0, domain.ErrInvalidRate},
+		{"123456789", 0, domain.ErrInvalidRate},
+		{".5", 0, domain.ErrInvalidRate},
+		{"1e3", 0, domain.ErrInvalidRate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := domain.ParseRate(tt.in)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRateString(t *testing.T) {
+	assert.Equal(t, "16250.5", domain.Rate(1625050000000).String())
+	assert.Equal(t, "150", domain.Rate(15000000000).String())
+	assert.Equal(t, "0.00006154", domain.Rate(6154).String())
+}
+
+func TestRateConvert(t *testing.T) {
+	usdIDR := domain.Rate(1625000000000)
+	assert.Equal(t, domain.NewMoney(16250000, "IDR"), usdIDR.Convert(domain.NewMoney(1000, "USD"), "IDR"))
+	assert.Equal(t, "0.00006154", usdIDR.Inverse().String())
+	assert.Equal(t, domain.NewMoney(1000, "USD"), usdIDR.Inverse().Convert(domain.NewMoney(16250000, "IDR"), "USD"))
+
+	// JPY has no minor unit
+	usdJPY := domain.Rate(15012000000)
+	assert.Equal(t, domain.NewMoney(1501, "JPY"), usdJPY.Convert(domain.NewMoney(1000, "USD"), "JPY"))
+	assert.Equal(t, domain.NewMoney(1000, "USD"), usdJPY.Inverse().Convert(domain.NewMoney(1501, "JPY"), "USD"))
+}
+
+func TestRateJSON(t *testing.T) {
+	data, err := json.Marshal(domain.ExchangeRate{Base: "USD", Quote: "IDR", Rate: 1625050000000})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"rate":"16250.5"`)
+
+	var r domain.Rate
+	require.NoError(t, json.Unmarshal([]byte(`"16250.5"`), &r))
+	assert.Equal(t, domain.Rate(1625050000000), r)
+	require.NoError(t, json.Unmarshal([]byte(`150`), &r))
+	assert.Equal(t, domain.Rate(15000000000), r)
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &r))
+}
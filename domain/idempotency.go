@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord is the first response given to a request carrying an
+// Idempotency-Key. Keys are scoped per user, anonymous requests share UserID 0.
+// A record is in flight until Completed is set, retries are answered from it
+// afterwards.
+type IdempotencyRecord struct {
+	UserID      int64
+	Key         string
+	RequestHash string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+// IdempotencyStore keeps IdempotencyRecord by user and key until they expire
+type IdempotencyStore interface {
+	// Get returns ErrNotFound when there is no unexpired record for the key
+	Get(ctx context.Context, userID int64, key string) (IdempotencyRecord, error)
+	// Create claims the key for an in-flight request, it returns ErrConflict
+	// when an unexpired record for the key already exists
+	Create(ctx context.Context, r *IdempotencyRecord) error
+	Complete(ctx context.Context, r *IdempotencyRecord) error
+	Delete(ctx context.Context, userID int64, key string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
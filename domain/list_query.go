@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sort orders a list on one field, the id breaking ties in the same direction
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// String returns s as written in the sort query param, e.g. -created_at
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// ParseSort reads a sort as written by String
+func ParseSort(s string) Sort {
+	if strings.HasPrefix(s, "-") {
+		return Sort{Field: s[1:], Desc: true}
+	}
+	return Sort{Field: strings.TrimPrefix(s, "+")}
+}
+
+// FilterOp is the comparison of a Filter
+type FilterOp string
+
+const (
+	FilterEq  FilterOp = "eq"
+	FilterIn  FilterOp = "in"
+	FilterGt  FilterOp = "gt"
+	FilterLt  FilterOp = "lt"
+	FilterGte FilterOp = "gte"
+	FilterLte FilterOp = "lte"
+)
+
+// Filter keeps the rows whose Field compares to Value. Value has the Go type
+// of the field, see FieldType, and is a []interface{} of them for FilterIn.
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value interface{}
+}
+
+// FieldType is the type of a list field, values of a FieldString are a
+// string, of a FieldInt an int64, of a FieldBool a bool and of a FieldTime a
+// time.Time
+type FieldType int
+
+const (
+	FieldString FieldType = iota + 1
+	FieldInt
+	FieldBool
+	FieldTime
+)
+
+// ListField is a field clients may filter or sort a list on
+type ListField struct {
+	Name     string
+	Type     FieldType
+	Sortable bool
+	// Filterable fields are matched by the param of their name. Time fields
+	// also take <name without _at>_after and _before, int fields <name>_min
+	// and <name>_max.
+	Filterable bool
+}
+
+// ListSchema is the whitelist of a list: anything it does not name is
+// rejected before reaching a repository
+type ListSchema struct {
+	Fields      []ListField
+	DefaultSort Sort
+}
+
+// Field returns the field called name
+func (s ListSchema) Field(name string) (ListField, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return ListField{}, false
+}
+
+// QueryProblem is what is wrong with one query param of a list request
+type QueryProblem struct {
+	Param  string `json:"param"`
+	Reason string `json:"reason"`
+}
+
+// QueryError lists every problem of a list request, errors.Is(err, ErrBadParamInput) holds for it
+type QueryError struct {
+	Problems []QueryProblem
+}
+
+func (e *QueryError) Error() string {
+	lines := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		lines = append(lines, fmt.Sprintf("%s: %s", p.Param, p.Reason))
+	}
+	return ErrBadParamInput.Error() + ": " + strings.Join(lines, "; ")
+}
+
+func (e *QueryError) Unwrap() error {
+	return ErrBadParamInput
+}
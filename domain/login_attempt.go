@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTooManyAttempts will throw if login is temporarily blocked after repeated failures
+var ErrTooManyAttempts = errors.New("too many failed login attempts, try again later")
+
+const (
+	LoginFailureUnknownUser   = "unknown_user"
+	LoginFailureWrongPassword = "wrong_password"
+	LoginFailureWrongMFACode  = "wrong_mfa_code"
+	LoginFailureThrottled     = "throttled"
+)
+
+// LoginAttempt is the audit record of a failed login
+type LoginAttempt struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	ClientIP  string    `json:"ip"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginThrottle is the failed-attempt state kept for one account or one IP
+type LoginThrottle struct {
+	Failures     int
+	LastFailure  time.Time
+	BlockedUntil time.Time
+}
+
+// LoginAttemptRepository represent the LoginAttempt's repository contract
+type LoginAttemptRepository interface {
+	Store(ctx context.Context, a *LoginAttempt) error
+}
+
+// LoginThrottleStore keeps LoginThrottle state by key. Entries are dropped after ttl.
+type LoginThrottleStore interface {
+	Get(ctx context.Context, key string) (LoginThrottle, error)
+	Save(ctx context.Context, key string, t LoginThrottle, ttl time.Duration) error
+	// Increment atomically counts one more failure on key, keeps the entry for
+	// at least ttl and returns the state after the increment
+	Increment(ctx context.Context, key string, ttl time.Duration) (LoginThrottle, error)
+	// Block moves BlockedUntil of key to until unless it is already later
+	Block(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
+
+// LoginLimiter decides whether a login may be attempted and tracks the outcome
+type LoginLimiter interface {
+	Allow(ctx context.Context, username string, ip string) error
+	Fail(ctx context.Context, username string, ip string, reason string) error
+	Succeed(ctx context.Context, username string, ip string) error
+	Unlock(ctx context.Context, username string) error
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "context"
+
+// Mail is a single plain text email
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer represent the contract to deliver emails
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// UserMailer composes and sends the account emails of a user
+type UserMailer interface {
+	SendVerification(ctx context.Context, user User, token string) error
+	SendPasswordReset(ctx context.Context, user User, token string) error
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidMFACode will throw if the given TOTP or recovery code is not valid
+var ErrInvalidMFACode = errors.New("invalid two-factor authentication code")
+
+// MFA is the TOTP enrollment of a user
+type MFA struct {
+	UserID    int64  `json:"user_id"`
+	Secret    string `json:"-"`
+	IsEnabled bool   `json:"is_enabled"`
+	// LastUsedStep is the time step of the last accepted TOTP code
+	LastUsedStep int64     `json:"-"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MFAEnrollment is returned when a user starts a TOTP enrollment
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFARepository represent the MFA's repository contract
+type MFARepository interface {
+	GetByUserID(ctx context.Context, userID int64) (MFA, error)
+	Upsert(ctx context.Context, m *MFA) error
+	Enable(ctx context.Context, userID int64) error
+	StoreRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error
+	UseStep(ctx context.Context, userID int64, step int64) error
+}
+
+// MFAAuthenticator enrolls users into TOTP and verifies their codes
+type MFAAuthenticator interface {
+	IsRequired(role RolesType) bool
+	IsEnabled(ctx context.Context, userID int64) (bool, error)
+	Enroll(ctx context.Context, user User) (MFAEnrollment, error)
+	Confirm(ctx context.Context, userID int64, code string) (recoveryCodes []string, err error)
+	Verify(ctx context.Context, userID int64, code string) error
+}
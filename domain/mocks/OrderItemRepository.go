@@ -14,53 +14,39 @@ type OrderItemRepository struct {
 	mock.Mock
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, id
-func (_m *OrderItemRepository) Fetch(ctx context.Context, cursor string, id int64) ([]domain.OrderItem, string, error) {
-	ret := _m.Called(ctx, cursor, id)
+// FetchByOrder provides a mock function with given fields: ctx, orderID
+func (_m *OrderItemRepository) FetchByOrder(ctx context.Context, orderID int64) ([]domain.OrderItem, error) {
+	ret := _m.Called(ctx, orderID)
 
 	var r0 []domain.OrderItem
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.OrderItem); ok {
-		r0 = rf(ctx, cursor, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.OrderItem); ok {
+		r0 = rf(ctx, orderID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.OrderItem)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, id)
-	} else {
-		r1 = ret.Get(1).(string)
-	}
-
-	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, id)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *OrderItemRepository) GetByID(ctx context.Context, id int64) (domain.OrderItem, error) {
-	ret := _m.Called(ctx, id)
+// Store provides a mock function with given fields: ctx, item
+func (_m *OrderItemRepository) Store(ctx context.Context, item *domain.OrderItem) error {
+	ret := _m.Called(ctx, item)
 
-	var r0 domain.OrderItem
-	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.OrderItem); ok {
-		r0 = rf(ctx, id)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OrderItem) error); ok {
+		r0 = rf(ctx, item)
 	} else {
-		r0 = ret.Get(0).(domain.OrderItem)
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
+	return r0
 }
@@ -14,12 +14,26 @@ type OrderRepository struct {
 	mock.Mock
 }
 
+// AddRefunded provides a mock function with given fields: ctx, id, amount
+func (_m *OrderRepository) AddRefunded(ctx context.Context, id int64, amount domain.Money) error {
+	ret := _m.Called(ctx, id, amount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Money) error); ok {
+		r0 = rf(ctx, id, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, id
-func (_m *OrderRepository) Delete(ctx context.Context, id int) error {
+func (_m *OrderRepository) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
 		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
@@ -28,29 +42,59 @@ func (_m *OrderRepository) Delete(ctx context.Context, id int) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *OrderRepository) Fetch(ctx context.Context, cursor string, num int) ([]domain.Order, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *OrderRepository) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.Order, domain.PageInfo, error) {
+	ret := _m.Called(ctx, page)
+
+	var r0 []domain.Order
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) []domain.Order); ok {
+		r0 = rf(ctx, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Order)
+		}
+	}
+
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Get(1).(domain.PageInfo)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PageRequest) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchByUser provides a mock function with given fields: ctx, userID, page
+func (_m *OrderRepository) FetchByUser(ctx context.Context, userID int64, page domain.PageRequest) ([]domain.Order, domain.PageInfo, error) {
+	ret := _m.Called(ctx, userID, page)
 
 	var r0 []domain.Order
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.Order); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.PageRequest) []domain.Order); ok {
+		r0 = rf(ctx, userID, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Order)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, userID, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.PageRequest) error); ok {
+		r2 = rf(ctx, userID, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -59,18 +103,39 @@ func (_m *OrderRepository) Fetch(ctx context.Context, cursor string, num int) ([
 }
 
 // GetByID provides a mock function with given fields: ctx, id
-func (_m *OrderRepository) GetByID(ctx context.Context, id int) (domain.Order, error) {
+func (_m *OrderRepository) GetByID(ctx context.Context, id int64) (domain.Order, error) {
 	ret := _m.Called(ctx, id)
 
 	var r0 domain.Order
-	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Order); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Order); ok {
 		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(domain.Order)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDForUpdate provides a mock function with given fields: ctx, id
+func (_m *OrderRepository) GetByIDForUpdate(ctx context.Context, id int64) (domain.Order, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 domain.Order
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Order); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
 		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
@@ -106,3 +171,17 @@ func (_m *OrderRepository) Update(ctx context.Context, updateOrder *domain.Order
 
 	return r0
 }
+
+// UpdateStatus provides a mock function with given fields: ctx, o, from
+func (_m *OrderRepository) UpdateStatus(ctx context.Context, o *domain.Order, from domain.OrderStatus) error {
+	ret := _m.Called(ctx, o, from)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Order, domain.OrderStatus) error); ok {
+		r0 = rf(ctx, o, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
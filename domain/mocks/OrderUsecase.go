@@ -14,12 +14,33 @@ type OrderUsecase struct {
 	mock.Mock
 }
 
+// CancelUserOrder provides a mock function with given fields: ctx, userID, id, reason
+func (_m *OrderUsecase) CancelUserOrder(ctx context.Context, userID int64, id int64, reason string) (domain.Order, error) {
+	ret := _m.Called(ctx, userID, id, reason)
+
+	var r0 domain.Order
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) domain.Order); ok {
+		r0 = rf(ctx, userID, id, reason)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string) error); ok {
+		r1 = rf(ctx, userID, id, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
-func (_m *OrderUsecase) Delete(ctx context.Context, id int) error {
+func (_m *OrderUsecase) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
 		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
@@ -28,29 +49,29 @@ func (_m *OrderUsecase) Delete(ctx context.Context, id int) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *OrderUsecase) Fetch(ctx context.Context, cursor string, num int) ([]domain.Order, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *OrderUsecase) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.Order, domain.PageInfo, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []domain.Order
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.Order); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) []domain.Order); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Order)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PageRequest) error); ok {
+		r2 = rf(ctx, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -58,19 +79,72 @@ func (_m *OrderUsecase) Fetch(ctx context.Context, cursor string, num int) ([]do
 	return r0, r1, r2
 }
 
+// FetchByUser provides a mock function with given fields: ctx, userID, page
+func (_m *OrderUsecase) FetchByUser(ctx context.Context, userID int64, page domain.PageRequest) ([]domain.Order, domain.PageInfo, error) {
+	ret := _m.Called(ctx, userID, page)
+
+	var r0 []domain.Order
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.PageRequest) []domain.Order); ok {
+		r0 = rf(ctx, userID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Order)
+		}
+	}
+
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, userID, page)
+	} else {
+		r1 = ret.Get(1).(domain.PageInfo)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.PageRequest) error); ok {
+		r2 = rf(ctx, userID, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchHistory provides a mock function with given fields: ctx, id
+func (_m *OrderUsecase) FetchHistory(ctx context.Context, id int64) ([]domain.OrderStatusHistory, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.OrderStatusHistory
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.OrderStatusHistory); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.OrderStatusHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
-func (_m *OrderUsecase) GetByID(ctx context.Context, id int) (domain.Order, error) {
+func (_m *OrderUsecase) GetByID(ctx context.Context, id int64) (domain.Order, error) {
 	ret := _m.Called(ctx, id)
 
 	var r0 domain.Order
-	if rf, ok := ret.Get(0).(func(context.Context, int) domain.Order); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Order); ok {
 		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(domain.Order)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
 		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
@@ -79,6 +153,27 @@ func (_m *OrderUsecase) GetByID(ctx context.Context, id int) (domain.Order, erro
 	return r0, r1
 }
 
+// GetUserOrder provides a mock function with given fields: ctx, userID, id
+func (_m *OrderUsecase) GetUserOrder(ctx context.Context, userID int64, id int64) (domain.Order, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	var r0 domain.Order
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Order); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Store provides a mock function with given fields: ctx, createOrder
 func (_m *OrderUsecase) Store(ctx context.Context, createOrder *domain.Order) error {
 	ret := _m.Called(ctx, createOrder)
@@ -93,6 +188,27 @@ func (_m *OrderUsecase) Store(ctx context.Context, createOrder *domain.Order) er
 	return r0
 }
 
+// Transition provides a mock function with given fields: ctx, id, to, reason
+func (_m *OrderUsecase) Transition(ctx context.Context, id int64, to domain.OrderStatus, reason string) (domain.Order, error) {
+	ret := _m.Called(ctx, id, to, reason)
+
+	var r0 domain.Order
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.OrderStatus, string) domain.Order); ok {
+		r0 = rf(ctx, id, to, reason)
+	} else {
+		r0 = ret.Get(0).(domain.Order)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.OrderStatus, string) error); ok {
+		r1 = rf(ctx, id, to, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, updateOrder
 func (_m *OrderUsecase) Update(ctx context.Context, updateOrder *domain.Order) error {
 	ret := _m.Called(ctx, updateOrder)
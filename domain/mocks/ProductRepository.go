@@ -14,6 +14,20 @@ type ProductRepository struct {
 	mock.Mock
 }
 
+// DecrementStock provides a mock function with given fields: ctx, id, qty
+func (_m *ProductRepository) DecrementStock(ctx context.Context, id int64, qty int) error {
+	ret := _m.Called(ctx, id, qty)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) error); ok {
+		r0 = rf(ctx, id, qty)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *ProductRepository) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -28,29 +42,80 @@ func (_m *ProductRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ProductRepository) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Product, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Facets provides a mock function with given fields: ctx, query, filters, priceBounds
+func (_m *ProductRepository) Facets(ctx context.Context, query string, filters []domain.Filter, priceBounds []int64) (domain.ProductFacets, error) {
+	ret := _m.Called(ctx, query, filters, priceBounds)
+
+	var r0 domain.ProductFacets
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.Filter, []int64) domain.ProductFacets); ok {
+		r0 = rf(ctx, query, filters, priceBounds)
+	} else {
+		r0 = ret.Get(0).(domain.ProductFacets)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, []domain.Filter, []int64) error); ok {
+		r1 = rf(ctx, query, filters, priceBounds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *ProductRepository) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.Product, domain.PageInfo, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []domain.Product
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Product); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) []domain.Product); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Product)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PageRequest) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchInCategory provides a mock function with given fields: ctx, root, page
+func (_m *ProductRepository) FetchInCategory(ctx context.Context, root domain.Category, page domain.PageRequest) ([]domain.Product, domain.PageInfo, error) {
+	ret := _m.Called(ctx, root, page)
+
+	var r0 []domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Category, domain.PageRequest) []domain.Product); ok {
+		r0 = rf(ctx, root, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Product)
+		}
+	}
+
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Category, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, root, page)
+	} else {
+		r1 = ret.Get(1).(domain.PageInfo)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, domain.Category, domain.PageRequest) error); ok {
+		r2 = rf(ctx, root, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -79,6 +144,71 @@ func (_m *ProductRepository) GetByID(ctx context.Context, id int64) (domain.Prod
 	return r0, r1
 }
 
+// GetByIDForUpdate provides a mock function with given fields: ctx, id
+func (_m *ProductRepository) GetByIDForUpdate(ctx context.Context, id int64) (domain.Product, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Product); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Product)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementStock provides a mock function with given fields: ctx, id, qty
+func (_m *ProductRepository) IncrementStock(ctx context.Context, id int64, qty int) error {
+	ret := _m.Called(ctx, id, qty)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) error); ok {
+		r0 = rf(ctx, id, qty)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, query, page
+func (_m *ProductRepository) Search(ctx context.Context, query string, page domain.PageRequest) ([]domain.Product, domain.PageInfo, error) {
+	ret := _m.Called(ctx, query, page)
+
+	var r0 []domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PageRequest) []domain.Product); ok {
+		r0 = rf(ctx, query, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Product)
+		}
+	}
+
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, query, page)
+	} else {
+		r1 = ret.Get(1).(domain.PageInfo)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.PageRequest) error); ok {
+		r2 = rf(ctx, query, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Store provides a mock function with given fields: ctx, a
 func (_m *ProductRepository) Store(ctx context.Context, a *domain.Product) error {
 	ret := _m.Called(ctx, a)
@@ -93,6 +223,29 @@ func (_m *ProductRepository) Store(ctx context.Context, a *domain.Product) error
 	return r0
 }
 
+// Terms provides a mock function with given fields: ctx
+func (_m *ProductRepository) Terms(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, ar
 func (_m *ProductRepository) Update(ctx context.Context, ar *domain.Product) error {
 	ret := _m.Called(ctx, ar)
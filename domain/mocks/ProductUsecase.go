@@ -28,29 +28,59 @@ func (_m *ProductUsecase) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ProductUsecase) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Product, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *ProductUsecase) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.Product, domain.PageInfo, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []domain.Product
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Product); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) []domain.Product); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Product)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PageRequest) error); ok {
+		r2 = rf(ctx, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchInCategory provides a mock function with given fields: ctx, categoryID, page
+func (_m *ProductUsecase) FetchInCategory(ctx context.Context, categoryID int64, page domain.PageRequest) ([]domain.Product, domain.PageInfo, error) {
+	ret := _m.Called(ctx, categoryID, page)
+
+	var r0 []domain.Product
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.PageRequest) []domain.Product); ok {
+		r0 = rf(ctx, categoryID, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Product)
+		}
+	}
+
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, categoryID, page)
+	} else {
+		r1 = ret.Get(1).(domain.PageInfo)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int64, domain.PageRequest) error); ok {
+		r2 = rf(ctx, categoryID, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -79,6 +109,34 @@ func (_m *ProductUsecase) GetByID(ctx context.Context, id int64) (domain.Product
 	return r0, r1
 }
 
+// Search provides a mock function with given fields: ctx, query, page
+func (_m *ProductUsecase) Search(ctx context.Context, query string, page domain.PageRequest) (domain.ProductSearchResult, domain.PageInfo, error) {
+	ret := _m.Called(ctx, query, page)
+
+	var r0 domain.ProductSearchResult
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PageRequest) domain.ProductSearchResult); ok {
+		r0 = rf(ctx, query, page)
+	} else {
+		r0 = ret.Get(0).(domain.ProductSearchResult)
+	}
+
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, query, page)
+	} else {
+		r1 = ret.Get(1).(domain.PageInfo)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.PageRequest) error); ok {
+		r2 = rf(ctx, query, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *ProductUsecase) Store(_a0 context.Context, _a1 *domain.Product) error {
 	ret := _m.Called(_a0, _a1)
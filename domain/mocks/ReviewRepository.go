@@ -14,29 +14,29 @@ type ReviewRepository struct {
 	mock.Mock
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ReviewRepository) Fetch(ctx context.Context, cursor string, num int) ([]domain.Review, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *ReviewRepository) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.Review, domain.PageInfo, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []domain.Review
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.Review); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) []domain.Review); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Review)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PageRequest) error); ok {
+		r2 = rf(ctx, page)
 	} else {
 		r2 = ret.Error(2)
 	}
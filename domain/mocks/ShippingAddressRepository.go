@@ -14,53 +14,37 @@ type ShippingAddressRepository struct {
 	mock.Mock
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, id
-func (_m *ShippingAddressRepository) Fetch(ctx context.Context, cursor string, id int) ([]domain.ShippingAddress, string, error) {
-	ret := _m.Called(ctx, cursor, id)
-
-	var r0 []domain.ShippingAddress
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.ShippingAddress); ok {
-		r0 = rf(ctx, cursor, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.ShippingAddress)
-		}
-	}
-
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int) string); ok {
-		r1 = rf(ctx, cursor, id)
-	} else {
-		r1 = ret.Get(1).(string)
-	}
-
-	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int) error); ok {
-		r2 = rf(ctx, cursor, id)
-	} else {
-		r2 = ret.Error(2)
-	}
-
-	return r0, r1, r2
-}
-
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *ShippingAddressRepository) GetByID(ctx context.Context, id int64) (domain.ShippingAddress, error) {
-	ret := _m.Called(ctx, id)
+// GetByOrderID provides a mock function with given fields: ctx, orderID
+func (_m *ShippingAddressRepository) GetByOrderID(ctx context.Context, orderID int64) (domain.ShippingAddress, error) {
+	ret := _m.Called(ctx, orderID)
 
 	var r0 domain.ShippingAddress
 	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.ShippingAddress); ok {
-		r0 = rf(ctx, id)
+		r0 = rf(ctx, orderID)
 	} else {
 		r0 = ret.Get(0).(domain.ShippingAddress)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+		r1 = rf(ctx, orderID)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
+
+// Store provides a mock function with given fields: ctx, address
+func (_m *ShippingAddressRepository) Store(ctx context.Context, address *domain.ShippingAddress) error {
+	ret := _m.Called(ctx, address)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ShippingAddress) error); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
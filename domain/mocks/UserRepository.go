@@ -28,29 +28,29 @@ func (_m *UserRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *UserRepository) Fetch(ctx context.Context, cursor string, num int64) ([]domain.User, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *UserRepository) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.User, domain.PageInfo, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []domain.User
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.User); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) []domain.User); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.User)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PageRequest) error); ok {
+		r2 = rf(ctx, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -58,6 +58,27 @@ func (_m *UserRepository) Fetch(ctx context.Context, cursor string, num int64) (
 	return r0, r1, r2
 }
 
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *UserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *UserRepository) GetByID(ctx context.Context, id int64) (domain.User, error) {
 	ret := _m.Called(ctx, id)
@@ -121,6 +142,20 @@ func (_m *UserRepository) Login(ctx context.Context, username string, password s
 	return r0, r1
 }
 
+// MarkVerified provides a mock function with given fields: ctx, id
+func (_m *UserRepository) MarkVerified(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Register provides a mock function with given fields: ctx, users
 func (_m *UserRepository) Register(ctx context.Context, users *domain.User) error {
 	ret := _m.Called(ctx, users)
@@ -162,3 +197,17 @@ func (_m *UserRepository) Update(ctx context.Context, ar *domain.User) error {
 
 	return r0
 }
+
+// UpdatePassword provides a mock function with given fields: ctx, id, hashedPassword
+func (_m *UserRepository) UpdatePassword(ctx context.Context, id int64, hashedPassword string) error {
+	ret := _m.Called(ctx, id, hashedPassword)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, hashedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -14,6 +14,29 @@ type UserUsecase struct {
 	mock.Mock
 }
 
+// ConfirmMFA provides a mock function with given fields: ctx, userID, code
+func (_m *UserUsecase) ConfirmMFA(ctx context.Context, userID int64, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []string); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateAdmin provides a mock function with given fields: ctx, user
 func (_m *UserUsecase) CreateAdmin(ctx context.Context, user *domain.User) error {
 	ret := _m.Called(ctx, user)
@@ -56,29 +79,50 @@ func (_m *UserUsecase) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *UserUsecase) Fetch(ctx context.Context, cursor string, num int64) ([]domain.User, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// EnrollMFA provides a mock function with given fields: ctx, userID
+func (_m *UserUsecase) EnrollMFA(ctx context.Context, userID int64) (domain.MFAEnrollment, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 domain.MFAEnrollment
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.MFAEnrollment); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(domain.MFAEnrollment)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, page
+func (_m *UserUsecase) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.User, domain.PageInfo, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []domain.User
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.User); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PageRequest) []domain.User); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.User)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(ctx, page)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PageRequest) error); ok {
+		r2 = rf(ctx, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -86,6 +130,43 @@ func (_m *UserUsecase) Fetch(ctx context.Context, cursor string, num int64) ([]d
 	return r0, r1, r2
 }
 
+// FetchSessions provides a mock function with given fields: ctx, userID
+func (_m *UserUsecase) FetchSessions(ctx context.Context, userID int64) ([]domain.RefreshToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []domain.RefreshToken
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.RefreshToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.RefreshToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgotPassword provides a mock function with given fields: ctx, email
+func (_m *UserUsecase) ForgotPassword(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *UserUsecase) GetByID(ctx context.Context, id int64) (domain.User, error) {
 	ret := _m.Called(ctx, id)
@@ -128,20 +209,76 @@ func (_m *UserUsecase) GetByUsername(ctx context.Context, username string) (doma
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, username, password
-func (_m *UserUsecase) Login(ctx context.Context, username string, password string) (domain.User, error) {
-	ret := _m.Called(ctx, username, password)
+// Login provides a mock function with given fields: ctx, username, password, client
+func (_m *UserUsecase) Login(ctx context.Context, username string, password string, client domain.ClientInfo) (domain.LoginResponse, error) {
+	ret := _m.Called(ctx, username, password, client)
 
-	var r0 domain.User
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
-		r0 = rf(ctx, username, password)
+	var r0 domain.LoginResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ClientInfo) domain.LoginResponse); ok {
+		r0 = rf(ctx, username, password, client)
 	} else {
-		r0 = ret.Get(0).(domain.User)
+		r0 = ret.Get(0).(domain.LoginResponse)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, username, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.ClientInfo) error); ok {
+		r1 = rf(ctx, username, password, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginMFA provides a mock function with given fields: ctx, mfaToken, code, client
+func (_m *UserUsecase) LoginMFA(ctx context.Context, mfaToken string, code string, client domain.ClientInfo) (domain.LoginResponse, error) {
+	ret := _m.Called(ctx, mfaToken, code, client)
+
+	var r0 domain.LoginResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ClientInfo) domain.LoginResponse); ok {
+		r0 = rf(ctx, mfaToken, code, client)
+	} else {
+		r0 = ret.Get(0).(domain.LoginResponse)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.ClientInfo) error); ok {
+		r1 = rf(ctx, mfaToken, code, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, refreshToken
+func (_m *UserUsecase) Logout(ctx context.Context, refreshToken string) error {
+	ret := _m.Called(ctx, refreshToken)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refresh provides a mock function with given fields: ctx, refreshToken, client
+func (_m *UserUsecase) Refresh(ctx context.Context, refreshToken string, client domain.ClientInfo) (domain.LoginResponse, error) {
+	ret := _m.Called(ctx, refreshToken, client)
+
+	var r0 domain.LoginResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ClientInfo) domain.LoginResponse); ok {
+		r0 = rf(ctx, refreshToken, client)
+	} else {
+		r0 = ret.Get(0).(domain.LoginResponse)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ClientInfo) error); ok {
+		r1 = rf(ctx, refreshToken, client)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -163,6 +300,48 @@ func (_m *UserUsecase) Register(ctx context.Context, users *domain.User) error {
 	return r0
 }
 
+// ResendVerification provides a mock function with given fields: ctx, userID
+func (_m *UserUsecase) ResendVerification(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *UserUsecase) ResetPassword(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID
+func (_m *UserUsecase) RevokeSession(ctx context.Context, userID int64, sessionID int64) error {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *UserUsecase) Store(_a0 context.Context, _a1 *domain.User) error {
 	ret := _m.Called(_a0, _a1)
@@ -177,6 +356,20 @@ func (_m *UserUsecase) Store(_a0 context.Context, _a1 *domain.User) error {
 	return r0
 }
 
+// Unlock provides a mock function with given fields: ctx, id
+func (_m *UserUsecase) Unlock(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ar
 func (_m *UserUsecase) Update(ctx context.Context, ar *domain.User) error {
 	ret := _m.Called(ctx, ar)
@@ -190,3 +383,38 @@ func (_m *UserUsecase) Update(ctx context.Context, ar *domain.User) error {
 
 	return r0
 }
+
+// UpdateProfile provides a mock function with given fields: ctx, id, username, email
+func (_m *UserUsecase) UpdateProfile(ctx context.Context, id int64, username string, email string) (domain.User, error) {
+	ret := _m.Called(ctx, id, username, email)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) domain.User); ok {
+		r0 = rf(ctx, id, username, email)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, username, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, token
+func (_m *UserUsecase) Verify(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyMismatch will throw if amounts of different currencies are combined
+	ErrCurrencyMismatch = errors.New("amounts of different currencies can not be combined")
+	// ErrInvalidCurrency will throw if a currency is not a supported ISO 4217 code
+	ErrInvalidCurrency = errors.New("unsupported currency")
+)
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+// currencyExponents holds the number of minor unit digits of the supported currencies
+var currencyExponents = map[Currency]int{
+	"AUD": 2,
+	"CNY": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"IDR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"MYR": 2,
+	"PHP": 2,
+	"SGD": 2,
+	"THB": 2,
+	"USD": 2,
+	"VND": 0,
+}
+
+// IsValid reports whether c is a supported currency
+func (c Currency) IsValid() bool {
+	_, ok := currencyExponents[c]
+	return ok
+}
+
+// Exponent is the number of digits after the decimal point, e.g. 2 for cents
+func (c Currency) Exponent() int {
+	return currencyExponents[c]
+}
+
+// RoundingMode decides how a fraction of a minor unit is rounded
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds halves away from zero, as printed on receipts
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds halves to the even neighbour, so repeated
+	// roundings do not drift in one direction
+	RoundHalfEven
+	// RoundDown drops the fraction
+	RoundDown
+)
+
+// Money is an amount in minor units (e.g. cents) of a currency. Amounts of
+// different currencies are never combined, the arithmetic returns
+// ErrCurrencyMismatch instead.
+//
+// In SQL the amount is stored as a BIGINT and the currency in a column of its
+// own, Value and Scan only handle the amount.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// NewMoney returns amount minor units of currency
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// IsPositive reports whether the amount is above zero
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return ErrCurrencyMismatch
+	}
+	return nil
+}
+
+// SameCurrency returns ErrCurrencyMismatch unless all amounts share one
+// currency, and ErrInvalidCurrency when that currency is not supported
+func SameCurrency(ms ...Money) error {
+	if len(ms) > 0 && !ms[0].Currency.IsValid() {
+		return ErrInvalidCurrency
+	}
+	for _, m := range ms {
+		if err := m.sameCurrency(ms[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add returns m + o
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m - o
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp returns -1, 0 or +1 when m is less than, equal to or greater than o
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Mul returns m times n, e.g. the price of n units
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+// MulFrac returns m * num / den rounded to a whole minor unit with mode,
+// e.g. MulFrac(11, 100, RoundHalfUp) for an 11% tax. den must be positive.
+func (m Money) MulFrac(num int64, den int64, mode RoundingMode) Money {
+	if den <= 0 {
+		panic("domain: MulFrac with a denominator that is not positive")
+	}
+	n := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	q, r := new(big.Int).QuoRem(n, big.NewInt(den), new(big.Int))
+	return Money{Amount: roundQuotient(q, r, den, mode), Currency: m.Currency}
+}
+
+// roundQuotient rounds q + r/den, r carries the sign of the dividend
+func roundQuotient(q *big.Int, r *big.Int, den int64, mode RoundingMode) int64 {
+	if r.Sign() == 0 || mode == RoundDown {
+		return q.Int64()
+	}
+	step := int64(r.Sign())
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	switch c := twice.Cmp(big.NewInt(den)); {
+	case c > 0:
+		return q.Int64() + step
+	case c < 0:
+		return q.Int64()
+	}
+	if mode == RoundHalfEven && q.Bit(0) == 0 {
+		return q.Int64()
+	}
+	return q.Int64() + step
+}
+
+// String formats m with the decimals of its currency, e.g. "IDR 15000.00"
+func (m Money) String() string {
+	exp := m.Currency.Exponent()
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	return strings.TrimSpace(string(m.Currency) + " " + sign + digits)
+}
+
+// UnmarshalJSON rejects currencies that are not supported
+func (m *Money) UnmarshalJSON(data []byte) error {
+	type money Money
+	var v money
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if !v.Currency.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, v.Currency)
+	}
+	*m = Money(v)
+	return nil
+}
+
+// Value implements driver.Valuer, only the amount is written
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount, nil
+}
+
+// Scan implements sql.Scanner, only the amount is read and the currency is
+// left for the caller to fill from its own column
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		m.Amount = v
+	case []byte:
+		amount, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return err
+		}
+		m.Amount = amount
+	case string:
+		amount, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		m.Amount = amount
+	case nil:
+		m.Amount = 0
+	default:
+		return fmt.Errorf("domain: can not scan %T into Money", src)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := domain.NewMoney(1050, "USD")
+	b := domain.NewMoney(250, "USD")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(1300, "USD"), sum)
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.True(t, diff.IsNegative())
+
+	c, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c)
+
+	assert.Equal(t, domain.NewMoney(3150, "USD"), a.Mul(3))
+
+	_, err = a.Add(domain.NewMoney(250, "IDR"))
+	assert.Equal(t, domain.ErrCurrencyMismatch, err)
+	_, err = a.Cmp(domain.NewMoney(250, "IDR"))
+	assert.Equal(t, domain.ErrCurrencyMismatch, err)
+}
+
+func TestMoneyMulFrac(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		num    int64
+		den    int64
+		mode   domain.RoundingMode
+		want   int64
+	}{
+		{"exact", 1000, 11, 100, domain.RoundHalfUp, 110},
+		{"half-up", 250, 1, 100, domain.RoundHalfUp, 3},
+		{"half-up-negative", -250, 1, 100, domain.RoundHalfUp, -3},
+		{"half-even-down", 250, 1, 100, domain.RoundHalfEven, 2},
+		{"half-even-up", 350, 1, 100, domain.RoundHalfEven, 4},
+		{"above-half", 260, 1, 100, domain.RoundHalfEven, 3},
+		{"below-half", 240, 1, 100, domain.RoundHalfUp, 2},
+		{"down", 299, 1, 100, domain.RoundDown, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := domain.NewMoney(tt.amount, "USD").MulFrac(tt.num, tt.den, tt.mode)
+			assert.Equal(t, domain.NewMoney(tt.want, "USD"), got)
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	assert.Equal(t, "IDR 15000.00", domain.NewMoney(1500000, "IDR").String())
+	assert.Equal(t, "USD -0.05", domain.NewMoney(-5, "USD").String())
+	assert.Equal(t, "JPY 1200", domain.NewMoney(1200, "JPY").String())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(domain.NewMoney(1500000, "IDR"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":1500000,"currency":"IDR"}`, string(data))
+
+	var m domain.Money
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":250,"currency":"USD"}`), &m))
+	assert.Equal(t, domain.NewMoney(250, "USD"), m)
+
+	err = json.Unmarshal([]byte(`{"amount":250,"currency":"usd"}`), &m)
+	assert.ErrorIs(t, err, domain.ErrInvalidCurrency)
+	err = json.Unmarshal([]byte(`{"amount":2.5,"currency":"USD"}`), &m)
+	assert.Error(t, err)
+}
+
+func TestSameCurrency(t *testing.T) {
+	assert.NoError(t, domain.SameCurrency(domain.NewMoney(1, "IDR"), domain.NewMoney(2, "IDR")))
+	assert.Equal(t, domain.ErrCurrencyMismatch, domain.SameCurrency(domain.NewMoney(1, "IDR"), domain.NewMoney(2, "USD")))
+	assert.Equal(t, domain.ErrInvalidCurrency, domain.SameCurrency(domain.Money{}))
+}
@@ -2,34 +2,93 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrOrderPricesLocked will throw if the prices of an order are changed after
+// it left pending
+var ErrOrderPricesLocked = errors.New("the prices of an order can not change once it is no longer pending")
+
+// Order is placed by a customer, UserID is the owner of the order.
+// All amounts of an order are in the same currency. RemainingBalance is
+// TotalPrice less RefundedTotal and is not stored. ExchangeRate to
+// DisplayCurrency is locked at checkout when the customer browsed in another
+// currency, DisplayTotalPrice is computed from it.
 type Order struct {
-	UserID        User      `json:"user_id" validate:"required"`
-	PayMethod     string    `json:"paymethod" validate:"required"`
-	TaxPrice      float32   `json:"tax_price" validate:"required"`
-	ShippingPrice float32   `json:"shipping_price" validate:"required"`
-	TotalPrice    float32   `json:"total_price" validate:"required"`
-	IsPaid        bool      `json:"is_paid" validate:"required"`
-	IsDelivered   bool      `json:"is_delivered" validate:"required"`
-	PaidAt        time.Time `json:"paid_at" validate:"required"`
-	DeliveredAt   time.Time `json:"delivered_at" validate:"required"`
-	CreatedAt     time.Time `json:"created_at" validate:"required"`
+	ID                int64            `json:"id"`
+	UserID            int64            `json:"user_id"`
+	PayMethod         string           `json:"paymethod" validate:"required"`
+	TaxPrice          Money            `json:"tax_price"`
+	ShippingPrice     Money            `json:"shipping_price"`
+	TotalPrice        Money            `json:"total_price"`
+	RefundedTotal     Money            `json:"refunded_total"`
+	RemainingBalance  Money            `json:"remaining_balance"`
+	DisplayCurrency   Currency         `json:"display_currency,omitempty"`
+	ExchangeRate      Rate             `json:"exchange_rate,omitempty"`
+	DisplayTotalPrice *Money           `json:"display_total_price,omitempty"`
+	Status            OrderStatus      `json:"status"`
+	PaidAt            *time.Time       `json:"paid_at,omitempty"`
+	DeliveredAt       *time.Time       `json:"delivered_at,omitempty"`
+	Items             []OrderItem      `json:"items,omitempty"`
+	ShippingAddress   *ShippingAddress `json:"shipping_address,omitempty"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+	CreatedAt         time.Time        `json:"created_at"`
+}
+
+// LockExchangeRate records the rate the customer was shown at checkout
+func (o *Order) LockExchangeRate(display Currency, rate Rate) {
+	o.DisplayCurrency = display
+	o.ExchangeRate = rate
+	total := rate.Convert(o.TotalPrice, display)
+	o.DisplayTotalPrice = &total
 }
 
+// OrderRepository represent the Order's repository contract
 type OrderRepository interface {
-	Fetch(ctx context.Context, cursor string, num int) ([]Order, string, error)
-	GetByID(ctx context.Context, id int) (Order, error)
+	Fetch(ctx context.Context, page PageRequest) ([]Order, PageInfo, error)
+	FetchByUser(ctx context.Context, userID int64, page PageRequest) ([]Order, PageInfo, error)
+	GetByID(ctx context.Context, id int64) (Order, error)
+	// GetByIDForUpdate locks the order row until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int64) (Order, error)
 	Update(ctx context.Context, updateOrder *Order) error
+	// UpdateStatus saves the status of o only if the stored status is still
+	// from, ErrConflict is returned when another change got there first
+	UpdateStatus(ctx context.Context, o *Order, from OrderStatus) error
+	// AddRefunded adds amount to the refunded total of the order, refusing
+	// with ErrRefundExceedsBalance to refund more than the order total. A
+	// negative amount gives a failed refund back to the balance.
+	AddRefunded(ctx context.Context, id int64, amount Money) error
 	Store(ctx context.Context, createOrder *Order) error
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// OrderListSchema is what orders can be listed by, total_price is in the
+// minor units of the currency of each order
+var OrderListSchema = ListSchema{
+	Fields: []ListField{
+		{Name: "status", Type: FieldString, Filterable: true},
+		{Name: "pay_method", Type: FieldString, Filterable: true},
+		{Name: "total_price", Type: FieldInt, Sortable: true, Filterable: true},
+		{Name: "updated_at", Type: FieldTime, Sortable: true, Filterable: true},
+		{Name: "created_at", Type: FieldTime, Sortable: true, Filterable: true},
+	},
+	DefaultSort: Sort{Field: "created_at"},
 }
 
+// OrderUsecase represent the Order's usecases
 type OrderUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int) ([]Order, string, error)
-	GetByID(ctx context.Context, id int) (Order, error)
+	Fetch(ctx context.Context, page PageRequest) ([]Order, PageInfo, error)
+	FetchByUser(ctx context.Context, userID int64, page PageRequest) ([]Order, PageInfo, error)
+	GetByID(ctx context.Context, id int64) (Order, error)
+	GetUserOrder(ctx context.Context, userID int64, id int64) (Order, error)
+	// Transition moves the order to the given status when the lifecycle allows
+	// it, the authenticated user in ctx is recorded as the author of the change
+	Transition(ctx context.Context, id int64, to OrderStatus, reason string) (Order, error)
+	// CancelUserOrder lets a customer cancel one of their orders while it is still pending
+	CancelUserOrder(ctx context.Context, userID int64, id int64, reason string) (Order, error)
+	FetchHistory(ctx context.Context, id int64) ([]OrderStatusHistory, error)
 	Update(ctx context.Context, updateOrder *Order) error
 	Store(ctx context.Context, createOrder *Order) error
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int64) error
 }
@@ -2,17 +2,42 @@ package domain
 
 import "context"
 
+// OrderItem is a line of an order, Name, Image, Price and TaxCategory are
+// copied from the product, or its variant, when the order is placed. TaxRate, TaxInclusive and
+// TaxAmount are the tax of the whole line as computed at checkout.
 type OrderItem struct {
-	ID        int64   `json:"id" verified:"required"`
-	ProductID Product `json:"product_id" verified:"required"`
-	OrderID   Order   `json:"order_id" verified:"required"`
-	Name      string  `json:"name" verified:"required"`
-	Qty       int     `json:"qty" verified:"required"`
-	Price     float32 `json:"price" verified:"required"`
-	Image     string  `json:"image" verified:"required"`
+	ID           int64       `json:"id"`
+	OrderID      int64       `json:"order_id"`
+	ProductID    int64       `json:"product_id"`
+	VariantID    int64       `json:"variant_id,omitempty"`
+	SKU          string      `json:"sku,omitempty"`
+	Name         string      `json:"name"`
+	Qty          int         `json:"qty"`
+	Price        Money       `json:"price"`
+	Image        string      `json:"image"`
+	TaxCategory  TaxCategory `json:"tax_category"`
+	TaxRate      int64       `json:"tax_rate"`
+	TaxInclusive bool        `json:"tax_inclusive"`
+	TaxAmount    Money       `json:"tax_amount"`
 }
 
+// Subtotal is the price of the whole line
+func (i OrderItem) Subtotal() Money {
+	return i.Price.Mul(int64(i.Qty))
+}
+
+// Charged is what the customer paid for the first qty units of the line, the
+// share of an exclusive tax included. Charged(Qty) is the whole line.
+func (i OrderItem) Charged(qty int) Money {
+	res := i.Price.Mul(int64(qty))
+	if !i.TaxInclusive && i.Qty > 0 {
+		res.Amount += i.TaxAmount.MulFrac(int64(qty), int64(i.Qty), RoundHalfUp).Amount
+	}
+	return res
+}
+
+// OrderItemRepository represent the OrderItem's repository contract
 type OrderItemRepository interface {
-	Fetch(ctx context.Context, cursor string, id int64) ([]OrderItem, string, error)
-	GetByID(ctx context.Context, id int64) (OrderItem, error)
+	FetchByOrder(ctx context.Context, orderID int64) ([]OrderItem, error)
+	Store(ctx context.Context, item *OrderItem) error
 }
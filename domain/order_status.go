@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition will throw if an order can not move to the requested status
+var ErrInvalidTransition = errors.New("invalid order status transition")
+
+// OrderStatus is the step of its lifecycle an order is in
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusReturned  OrderStatus = "returned"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+// TransitionError tells which move was refused, errors.Is(err, ErrInvalidTransition) holds for it
+type TransitionError struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: order is %s and can not become %s", ErrInvalidTransition.Error(), e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// OrderStatusHistory records a status change of an order. ChangedBy is 0 when
+// the change was not made by a user, e.g. by a payment notification.
+type OrderStatusHistory struct {
+	ID         int64       `json:"id"`
+	OrderID    int64       `json:"order_id"`
+	FromStatus OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus `json:"to_status"`
+	ChangedBy  int64       `json:"changed_by,omitempty"`
+	Reason     string      `json:"reason,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// OrderStatusHistoryRepository represent the OrderStatusHistory's repository contract
+type OrderStatusHistoryRepository interface {
+	FetchByOrder(ctx context.Context, orderID int64) ([]OrderStatusHistory, error)
+	Store(ctx context.Context, h *OrderStatusHistory) error
+}
@@ -0,0 +1,32 @@
+package domain
+
+// Cursor is a position in a list ordered by Sort with the id breaking ties, so
+// rows sharing a key are neither skipped nor repeated. Clients only ever see
+// it signed and encoded by the pagination package.
+type Cursor struct {
+	// Sort is the order of the list the cursor was issued for
+	Sort Sort
+	// Key is the value of the sort field at the position, typed as the field,
+	// see FieldType
+	Key interface{}
+	ID  int64
+	// Backward asks for the rows before the position instead of after it
+	Backward bool
+}
+
+// PageRequest asks for at most Limit rows from Cursor, the first page when
+// Cursor is nil, of the list matching Filters in the order of Sort. The zero
+// Sort is the default order of the list.
+type PageRequest struct {
+	Cursor  *Cursor
+	Limit   int64
+	Sort    Sort
+	Filters []Filter
+}
+
+// PageInfo holds the cursors of the pages around a page, nil when there is no
+// such page
+type PageInfo struct {
+	Next *Cursor
+	Prev *Cursor
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PasswordReset is a single-use token sent to reset the password of a user
+type PasswordReset struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	TokenHash string    `json:"-"`
+	IsUsed    bool      `json:"is_used"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PasswordResetRepository represent the PasswordReset's repository contract
+type PasswordResetRepository interface {
+	Store(ctx context.Context, r *PasswordReset) error
+	GetByHash(ctx context.Context, tokenHash string) (PasswordReset, error)
+	MarkUsed(ctx context.Context, id int64) error
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidSignature will throw if a payment notification is not signed by the provider
+var ErrInvalidSignature = errors.New("invalid payment notification signature")
+
+// PaymentStatus is the state of a payment attempt
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	// PaymentStatusReview means the provider reported another amount than the
+	// one asked for, the order is not marked paid until someone looks at it
+	PaymentStatusReview PaymentStatus = "review"
+)
+
+// PaymentEventType is what a provider notifies about a payment
+type PaymentEventType string
+
+const (
+	// PaymentEventAuthorized means the funds are held and must still be captured
+	PaymentEventAuthorized PaymentEventType = "payment.authorized"
+	// PaymentEventSucceeded means the funds are captured
+	PaymentEventSucceeded PaymentEventType = "payment.succeeded"
+	PaymentEventFailed    PaymentEventType = "payment.failed"
+)
+
+// Payment is an attempt to pay an order through a provider. ClientSecret is
+// only known right after the attempt is created and is never stored.
+type Payment struct {
+	ID            int64         `json:"id"`
+	OrderID       int64         `json:"order_id"`
+	Provider      string        `json:"provider"`
+	ProviderRef   string        `json:"provider_ref"`
+	Amount        Money         `json:"amount"`
+	Status        PaymentStatus `json:"status"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+	ClientSecret  string        `json:"client_secret,omitempty"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// PaymentIntent is what the provider hands back for a new payment, the
+// client uses ClientSecret to complete the payment with the provider
+type PaymentIntent struct {
+	ProviderRef  string
+	ClientSecret string
+}
+
+// PaymentEvent is a verified notification of the provider
+type PaymentEvent struct {
+	ID            string           `json:"id"`
+	Type          PaymentEventType `json:"type"`
+	ProviderRef   string           `json:"provider_ref"`
+	Amount        Money            `json:"amount"`
+	FailureReason string           `json:"failure_reason,omitempty"`
+}
+
+// PaymentProvider represent the contract of a payment gateway
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, orderID int64, amount Money) (PaymentIntent, error)
+	Capture(ctx context.Context, providerRef string) error
+	// Refund returns the reference of the refund at the provider
+	Refund(ctx context.Context, providerRef string, amount Money) (string, error)
+	// VerifyWebhook checks the signature of a notification and decodes it,
+	// ErrInvalidSignature is returned when it was not sent by the provider
+	VerifyWebhook(payload []byte, header http.Header) (PaymentEvent, error)
+}
+
+// PaymentRepository represent the Payment's repository contract
+type PaymentRepository interface {
+	GetByProviderRef(ctx context.Context, provider string, providerRef string) (Payment, error)
+	FetchByOrder(ctx context.Context, orderID int64) ([]Payment, error)
+	Store(ctx context.Context, p *Payment) error
+	// UpdateStatus saves the status of p only if the stored status is still
+	// from, ErrConflict is returned when another change got there first
+	UpdateStatus(ctx context.Context, p *Payment, from PaymentStatus) error
+}
+
+// PaymentUsecase represent the Payment's usecases
+type PaymentUsecase interface {
+	// Pay starts a payment attempt for a pending order of the user
+	Pay(ctx context.Context, userID int64, orderID int64) (Payment, error)
+	FetchByOrder(ctx context.Context, orderID int64) ([]Payment, error)
+	// HandleWebhook applies a provider notification. Notifications already
+	// applied are accepted and ignored so retried callbacks are harmless.
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+}
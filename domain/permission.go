@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrForbidden will throw if the authenticated user is not allowed to do the action
+var ErrForbidden = errors.New("you are not allowed to perform this action")
+
+// Permission is a single action a role can be granted
+type Permission string
+
+const (
+	// PermissionAll grants every permission
+	PermissionAll Permission = "*"
+
+	PermissionUserRead    Permission = "user:read"
+	PermissionUserWrite   Permission = "user:write"
+	PermissionUserDelete  Permission = "user:delete"
+	PermissionUserUnlock  Permission = "user:unlock"
+	PermissionAdminCreate Permission = "admin:create"
+	PermissionStaffCreate Permission = "staff:create"
+
+	PermissionProductWrite  Permission = "product:write"
+	PermissionProductDelete Permission = "product:delete"
+	PermissionCategoryWrite Permission = "category:write"
+
+	PermissionOrderRead   Permission = "order:read"
+	PermissionOrderWrite  Permission = "order:write"
+	PermissionOrderDelete Permission = "order:delete"
+	PermissionOrderShip   Permission = "order:ship"
+	PermissionOrderRefund Permission = "order:refund"
+
+	PermissionExchangeRateWrite Permission = "exchange_rate:write"
+	PermissionTaxWrite          Permission = "tax:write"
+)
+
+// RolePermissions maps every role to the set of permissions it is granted
+type RolePermissions map[RolesType]map[Permission]bool
+
+// DefaultRolePermissions is used when no permissions are configured
+func DefaultRolePermissions() RolePermissions {
+	return RolePermissions{
+		RolesTypeSuperadmin: {PermissionAll: true},
+		RolesTypeAdmin: {
+			PermissionUserRead:          true,
+			PermissionUserWrite:         true,
+			PermissionUserDelete:        true,
+			PermissionUserUnlock:        true,
+			PermissionStaffCreate:       true,
+			PermissionProductWrite:      true,
+			PermissionProductDelete:     true,
+			PermissionCategoryWrite:     true,
+			PermissionOrderRead:         true,
+			PermissionOrderWrite:        true,
+			PermissionOrderDelete:       true,
+			PermissionOrderShip:         true,
+			PermissionOrderRefund:       true,
+			PermissionExchangeRateWrite: true,
+			PermissionTaxWrite:          true,
+		},
+		RolesTypeSuperstaff: {
+			PermissionUserRead:     true,
+			PermissionStaffCreate:  true,
+			PermissionProductWrite: true,
+			PermissionOrderRead:    true,
+			PermissionOrderWrite:   true,
+			PermissionOrderShip:    true,
+			PermissionOrderRefund:  true,
+		},
+		RolesTypeStaff: {
+			PermissionUserRead:     true,
+			PermissionProductWrite: true,
+			PermissionOrderRead:    true,
+			PermissionOrderWrite:   true,
+			PermissionOrderShip:    true,
+		},
+		RolesTypeUser: {},
+	}
+}
+
+// NewRolePermissions builds RolePermissions from the role -> permissions map in config.json
+func NewRolePermissions(config map[string][]string) (RolePermissions, error) {
+	res := RolePermissions{}
+	for role, perms := range config {
+		r := RolesType(role)
+		if !r.IsValid() {
+			return nil, fmt.Errorf("unknown role %q in permissions config", role)
+		}
+		res[r] = make(map[Permission]bool, len(perms))
+		for _, p := range perms {
+			res[r][Permission(p)] = true
+		}
+	}
+	return res, nil
+}
+
+// Has reports whether role is granted the given permission
+func (rp RolePermissions) Has(role RolesType, perm Permission) bool {
+	perms, ok := rp[role]
+	if !ok {
+		return false
+	}
+	return perms[PermissionAll] || perms[perm]
+}
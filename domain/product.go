@@ -5,32 +5,88 @@ import (
 	"time"
 )
 
+// Product is an item of the catalog, UserID is the staff account that created it.
+// DisplayPrice is Price converted to the currency asked by the client.
 type Product struct {
-	ID           int64     `json:"id" `
-	UserID       User      `json:"user_id"`
-	Image        string    `json:"image" validate:"required"`
-	Name         string    `json:"name" validate:"required"`
-	Brand        string    `json:"brand" validate:"required"`
-	Category     string    `json:"category" validate:"required"`
-	Description  string    `json:"description" validate:"required"`
-	Rating       int       `json:"rating" validate:"required"`
-	NumReviews   int       `json:"num_reviews" validate:"required"`
-	Price        int       `json:"price" validate:"required"`
-	CountInStock int       `json:"count_in_stock" validate:"required"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           int64       `json:"id" `
+	UserID       int64       `json:"user_id"`
+	Image        string      `json:"image" validate:"required"`
+	Name         string      `json:"name" validate:"required"`
+	Brand        string      `json:"brand" validate:"required"`
+	CategoryID   int64       `json:"category_id" validate:"required"`
+	Description  string      `json:"description" validate:"required"`
+	Rating       int         `json:"rating" validate:"required"`
+	NumReviews   int         `json:"num_reviews" validate:"required"`
+	Price        Money       `json:"price"`
+	DisplayPrice *Money      `json:"display_price,omitempty"`
+	TaxCategory  TaxCategory `json:"tax_category"`
+	CountInStock int         `json:"count_in_stock" validate:"required"`
+	// WeightGrams and the dimensions in millimetres of one unit, packed,
+	// are what shipping is priced on
+	WeightGrams int `json:"weight_grams"`
+	LengthMM    int `json:"length_mm"`
+	WidthMM     int `json:"width_mm"`
+	HeightMM    int `json:"height_mm"`
+	// Options and Variants are only filled when a single product is read,
+	// CountInStock of a product with variants is the sum of theirs
+	Options   []ProductOption `json:"options,omitempty"`
+	Variants  []Variant       `json:"variants,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	CreatedAt time.Time       `json:"created_at"`
 }
 
+// ProductListSchema is what the catalog can be listed by, price is in the
+// minor units of the currency of each product
+var ProductListSchema = ListSchema{
+	Fields: []ListField{
+		{Name: "name", Type: FieldString, Sortable: true},
+		{Name: "brand", Type: FieldString, Filterable: true},
+		{Name: "category_id", Type: FieldInt, Filterable: true},
+		{Name: "price", Type: FieldInt, Sortable: true, Filterable: true},
+		{Name: "rating", Type: FieldInt, Sortable: true, Filterable: true},
+		{Name: "updated_at", Type: FieldTime, Sortable: true, Filterable: true},
+		{Name: "created_at", Type: FieldTime, Sortable: true, Filterable: true},
+	},
+	DefaultSort: Sort{Field: "created_at"},
+}
+
+// ProductUsecase represent the Product's usecases
 type ProductUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]Product, string, error)
+	Fetch(ctx context.Context, page PageRequest) ([]Product, PageInfo, error)
+	// FetchInCategory lists the products of the category and of all its
+	// descendants, ErrNotFound is returned for an unknown category
+	FetchInCategory(ctx context.Context, categoryID int64, page PageRequest) ([]Product, PageInfo, error)
+	// Search returns the products matching query, with the facets of the
+	// search, ErrBadParamInput is returned for an empty query
+	Search(ctx context.Context, query string, page PageRequest) (ProductSearchResult, PageInfo, error)
 	GetByID(ctx context.Context, id int64) (Product, error)
 	Update(ctx context.Context, ar *Product) error
 	Store(context.Context, *Product) error
 	Delete(ctx context.Context, id int64) error
 }
 
+// ProductRepository represent the Product's repository contract
 type ProductRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []Product, nextCursor string, err error)
+	Fetch(ctx context.Context, page PageRequest) (res []Product, info PageInfo, err error)
+	// FetchInCategory pages the products of root and its descendants
+	FetchInCategory(ctx context.Context, root Category, page PageRequest) (res []Product, info PageInfo, err error)
+	// Search pages the products matching query in full text, page is on the
+	// fields of ProductSearchSchema
+	Search(ctx context.Context, query string, page PageRequest) (res []Product, info PageInfo, err error)
+	// Facets counts the products matching query and filters, priceBounds
+	// split the prices into buckets
+	Facets(ctx context.Context, query string, filters []Filter, priceBounds []int64) (ProductFacets, error)
+	// Terms returns the distinct names and brands of the catalog and the
+	// names of its categories, a large catalog is cut to its newest products
+	Terms(ctx context.Context) ([]string, error)
 	GetByID(ctx context.Context, id int64) (Product, error)
+	// GetByIDForUpdate locks the product row until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int64) (Product, error)
+	// DecrementStock takes qty out of the stock, ErrInsufficientStock is returned when there is not enough
+	DecrementStock(ctx context.Context, id int64, qty int) error
+	// IncrementStock puts qty back into the stock, e.g. when returned items are
+	// refunded. ErrNotFound is returned when the product no longer exists.
+	IncrementStock(ctx context.Context, id int64, qty int) error
 	Update(ctx context.Context, ar *Product) error
 	Store(ctx context.Context, a *Product) error
 	Delete(ctx context.Context, id int64) error
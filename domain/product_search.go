@@ -0,0 +1,53 @@
+package domain
+
+// ProductSearchSchema is what catalog searches can be filtered and sorted by.
+// relevance ranks the products on how well they match the search, it is the
+// default order.
+var ProductSearchSchema = ListSchema{
+	Fields: []ListField{
+		{Name: "relevance", Type: FieldInt, Sortable: true},
+		{Name: "name", Type: FieldString, Sortable: true},
+		{Name: "brand", Type: FieldString, Filterable: true},
+		{Name: "category_id", Type: FieldInt, Filterable: true},
+		{Name: "price", Type: FieldInt, Sortable: true, Filterable: true},
+		{Name: "rating", Type: FieldInt, Sortable: true, Filterable: true},
+		{Name: "in_stock", Type: FieldBool, Filterable: true},
+		{Name: "created_at", Type: FieldTime, Sortable: true, Filterable: true},
+	},
+	DefaultSort: Sort{Field: "relevance", Desc: true},
+}
+
+// FacetCount is how many products of a search have a value, e.g. a brand.
+// ID is the id of the value when it is an entity, e.g. a category, and is
+// what the search is filtered by.
+type FacetCount struct {
+	ID    int64  `json:"id,omitempty"`
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// PriceBucket is how many products of a search cost at least Min and less
+// than Max, in minor units. The last bucket has no Max.
+type PriceBucket struct {
+	Min   int64  `json:"min"`
+	Max   *int64 `json:"max,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// ProductFacets break the products of a search down by brand, category and
+// price. Each facet is counted without its own filter, so that the other
+// values can still be picked.
+type ProductFacets struct {
+	Brands     []FacetCount  `json:"brands"`
+	Categories []FacetCount  `json:"categories"`
+	Prices     []PriceBucket `json:"prices"`
+}
+
+// ProductSearchResult is a page of a catalog search
+type ProductSearchResult struct {
+	Products []Product     `json:"products"`
+	Facets   ProductFacets `json:"facets"`
+	// Suggestions are spellings of the search made of catalog terms, given
+	// when nothing matched
+	Suggestions []string `json:"suggestions"`
+}
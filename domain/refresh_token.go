@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshToken is a server-side refresh token. Every rotation creates a new row
+// in the same family, so a family represents one login session of a user.
+type RefreshToken struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	FamilyID   string    `json:"-"`
+	TokenHash  string    `json:"-"`
+	Device     string    `json:"device"`
+	ClientIP   string    `json:"ip"`
+	IsUsed     bool      `json:"-"`
+	IsRevoked  bool      `json:"-"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ClientInfo describe the client a session is issued to
+type ClientInfo struct {
+	Device string
+	IP     string
+}
+
+// RefreshTokenRepository represent the RefreshToken's repository contract
+type RefreshTokenRepository interface {
+	Store(ctx context.Context, t *RefreshToken) error
+	GetByID(ctx context.Context, id int64) (RefreshToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	FetchActiveByUser(ctx context.Context, userID int64) ([]RefreshToken, error)
+	MarkUsed(ctx context.Context, id int64) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeByUser(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRefundExceedsBalance will throw if a refund is larger than what is left to refund on the order
+var ErrRefundExceedsBalance = errors.New("refund exceeds the remaining balance of the order")
+
+// RefundReason is the reason code recorded with every refund
+type RefundReason string
+
+const (
+	RefundReasonCustomerRequest RefundReason = "customer_request"
+	RefundReasonDamaged         RefundReason = "damaged"
+	RefundReasonWrongItem       RefundReason = "wrong_item"
+	RefundReasonNotDelivered    RefundReason = "not_delivered"
+	RefundReasonOther           RefundReason = "other"
+)
+
+// IsValid reports whether r is one of the known reason codes
+func (r RefundReason) IsValid() bool {
+	switch r {
+	case RefundReasonCustomerRequest, RefundReasonDamaged, RefundReasonWrongItem, RefundReasonNotDelivered, RefundReasonOther:
+		return true
+	}
+	return false
+}
+
+// RefundStatus is the state of a refund at the payment provider
+type RefundStatus string
+
+const (
+	// RefundStatusPending means the refund is recorded and the provider was not told yet
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	// RefundStatusFailed means the provider refused the refund, it can be
+	// retried. Its amount is not part of the refunded total of the order but
+	// its items are still counted as refunded.
+	RefundStatusFailed RefundStatus = "failed"
+)
+
+// Refund gives money of a paid order back. PaymentID and ProviderRef are
+// empty when the order was not paid through a payment provider and the money
+// is returned by hand.
+type Refund struct {
+	ID            int64        `json:"id"`
+	OrderID       int64        `json:"order_id"`
+	PaymentID     int64        `json:"payment_id,omitempty"`
+	ProviderRef   string       `json:"provider_ref,omitempty"`
+	Status        RefundStatus `json:"status"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	Amount        Money        `json:"amount"`
+	Reason        RefundReason `json:"reason"`
+	Note          string       `json:"note,omitempty"`
+	CreatedBy     int64        `json:"created_by"`
+	Items         []RefundItem `json:"items,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// RefundItem is the part of an order line a refund covers
+type RefundItem struct {
+	ID          int64 `json:"id"`
+	RefundID    int64 `json:"refund_id"`
+	OrderItemID int64 `json:"order_item_id"`
+	Qty         int   `json:"qty"`
+	Amount      Money `json:"amount"`
+	Restocked   bool  `json:"restocked"`
+}
+
+// RefundRequest asks for a refund either of order lines, of a plain amount,
+// or, when both are left empty, of everything not refunded yet
+type RefundRequest struct {
+	Items  []RefundItemRequest
+	Amount Money
+	Reason RefundReason
+	Note   string
+}
+
+// RefundItemRequest refunds Qty units of an order line, Restock puts them
+// back into the stock of the product when they were returned
+type RefundItemRequest struct {
+	OrderItemID int64
+	Qty         int
+	Restock     bool
+}
+
+// RefundRepository represent the Refund's repository contract
+type RefundRepository interface {
+	// FetchByOrder returns the refunds of an order with their items
+	FetchByOrder(ctx context.Context, orderID int64) ([]Refund, error)
+	GetByID(ctx context.Context, id int64) (Refund, error)
+	// Store saves the refund together with its items
+	Store(ctx context.Context, r *Refund) error
+	// UpdateStatus saves the status of r only if the stored status is still
+	// from, ErrConflict is returned when another change got there first
+	UpdateStatus(ctx context.Context, r *Refund, from RefundStatus) error
+}
+
+// RefundUsecase represent the Refund's usecases
+type RefundUsecase interface {
+	Refund(ctx context.Context, orderID int64, req RefundRequest) (Refund, error)
+	// Retry asks the payment provider again for a refund that is still
+	// pending or failed
+	Retry(ctx context.Context, orderID int64, id int64) (Refund, error)
+	FetchByOrder(ctx context.Context, orderID int64) ([]Refund, error)
+}
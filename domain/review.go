@@ -16,6 +16,6 @@ type Review struct {
 }
 
 type ReviewRepository interface {
-	Fetch(ctx context.Context, cursor string, num int) ([]Review, string, error)
+	Fetch(ctx context.Context, page PageRequest) ([]Review, PageInfo, error)
 	GetByID(ctx context.Context, id int64) (Review, error)
 }
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrShippingUnavailable will throw if no shipping method delivers the parcel to the address
+var ErrShippingUnavailable = errors.New("the requested shipping method is not available for this address")
+
+// ShippingMethod is how an order travels to the customer
+type ShippingMethod string
+
+const (
+	ShippingMethodStandard ShippingMethod = "standard"
+	ShippingMethodExpress  ShippingMethod = "express"
+	// ShippingMethodPickup is collected by the customer, it is usually free
+	ShippingMethodPickup ShippingMethod = "pickup"
+)
+
+// IsValid reports whether m is a known shipping method
+func (m ShippingMethod) IsValid() bool {
+	switch m {
+	case ShippingMethodStandard, ShippingMethodExpress, ShippingMethodPickup:
+		return true
+	}
+	return false
+}
+
+// Parcel is what is shipped for an order, weights are in grams and sizes in
+// millimetres. Value is the price of the goods, free shipping thresholds are
+// compared against it.
+type Parcel struct {
+	WeightGrams int64 `json:"weight_grams"`
+	VolumeMM3   int64 `json:"volume_mm3"`
+	// LongestMM is the longest side of the largest item
+	LongestMM int64 `json:"longest_mm"`
+	Value     Money `json:"value"`
+}
+
+// Add puts qty units of p, each worth price, into the parcel. price is what
+// the unit is charged, which differs from p.Price for a variant.
+// ErrCurrencyMismatch is returned when price is in another currency than
+// what is already in the parcel.
+func (pc *Parcel) Add(p Product, price Money, qty int) error {
+	value := price.Mul(int64(qty))
+	if pc.Value.Currency != "" {
+		var err error
+		if value, err = pc.Value.Add(value); err != nil {
+			return err
+		}
+	}
+	pc.Value = value
+	pc.WeightGrams += int64(p.WeightGrams) * int64(qty)
+	pc.VolumeMM3 += int64(p.LengthMM) * int64(p.WidthMM) * int64(p.HeightMM) * int64(qty)
+	for _, side := range []int{p.LengthMM, p.WidthMM, p.HeightMM} {
+		if int64(side) > pc.LongestMM {
+			pc.LongestMM = int64(side)
+		}
+	}
+	return nil
+}
+
+// ShippingQuote is the price of shipping a parcel with one method of a carrier
+type ShippingQuote struct {
+	Carrier       string         `json:"carrier"`
+	Method        ShippingMethod `json:"method"`
+	Service       string         `json:"service"`
+	Price         Money          `json:"price"`
+	EstimatedDays int            `json:"estimated_days"`
+}
+
+// ShippingCarrier prices parcels, it is implemented by the local rate table
+// and by the integrations of the carriers
+type ShippingCarrier interface {
+	// Quote lists the methods delivering parcel to address, priced in the
+	// currency of the parcel value. An empty list means nothing ships there.
+	Quote(ctx context.Context, address ShippingAddress, parcel Parcel) ([]ShippingQuote, error)
+}
+
+// ShippingUsecase represent the shipping price calculation
+type ShippingUsecase interface {
+	// Quote lists the methods available for the products and quantities of
+	// items shipped to address
+	Quote(ctx context.Context, address ShippingAddress, items []CartItem) ([]ShippingQuote, error)
+	// Price returns the cheapest quote of method for parcel, it returns
+	// ErrShippingUnavailable when method does not deliver to address
+	Price(ctx context.Context, address ShippingAddress, parcel Parcel, method ShippingMethod) (ShippingQuote, error)
+}
@@ -2,17 +2,21 @@ package domain
 
 import "context"
 
+// ShippingAddress is where an order is delivered to and with which method,
+// ShippingPrice is computed by the server at checkout
 type ShippingAddress struct {
-	ID            int64   `json:"id" verified:"required"`
-	OderID        Order   `json:"order_id" verified:"required"`
-	Address       string  `json:"string" verified:"required"`
-	City          string  `json:"city" verified:"required"`
-	PostalCode    int     `json:"postal_code" verified:"required"`
-	Country       string  `json:"country" verified:"required"`
-	ShippingPrice float32 `json:"shipping_price" verified:"required"`
+	ID             int64          `json:"id"`
+	OrderID        int64          `json:"order_id"`
+	Address        string         `json:"address" validate:"required"`
+	City           string         `json:"city" validate:"required"`
+	PostalCode     string         `json:"postal_code" validate:"required"`
+	Country        string         `json:"country" validate:"required"`
+	ShippingMethod ShippingMethod `json:"shipping_method"`
+	ShippingPrice  Money          `json:"shipping_price"`
 }
 
+// ShippingAddressRepository represent the ShippingAddress's repository contract
 type ShippingAddressRepository interface {
-	Fetch(ctx context.Context, cursor string, id int) ([]ShippingAddress, string, error)
-	GetByID(ctx context.Context, id int64) (ShippingAddress, error)
+	GetByOrderID(ctx context.Context, orderID int64) (ShippingAddress, error)
+	Store(ctx context.Context, address *ShippingAddress) error
 }
@@ -0,0 +1,32 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParcelAdd(t *testing.T) {
+	var p domain.Parcel
+	shirt := domain.Product{Price: domain.NewMoney(15000000, "IDR"), WeightGrams: 250, LengthMM: 300, WidthMM: 250, HeightMM: 20}
+	bag := domain.Product{Price: domain.NewMoney(20000000, "IDR"), WeightGrams: 600, LengthMM: 400, WidthMM: 300, HeightMM: 50}
+	require.NoError(t, p.Add(shirt, shirt.Price, 2))
+	// a variant of the bag is charged more than the bag
+	require.NoError(t, p.Add(bag, domain.NewMoney(25000000, "IDR"), 1))
+	assert.Equal(t, domain.Parcel{
+		WeightGrams: 1100,
+		VolumeMM3:   2*300*250*20 + 400*300*50,
+		LongestMM:   400,
+		Value:       domain.NewMoney(55000000, "IDR"),
+	}, p)
+
+	err := p.Add(domain.Product{}, domain.NewMoney(1000, "USD"), 1)
+	assert.Equal(t, domain.ErrCurrencyMismatch, err)
+}
+
+func TestShippingMethodIsValid(t *testing.T) {
+	assert.True(t, domain.ShippingMethodPickup.IsValid())
+	assert.False(t, domain.ShippingMethod("drone").IsValid())
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInsufficientStock will throw if a product has less stock than requested
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// StockShortage is a line that can not be fulfilled
+type StockShortage struct {
+	ProductID int64  `json:"product_id"`
+	VariantID int64  `json:"variant_id,omitempty"`
+	Name      string `json:"name"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+// StockError lists every line short of stock, errors.Is(err, ErrInsufficientStock) holds for it
+type StockError struct {
+	Shortages []StockShortage
+}
+
+func (e *StockError) Error() string {
+	lines := make([]string, 0, len(e.Shortages))
+	for _, s := range e.Shortages {
+		lines = append(lines, fmt.Sprintf("product %d: requested %d, available %d", s.ProductID, s.Requested, s.Available))
+	}
+	return ErrInsufficientStock.Error() + ": " + strings.Join(lines, "; ")
+}
+
+func (e *StockError) Unwrap() error {
+	return ErrInsufficientStock
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// TaxRateScale is the denominator of TaxRule.Rate, rates are in basis points
+const TaxRateScale = 10000
+
+// TaxCategory groups the products taxed alike, e.g. food at a reduced rate
+type TaxCategory string
+
+// TaxCategoryStandard is the category of products without a category of their own
+const TaxCategoryStandard TaxCategory = "standard"
+
+// TaxRule is the tax rate of a category of products shipped to a country,
+// optionally limited to the postal codes starting with PostalPrefix. A rule
+// without Category applies to the categories without a rule of their own.
+// Inclusive rules treat prices as already containing the tax, exclusive ones
+// add the tax on top of the price.
+type TaxRule struct {
+	ID           int64       `json:"id"`
+	Name         string      `json:"name"`
+	Country      string      `json:"country"`
+	PostalPrefix string      `json:"postal_prefix"`
+	Category     TaxCategory `json:"category"`
+	// Rate is in basis points, 1100 is 11%
+	Rate      int64     `json:"rate"`
+	Inclusive bool      `json:"inclusive"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches reports whether r applies to a product of category shipped to address
+func (r TaxRule) Matches(address ShippingAddress, category TaxCategory) bool {
+	return strings.EqualFold(strings.TrimSpace(r.Country), strings.TrimSpace(address.Country)) &&
+		strings.HasPrefix(address.PostalCode, r.PostalPrefix) &&
+		(r.Category == "" || r.Category == category)
+}
+
+// specificity ranks matching rules, a longer postal prefix wins over a
+// category of its own
+func (r TaxRule) specificity() int {
+	res := len(r.PostalPrefix) * 2
+	if r.Category != "" {
+		res++
+	}
+	return res
+}
+
+// MoreSpecific reports whether r is preferred over o when both match
+func (r TaxRule) MoreSpecific(o TaxRule) bool {
+	if r.specificity() != o.specificity() {
+		return r.specificity() > o.specificity()
+	}
+	return r.ID < o.ID
+}
+
+// Tax returns the tax of amount rounded half up, the part of amount that is
+// tax for an inclusive rule
+func (r TaxRule) Tax(amount Money) Money {
+	if r.Inclusive {
+		return amount.MulFrac(r.Rate, TaxRateScale+r.Rate, RoundHalfUp)
+	}
+	return amount.MulFrac(r.Rate, TaxRateScale, RoundHalfUp)
+}
+
+// TaxRuleRepository represent the TaxRule's repository contract
+type TaxRuleRepository interface {
+	Fetch(ctx context.Context) ([]TaxRule, error)
+	FetchByCountry(ctx context.Context, country string) ([]TaxRule, error)
+	GetByID(ctx context.Context, id int64) (TaxRule, error)
+	Store(ctx context.Context, r *TaxRule) error
+	Update(ctx context.Context, r *TaxRule) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// TaxUsecase represent the tax engine and the management of its rules
+type TaxUsecase interface {
+	Fetch(ctx context.Context) ([]TaxRule, error)
+	GetByID(ctx context.Context, id int64) (TaxRule, error)
+	Store(ctx context.Context, r *TaxRule) error
+	Update(ctx context.Context, r *TaxRule) error
+	Delete(ctx context.Context, id int64) error
+	// Apply fills the tax of every item shipped to address from the most
+	// specific matching rule, items without a rule are not taxed
+	Apply(ctx context.Context, address ShippingAddress, items []OrderItem) error
+}
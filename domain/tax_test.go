@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxRuleTax(t *testing.T) {
+	exclusive := domain.TaxRule{Rate: 1100}
+	assert.Equal(t, domain.NewMoney(1650000, "IDR"), exclusive.Tax(domain.NewMoney(15000000, "IDR")))
+
+	inclusive := domain.TaxRule{Rate: 2000, Inclusive: true}
+	assert.Equal(t, domain.NewMoney(2000, "EUR"), inclusive.Tax(domain.NewMoney(12000, "EUR")))
+}
+
+func TestTaxRuleMatches(t *testing.T) {
+	address := domain.ShippingAddress{Country: "Indonesia", PostalCode: "29432"}
+	country := domain.TaxRule{ID: 1, Country: "indonesia"}
+	food := domain.TaxRule{ID: 2, Country: "Indonesia", Category: "food"}
+	batam := domain.TaxRule{ID: 3, Country: "Indonesia", PostalPrefix: "29"}
+
+	assert.True(t, country.Matches(address, "food"))
+	assert.True(t, food.Matches(address, "food"))
+	assert.False(t, food.Matches(address, domain.TaxCategoryStandard))
+	assert.False(t, batam.Matches(domain.ShippingAddress{Country: "Indonesia", PostalCode: "40111"}, "food"))
+
+	assert.True(t, food.MoreSpecific(country))
+	assert.True(t, batam.MoreSpecific(food))
+}
+
+func TestOrderItemCharged(t *testing.T) {
+	line := domain.OrderItem{Qty: 2, Price: domain.NewMoney(1000, "USD"), TaxAmount: domain.NewMoney(101, "USD")}
+	first := line.Charged(1)
+	all := line.Charged(2)
+	assert.Equal(t, domain.NewMoney(1051, "USD"), first)
+	assert.Equal(t, domain.NewMoney(2101, "USD"), all)
+
+	line.TaxInclusive = true
+	assert.Equal(t, domain.NewMoney(2000, "USD"), line.Charged(2))
+}
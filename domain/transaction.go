@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// Transactor runs fn inside a single database transaction. Repositories
+// called with the context handed to fn take part in the transaction, which
+// is committed when fn returns nil and rolled back otherwise.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -15,40 +15,78 @@ const (
 	RolesTypeSuperstaff RolesType = "superstaff"
 )
 
+// IsValid reports whether r is one of the known roles
+func (r RolesType) IsValid() bool {
+	switch r {
+	case RolesTypeAdmin, RolesTypeUser, RolesTypeStaff, RolesTypeSuperadmin, RolesTypeSuperstaff:
+		return true
+	}
+	return false
+}
+
 // User ...
 type User struct {
 	ID             int64     `json:"id"`
 	Username       string    `json:"username" validate:"required"`
 	Email          string    `json:"email" validate:"required"`
-	HashedPassword string    `json:"hashed_password" validate:"required"`
+	HashedPassword string    `json:"-"`
 	IsVerified     bool      `json:"is_verified" validate:"required"`
-	Role           string    `json:"role"`
+	Role           RolesType `json:"role" validate:"omitempty,oneof=admin user staff superadmin superstaff"`
 	UpdatedAt      time.Time `json:"updated_at"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+// UserListSchema is what users can be listed by
+var UserListSchema = ListSchema{
+	Fields: []ListField{
+		{Name: "username", Type: FieldString, Sortable: true, Filterable: true},
+		{Name: "email", Type: FieldString, Sortable: true, Filterable: true},
+		{Name: "role", Type: FieldString, Filterable: true},
+		{Name: "is_verified", Type: FieldBool, Filterable: true},
+		{Name: "updated_at", Type: FieldTime, Sortable: true, Filterable: true},
+		{Name: "created_at", Type: FieldTime, Sortable: true, Filterable: true},
+	},
+	DefaultSort: Sort{Field: "created_at"},
+}
+
 // UserUsecase represent the User's usecases
 type UserUsecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]User, string, error)
+	Fetch(ctx context.Context, page PageRequest) ([]User, PageInfo, error)
 	GetByID(ctx context.Context, id int64) (User, error)
 	Update(ctx context.Context, ar *User) error
+	UpdateProfile(ctx context.Context, id int64, username string, email string) (User, error)
 	GetByUsername(ctx context.Context, username string) (User, error)
 	Store(context.Context, *User) error
 	Delete(ctx context.Context, id int64) error
 	Register(ctx context.Context, users *User) (err error)
-	Login(ctx context.Context, username string, password string) (User, error)
+	Verify(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID int64) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, password string) error
+	Login(ctx context.Context, username string, password string, client ClientInfo) (LoginResponse, error)
+	LoginMFA(ctx context.Context, mfaToken string, code string, client ClientInfo) (LoginResponse, error)
+	EnrollMFA(ctx context.Context, userID int64) (MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userID int64, code string) (recoveryCodes []string, err error)
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (LoginResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	Unlock(ctx context.Context, id int64) error
+	FetchSessions(ctx context.Context, userID int64) ([]RefreshToken, error)
+	RevokeSession(ctx context.Context, userID int64, sessionID int64) error
 	CreateAdmin(ctx context.Context, user *User) (err error)
 	CreateStaff(ctx context.Context, user *User) (err error)
 }
 
 // UserRepository represent the User's repository contract
 type UserRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []User, nextCursor string, err error)
+	Fetch(ctx context.Context, page PageRequest) (res []User, info PageInfo, err error)
 	GetByID(ctx context.Context, id int64) (User, error)
 	GetByUsername(ctx context.Context, username string) (User, error)
+	GetByEmail(ctx context.Context, email string) (User, error)
 	Update(ctx context.Context, ar *User) error
+	UpdatePassword(ctx context.Context, id int64, hashedPassword string) error
 	Store(ctx context.Context, a *User) error
 	Delete(ctx context.Context, id int64) error
 	Register(ctx context.Context, users *User) (err error)
 	Login(ctx context.Context, username string, password string) (User, error)
+	MarkVerified(ctx context.Context, id int64) error
 }
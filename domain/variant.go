@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrVariantRequired will throw if a product sold in variants is put in a cart without choosing one
+	ErrVariantRequired = errors.New("a variant of the product must be chosen")
+	// ErrVariantsExist will throw if the options or the currency of a product are changed while it has variants
+	ErrVariantsExist = errors.New("options and currency can not change while the product has variants")
+)
+
+// ProductOption is a way a product varies, e.g. Size with the values S, M
+// and L. Position orders the options of a product, and Values, lowest first.
+type ProductOption struct {
+	ID        int64         `json:"id"`
+	ProductID int64         `json:"product_id"`
+	Name      string        `json:"name"`
+	Position  int           `json:"position"`
+	Values    []OptionValue `json:"values"`
+}
+
+// OptionValue is one of the values of a ProductOption
+type OptionValue struct {
+	ID       int64  `json:"id"`
+	OptionID int64  `json:"option_id"`
+	Value    string `json:"value"`
+	Position int    `json:"position"`
+}
+
+// Variant is a sellable version of a product with one value of each of its
+// options, e.g. the M Red shirt. A variant has its own price and stock, the
+// stock of a product with variants is the sum of theirs. Options are in the
+// order of the options of the product.
+type Variant struct {
+	ID           int64         `json:"id"`
+	ProductID    int64         `json:"product_id"`
+	SKU          string        `json:"sku"`
+	Price        Money         `json:"price"`
+	DisplayPrice *Money        `json:"display_price,omitempty"`
+	CountInStock int           `json:"count_in_stock"`
+	Image        string        `json:"image"`
+	Barcode      string        `json:"barcode"`
+	Options      []OptionValue `json:"options"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// Title names the variant by its values, e.g. "M / Red"
+func (v Variant) Title() string {
+	values := make([]string, 0, len(v.Options))
+	for _, o := range v.Options {
+		values = append(values, o.Value)
+	}
+	return strings.Join(values, " / ")
+}
+
+// ItemName is the name of the variant on a cart or order line
+func (v Variant) ItemName(productName string) string {
+	if len(v.Options) == 0 {
+		return productName
+	}
+	return productName + " (" + v.Title() + ")"
+}
+
+// ProductOptionRepository represent the ProductOption's repository contract
+type ProductOptionRepository interface {
+	// FetchByProduct returns the options of the product with their values
+	FetchByProduct(ctx context.Context, productID int64) ([]ProductOption, error)
+	// Store inserts the option and its values, it only makes sense inside a
+	// transaction
+	Store(ctx context.Context, o *ProductOption) error
+	// Delete removes the option and its values
+	Delete(ctx context.Context, id int64) error
+	// DeleteByProduct removes every option of the product and their values
+	DeleteByProduct(ctx context.Context, productID int64) error
+}
+
+// VariantRepository represent the Variant's repository contract
+type VariantRepository interface {
+	FetchByProduct(ctx context.Context, productID int64) ([]Variant, error)
+	GetByID(ctx context.Context, id int64) (Variant, error)
+	// GetByIDForUpdate locks the variant row until the surrounding transaction ends
+	GetByIDForUpdate(ctx context.Context, id int64) (Variant, error)
+	GetBySKU(ctx context.Context, sku string) (Variant, error)
+	Store(ctx context.Context, v *Variant) error
+	// Update changes the variant and the values it is made of
+	Update(ctx context.Context, v *Variant) error
+	Delete(ctx context.Context, id int64) error
+	// DeleteByProduct removes every variant of the product and their values
+	DeleteByProduct(ctx context.Context, productID int64) error
+	// DecrementStock takes qty out of the stock, ErrInsufficientStock is returned when there is not enough
+	DecrementStock(ctx context.Context, id int64, qty int) error
+	// IncrementStock puts qty back into the stock, ErrNotFound is returned
+	// when the variant no longer exists
+	IncrementStock(ctx context.Context, id int64, qty int) error
+	// SyncProductStock sets the stock of the product to the sum of the stock
+	// of its variants
+	SyncProductStock(ctx context.Context, productID int64) error
+}
+
+// VariantUsecase represent the management of the options and variants of products
+type VariantUsecase interface {
+	FetchOptions(ctx context.Context, productID int64) ([]ProductOption, error)
+	// StoreOption adds an option to a product without variants,
+	// ErrVariantsExist is returned otherwise
+	StoreOption(ctx context.Context, o *ProductOption) error
+	// DeleteOption removes an option of a product without variants,
+	// ErrVariantsExist is returned otherwise
+	DeleteOption(ctx context.Context, productID int64, id int64) error
+	FetchVariants(ctx context.Context, productID int64) ([]Variant, error)
+	// StoreVariant creates v from the ids of the values in v.Options, one of
+	// each option of the product. ErrConflict is returned for a SKU or a
+	// combination of values another variant already has.
+	StoreVariant(ctx context.Context, v *Variant) error
+	UpdateVariant(ctx context.Context, v *Variant) error
+	DeleteVariant(ctx context.Context, productID int64, id int64) error
+}
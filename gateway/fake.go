@@ -0,0 +1,160 @@
+package gateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+// FakeSignatureHeader carries the hex HMAC-SHA256 of the body of a fake notification
+const FakeSignatureHeader = "X-Fake-Signature"
+
+const fakeProviderName = "fake"
+
+var (
+	errUnknownIntent = errors.New("fake: unknown payment intent")
+	errNotCaptured   = errors.New("fake: payment is not captured")
+	errOverRefund    = errors.New("fake: refund exceeds captured amount")
+)
+
+type fakeIntent struct {
+	orderID  int64
+	amount   domain.Money
+	captured bool
+	refunded domain.Money
+}
+
+// FakeProvider is a domain.PaymentProvider keeping its payments in memory. It
+// is meant for local development and tests: nothing leaves the process and
+// notifications are produced with SignedEvent.
+type FakeProvider struct {
+	mu      sync.Mutex
+	secret  []byte
+	intents map[string]*fakeIntent
+}
+
+// NewFakeProvider will create a FakeProvider signing notifications with secret
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:  []byte(secret),
+		intents: map[string]*fakeIntent{},
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return fakeProviderName
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, orderID int64, amount domain.Money) (domain.PaymentIntent, error) {
+	ref, err := randomRef("fake_pi_")
+	if err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	secret, err := randomRef(ref + "_secret_")
+	if err != nil {
+		return domain.PaymentIntent{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.intents[ref] = &fakeIntent{orderID: orderID, amount: amount, refunded: domain.NewMoney(0, amount.Currency)}
+	return domain.PaymentIntent{ProviderRef: ref, ClientSecret: secret}, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, providerRef string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[providerRef]
+	if !ok {
+		return errUnknownIntent
+	}
+	intent.captured = true
+	return nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, providerRef string, amount domain.Money) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[providerRef]
+	if !ok {
+		return "", errUnknownIntent
+	}
+	if !intent.captured {
+		return "", errNotCaptured
+	}
+	refunded, err := intent.refunded.Add(amount)
+	if err != nil {
+		return "", err
+	}
+	if refunded.Amount > intent.amount.Amount {
+		return "", errOverRefund
+	}
+	intent.refunded = refunded
+	return randomRef("fake_re_")
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, header http.Header) (domain.PaymentEvent, error) {
+	signature, err := hex.DecodeString(header.Get(FakeSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(payload)) {
+		return domain.PaymentEvent{}, domain.ErrInvalidSignature
+	}
+
+	var event domain.PaymentEvent
+	if err = json.Unmarshal(payload, &event); err != nil {
+		return domain.PaymentEvent{}, domain.ErrBadParamInput
+	}
+	return event, nil
+}
+
+// SignedEvent builds the notification the provider would send about the
+// payment, with the headers VerifyWebhook expects
+func (p *FakeProvider) SignedEvent(providerRef string, eventType domain.PaymentEventType) ([]byte, http.Header, error) {
+	p.mu.Lock()
+	intent, ok := p.intents[providerRef]
+	p.mu.Unlock()
+	if !ok {
+		return nil, nil, errUnknownIntent
+	}
+
+	id, err := randomRef("fake_evt_")
+	if err != nil {
+		return nil, nil, err
+	}
+	event := domain.PaymentEvent{ID: id, Type: eventType, ProviderRef: providerRef, Amount: intent.amount}
+	if eventType == domain.PaymentEventFailed {
+		event.FailureReason = "card_declined"
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, hex.EncodeToString(p.sign(payload)))
+	return payload, header, nil
+}
+
+func (p *FakeProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func randomRef(prefix string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("fake: %w", err)
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,65 @@
+package gateway_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProviderWebhook(t *testing.T) {
+	p := gateway.NewFakeProvider("secret")
+	intent, err := p.CreateIntent(context.TODO(), 1, domain.NewMoney(15000000, "IDR"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, intent.ProviderRef)
+	assert.NotEmpty(t, intent.ClientSecret)
+
+	payload, header, err := p.SignedEvent(intent.ProviderRef, domain.PaymentEventSucceeded)
+	require.NoError(t, err)
+
+	t.Run("valid-signature", func(t *testing.T) {
+		event, err := p.VerifyWebhook(payload, header)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PaymentEventSucceeded, event.Type)
+		assert.Equal(t, intent.ProviderRef, event.ProviderRef)
+		assert.Equal(t, domain.NewMoney(15000000, "IDR"), event.Amount)
+	})
+
+	t.Run("tampered-payload", func(t *testing.T) {
+		tampered := append([]byte{}, payload...)
+		tampered[len(tampered)-2] = '9'
+		_, err := p.VerifyWebhook(tampered, header)
+		assert.Equal(t, domain.ErrInvalidSignature, err)
+	})
+
+	t.Run("other-secret", func(t *testing.T) {
+		_, err := gateway.NewFakeProvider("other").VerifyWebhook(payload, header)
+		assert.Equal(t, domain.ErrInvalidSignature, err)
+	})
+
+	t.Run("missing-signature", func(t *testing.T) {
+		_, err := p.VerifyWebhook(payload, http.Header{})
+		assert.Equal(t, domain.ErrInvalidSignature, err)
+	})
+}
+
+func TestFakeProviderRefund(t *testing.T) {
+	p := gateway.NewFakeProvider("secret")
+	intent, err := p.CreateIntent(context.TODO(), 1, domain.NewMoney(10000, "IDR"))
+	require.NoError(t, err)
+
+	_, err = p.Refund(context.TODO(), intent.ProviderRef, domain.NewMoney(1000, "IDR"))
+	assert.Error(t, err, "refund before capture")
+
+	require.NoError(t, p.Capture(context.TODO(), intent.ProviderRef))
+	ref, err := p.Refund(context.TODO(), intent.ProviderRef, domain.NewMoney(6000, "IDR"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ref)
+
+	_, err = p.Refund(context.TODO(), intent.ProviderRef, domain.NewMoney(6000, "IDR"))
+	assert.Error(t, err, "refund above the captured amount")
+}
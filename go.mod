@@ -3,16 +3,18 @@ module github.com/alfathaulia/ca_ecommerce_api
 go 1.16
 
 require (
-	github.com/bxcodec/faker v2.0.1+incompatible // indirect
+	github.com/bxcodec/faker v2.0.1+incompatible
 	github.com/go-playground/universal-translator v0.18.0 // indirect
-	github.com/go-sql-driver/mysql v1.6.0 // indirect
-	github.com/labstack/echo/v4 v4.6.1 // indirect
+	github.com/go-sql-driver/mysql v1.6.0
+	github.com/golang-jwt/jwt v3.2.2+incompatible
+	github.com/labstack/echo/v4 v4.6.1
 	github.com/leodido/go-urn v1.2.1 // indirect
-	github.com/sirupsen/logrus v1.8.1 // indirect
-	github.com/spf13/viper v1.9.0 // indirect
-	github.com/stretchr/testify v1.7.0 // indirect
+	github.com/pquerna/otp v1.3.0
+	github.com/sirupsen/logrus v1.8.1
+	github.com/spf13/viper v1.9.0
+	github.com/stretchr/testify v1.7.0
 	github.com/vektra/mockery/v2 v2.9.4 // indirect
-	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519 // indirect
-	gopkg.in/DATA-DOG/go-sqlmock.v2 v2.0.0-20180914054222-c19298f520d0 // indirect
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
+	gopkg.in/DATA-DOG/go-sqlmock.v2 v2.0.0-20180914054222-c19298f520d0
 	gopkg.in/go-playground/validator.v9 v9.31.0
 )
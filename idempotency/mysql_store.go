@@ -0,0 +1,127 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlStore struct {
+	DB *sql.DB
+}
+
+// NewMysqlStore will create an object that represent the domain.IdempotencyStore interface
+func NewMysqlStore(DB *sql.DB) domain.IdempotencyStore {
+	return &mysqlStore{DB: DB}
+}
+
+func (m *mysqlStore) Get(ctx context.Context, userID int64, key string) (res domain.IdempotencyRecord, err error) {
+	query := `SELECT user_id, idempotency_key, request_hash, completed, status_code, content_type, body, expires_at, created_at
+  						FROM idempotency_key WHERE user_id = ? AND idempotency_key = ? AND expires_at > ?`
+
+	err = transaction.Conn(ctx, m.DB).QueryRowContext(ctx, query, userID, key, time.Now()).Scan(
+		&res.UserID,
+		&res.Key,
+		&res.RequestHash,
+		&res.Completed,
+		&res.StatusCode,
+		&res.ContentType,
+		&res.Body,
+		&res.ExpiresAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.IdempotencyRecord{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.IdempotencyRecord{}, err
+	}
+	return
+}
+
+// Create first drops an expired record left behind for the key, so that the
+// key can be claimed again before the cleanup got to it
+func (m *mysqlStore) Create(ctx context.Context, r *domain.IdempotencyRecord) (err error) {
+	conn := transaction.Conn(ctx, m.DB)
+	_, err = conn.ExecContext(ctx, `DELETE FROM idempotency_key WHERE user_id = ? AND idempotency_key = ? AND expires_at <= ?`, r.UserID, r.Key, r.CreatedAt)
+	if err != nil {
+		return
+	}
+
+	query := `INSERT IGNORE idempotency_key SET user_id=? , idempotency_key=? , request_hash=? , completed=? , status_code=? , content_type=? , body=? , expires_at=? , created_at=?`
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, r.UserID, r.Key, r.RequestHash, r.Completed, r.StatusCode, r.ContentType, r.Body, r.ExpiresAt, r.CreatedAt)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
+
+func (m *mysqlStore) Complete(ctx context.Context, r *domain.IdempotencyRecord) (err error) {
+	query := `UPDATE  idempotency_key SET completed=1 , status_code=? , content_type=? , body=? WHERE user_id=? AND idempotency_key=? AND completed=0`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, r.StatusCode, r.ContentType, r.Body, r.UserID, r.Key)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	r.Completed = true
+	return
+}
+
+func (m *mysqlStore) Delete(ctx context.Context, userID int64, key string) (err error) {
+	query := "DELETE FROM idempotency_key WHERE user_id = ? AND idempotency_key = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, userID, key)
+	return
+}
+
+func (m *mysqlStore) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := "DELETE FROM idempotency_key WHERE expires_at <= ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := stmt.ExecContext(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
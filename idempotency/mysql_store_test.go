@@ -0,0 +1,116 @@
+package idempotency_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/idempotency"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+var record = &domain.IdempotencyRecord{
+	UserID:      3,
+	Key:         "key-1",
+	RequestHash: "hash",
+	ExpiresAt:   now.Add(time.Hour),
+	CreatedAt:   now,
+}
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func TestGet(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"user_id", "idempotency_key", "request_hash", "completed", "status_code", "content_type", "body", "expires_at", "created_at"}).
+		AddRow(3, "key-1", "hash", true, 201, "application/json", []byte(`{"id":1}`), record.ExpiresAt, record.CreatedAt)
+
+	query := `SELECT user_id, idempotency_key, request_hash, completed, status_code, content_type, body, expires_at, created_at FROM idempotency_key WHERE user_id = \? AND idempotency_key = \? AND expires_at > \?`
+	mock.ExpectQuery(query).WithArgs(int64(3), "key-1", sqlmock.AnyArg()).WillReturnRows(rows)
+
+	s := idempotency.NewMysqlStore(db)
+	res, err := s.Get(context.TODO(), 3, "key-1")
+	assert.NoError(t, err)
+	assert.True(t, res.Completed)
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, `{"id":1}`, string(res.Body))
+}
+
+func TestGetNotFound(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"user_id", "idempotency_key", "request_hash", "completed", "status_code", "content_type", "body", "expires_at", "created_at"})
+
+	query := `SELECT user_id, idempotency_key, request_hash, completed, status_code, content_type, body, expires_at, created_at FROM idempotency_key WHERE user_id = \? AND idempotency_key = \? AND expires_at > \?`
+	mock.ExpectQuery(query).WithArgs(int64(3), "key-1", sqlmock.AnyArg()).WillReturnRows(rows)
+
+	s := idempotency.NewMysqlStore(db)
+	_, err := s.Get(context.TODO(), 3, "key-1")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestCreate(t *testing.T) {
+	db, mock := NewMock()
+	mock.ExpectExec(`DELETE FROM idempotency_key WHERE user_id = \? AND idempotency_key = \? AND expires_at <= \?`).
+		WithArgs(record.UserID, record.Key, record.CreatedAt).WillReturnResult(sqlmock.NewResult(0, 0))
+	query := `INSERT IGNORE idempotency_key SET user_id=\? , idempotency_key=\? , request_hash=\? , completed=\? , status_code=\? , content_type=\? , body=\? , expires_at=\? , created_at=\?`
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(record.UserID, record.Key, record.RequestHash, false, 0, "", sqlmock.AnyArg(), record.ExpiresAt, record.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s := idempotency.NewMysqlStore(db)
+	err := s.Create(context.TODO(), record)
+	assert.NoError(t, err)
+}
+
+func TestCreateConflict(t *testing.T) {
+	db, mock := NewMock()
+	mock.ExpectExec(`DELETE FROM idempotency_key WHERE user_id = \? AND idempotency_key = \? AND expires_at <= \?`).
+		WithArgs(record.UserID, record.Key, record.CreatedAt).WillReturnResult(sqlmock.NewResult(0, 0))
+	query := `INSERT IGNORE idempotency_key SET user_id=\? , idempotency_key=\?`
+	mock.ExpectPrepare(query).ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s := idempotency.NewMysqlStore(db)
+	err := s.Create(context.TODO(), record)
+	assert.Equal(t, domain.ErrConflict, err)
+}
+
+func TestComplete(t *testing.T) {
+	db, mock := NewMock()
+	r := *record
+	r.StatusCode = 201
+	r.ContentType = "application/json"
+	r.Body = []byte(`{"id":1}`)
+
+	query := `UPDATE  idempotency_key SET completed=1 , status_code=\? , content_type=\? , body=\? WHERE user_id=\? AND idempotency_key=\? AND completed=0`
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(r.StatusCode, r.ContentType, r.Body, r.UserID, r.Key).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s := idempotency.NewMysqlStore(db)
+	err := s.Complete(context.TODO(), &r)
+	assert.NoError(t, err)
+	assert.True(t, r.Completed)
+}
+
+func TestDeleteExpired(t *testing.T) {
+	db, mock := NewMock()
+	mock.ExpectPrepare(`DELETE FROM idempotency_key WHERE expires_at <= \?`).ExpectExec().
+		WithArgs(now).WillReturnResult(sqlmock.NewResult(0, 4))
+
+	s := idempotency.NewMysqlStore(db)
+	deleted, err := s.DeleteExpired(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+}
@@ -0,0 +1,11 @@
+package limiter
+
+import "github.com/alfathaulia/ca_ecommerce_api/domain"
+
+// Len returns the number of keys held by a store of NewMemoryStore
+func Len(s domain.LoginThrottleStore) int {
+	m := s.(*memoryStore)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
@@ -0,0 +1,128 @@
+package limiter
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// Config holds the login throttling thresholds
+type Config struct {
+	// FreeAttempts is the number of failures allowed before any backoff applies
+	FreeAttempts int
+	// AccountMaxAttempts failures on one account lock it for LockoutDuration
+	AccountMaxAttempts int
+	// IPMaxAttempts failures from one IP block it for LockoutDuration
+	IPMaxAttempts int
+	// BaseDelay is the first backoff, doubled on every further failure up to MaxDelay
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	// Window is how long failures are remembered after the last one
+	Window time.Duration
+}
+
+type loginLimiter struct {
+	store       domain.LoginThrottleStore
+	attemptRepo domain.LoginAttemptRepository
+	cfg         Config
+}
+
+// NewLoginLimiter will create an object that represent the domain.LoginLimiter interface
+func NewLoginLimiter(store domain.LoginThrottleStore, ar domain.LoginAttemptRepository, cfg Config) domain.LoginLimiter {
+	return &loginLimiter{
+		store:       store,
+		attemptRepo: ar,
+		cfg:         cfg,
+	}
+}
+
+func accountKey(username string) string {
+	return "account:" + strings.ToLower(username)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (l *loginLimiter) Allow(ctx context.Context, username string, ip string) error {
+	now := time.Now()
+	for _, key := range []string{accountKey(username), ipKey(ip)} {
+		t, err := l.store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if now.Before(t.BlockedUntil) {
+			l.record(ctx, username, ip, domain.LoginFailureThrottled)
+			return domain.ErrTooManyAttempts
+		}
+	}
+	return nil
+}
+
+func (l *loginLimiter) Fail(ctx context.Context, username string, ip string, reason string) error {
+	l.record(ctx, username, ip, reason)
+
+	if err := l.fail(ctx, accountKey(username), l.cfg.AccountMaxAttempts); err != nil {
+		return err
+	}
+	return l.fail(ctx, ipKey(ip), l.cfg.IPMaxAttempts)
+}
+
+// fail counts the failure on key with one atomic increment, so concurrent
+// failures are never lost, and blocks the key for as long as the new count asks
+func (l *loginLimiter) fail(ctx context.Context, key string, maxAttempts int) error {
+	t, err := l.store.Increment(ctx, key, l.cfg.Window)
+	if err != nil {
+		return err
+	}
+	block := l.blockFor(t.Failures, maxAttempts)
+	if block <= 0 {
+		return nil
+	}
+	return l.store.Block(ctx, key, t.LastFailure.Add(block))
+}
+
+// blockFor returns how long to block after the given number of failures
+func (l *loginLimiter) blockFor(failures int, maxAttempts int) time.Duration {
+	if maxAttempts > 0 && failures >= maxAttempts {
+		return l.cfg.LockoutDuration
+	}
+	if failures <= l.cfg.FreeAttempts {
+		return 0
+	}
+	delay := l.cfg.BaseDelay
+	for i := l.cfg.FreeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= l.cfg.MaxDelay {
+			return l.cfg.MaxDelay
+		}
+	}
+	return delay
+}
+
+// Succeed clears the failures of the account only, a valid login from an IP
+// guessing many accounts must not reset its counter
+func (l *loginLimiter) Succeed(ctx context.Context, username string, ip string) error {
+	return l.store.Delete(ctx, accountKey(username))
+}
+
+func (l *loginLimiter) Unlock(ctx context.Context, username string) error {
+	return l.store.Delete(ctx, accountKey(username))
+}
+
+// record stores the audit trail of a failed attempt, it never blocks the login flow
+func (l *loginLimiter) record(ctx context.Context, username string, ip string, reason string) {
+	err := l.attemptRepo.Store(ctx, &domain.LoginAttempt{
+		Username:  username,
+		ClientIP:  ip,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logrus.Error(err)
+	}
+}
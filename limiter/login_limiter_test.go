@@ -0,0 +1,137 @@
+package limiter_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/alfathaulia/ca_ecommerce_api/limiter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var cfg = limiter.Config{
+	FreeAttempts:       2,
+	AccountMaxAttempts: 5,
+	IPMaxAttempts:      8,
+	BaseDelay:          time.Minute,
+	MaxDelay:           time.Minute * 2,
+	LockoutDuration:    time.Hour,
+	Window:             time.Hour,
+}
+
+func TestLoginLimiter(t *testing.T) {
+	mockAttemptRepo := new(mocks.LoginAttemptRepository)
+	mockAttemptRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.LoginAttempt")).Return(nil)
+	ctx := context.TODO()
+
+	t.Run("free-attempts", func(t *testing.T) {
+		l := limiter.NewLoginLimiter(limiter.NewMemoryStore(), mockAttemptRepo, cfg)
+		for i := 0; i < cfg.FreeAttempts; i++ {
+			require.NoError(t, l.Allow(ctx, "user1", "10.0.0.1"))
+			require.NoError(t, l.Fail(ctx, "user1", "10.0.0.1", domain.LoginFailureWrongPassword))
+		}
+		assert.NoError(t, l.Allow(ctx, "user1", "10.0.0.1"))
+	})
+
+	t.Run("backoff-after-free-attempts", func(t *testing.T) {
+		l := limiter.NewLoginLimiter(limiter.NewMemoryStore(), mockAttemptRepo, cfg)
+		for i := 0; i <= cfg.FreeAttempts; i++ {
+			require.NoError(t, l.Fail(ctx, "user1", "10.0.0.1", domain.LoginFailureWrongPassword))
+		}
+		assert.Equal(t, domain.ErrTooManyAttempts, l.Allow(ctx, "user1", "10.0.0.1"))
+		// the account is blocked from any IP
+		assert.Equal(t, domain.ErrTooManyAttempts, l.Allow(ctx, "USER1", "10.0.0.2"))
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		store := limiter.NewMemoryStore()
+		l := limiter.NewLoginLimiter(store, mockAttemptRepo, cfg)
+		for i := 0; i < cfg.AccountMaxAttempts; i++ {
+			require.NoError(t, l.Fail(ctx, "user1", "10.0.0.1", domain.LoginFailureWrongPassword))
+		}
+		state, err := store.Get(ctx, "account:user1")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(cfg.LockoutDuration), state.BlockedUntil, time.Second)
+
+		require.NoError(t, l.Unlock(ctx, "user1"))
+		assert.NoError(t, l.Allow(ctx, "user1", "10.0.0.9"))
+	})
+
+	t.Run("success-resets-account-counter", func(t *testing.T) {
+		store := limiter.NewMemoryStore()
+		l := limiter.NewLoginLimiter(store, mockAttemptRepo, cfg)
+		require.NoError(t, l.Fail(ctx, "user1", "10.0.0.1", domain.LoginFailureWrongPassword))
+		require.NoError(t, l.Succeed(ctx, "user1", "10.0.0.1"))
+
+		state, err := store.Get(ctx, "account:user1")
+		require.NoError(t, err)
+		assert.Zero(t, state.Failures)
+
+		state, err = store.Get(ctx, "ip:10.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, state.Failures)
+	})
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	store := limiter.NewMemoryStore()
+	ctx := context.TODO()
+
+	require.NoError(t, store.Save(ctx, "key", domain.LoginThrottle{Failures: 3}, -time.Second))
+	state, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Zero(t, state.Failures)
+}
+
+func TestMemoryStoreIncrement(t *testing.T) {
+	store := limiter.NewMemoryStore()
+	ctx := context.TODO()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.Increment(ctx, "key", time.Hour)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	state, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, 50, state.Failures)
+
+	// a shorter block never cuts a longer one
+	until := time.Now().Add(time.Hour)
+	require.NoError(t, store.Block(ctx, "key", until))
+	require.NoError(t, store.Block(ctx, "key", time.Now().Add(time.Minute)))
+	state, err = store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, 50, state.Failures)
+	assert.True(t, until.Equal(state.BlockedUntil))
+}
+
+func TestMemoryStorePurge(t *testing.T) {
+	store := limiter.NewMemoryStore()
+	ctx := context.TODO()
+
+	_, err := store.Increment(ctx, "account:user1", time.Hour)
+	require.NoError(t, err)
+	// usernames sprayed once each, already expired
+	for i := 0; i < 5000; i++ {
+		_, err = store.Increment(ctx, fmt.Sprintf("account:random%d", i), -time.Second)
+		require.NoError(t, err)
+	}
+
+	assert.Less(t, limiter.Len(store), 1024)
+	state, err := store.Get(ctx, "account:user1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.Failures)
+}
@@ -0,0 +1,118 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type memoryEntry struct {
+	throttle  domain.LoginThrottle
+	expiresAt time.Time
+}
+
+// minPurgeSize is the number of keys a memory store holds before it first
+// looks for expired ones
+const minPurgeSize = 1024
+
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	purgeSize int
+}
+
+// NewMemoryStore will create an in-process domain.LoginThrottleStore. State is
+// not shared between instances of the API, use a shared store when scaling out.
+func NewMemoryStore() domain.LoginThrottleStore {
+	return &memoryStore{entries: make(map[string]memoryEntry), purgeSize: minPurgeSize}
+}
+
+// put stores e and drops the expired keys whenever the store doubled in size
+// since the last time, so that keys tried once, e.g. random usernames, do not
+// pile up and the cost of the purge is spread over the writes
+func (s *memoryStore) put(key string, e memoryEntry, now time.Time) {
+	s.entries[key] = e
+	if len(s.entries) < s.purgeSize {
+		return
+	}
+	for k, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.purgeSize = 2 * len(s.entries)
+	if s.purgeSize < minPurgeSize {
+		s.purgeSize = minPurgeSize
+	}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (domain.LoginThrottle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return domain.LoginThrottle{}, nil
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(s.entries, key)
+		return domain.LoginThrottle{}, nil
+	}
+	return e.throttle, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, key string, t domain.LoginThrottle, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.put(key, memoryEntry{throttle: t, expiresAt: now.Add(ttl)}, now)
+	return nil
+}
+
+func (s *memoryStore) Increment(ctx context.Context, key string, ttl time.Duration) (domain.LoginThrottle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, ok := s.entries[key]
+	if !ok || now.After(e.expiresAt) {
+		e = memoryEntry{}
+	}
+	e.throttle.Failures++
+	e.throttle.LastFailure = now
+	if expiresAt := now.Add(ttl); expiresAt.After(e.expiresAt) {
+		e.expiresAt = expiresAt
+	}
+	s.put(key, e, now)
+	return e.throttle, nil
+}
+
+func (s *memoryStore) Block(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, ok := s.entries[key]
+	if !ok || now.After(e.expiresAt) {
+		e = memoryEntry{}
+	}
+	if until.After(e.throttle.BlockedUntil) {
+		e.throttle.BlockedUntil = until
+	}
+	if until.After(e.expiresAt) {
+		e.expiresAt = until
+	}
+	s.put(key, e, now)
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
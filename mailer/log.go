@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+// LogMailer is a domain.Mailer that writes every mail to out instead of sending
+// it, and keeps them in memory. It is meant for local development and tests.
+type LogMailer struct {
+	mu   sync.Mutex
+	out  io.Writer
+	sent []domain.Mail
+}
+
+// NewLogMailer will create a LogMailer writing to out
+func NewLogMailer(out io.Writer) *LogMailer {
+	return &LogMailer{out: out}
+}
+
+func (m *LogMailer) Send(ctx context.Context, mail domain.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = append(m.sent, mail)
+	if m.out == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(m.out, "To: %s\nSubject: %s\n\n%s\n----\n", mail.To, mail.Subject, mail.Body)
+	return err
+}
+
+// Sent returns every mail sent so far
+func (m *LogMailer) Sent() []domain.Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make([]domain.Mail, len(m.sent))
+	copy(res, m.sent)
+	return res
+}
@@ -0,0 +1,50 @@
+package mailer_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer(t *testing.T) {
+	var out bytes.Buffer
+	m := mailer.NewLogMailer(&out)
+
+	err := m.Send(context.TODO(), domain.Mail{To: "user1@gmail.com", Subject: "hello", Body: "world"})
+	require.NoError(t, err)
+
+	assert.Len(t, m.Sent(), 1)
+	assert.Contains(t, out.String(), "To: user1@gmail.com")
+	assert.Contains(t, out.String(), "world")
+}
+
+func TestSendVerification(t *testing.T) {
+	m := mailer.NewLogMailer(nil)
+	um := mailer.NewUserMailer(m, "http://localhost:8382/")
+
+	err := um.SendVerification(context.TODO(), domain.User{Username: "user1", Email: "user1@gmail.com"}, "abc+def")
+	require.NoError(t, err)
+
+	sent := m.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "user1@gmail.com", sent[0].To)
+	assert.Contains(t, sent[0].Body, "http://localhost:8382/users/verify?token=abc%2Bdef")
+}
+
+func TestSendPasswordReset(t *testing.T) {
+	m := mailer.NewLogMailer(nil)
+	um := mailer.NewUserMailer(m, "http://localhost:8382")
+
+	err := um.SendPasswordReset(context.TODO(), domain.User{Username: "user1", Email: "user1@gmail.com"}, "reset-token")
+	require.NoError(t, err)
+
+	sent := m.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "user1@gmail.com", sent[0].To)
+	assert.Contains(t, sent[0].Body, "reset-token")
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type userMailer struct {
+	mailer  domain.Mailer
+	baseURL string
+}
+
+// NewUserMailer will create an object that represent the domain.UserMailer interface.
+// baseURL is the public address of this API, used to build the links in the emails.
+func NewUserMailer(m domain.Mailer, baseURL string) domain.UserMailer {
+	return &userMailer{
+		mailer:  m,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (m *userMailer) SendVerification(ctx context.Context, user domain.User, token string) error {
+	link := fmt.Sprintf("%s/users/verify?token=%s", m.baseURL, url.QueryEscape(token))
+	return m.mailer.Send(ctx, domain.Mail{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nplease verify your email address by opening the link below:\n\n%s\n\nThe link is valid for 24 hours.\n",
+			user.Username, link),
+	})
+}
+
+func (m *userMailer) SendPasswordReset(ctx context.Context, user domain.User, token string) error {
+	return m.mailer.Send(ctx, domain.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nsomeone requested a password reset for your account. Use the token below to choose a new password:\n\n%s\n\nThe token is valid for 1 hour. If you did not request a reset you can ignore this email.\n",
+			user.Username, token),
+	})
+}
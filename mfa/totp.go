@@ -0,0 +1,176 @@
+package mfa
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	util "github.com/alfathaulia/ca_ecommerce_api/user/repository/utils"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 6
+	// period and skew are the defaults of totp.Generate and totp.Validate
+	period = 30
+	skew   = 1
+)
+
+type totpAuthenticator struct {
+	mfaRepo       domain.MFARepository
+	issuer        string
+	requiredRoles map[domain.RolesType]bool
+}
+
+// NewTOTPAuthenticator will create an object that represent the domain.MFAAuthenticator
+// interface using RFC 6238 time-based one-time passwords. Users with one of
+// requiredRoles must enroll before they can log in.
+func NewTOTPAuthenticator(repo domain.MFARepository, issuer string, requiredRoles []domain.RolesType) domain.MFAAuthenticator {
+	required := make(map[domain.RolesType]bool, len(requiredRoles))
+	for _, r := range requiredRoles {
+		required[r] = true
+	}
+	return &totpAuthenticator{
+		mfaRepo:       repo,
+		issuer:        issuer,
+		requiredRoles: required,
+	}
+}
+
+func (a *totpAuthenticator) IsRequired(role domain.RolesType) bool {
+	return a.requiredRoles[role]
+}
+
+func (a *totpAuthenticator) IsEnabled(ctx context.Context, userID int64) (bool, error) {
+	res, err := a.mfaRepo.GetByUserID(ctx, userID)
+	if err == domain.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return res.IsEnabled, nil
+}
+
+// Enroll generates a new secret. It is not used for logins until Confirm succeeds.
+func (a *totpAuthenticator) Enroll(ctx context.Context, user domain.User) (domain.MFAEnrollment, error) {
+	enabled, err := a.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return domain.MFAEnrollment{}, err
+	}
+	if enabled {
+		return domain.MFAEnrollment{}, domain.ErrConflict
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      a.issuer,
+		AccountName: user.Username,
+	})
+	if err != nil {
+		return domain.MFAEnrollment{}, err
+	}
+	err = a.mfaRepo.Upsert(ctx, &domain.MFA{
+		UserID: user.ID,
+		Secret: key.Secret(),
+	})
+	if err != nil {
+		return domain.MFAEnrollment{}, err
+	}
+	return domain.MFAEnrollment{Secret: key.Secret(), URI: key.URL()}, nil
+}
+
+// Confirm enables TOTP once the user proves the secret was set up, and returns
+// the recovery codes. They are only stored hashed and can not be shown again.
+func (a *totpAuthenticator) Confirm(ctx context.Context, userID int64, code string) ([]string, error) {
+	res, err := a.mfaRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if res.IsEnabled {
+		return nil, domain.ErrConflict
+	}
+	step, ok := matchStep(code, res.Secret, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+	if err = a.useStep(ctx, userID, step); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		c, err := util.RandomToken(recoveryCodeSize)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, util.HashToken(c))
+	}
+	if err = a.mfaRepo.StoreRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	if err = a.mfaRepo.Enable(ctx, userID); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code. A
+// TOTP code is accepted once, as is any code of an earlier time step.
+func (a *totpAuthenticator) Verify(ctx context.Context, userID int64, code string) error {
+	res, err := a.mfaRepo.GetByUserID(ctx, userID)
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidMFACode
+	}
+	if err != nil {
+		return err
+	}
+	if !res.IsEnabled {
+		return domain.ErrInvalidMFACode
+	}
+	code = strings.TrimSpace(code)
+	if step, ok := matchStep(code, res.Secret, time.Now()); ok {
+		if step <= res.LastUsedStep {
+			return domain.ErrInvalidMFACode
+		}
+		return a.useStep(ctx, userID, step)
+	}
+
+	err = a.mfaRepo.UseRecoveryCode(ctx, userID, util.HashToken(code))
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidMFACode
+	}
+	return err
+}
+
+// useStep records the time step of an accepted code, a concurrent request
+// that got a code of the same step accepted first wins
+func (a *totpAuthenticator) useStep(ctx context.Context, userID int64, step int64) error {
+	err := a.mfaRepo.UseStep(ctx, userID, step)
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidMFACode
+	}
+	return err
+}
+
+// matchStep returns the time step the code belongs to, it accepts the same
+// steps as totp.Validate
+func matchStep(code string, secret string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{
+		Period:    period,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+	for i := -skew; i <= skew; i++ {
+		t := now.Add(time.Duration(i*period) * time.Second)
+		ok, err := totp.ValidateCustom(code, secret, t, opts)
+		if err == nil && ok {
+			return t.Unix() / period, true
+		}
+	}
+	return 0, false
+}
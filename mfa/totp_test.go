@@ -0,0 +1,165 @@
+package mfa_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/alfathaulia/ca_ecommerce_api/mfa"
+	util "github.com/alfathaulia/ca_ecommerce_api/user/repository/utils"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "JBSWY3DPEHPK3PXP"
+
+func TestIsRequired(t *testing.T) {
+	a := mfa.NewTOTPAuthenticator(new(mocks.MFARepository), "shop", []domain.RolesType{domain.RolesTypeAdmin})
+	assert.True(t, a.IsRequired(domain.RolesTypeAdmin))
+	assert.False(t, a.IsRequired(domain.RolesTypeUser))
+}
+
+func TestEnroll(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	user := domain.User{ID: 1, Username: "admin1"}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.On("GetByUserID", mock.Anything, user.ID).Return(domain.MFA{}, domain.ErrNotFound).Once()
+		mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(m *domain.MFA) bool {
+			return m.UserID == user.ID && m.Secret != "" && !m.IsEnabled
+		})).Return(nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		res, err := a.Enroll(context.TODO(), user)
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Secret)
+		assert.Contains(t, res.URI, "otpauth://totp/shop:admin1")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("already-enabled", func(t *testing.T) {
+		mockRepo.On("GetByUserID", mock.Anything, user.ID).Return(domain.MFA{UserID: user.ID, Secret: secret, IsEnabled: true}, nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		_, err := a.Enroll(context.TODO(), user)
+		assert.Equal(t, domain.ErrConflict, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestConfirm(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	pending := domain.MFA{UserID: 1, Secret: secret}
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		code, err := totp.GenerateCode(secret, now)
+		require.NoError(t, err)
+		mockRepo.On("GetByUserID", mock.Anything, pending.UserID).Return(pending, nil).Once()
+		mockRepo.On("UseStep", mock.Anything, pending.UserID, now.Unix()/30).Return(nil).Once()
+		mockRepo.On("StoreRecoveryCodes", mock.Anything, pending.UserID, mock.AnythingOfType("[]string")).Return(nil).Once()
+		mockRepo.On("Enable", mock.Anything, pending.UserID).Return(nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		codes, err := a.Confirm(context.TODO(), pending.UserID, code)
+		require.NoError(t, err)
+		assert.Len(t, codes, 10)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("wrong-code", func(t *testing.T) {
+		mockRepo.On("GetByUserID", mock.Anything, pending.UserID).Return(pending, nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		_, err := a.Confirm(context.TODO(), pending.UserID, "000000x")
+		assert.Equal(t, domain.ErrInvalidMFACode, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	mockRepo := new(mocks.MFARepository)
+	enabled := domain.MFA{UserID: 1, Secret: secret, IsEnabled: true}
+
+	t.Run("totp-code", func(t *testing.T) {
+		now := time.Now()
+		code, err := totp.GenerateCode(secret, now)
+		require.NoError(t, err)
+		mockRepo.On("GetByUserID", mock.Anything, enabled.UserID).Return(enabled, nil).Once()
+		mockRepo.On("UseStep", mock.Anything, enabled.UserID, now.Unix()/30).Return(nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		assert.NoError(t, a.Verify(context.TODO(), enabled.UserID, code))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("previous-step-code", func(t *testing.T) {
+		previous := time.Now().Add(-30 * time.Second)
+		code, err := totp.GenerateCode(secret, previous)
+		require.NoError(t, err)
+		mockRepo.On("GetByUserID", mock.Anything, enabled.UserID).Return(enabled, nil).Once()
+		mockRepo.On("UseStep", mock.Anything, enabled.UserID, previous.Unix()/30).Return(nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		assert.NoError(t, a.Verify(context.TODO(), enabled.UserID, code))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("replayed-code", func(t *testing.T) {
+		repo := new(mocks.MFARepository)
+		now := time.Now()
+		code, err := totp.GenerateCode(secret, now)
+		require.NoError(t, err)
+		used := enabled
+		used.LastUsedStep = now.Unix() / 30
+		repo.On("GetByUserID", mock.Anything, enabled.UserID).Return(used, nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(repo, "shop", nil)
+		assert.Equal(t, domain.ErrInvalidMFACode, a.Verify(context.TODO(), enabled.UserID, code))
+		repo.AssertNotCalled(t, "UseStep", mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "UseRecoveryCode", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent-replay", func(t *testing.T) {
+		repo := new(mocks.MFARepository)
+		now := time.Now()
+		code, err := totp.GenerateCode(secret, now)
+		require.NoError(t, err)
+		repo.On("GetByUserID", mock.Anything, enabled.UserID).Return(enabled, nil).Once()
+		repo.On("UseStep", mock.Anything, enabled.UserID, now.Unix()/30).Return(domain.ErrNotFound).Once()
+
+		a := mfa.NewTOTPAuthenticator(repo, "shop", nil)
+		assert.Equal(t, domain.ErrInvalidMFACode, a.Verify(context.TODO(), enabled.UserID, code))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("recovery-code", func(t *testing.T) {
+		mockRepo.On("GetByUserID", mock.Anything, enabled.UserID).Return(enabled, nil).Once()
+		mockRepo.On("UseRecoveryCode", mock.Anything, enabled.UserID, util.HashToken("recovery1")).Return(nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		assert.NoError(t, a.Verify(context.TODO(), enabled.UserID, "recovery1"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid-code", func(t *testing.T) {
+		mockRepo.On("GetByUserID", mock.Anything, enabled.UserID).Return(enabled, nil).Once()
+		mockRepo.On("UseRecoveryCode", mock.Anything, enabled.UserID, mock.AnythingOfType("string")).Return(domain.ErrNotFound).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		assert.Equal(t, domain.ErrInvalidMFACode, a.Verify(context.TODO(), enabled.UserID, "nope"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-enabled", func(t *testing.T) {
+		mockRepo.On("GetByUserID", mock.Anything, enabled.UserID).Return(domain.MFA{UserID: 1, Secret: secret}, nil).Once()
+
+		a := mfa.NewTOTPAuthenticator(mockRepo, "shop", nil)
+		assert.Equal(t, domain.ErrInvalidMFACode, a.Verify(context.TODO(), enabled.UserID, "123456"))
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	idempotencyHeaderKey = "Idempotency-Key"
+	// IdempotentReplayedHeaderKey is set on responses answered from a stored record
+	IdempotentReplayedHeaderKey = "Idempotent-Replayed"
+	maxIdempotencyKeyLength     = 255
+)
+
+// responseRecorder keeps a copy of everything written to the client
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Idempotent honours the Idempotency-Key header: the first response for a key
+// is stored and replayed to retries of the same request. A retry while the
+// first request is still running gets 409, reusing a key for a different
+// request gets 422. Requests without the header go through untouched.
+// Responses with a server error are not stored so that they can be retried.
+// It must run after Auth on authenticated routes, keys are scoped per user.
+// Anonymous keys are scoped per client IP so that clients can not replay or
+// block each other's requests.
+func (m *GoMiddleware) Idempotent(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(idempotencyHeaderKey)
+		if key == "" {
+			return next(c)
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: "Idempotency-Key must be at most 255 characters"})
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request().Context()
+		var userID int64
+		if payload, ok := domain.AuthFromContext(ctx); ok {
+			userID = payload.UserID
+		} else {
+			key = anonymousKey(c.RealIP(), key)
+		}
+
+		now := time.Now()
+		record := domain.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash(c.Request(), body),
+			ExpiresAt:   now.Add(m.idempotencyTTL),
+			CreatedAt:   now,
+		}
+		err = m.idempotency.Create(ctx, &record)
+		if err == domain.ErrConflict {
+			return m.replay(c, record)
+		}
+		if err != nil {
+			logrus.Error(err)
+			return c.JSON(http.StatusInternalServerError, ResponseError{Message: domain.ErrInternalServerError.Error()})
+		}
+
+		res := c.Response()
+		recorder := &responseRecorder{ResponseWriter: res.Writer}
+		res.Writer = recorder
+		err = next(c)
+		res.Writer = recorder.ResponseWriter
+
+		if err != nil || !res.Committed || res.Status >= http.StatusInternalServerError {
+			if errDel := m.idempotency.Delete(ctx, userID, key); errDel != nil {
+				logrus.Error(errDel)
+			}
+			return err
+		}
+
+		record.StatusCode = res.Status
+		record.ContentType = res.Header().Get(echo.HeaderContentType)
+		record.Body = recorder.body.Bytes()
+		if errComplete := m.idempotency.Complete(ctx, &record); errComplete != nil {
+			logrus.Error(errComplete)
+			if errDel := m.idempotency.Delete(ctx, userID, key); errDel != nil {
+				logrus.Error(errDel)
+			}
+		}
+		return nil
+	}
+}
+
+// replay answers a request whose key is already claimed
+func (m *GoMiddleware) replay(c echo.Context, r domain.IdempotencyRecord) error {
+	stored, err := m.idempotency.Get(c.Request().Context(), r.UserID, r.Key)
+	if err == domain.ErrNotFound {
+		// the first request failed and released the key in the meantime
+		return c.JSON(http.StatusConflict, ResponseError{Message: "a request with this Idempotency-Key is being processed, retry later"})
+	}
+	if err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusInternalServerError, ResponseError{Message: domain.ErrInternalServerError.Error()})
+	}
+
+	if stored.RequestHash != r.RequestHash {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: "Idempotency-Key was already used for a different request"})
+	}
+	if !stored.Completed {
+		return c.JSON(http.StatusConflict, ResponseError{Message: "a request with this Idempotency-Key is being processed, retry later"})
+	}
+
+	c.Response().Header().Set(IdempotentReplayedHeaderKey, "true")
+	return c.Blob(stored.StatusCode, stored.ContentType, stored.Body)
+}
+
+// anonymousKey scopes a key sent without authentication to the client IP, the
+// hash keeps it within the length of the column
+func anonymousKey(ip string, key string) string {
+	h := sha256.Sum256([]byte(ip + "\n" + key))
+	return "anon:" + hex.EncodeToString(h[:])
+}
+
+// requestHash identifies a request by method, path and body
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
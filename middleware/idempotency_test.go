@@ -0,0 +1,184 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotentRequest(body string) *http.Request {
+	req := httptest.NewRequest(echo.POST, "/cart/checkout", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Idempotency-Key", "key-1")
+	return req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3}))
+}
+
+func TestIdempotent(t *testing.T) {
+	t.Run("first-request-is-stored", func(t *testing.T) {
+		store := new(mocks.IdempotencyStore)
+		store.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.IdempotencyRecord) bool {
+			return r.UserID == 3 && r.Key == "key-1" && !r.Completed && r.ExpiresAt.After(time.Now())
+		})).Return(nil).Once()
+		store.On("Complete", mock.Anything, mock.MatchedBy(func(r *domain.IdempotencyRecord) bool {
+			return r.StatusCode == http.StatusCreated && string(r.Body) == `{"id":1}`+"\n"
+		})).Return(nil).Once()
+		mw := middleware.InitMiddleware(nil, domain.DefaultRolePermissions(), store, time.Hour)
+
+		e := echo.New()
+		res := httptest.NewRecorder()
+		c := e.NewContext(newIdempotentRequest(`{"paymethod":"transfer"}`), res)
+
+		h := mw.Idempotent(func(c echo.Context) error {
+			var body map[string]string
+			require.NoError(t, c.Bind(&body))
+			assert.Equal(t, "transfer", body["paymethod"])
+			return c.JSON(http.StatusCreated, map[string]int{"id": 1})
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("retry-is-replayed", func(t *testing.T) {
+		var hash string
+		store := new(mocks.IdempotencyStore)
+		store.On("Create", mock.Anything, mock.AnythingOfType("*domain.IdempotencyRecord")).
+			Run(func(args mock.Arguments) { hash = args.Get(1).(*domain.IdempotencyRecord).RequestHash }).
+			Return(domain.ErrConflict).Once()
+		store.On("Get", mock.Anything, int64(3), "key-1").Return(func(ctx context.Context, userID int64, key string) domain.IdempotencyRecord {
+			return domain.IdempotencyRecord{UserID: 3, Key: "key-1", RequestHash: hash, Completed: true, StatusCode: http.StatusCreated, ContentType: echo.MIMEApplicationJSONCharsetUTF8, Body: []byte(`{"id":1}`)}
+		}, nil).Once()
+		mw := middleware.InitMiddleware(nil, domain.DefaultRolePermissions(), store, time.Hour)
+
+		e := echo.New()
+		res := httptest.NewRecorder()
+		c := e.NewContext(newIdempotentRequest(`{"paymethod":"transfer"}`), res)
+
+		h := mw.Idempotent(func(c echo.Context) error {
+			t.Fatal("handler must not run on a replay")
+			return nil
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, `{"id":1}`, res.Body.String())
+		assert.Equal(t, "true", res.Header().Get(middleware.IdempotentReplayedHeaderKey))
+		store.AssertExpectations(t)
+	})
+
+	t.Run("in-flight", func(t *testing.T) {
+		var hash string
+		store := new(mocks.IdempotencyStore)
+		store.On("Create", mock.Anything, mock.AnythingOfType("*domain.IdempotencyRecord")).
+			Run(func(args mock.Arguments) { hash = args.Get(1).(*domain.IdempotencyRecord).RequestHash }).
+			Return(domain.ErrConflict).Once()
+		store.On("Get", mock.Anything, int64(3), "key-1").Return(func(ctx context.Context, userID int64, key string) domain.IdempotencyRecord {
+			return domain.IdempotencyRecord{UserID: 3, Key: "key-1", RequestHash: hash}
+		}, nil).Once()
+		mw := middleware.InitMiddleware(nil, domain.DefaultRolePermissions(), store, time.Hour)
+
+		e := echo.New()
+		res := httptest.NewRecorder()
+		c := e.NewContext(newIdempotentRequest(`{"paymethod":"transfer"}`), res)
+
+		h := mw.Idempotent(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusConflict, res.Code)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("different-body", func(t *testing.T) {
+		store := new(mocks.IdempotencyStore)
+		store.On("Create", mock.Anything, mock.AnythingOfType("*domain.IdempotencyRecord")).Return(domain.ErrConflict).Once()
+		store.On("Get", mock.Anything, int64(3), "key-1").
+			Return(domain.IdempotencyRecord{UserID: 3, Key: "key-1", RequestHash: "other", Completed: true, StatusCode: http.StatusCreated}, nil).Once()
+		mw := middleware.InitMiddleware(nil, domain.DefaultRolePermissions(), store, time.Hour)
+
+		e := echo.New()
+		res := httptest.NewRecorder()
+		c := e.NewContext(newIdempotentRequest(`{"paymethod":"cash"}`), res)
+
+		h := mw.Idempotent(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("server-error-releases-key", func(t *testing.T) {
+		store := new(mocks.IdempotencyStore)
+		store.On("Create", mock.Anything, mock.AnythingOfType("*domain.IdempotencyRecord")).Return(nil).Once()
+		store.On("Delete", mock.Anything, int64(3), "key-1").Return(nil).Once()
+		mw := middleware.InitMiddleware(nil, domain.DefaultRolePermissions(), store, time.Hour)
+
+		e := echo.New()
+		res := httptest.NewRecorder()
+		c := e.NewContext(newIdempotentRequest(`{"paymethod":"transfer"}`), res)
+
+		h := mw.Idempotent(func(c echo.Context) error {
+			return c.JSON(http.StatusInternalServerError, middleware.ResponseError{Message: "boom"})
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+		store.AssertExpectations(t)
+		store.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("anonymous-keys-are-scoped-per-ip", func(t *testing.T) {
+		keys := make([]string, 0)
+		store := new(mocks.IdempotencyStore)
+		store.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.IdempotencyRecord) bool {
+			return r.UserID == 0 && r.Key != "key-1"
+		})).Run(func(args mock.Arguments) {
+			keys = append(keys, args.Get(1).(*domain.IdempotencyRecord).Key)
+		}).Return(nil).Twice()
+		store.On("Complete", mock.Anything, mock.AnythingOfType("*domain.IdempotencyRecord")).Return(nil).Twice()
+		mw := middleware.InitMiddleware(nil, domain.DefaultRolePermissions(), store, time.Hour)
+
+		e := echo.New()
+		h := mw.Idempotent(func(c echo.Context) error {
+			return c.NoContent(http.StatusCreated)
+		})
+		for _, ip := range []string{"203.0.113.1:1234", "203.0.113.2:1234"} {
+			req := httptest.NewRequest(echo.POST, "/users/register", strings.NewReader(`{}`))
+			req.Header.Set("Idempotency-Key", "key-1")
+			req.RemoteAddr = ip
+			res := httptest.NewRecorder()
+			require.NoError(t, h(e.NewContext(req, res)))
+			assert.Equal(t, http.StatusCreated, res.Code)
+		}
+		require.Len(t, keys, 2)
+		assert.NotEqual(t, keys[0], keys[1])
+		store.AssertExpectations(t)
+	})
+
+	t.Run("without-key", func(t *testing.T) {
+		store := new(mocks.IdempotencyStore)
+		mw := middleware.InitMiddleware(nil, domain.DefaultRolePermissions(), store, time.Hour)
+
+		e := echo.New()
+		req := httptest.NewRequest(echo.POST, "/users/register", strings.NewReader(`{}`))
+		res := httptest.NewRecorder()
+		c := e.NewContext(req, res)
+
+		h := mw.Idempotent(func(c echo.Context) error {
+			return c.NoContent(http.StatusCreated)
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		store.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
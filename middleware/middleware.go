@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	authorizationHeaderKey  = "Authorization"
+	authorizationTypeBearer = "bearer"
+	// AuthPayloadKey is the echo context key holding the authenticated domain.TokenPayload
+	AuthPayloadKey = "auth_payload"
+)
+
+// ResponseError represent the response error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// GoMiddleware represent the data-struct for middleware
+type GoMiddleware struct {
+	tokenMaker     domain.TokenMaker
+	permissions    domain.RolePermissions
+	idempotency    domain.IdempotencyStore
+	idempotencyTTL time.Duration
+}
+
+// InitMiddleware initialize the middleware
+func InitMiddleware(tokenMaker domain.TokenMaker, permissions domain.RolePermissions, idempotency domain.IdempotencyStore, idempotencyTTL time.Duration) *GoMiddleware {
+	return &GoMiddleware{
+		tokenMaker:     tokenMaker,
+		permissions:    permissions,
+		idempotency:    idempotency,
+		idempotencyTTL: idempotencyTTL,
+	}
+}
+
+// Auth will validate the bearer access token and put the authenticated user into the request context
+func (m *GoMiddleware) Auth(next echo.HandlerFunc) echo.HandlerFunc {
+	return m.authenticate(next, domain.TokenScopeAccess)
+}
+
+// AuthMFAEnrollment is Auth that also accepts the enrollment-only token handed
+// out to users who must set up two-factor authentication before logging in.
+func (m *GoMiddleware) AuthMFAEnrollment(next echo.HandlerFunc) echo.HandlerFunc {
+	return m.authenticate(next, domain.TokenScopeAccess, domain.TokenScopeMFAEnroll)
+}
+
+// AuthOptional is Auth for routes that also serve anonymous visitors: requests
+// without an Authorization header go through unauthenticated, a bad token is
+// still rejected.
+func (m *GoMiddleware) AuthOptional(next echo.HandlerFunc) echo.HandlerFunc {
+	auth := m.Auth(next)
+	return func(c echo.Context) error {
+		if c.Request().Header.Get(authorizationHeaderKey) == "" {
+			return next(c)
+		}
+		return auth(c)
+	}
+}
+
+func (m *GoMiddleware) authenticate(next echo.HandlerFunc, scopes ...string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		fields := strings.Fields(c.Request().Header.Get(authorizationHeaderKey))
+		if len(fields) != 2 || strings.ToLower(fields[0]) != authorizationTypeBearer {
+			return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+		}
+
+		payload, err := m.tokenMaker.VerifyToken(fields[1])
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, ResponseError{Message: err.Error()})
+		}
+		if !hasScope(payload.Scope, scopes) {
+			return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrInvalidToken.Error()})
+		}
+
+		c.Set(AuthPayloadKey, payload)
+		ctx := domain.NewContextWithAuth(c.Request().Context(), payload)
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+func hasScope(scope string, allowed []string) bool {
+	for _, s := range allowed {
+		if scope == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission will only let the request through when the role of the
+// authenticated user is granted every given permission. It must run after Auth.
+func (m *GoMiddleware) RequirePermission(perms ...domain.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			payload, ok := domain.AuthFromContext(c.Request().Context())
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+			}
+			for _, perm := range perms {
+				if !m.permissions.Has(payload.Role, perm) {
+					return c.JSON(http.StatusForbidden, ResponseError{Message: domain.ErrForbidden.Error()})
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireVerified will only let the request through when the authenticated user
+// has verified the email address. It must run after Auth.
+func (m *GoMiddleware) RequireVerified(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		payload, ok := domain.AuthFromContext(c.Request().Context())
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+		}
+		if !payload.IsVerified {
+			return c.JSON(http.StatusForbidden, ResponseError{Message: domain.ErrUnverified.Error()})
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,224 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/alfathaulia/ca_ecommerce_api/token"
+	util "github.com/alfathaulia/ca_ecommerce_api/user/repository/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+	mw := middleware.InitMiddleware(maker, domain.DefaultRolePermissions(), nil, 0)
+
+	signed, _, err := maker.CreateToken(domain.User{ID: 3, Username: "user1", Role: "user"})
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+signed)
+		res := httptest.NewRecorder()
+		c := e.NewContext(req, res)
+
+		h := mw.Auth(func(c echo.Context) error {
+			payload, ok := domain.AuthFromContext(c.Request().Context())
+			require.True(t, ok)
+			assert.Equal(t, int64(3), payload.UserID)
+			return c.NoContent(http.StatusOK)
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("missing-token", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/", nil)
+		res := httptest.NewRecorder()
+		c := e.NewContext(req, res)
+
+		h := mw.Auth(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("invalid-token", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(echo.GET, "/", nil)
+		req.Header.Set("Authorization", "Bearer abc.def.ghi")
+		res := httptest.NewRecorder()
+		c := e.NewContext(req, res)
+
+		h := mw.Auth(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		require.NoError(t, h(c))
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+}
+
+func TestAuthOptional(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+	mw := middleware.InitMiddleware(maker, domain.DefaultRolePermissions(), nil, 0)
+
+	signed, _, err := maker.CreateToken(domain.User{ID: 3, Username: "user1", Role: "user"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		header string
+		code   int
+		authed bool
+	}{
+		{"anonymous", "", http.StatusOK, false},
+		{"authenticated", "Bearer " + signed, http.StatusOK, true},
+		{"invalid-token", "Bearer abc.def.ghi", http.StatusUnauthorized, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			res := httptest.NewRecorder()
+			c := e.NewContext(req, res)
+
+			h := mw.AuthOptional(func(c echo.Context) error {
+				_, ok := domain.AuthFromContext(c.Request().Context())
+				assert.Equal(t, tc.authed, ok)
+				return c.NoContent(http.StatusOK)
+			})
+			require.NoError(t, h(c))
+			assert.Equal(t, tc.code, res.Code)
+		})
+	}
+}
+
+func TestAuthScopes(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+	mw := middleware.InitMiddleware(maker, domain.DefaultRolePermissions(), nil, 0)
+	user := domain.User{ID: 3, Username: "user1", Role: domain.RolesTypeAdmin}
+
+	enrollToken, _, err := maker.CreateScopedToken(user, domain.TokenScopeMFAEnroll, time.Minute)
+	require.NoError(t, err)
+	mfaToken, _, err := maker.CreateScopedToken(user, domain.TokenScopeMFA, time.Minute)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name  string
+		token string
+		mw    echo.MiddlewareFunc
+		code  int
+	}{
+		{"enroll-token-on-auth", enrollToken, mw.Auth, http.StatusUnauthorized},
+		{"enroll-token-on-enrollment", enrollToken, mw.AuthMFAEnrollment, http.StatusOK},
+		{"mfa-token-on-auth", mfaToken, mw.Auth, http.StatusUnauthorized},
+		{"mfa-token-on-enrollment", mfaToken, mw.AuthMFAEnrollment, http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			res := httptest.NewRecorder()
+			c := e.NewContext(req, res)
+
+			h := tc.mw(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			require.NoError(t, h(c))
+			assert.Equal(t, tc.code, res.Code)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+	mw := middleware.InitMiddleware(maker, domain.DefaultRolePermissions(), nil, 0)
+
+	cases := []struct {
+		name string
+		role domain.RolesType
+		perm domain.Permission
+		code int
+	}{
+		{"superadmin-create-admin", domain.RolesTypeSuperadmin, domain.PermissionAdminCreate, http.StatusOK},
+		{"admin-create-admin", domain.RolesTypeAdmin, domain.PermissionAdminCreate, http.StatusForbidden},
+		{"staff-read-user", domain.RolesTypeStaff, domain.PermissionUserRead, http.StatusOK},
+		{"staff-delete-user", domain.RolesTypeStaff, domain.PermissionUserDelete, http.StatusForbidden},
+		{"user-read-user", domain.RolesTypeUser, domain.PermissionUserRead, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			signed, _, err := maker.CreateToken(domain.User{ID: 1, Username: "user1", Role: tc.role})
+			require.NoError(t, err)
+
+			e := echo.New()
+			req := httptest.NewRequest(echo.DELETE, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+signed)
+			res := httptest.NewRecorder()
+			c := e.NewContext(req, res)
+
+			h := mw.Auth(mw.RequirePermission(tc.perm)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}))
+			require.NoError(t, h(c))
+			assert.Equal(t, tc.code, res.Code)
+		})
+	}
+}
+
+func TestRequireVerified(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+	mw := middleware.InitMiddleware(maker, domain.DefaultRolePermissions(), nil, 0)
+
+	for _, verified := range []bool{true, false} {
+		signed, _, err := maker.CreateToken(domain.User{ID: 1, Username: "user1", Role: domain.RolesTypeUser, IsVerified: verified})
+		require.NoError(t, err)
+
+		e := echo.New()
+		req := httptest.NewRequest(echo.POST, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+signed)
+		res := httptest.NewRecorder()
+		c := e.NewContext(req, res)
+
+		h := mw.Auth(mw.RequireVerified(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		}))
+		require.NoError(t, h(c))
+		if verified {
+			assert.Equal(t, http.StatusOK, res.Code)
+		} else {
+			assert.Equal(t, http.StatusForbidden, res.Code)
+		}
+	}
+}
+
+func TestNewRolePermissions(t *testing.T) {
+	perms, err := domain.NewRolePermissions(map[string][]string{
+		"staff": {"user:read", "user:delete"},
+	})
+	require.NoError(t, err)
+	assert.True(t, perms.Has(domain.RolesTypeStaff, domain.PermissionUserDelete))
+	assert.False(t, perms.Has(domain.RolesTypeAdmin, domain.PermissionUserRead))
+
+	_, err = domain.NewRolePermissions(map[string][]string{"owner": {"*"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `refresh_token` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NOT NULL,
+  `family_id` VARCHAR(64) NOT NULL,
+  `token_hash` CHAR(64) NOT NULL,
+  `device` VARCHAR(255) NOT NULL DEFAULT '',
+  `client_ip` VARCHAR(45) NOT NULL DEFAULT '',
+  `is_used` TINYINT(1) NOT NULL DEFAULT 0,
+  `is_revoked` TINYINT(1) NOT NULL DEFAULT 0,
+  `expires_at` DATETIME NOT NULL,
+  `last_seen_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_refresh_token_hash` (`token_hash`),
+  KEY `idx_refresh_token_family` (`family_id`),
+  KEY `idx_refresh_token_user` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
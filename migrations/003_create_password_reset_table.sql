@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `password_reset` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NOT NULL,
+  `token_hash` CHAR(64) NOT NULL,
+  `is_used` TINYINT(1) NOT NULL DEFAULT 0,
+  `expires_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_password_reset_hash` (`token_hash`),
+  KEY `idx_password_reset_user` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS `login_attempt` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `username` VARCHAR(255) NOT NULL,
+  `client_ip` VARCHAR(45) NOT NULL DEFAULT '',
+  `reason` VARCHAR(32) NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_login_attempt_username` (`username`, `created_at`),
+  KEY `idx_login_attempt_ip` (`client_ip`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
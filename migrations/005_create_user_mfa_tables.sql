@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `user_mfa` (
+  `user_id` BIGINT NOT NULL,
+  `secret` VARCHAR(64) NOT NULL,
+  `is_enabled` TINYINT(1) NOT NULL DEFAULT 0,
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `mfa_recovery_code` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NOT NULL,
+  `code_hash` CHAR(64) NOT NULL,
+  `is_used` TINYINT(1) NOT NULL DEFAULT 0,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_mfa_recovery_code_user` (`user_id`, `code_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
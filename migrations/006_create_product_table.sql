@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `product` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NOT NULL,
+  `image` VARCHAR(512) NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `brand` VARCHAR(255) NOT NULL,
+  `category` VARCHAR(255) NOT NULL,
+  `description` TEXT NOT NULL,
+  `rating` INT NOT NULL DEFAULT 0,
+  `num_reviews` INT NOT NULL DEFAULT 0,
+  `price` INT NOT NULL,
+  `count_in_stock` INT NOT NULL DEFAULT 0,
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_product_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
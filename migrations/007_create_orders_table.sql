@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `orders` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NOT NULL,
+  `pay_method` VARCHAR(64) NOT NULL,
+  `tax_price` DECIMAL(12,2) NOT NULL DEFAULT 0,
+  `shipping_price` DECIMAL(12,2) NOT NULL DEFAULT 0,
+  `total_price` DECIMAL(12,2) NOT NULL DEFAULT 0,
+  `is_paid` TINYINT(1) NOT NULL DEFAULT 0,
+  `is_delivered` TINYINT(1) NOT NULL DEFAULT 0,
+  `paid_at` DATETIME NULL,
+  `delivered_at` DATETIME NULL,
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_orders_user_created_at` (`user_id`, `created_at`),
+  KEY `idx_orders_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS `cart` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `user_id` BIGINT NULL,
+  `token_hash` CHAR(64) NULL,
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_cart_user` (`user_id`),
+  UNIQUE KEY `uq_cart_token_hash` (`token_hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `cart_item` (
+  `cart_id` BIGINT NOT NULL,
+  `product_id` BIGINT NOT NULL,
+  `qty` INT NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`cart_id`, `product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `order_item` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT NOT NULL,
+  `product_id` BIGINT NOT NULL,
+  `name` VARCHAR(255) NOT NULL,
+  `qty` INT NOT NULL,
+  `price` DECIMAL(12,2) NOT NULL,
+  `image` VARCHAR(512) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_order_item_order` (`order_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `shipping_address` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT NOT NULL,
+  `address` VARCHAR(255) NOT NULL,
+  `city` VARCHAR(128) NOT NULL,
+  `postal_code` VARCHAR(32) NOT NULL,
+  `country` VARCHAR(128) NOT NULL,
+  `shipping_price` DECIMAL(12,2) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_shipping_address_order` (`order_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,21 @@
+ALTER TABLE `orders` ADD COLUMN `status` VARCHAR(16) NOT NULL DEFAULT 'pending' AFTER `total_price`;
+
+UPDATE `orders` SET `status` = CASE
+  WHEN `is_delivered` = 1 THEN 'delivered'
+  WHEN `is_paid` = 1 THEN 'paid'
+  ELSE 'pending'
+END;
+
+ALTER TABLE `orders` DROP COLUMN `is_paid`, DROP COLUMN `is_delivered`;
+
+CREATE TABLE IF NOT EXISTS `order_status_history` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT NOT NULL,
+  `from_status` VARCHAR(16) NOT NULL,
+  `to_status` VARCHAR(16) NOT NULL,
+  `changed_by` BIGINT NULL,
+  `reason` VARCHAR(255) NOT NULL DEFAULT '',
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_order_status_history_order` (`order_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
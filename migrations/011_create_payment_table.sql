@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `payment` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT NOT NULL,
+  `provider` VARCHAR(32) NOT NULL,
+  `provider_ref` VARCHAR(128) NOT NULL,
+  `amount` DECIMAL(12,2) NOT NULL,
+  `status` VARCHAR(16) NOT NULL,
+  `failure_reason` VARCHAR(255) NOT NULL DEFAULT '',
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_payment_provider_ref` (`provider`, `provider_ref`),
+  KEY `idx_payment_order` (`order_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,26 @@
+ALTER TABLE `orders` ADD COLUMN `refunded_total` DECIMAL(12,2) NOT NULL DEFAULT 0 AFTER `total_price`;
+
+CREATE TABLE IF NOT EXISTS `refund` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `order_id` BIGINT NOT NULL,
+  `payment_id` BIGINT NULL,
+  `provider_ref` VARCHAR(128) NOT NULL DEFAULT '',
+  `amount` DECIMAL(12,2) NOT NULL,
+  `reason` VARCHAR(32) NOT NULL,
+  `note` VARCHAR(255) NOT NULL DEFAULT '',
+  `created_by` BIGINT NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_refund_order` (`order_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `refund_item` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `refund_id` BIGINT NOT NULL,
+  `order_item_id` BIGINT NOT NULL,
+  `qty` INT NOT NULL,
+  `amount` DECIMAL(12,2) NOT NULL,
+  `restocked` TINYINT(1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  KEY `idx_refund_item_refund` (`refund_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
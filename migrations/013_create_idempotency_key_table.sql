@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `idempotency_key` (
+  `user_id` BIGINT NOT NULL,
+  `idempotency_key` VARCHAR(255) NOT NULL,
+  `request_hash` CHAR(64) NOT NULL,
+  `completed` TINYINT(1) NOT NULL DEFAULT 0,
+  `status_code` INT NOT NULL DEFAULT 0,
+  `content_type` VARCHAR(128) NOT NULL DEFAULT '',
+  `body` MEDIUMBLOB NULL,
+  `expires_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`user_id`, `idempotency_key`),
+  KEY `idx_idempotency_key_expires` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
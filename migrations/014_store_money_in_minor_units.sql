@@ -0,0 +1,44 @@
+-- amounts become BIGINT minor units (e.g. cents) next to the ISO 4217 currency
+-- of the row, existing data is in IDR
+
+ALTER TABLE `product` MODIFY `price` BIGINT NOT NULL,
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'IDR' AFTER `price`;
+UPDATE `product` SET `price` = `price` * 100;
+
+ALTER TABLE `orders`
+  MODIFY `tax_price` DECIMAL(20,2) NOT NULL DEFAULT 0,
+  MODIFY `shipping_price` DECIMAL(20,2) NOT NULL DEFAULT 0,
+  MODIFY `total_price` DECIMAL(20,2) NOT NULL DEFAULT 0,
+  MODIFY `refunded_total` DECIMAL(20,2) NOT NULL DEFAULT 0,
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'IDR' AFTER `refunded_total`;
+UPDATE `orders` SET `tax_price` = ROUND(`tax_price` * 100), `shipping_price` = ROUND(`shipping_price` * 100),
+  `total_price` = ROUND(`total_price` * 100), `refunded_total` = ROUND(`refunded_total` * 100);
+ALTER TABLE `orders`
+  MODIFY `tax_price` BIGINT NOT NULL DEFAULT 0,
+  MODIFY `shipping_price` BIGINT NOT NULL DEFAULT 0,
+  MODIFY `total_price` BIGINT NOT NULL DEFAULT 0,
+  MODIFY `refunded_total` BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE `order_item` MODIFY `price` DECIMAL(20,2) NOT NULL,
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'IDR' AFTER `price`;
+UPDATE `order_item` SET `price` = ROUND(`price` * 100);
+ALTER TABLE `order_item` MODIFY `price` BIGINT NOT NULL;
+
+ALTER TABLE `shipping_address` MODIFY `shipping_price` DECIMAL(20,2) NOT NULL DEFAULT 0,
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'IDR' AFTER `shipping_price`;
+UPDATE `shipping_address` SET `shipping_price` = ROUND(`shipping_price` * 100);
+ALTER TABLE `shipping_address` MODIFY `shipping_price` BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE `payment` MODIFY `amount` DECIMAL(20,2) NOT NULL,
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'IDR' AFTER `amount`;
+UPDATE `payment` SET `amount` = ROUND(`amount` * 100);
+ALTER TABLE `payment` MODIFY `amount` BIGINT NOT NULL;
+
+ALTER TABLE `refund` MODIFY `amount` DECIMAL(20,2) NOT NULL,
+  ADD COLUMN `currency` CHAR(3) NOT NULL DEFAULT 'IDR' AFTER `amount`;
+UPDATE `refund` SET `amount` = ROUND(`amount` * 100);
+ALTER TABLE `refund` MODIFY `amount` BIGINT NOT NULL;
+
+ALTER TABLE `refund_item` MODIFY `amount` DECIMAL(20,2) NOT NULL;
+UPDATE `refund_item` SET `amount` = ROUND(`amount` * 100);
+ALTER TABLE `refund_item` MODIFY `amount` BIGINT NOT NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `exchange_rate` (
+  `base` CHAR(3) NOT NULL,
+  `quote` CHAR(3) NOT NULL,
+  `rate` DECIMAL(16,8) NOT NULL,
+  `updated_at` DATETIME NOT NULL,
+  PRIMARY KEY (`base`, `quote`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- the rate shown to the customer at checkout, the order is still settled in
+-- its own currency
+ALTER TABLE `orders`
+  ADD COLUMN `display_currency` CHAR(3) NOT NULL DEFAULT '' AFTER `currency`,
+  ADD COLUMN `exchange_rate` DECIMAL(16,8) NOT NULL DEFAULT 0 AFTER `display_currency`;
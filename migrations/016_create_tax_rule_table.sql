@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS `tax_rule` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(128) NOT NULL,
+  `country` VARCHAR(128) NOT NULL,
+  `postal_prefix` VARCHAR(32) NOT NULL DEFAULT '',
+  `category` VARCHAR(64) NOT NULL DEFAULT '',
+  `rate` INT NOT NULL,
+  `inclusive` TINYINT(1) NOT NULL DEFAULT 0,
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_tax_rule_scope` (`country`, `postal_prefix`, `category`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `product`
+  ADD COLUMN `tax_category` VARCHAR(64) NOT NULL DEFAULT 'standard' AFTER `currency`;
+
+-- the tax of the whole line as computed at checkout
+ALTER TABLE `order_item`
+  ADD COLUMN `tax_category` VARCHAR(64) NOT NULL DEFAULT 'standard' AFTER `image`,
+  ADD COLUMN `tax_rate` INT NOT NULL DEFAULT 0 AFTER `tax_category`,
+  ADD COLUMN `tax_inclusive` TINYINT(1) NOT NULL DEFAULT 0 AFTER `tax_rate`,
+  ADD COLUMN `tax_amount` BIGINT NOT NULL DEFAULT 0 AFTER `tax_inclusive`;
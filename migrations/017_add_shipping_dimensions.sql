@@ -0,0 +1,9 @@
+-- the weight and packed size of one unit, shipping is priced on them
+ALTER TABLE `product`
+  ADD COLUMN `weight_grams` INT NOT NULL DEFAULT 0 AFTER `count_in_stock`,
+  ADD COLUMN `length_mm` INT NOT NULL DEFAULT 0 AFTER `weight_grams`,
+  ADD COLUMN `width_mm` INT NOT NULL DEFAULT 0 AFTER `length_mm`,
+  ADD COLUMN `height_mm` INT NOT NULL DEFAULT 0 AFTER `width_mm`;
+
+ALTER TABLE `shipping_address`
+  ADD COLUMN `shipping_method` VARCHAR(32) NOT NULL DEFAULT 'standard' AFTER `country`;
@@ -0,0 +1,14 @@
+-- lists are paged on (created_at, id), the id breaking ties between rows
+-- created in the same instant
+ALTER TABLE `product`
+  DROP INDEX `idx_product_created_at`,
+  ADD KEY `idx_product_created_at_id` (`created_at`, `id`);
+
+ALTER TABLE `orders`
+  DROP INDEX `idx_orders_user_created_at`,
+  DROP INDEX `idx_orders_created_at`,
+  ADD KEY `idx_orders_user_created_at_id` (`user_id`, `created_at`, `id`),
+  ADD KEY `idx_orders_created_at_id` (`created_at`, `id`);
+
+ALTER TABLE `user`
+  ADD KEY `idx_user_created_at_id` (`created_at`, `id`);
@@ -0,0 +1,3 @@
+-- catalog search matches these columns with MATCH ... AGAINST
+ALTER TABLE `product`
+  ADD FULLTEXT KEY `ft_product_search` (`name`, `brand`, `category`, `description`);
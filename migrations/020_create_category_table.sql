@@ -0,0 +1,42 @@
+-- path holds the ids from the root down to the category, e.g. /1/4/, a
+-- subtree is every category whose path starts with the path of its root
+CREATE TABLE IF NOT EXISTS `category` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `parent_id` BIGINT NULL,
+  `name` VARCHAR(128) NOT NULL,
+  `slug` VARCHAR(128) NOT NULL,
+  `position` INT NOT NULL DEFAULT 0,
+  `path` VARCHAR(255) NOT NULL DEFAULT '',
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_category_slug` (`slug`),
+  KEY `idx_category_parent_id` (`parent_id`),
+  KEY `idx_category_path` (`path`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- the free-form categories of the products become root categories, the ones
+-- spelled alike but for case and punctuation, e.g. Shoes and shoes, are merged
+-- on their slug, made like the slugs of the category usecase
+INSERT INTO `category` (`name`, `slug`, `position`, `path`, `updated_at`, `created_at`)
+  SELECT MIN(TRIM(`category`)), `slug`, 0, '', NOW(), NOW()
+  FROM (
+    SELECT `category`, COALESCE(NULLIF(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(`category`), '[^a-z0-9]+', '-')), ''), 'uncategorized') AS `slug`
+    FROM `product`
+  ) AS `p`
+  GROUP BY `slug`;
+UPDATE `category` SET `path` = CONCAT('/', `id`, '/');
+
+ALTER TABLE `product`
+  ADD COLUMN `category_id` BIGINT NULL AFTER `brand`;
+UPDATE `product` JOIN `category`
+  ON `category`.`slug` = COALESCE(NULLIF(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(`product`.`category`), '[^a-z0-9]+', '-')), ''), 'uncategorized')
+  SET `product`.`category_id` = `category`.`id`;
+
+-- the name of the category lives in category, catalog search matches it there
+ALTER TABLE `product`
+  DROP INDEX `ft_product_search`,
+  DROP COLUMN `category`,
+  MODIFY `category_id` BIGINT NOT NULL,
+  ADD KEY `idx_product_category_id_created_at_id` (`category_id`, `created_at`, `id`),
+  ADD FULLTEXT KEY `ft_product_search` (`name`, `brand`, `description`);
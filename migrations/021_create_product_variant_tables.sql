@@ -0,0 +1,54 @@
+-- the ways a product varies, e.g. Size, and the values it comes in, e.g. M
+CREATE TABLE IF NOT EXISTS `product_option` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `product_id` BIGINT NOT NULL,
+  `name` VARCHAR(64) NOT NULL,
+  `position` INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_product_option_name` (`product_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `product_option_value` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `option_id` BIGINT NOT NULL,
+  `value` VARCHAR(64) NOT NULL,
+  `position` INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_product_option_value` (`option_id`, `value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- a variant has its own price and stock, the count_in_stock of its product
+-- is kept to the sum of the stock of its variants
+CREATE TABLE IF NOT EXISTS `product_variant` (
+  `id` BIGINT NOT NULL AUTO_INCREMENT,
+  `product_id` BIGINT NOT NULL,
+  `sku` VARCHAR(64) NOT NULL,
+  `price` BIGINT NOT NULL,
+  `currency` CHAR(3) NOT NULL,
+  `count_in_stock` INT NOT NULL DEFAULT 0,
+  `image` VARCHAR(512) NOT NULL DEFAULT '',
+  `barcode` VARCHAR(64) NOT NULL DEFAULT '',
+  `updated_at` DATETIME NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_product_variant_sku` (`sku`),
+  KEY `idx_product_variant_product_id` (`product_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- the value of each option of the product a variant is made of
+CREATE TABLE IF NOT EXISTS `product_variant_value` (
+  `variant_id` BIGINT NOT NULL,
+  `option_value_id` BIGINT NOT NULL,
+  PRIMARY KEY (`variant_id`, `option_value_id`),
+  KEY `idx_product_variant_value_option_value_id` (`option_value_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 0 stands for a product without variants, it keeps the line in the key
+ALTER TABLE `cart_item`
+  ADD COLUMN `variant_id` BIGINT NOT NULL DEFAULT 0 AFTER `product_id`,
+  DROP PRIMARY KEY,
+  ADD PRIMARY KEY (`cart_id`, `product_id`, `variant_id`);
+
+ALTER TABLE `order_item`
+  ADD COLUMN `variant_id` BIGINT NOT NULL DEFAULT 0 AFTER `product_id`,
+  ADD COLUMN `sku` VARCHAR(64) NOT NULL DEFAULT '' AFTER `variant_id`;
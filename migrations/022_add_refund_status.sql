@@ -0,0 +1,5 @@
+-- the payment provider is asked for the refund after it is recorded, the
+-- refunds recorded before were all refunded at once
+ALTER TABLE `refund`
+  ADD COLUMN `status` VARCHAR(16) NOT NULL DEFAULT 'succeeded' AFTER `provider_ref`,
+  ADD COLUMN `failure_reason` VARCHAR(255) NOT NULL DEFAULT '' AFTER `status`;
@@ -0,0 +1,4 @@
+-- a TOTP code is accepted once, the time step of the last accepted code is
+-- kept and codes of that step or an earlier one are refused
+ALTER TABLE `user_mfa`
+  ADD COLUMN `last_used_step` BIGINT NOT NULL DEFAULT 0 AFTER `is_enabled`;
@@ -0,0 +1,6 @@
+-- a verification token only proves the address it was sent to, the tokens
+-- sent before are tied to the current email of their user
+ALTER TABLE `email_verification`
+  ADD COLUMN `email` VARCHAR(255) NOT NULL DEFAULT '' AFTER `user_id`;
+
+UPDATE `email_verification` v JOIN `user` u ON u.id = v.user_id SET v.email = u.email;
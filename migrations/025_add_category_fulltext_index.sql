@@ -0,0 +1,4 @@
+-- catalog search matches the name of the category of a product and of the
+-- categories above it with MATCH ... AGAINST
+ALTER TABLE `category`
+  ADD FULLTEXT KEY `ft_category_name` (`name`);
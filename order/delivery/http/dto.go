@@ -0,0 +1,59 @@
+package http
+
+import (
+	"reflect"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// amounts are validated on their minor units, e.g. validate:"gte=0"
+	v.RegisterCustomTypeFunc(moneyAmount, domain.Money{})
+	return v
+}
+
+func moneyAmount(field reflect.Value) interface{} {
+	return field.Interface().(domain.Money).Amount
+}
+
+// createOrderRequest is only accepted from staff, it has neither tax nor
+// shipping which are computed by the server at checkout
+type createOrderRequest struct {
+	PayMethod  string       `json:"paymethod" validate:"required,max=64"`
+	TotalPrice domain.Money `json:"total_price" validate:"gte=0"`
+}
+
+func (r createOrderRequest) toOrder() domain.Order {
+	return domain.Order{
+		PayMethod:     r.PayMethod,
+		TaxPrice:      domain.NewMoney(0, r.TotalPrice.Currency),
+		ShippingPrice: domain.NewMoney(0, r.TotalPrice.Currency),
+		TotalPrice:    r.TotalPrice,
+	}
+}
+
+// updateOrderRequest is used by staff to correct the payment details of an
+// order, the status is changed through the transition endpoints. The tax is
+// computed by the server from the lines of the order.
+type updateOrderRequest struct {
+	PayMethod     string       `json:"paymethod" validate:"required,max=64"`
+	ShippingPrice domain.Money `json:"shipping_price" validate:"gte=0"`
+	TotalPrice    domain.Money `json:"total_price" validate:"gte=0"`
+}
+
+func (r updateOrderRequest) toOrder() domain.Order {
+	return domain.Order{
+		PayMethod:     r.PayMethod,
+		ShippingPrice: r.ShippingPrice,
+		TotalPrice:    r.TotalPrice,
+	}
+}
+
+// transitionRequest optionally explains why the status of an order is changed
+type transitionRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
@@ -0,0 +1,308 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// queryErrorResponse answers a list request with query params that were rejected
+type queryErrorResponse struct {
+	Message string                `json:"message"`
+	Details []domain.QueryProblem `json:"details"`
+}
+
+type OrderHandler struct {
+	OUsecase  domain.OrderUsecase
+	Paginator *pagination.Paginator
+}
+
+func NewOrderHandler(e *echo.Echo, oucase domain.OrderUsecase, pg *pagination.Paginator, mw *middleware.GoMiddleware) {
+	handler := &OrderHandler{
+		OUsecase:  oucase,
+		Paginator: pg,
+	}
+	e.GET("/orders/mine", handler.FetchMine, mw.Auth)
+	e.GET("/orders/mine/:id", handler.GetMine, mw.Auth)
+	e.POST("/orders/mine/:id/cancel", handler.CancelMine, mw.Auth)
+
+	// customers place orders through /cart/checkout, which prices them on the server
+	e.POST("/orders", handler.Store, mw.Auth, mw.RequirePermission(domain.PermissionOrderWrite))
+	e.GET("/orders", handler.FetchOrder, mw.Auth, mw.RequirePermission(domain.PermissionOrderRead))
+	e.GET("/orders/:id", handler.GetByID, mw.Auth, mw.RequirePermission(domain.PermissionOrderRead))
+	e.PUT("/orders/:id", handler.Update, mw.Auth, mw.RequirePermission(domain.PermissionOrderWrite))
+	e.DELETE("/orders/:id", handler.Delete, mw.Auth, mw.RequirePermission(domain.PermissionOrderDelete))
+	e.GET("/orders/:id/history", handler.FetchHistory, mw.Auth, mw.RequirePermission(domain.PermissionOrderRead))
+
+	e.POST("/orders/:id/pay", handler.Transition(domain.OrderStatusPaid), mw.Auth, mw.RequirePermission(domain.PermissionOrderWrite))
+	e.POST("/orders/:id/cancel", handler.Transition(domain.OrderStatusCancelled), mw.Auth, mw.RequirePermission(domain.PermissionOrderWrite))
+	e.POST("/orders/:id/ship", handler.Transition(domain.OrderStatusShipped), mw.Auth, mw.RequirePermission(domain.PermissionOrderShip))
+	e.POST("/orders/:id/deliver", handler.Transition(domain.OrderStatusDelivered), mw.Auth, mw.RequirePermission(domain.PermissionOrderShip))
+	e.POST("/orders/:id/return", handler.Transition(domain.OrderStatusReturned), mw.Auth, mw.RequirePermission(domain.PermissionOrderRefund))
+	// an order becomes refunded through POST /orders/:id/refunds, which gives the money back
+}
+
+// FetchOrder will list the orders of every customer
+func (o *OrderHandler) FetchOrder(c echo.Context) error {
+	page, err := o.Paginator.Page(c, domain.OrderListSchema)
+	var queryErr *domain.QueryError
+	if errors.As(err, &queryErr) {
+		return c.JSON(http.StatusBadRequest, queryErrorResponse{Message: domain.ErrBadParamInput.Error(), Details: queryErr.Problems})
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+
+	listOrder, info, err := o.OUsecase.Fetch(ctx, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	o.Paginator.SetHeaders(c, info)
+	return c.JSON(http.StatusOK, listOrder)
+}
+
+// FetchMine will list the orders of the authenticated user
+func (o *OrderHandler) FetchMine(c echo.Context) error {
+	page, err := o.Paginator.Page(c, domain.OrderListSchema)
+	var queryErr *domain.QueryError
+	if errors.As(err, &queryErr) {
+		return c.JSON(http.StatusBadRequest, queryErrorResponse{Message: domain.ErrBadParamInput.Error(), Details: queryErr.Problems})
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	listOrder, info, err := o.OUsecase.FetchByUser(ctx, auth.UserID, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	o.Paginator.SetHeaders(c, info)
+	return c.JSON(http.StatusOK, listOrder)
+}
+
+// GetMine will get an order of the authenticated user by given id
+func (o *OrderHandler) GetMine(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	order, err := o.OUsecase.GetUserOrder(ctx, auth.UserID, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// GetByID will get order by given id
+func (o *OrderHandler) GetByID(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	order, err := o.OUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// Store will let staff record an order placed outside the cart, in the name
+// of the authenticated staff member
+func (o *OrderHandler) Store(c echo.Context) (err error) {
+	var req createOrderRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	order := req.toOrder()
+	order.UserID = auth.UserID
+	err = o.OUsecase.Store(ctx, &order)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+// Update will change the order by given param
+func (o *OrderHandler) Update(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req updateOrderRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	order := req.toOrder()
+	order.ID = int64(idP)
+	ctx := c.Request().Context()
+	err = o.OUsecase.Update(ctx, &order)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// Transition returns the handler moving an order to the given status, the
+// route decides which roles may do that
+func (o *OrderHandler) Transition(to domain.OrderStatus) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idP, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+		}
+
+		var req transitionRequest
+		err = c.Bind(&req)
+		if err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, err.Error())
+		}
+		if err = validate.Struct(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+
+		ctx := c.Request().Context()
+		order, err := o.OUsecase.Transition(ctx, int64(idP), to, req.Reason)
+		if err != nil {
+			return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		}
+
+		return c.JSON(http.StatusOK, order)
+	}
+}
+
+// CancelMine will cancel a pending order of the authenticated user
+func (o *OrderHandler) CancelMine(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req transitionRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	order, err := o.OUsecase.CancelUserOrder(ctx, auth.UserID, int64(idP), req.Reason)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// FetchHistory will list the status changes of an order, oldest first
+func (o *OrderHandler) FetchHistory(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	history, err := o.OUsecase.FetchHistory(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// Delete will delete order by given param
+func (o *OrderHandler) Delete(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = o.OUsecase.Delete(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		return http.StatusConflict
+	}
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict, domain.ErrOrderPricesLocked:
+		return http.StatusConflict
+	case domain.ErrBadParamInput, domain.ErrCurrencyMismatch, domain.ErrInvalidCurrency:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden, domain.ErrUnverified:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,147 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	orderHttp "github.com/alfathaulia/ca_ecommerce_api/order/delivery/http"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchMine(t *testing.T) {
+	mockUcase := new(mocks.OrderUsecase)
+	mockListOrder := []domain.Order{{ID: 1, UserID: 3}}
+	next := &domain.Cursor{Sort: domain.OrderListSchema.DefaultSort, Key: time.Now().UTC(), ID: 1}
+	mockUcase.On("FetchByUser", mock.Anything, int64(3), domain.PageRequest{Limit: 100, Sort: domain.OrderListSchema.DefaultSort}).Return(mockListOrder, domain.PageInfo{Next: next}, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/orders/mine?num=500", strings.NewReader(""))
+	assert.NoError(t, err)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := orderHttp.OrderHandler{
+		OUsecase:  mockUcase,
+		Paginator: pagination.NewPaginator("cursor-secret", 10, 100),
+	}
+
+	err = handler.FetchMine(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, pagination.NewCodec("cursor-secret").Encode(*next), w.Header().Get("X-Cursor"))
+	mockUcase.AssertExpectations(t)
+}
+
+func TestGetMine(t *testing.T) {
+	mockUcase := new(mocks.OrderUsecase)
+	mockUcase.On("GetUserOrder", mock.Anything, int64(3), int64(7)).Return(domain.Order{}, domain.ErrNotFound)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/orders/mine/7", strings.NewReader(""))
+	assert.NoError(t, err)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	c.SetPath("orders/mine/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	handler := orderHttp.OrderHandler{
+		OUsecase: mockUcase,
+	}
+
+	err = handler.GetMine(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUcase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	mockUcase := new(mocks.OrderUsecase)
+	mockUcase.On("Store", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+		return o.UserID == 3 && o.PayMethod == "transfer" && o.TotalPrice == domain.NewMoney(16000000, "IDR") &&
+			o.TaxPrice == domain.NewMoney(0, "IDR") && o.ShippingPrice == domain.NewMoney(0, "IDR")
+	})).Return(nil).Once()
+
+	e := echo.New()
+	body := `{"paymethod":"transfer","tax_price":{"amount":100000,"currency":"IDR"},"shipping_price":{"amount":900000,"currency":"IDR"},"total_price":{"amount":16000000,"currency":"IDR"}}`
+	req, err := http.NewRequest(echo.POST, "/orders", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, IsVerified: true}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := orderHttp.OrderHandler{
+		OUsecase: mockUcase,
+	}
+
+	err = handler.Store(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUcase.AssertExpectations(t)
+}
+
+func TestTransition(t *testing.T) {
+	mockUcase := new(mocks.OrderUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		shipped := domain.Order{ID: 7, UserID: 3, Status: domain.OrderStatusShipped}
+		mockUcase.On("Transition", mock.Anything, int64(7), domain.OrderStatusShipped, "JNE 123").Return(shipped, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/orders/7/ship", strings.NewReader(`{"reason":"JNE 123"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("orders/:id/ship")
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		handler := orderHttp.OrderHandler{
+			OUsecase: mockUcase,
+		}
+
+		err = handler.Transition(domain.OrderStatusShipped)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"shipped"`)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("illegal-transition", func(t *testing.T) {
+		transitionErr := &domain.TransitionError{From: domain.OrderStatusDelivered, To: domain.OrderStatusCancelled}
+		mockUcase.On("Transition", mock.Anything, int64(7), domain.OrderStatusCancelled, "").Return(domain.Order{}, transitionErr).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/orders/7/cancel", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("orders/:id/cancel")
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		handler := orderHttp.OrderHandler{
+			OUsecase: mockUcase,
+		}
+
+		err = handler.Transition(domain.OrderStatusCancelled)(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "order is delivered and can not become cancelled")
+		mockUcase.AssertExpectations(t)
+	})
+}
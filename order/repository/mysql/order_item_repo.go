@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlOrderItemRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlOrderItemRepo will create an object that represent the domain.OrderItemRepository interface
+func NewMysqlOrderItemRepo(DB *sql.DB) domain.OrderItemRepository {
+	return &mysqlOrderItemRepo{DB: DB}
+}
+
+func (m *mysqlOrderItemRepo) FetchByOrder(ctx context.Context, orderID int64) (result []domain.OrderItem, err error) {
+	query := `SELECT id, order_id, product_id, variant_id, sku, name, qty, price, currency, image, tax_category, tax_rate, tax_inclusive, tax_amount FROM order_item WHERE order_id = ? ORDER BY id`
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, orderID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.OrderItem, 0)
+	for rows.Next() {
+		t := domain.OrderItem{}
+		err = rows.Scan(
+			&t.ID,
+			&t.OrderID,
+			&t.ProductID,
+			&t.VariantID,
+			&t.SKU,
+			&t.Name,
+			&t.Qty,
+			&t.Price,
+			&t.Price.Currency,
+			&t.Image,
+			&t.TaxCategory,
+			&t.TaxRate,
+			&t.TaxInclusive,
+			&t.TaxAmount,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.TaxAmount.Currency = t.Price.Currency
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlOrderItemRepo) Store(ctx context.Context, item *domain.OrderItem) (err error) {
+	query := `INSERT  order_item SET order_id=? , product_id=? , variant_id=? , sku=? , name=? , qty=? , price=? , currency=? , image=? , tax_category=? , tax_rate=? , tax_inclusive=? , tax_amount=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, item.OrderID, item.ProductID, item.VariantID, item.SKU, item.Name, item.Qty, item.Price, item.Price.Currency, item.Image, item.TaxCategory, item.TaxRate, item.TaxInclusive, item.TaxAmount)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	item.ID = lastID
+	return
+}
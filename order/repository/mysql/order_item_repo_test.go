@@ -0,0 +1,56 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	orderMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/order/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var orderItem = &domain.OrderItem{
+	ID:          1,
+	OrderID:     order.ID,
+	ProductID:   4,
+	VariantID:   7,
+	SKU:         "SHIRT-XL",
+	Name:        "Shirt",
+	Qty:         2,
+	Price:       domain.NewMoney(15000000, "IDR"),
+	Image:       "/images/shirt.jpg",
+	TaxCategory: domain.TaxCategoryStandard,
+	TaxRate:     1100,
+	TaxAmount:   domain.NewMoney(3300000, "IDR"),
+}
+
+func TestFetchByOrder(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"id", "order_id", "product_id", "variant_id", "sku", "name", "qty", "price", "currency", "image", "tax_category", "tax_rate", "tax_inclusive", "tax_amount"}).
+		AddRow(orderItem.ID, orderItem.OrderID, orderItem.ProductID, orderItem.VariantID, orderItem.SKU, orderItem.Name, orderItem.Qty, orderItem.Price.Amount, orderItem.Price.Currency, orderItem.Image,
+			orderItem.TaxCategory, orderItem.TaxRate, orderItem.TaxInclusive, orderItem.TaxAmount.Amount)
+
+	query := `SELECT id, order_id, product_id, variant_id, sku, name, qty, price, currency, image, tax_category, tax_rate, tax_inclusive, tax_amount FROM order_item WHERE order_id = \? ORDER BY id`
+	mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderItemRepo(db)
+	list, err := a.FetchByOrder(context.TODO(), order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.OrderItem{*orderItem}, list)
+}
+
+func TestStoreOrderItem(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  order_item SET order_id=\\? , product_id=\\? , variant_id=\\? , sku=\\? , name=\\? , qty=\\? , price=\\? , currency=\\? , image=\\? , tax_category=\\? , tax_rate=\\? , tax_inclusive=\\? , tax_amount=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(orderItem.OrderID, orderItem.ProductID, orderItem.VariantID, orderItem.SKU, orderItem.Name, orderItem.Qty, orderItem.Price, orderItem.Price.Currency, orderItem.Image, orderItem.TaxCategory, orderItem.TaxRate, orderItem.TaxInclusive, orderItem.TaxAmount).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	a := orderMysqlRepo.NewMysqlOrderItemRepo(db)
+	tmp := *orderItem
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tmp.ID)
+}
@@ -0,0 +1,271 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+const selectOrder = `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at
+  						FROM orders`
+
+// orderKeyset holds the columns of the fields of domain.OrderListSchema
+var orderKeyset = pagination.NewKeyset(map[string]string{
+	"status":      "status",
+	"pay_method":  "pay_method",
+	"total_price": "total_price",
+	"updated_at":  "updated_at",
+	"created_at":  "created_at",
+}, domain.OrderListSchema.DefaultSort)
+
+type mysqlOrderRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlOrderRepo will create an object that represent the domain.OrderRepository interface
+func NewMysqlOrderRepo(DB *sql.DB) domain.OrderRepository {
+	return &mysqlOrderRepo{DB: DB}
+}
+
+func (m *mysqlOrderRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Order, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Order, 0)
+	for rows.Next() {
+		t := domain.Order{}
+		var currency, displayCurrency domain.Currency
+		var rate domain.Rate
+		err = rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.PayMethod,
+			&t.TaxPrice,
+			&t.ShippingPrice,
+			&t.TotalPrice,
+			&t.RefundedTotal,
+			&currency,
+			&displayCurrency,
+			&rate,
+			&t.Status,
+			&t.PaidAt,
+			&t.DeliveredAt,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.TaxPrice.Currency = currency
+		t.ShippingPrice.Currency = currency
+		t.TotalPrice.Currency = currency
+		t.RefundedTotal.Currency = currency
+		t.RemainingBalance = domain.NewMoney(t.TotalPrice.Amount-t.RefundedTotal.Amount, currency)
+		if displayCurrency != "" {
+			t.LockExchangeRate(displayCurrency, rate)
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlOrderRepo) fetchPage(ctx context.Context, filter string, page domain.PageRequest, args ...interface{}) ([]domain.Order, domain.PageInfo, error) {
+	query, pageArgs, err := orderKeyset.Query(selectOrder, filter, page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	res, err := m.fetch(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	from, to, info := orderKeyset.Window(page, len(res), func(i int, field string) (interface{}, int64) {
+		return orderSortKey(res[i], field), res[i].ID
+	})
+	return res[from:to], info, nil
+}
+
+// orderSortKey is the value of the sortable field of o
+func orderSortKey(o domain.Order, field string) interface{} {
+	switch field {
+	case "total_price":
+		return o.TotalPrice.Amount
+	case "updated_at":
+		return o.UpdatedAt
+	}
+	return o.CreatedAt
+}
+
+func (m *mysqlOrderRepo) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.Order, domain.PageInfo, error) {
+	return m.fetchPage(ctx, "", page)
+}
+
+func (m *mysqlOrderRepo) FetchByUser(ctx context.Context, userID int64, page domain.PageRequest) ([]domain.Order, domain.PageInfo, error) {
+	return m.fetchPage(ctx, "user_id = ?", page, userID)
+}
+
+func (m *mysqlOrderRepo) GetByID(ctx context.Context, id int64) (res domain.Order, err error) {
+	query := selectOrder + ` WHERE id = ?`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+// GetByIDForUpdate is GetByID holding a write lock on the row, it only makes
+// sense inside a transaction where the lock lasts until commit or rollback
+func (m *mysqlOrderRepo) GetByIDForUpdate(ctx context.Context, id int64) (res domain.Order, err error) {
+	query := selectOrder + ` WHERE id = ? FOR UPDATE`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlOrderRepo) Store(ctx context.Context, o *domain.Order) (err error) {
+	query := `INSERT  orders SET user_id=? , pay_method=? , tax_price=? , shipping_price=? , total_price=? , currency=? , display_currency=? , exchange_rate=? , status=? , paid_at=? , delivered_at=? , updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, o.UserID, o.PayMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.TotalPrice.Currency, o.DisplayCurrency, o.ExchangeRate, o.Status, o.PaidAt, o.DeliveredAt, o.UpdatedAt, o.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	o.ID = lastID
+	o.RefundedTotal = domain.NewMoney(0, o.TotalPrice.Currency)
+	o.RemainingBalance = o.TotalPrice
+	return
+}
+
+// Update changes the payment details of an order, the status only moves through UpdateStatus
+func (m *mysqlOrderRepo) Update(ctx context.Context, o *domain.Order) (err error) {
+	query := `UPDATE  orders SET pay_method=? , tax_price=? , shipping_price=? , total_price=? , currency=? , updated_at=? WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, o.PayMethod, o.TaxPrice, o.ShippingPrice, o.TotalPrice, o.TotalPrice.Currency, o.UpdatedAt, o.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
+
+func (m *mysqlOrderRepo) UpdateStatus(ctx context.Context, o *domain.Order, from domain.OrderStatus) (err error) {
+	query := `UPDATE  orders SET status=? , paid_at=? , delivered_at=? , updated_at=? WHERE id=? AND status=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, o.Status, o.PaidAt, o.DeliveredAt, o.UpdatedAt, o.ID, from)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
+
+// AddRefunded only touches an order of the same currency as amount, the
+// refund of another currency is refused like one exceeding the balance
+func (m *mysqlOrderRepo) AddRefunded(ctx context.Context, id int64, amount domain.Money) (err error) {
+	query := `UPDATE  orders SET refunded_total = refunded_total + ? WHERE id = ? AND currency = ? AND refunded_total + ? <= total_price`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, amount, id, amount.Currency, amount)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrRefundExceedsBalance
+		return
+	}
+	return
+}
+
+func (m *mysqlOrderRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM orders WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", rowsAfected)
+		return
+	}
+	return
+}
@@ -0,0 +1,256 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	orderMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/order/repository/mysql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+var order = &domain.Order{
+	ID:               1,
+	UserID:           2,
+	PayMethod:        "transfer",
+	TaxPrice:         domain.NewMoney(100000, "IDR"),
+	ShippingPrice:    domain.NewMoney(900000, "IDR"),
+	TotalPrice:       domain.NewMoney(16000000, "IDR"),
+	RefundedTotal:    domain.NewMoney(0, "IDR"),
+	RemainingBalance: domain.NewMoney(16000000, "IDR"),
+	Status:           domain.OrderStatusPending,
+	UpdatedAt:        now,
+	CreatedAt:        now,
+}
+
+var orderColumns = []string{"id", "user_id", "pay_method", "tax_price", "shipping_price", "total_price", "refunded_total", "currency", "display_currency", "exchange_rate", "status", "paid_at", "delivered_at", "updated_at", "created_at"}
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func addOrderRow(rows *sqlmock.Rows, o domain.Order) *sqlmock.Rows {
+	return rows.AddRow(o.ID, o.UserID, o.PayMethod, o.TaxPrice.Amount, o.ShippingPrice.Amount, o.TotalPrice.Amount, o.RefundedTotal.Amount, o.TotalPrice.Currency, o.DisplayCurrency, []byte(o.ExchangeRate.String()), o.Status, o.PaidAt, o.DeliveredAt, o.UpdatedAt, o.CreatedAt)
+}
+
+func TestFetch(t *testing.T) {
+	db, mock := NewMock()
+
+	paid := *order
+	paid.ID = 2
+	paid.Status = domain.OrderStatusPaid
+	paid.PaidAt = &now
+	rows := sqlmock.NewRows(orderColumns)
+	addOrderRow(rows, *order)
+	addOrderRow(rows, paid)
+
+	query := `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at FROM orders ORDER BY created_at, id LIMIT \?`
+	mock.ExpectQuery(query).WithArgs(int64(3)).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	list, info, err := a.Fetch(context.TODO(), domain.PageRequest{Limit: 2})
+	assert.NoError(t, err)
+	assert.Nil(t, info.Next)
+	assert.Len(t, list, 2)
+	assert.Nil(t, list[0].PaidAt)
+	assert.Equal(t, now, *list[1].PaidAt)
+}
+
+func TestFetchByUser(t *testing.T) {
+	db, mock := NewMock()
+	rows := addOrderRow(sqlmock.NewRows(orderColumns), *order)
+
+	query := `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at FROM orders WHERE user_id = \? AND \(created_at > \? OR \(created_at = \? AND id > \?\)\) ORDER BY created_at, id LIMIT \?`
+	cursor := &domain.Cursor{Sort: domain.Sort{Field: "created_at"}, Key: now.Add(-time.Hour), ID: 9}
+	mock.ExpectQuery(query).WithArgs(order.UserID, cursor.Key, cursor.Key, cursor.ID, int64(11)).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	list, info, err := a.FetchByUser(context.TODO(), order.UserID, domain.PageRequest{Cursor: cursor, Limit: 10})
+	assert.NoError(t, err)
+	assert.Nil(t, info.Next)
+	assert.Equal(t, &domain.Cursor{Sort: cursor.Sort, Key: order.CreatedAt, ID: order.ID, Backward: true}, info.Prev)
+	assert.Len(t, list, 1)
+	assert.Equal(t, order.UserID, list[0].UserID)
+}
+
+func TestFetchByUserSorted(t *testing.T) {
+	db, mock := NewMock()
+	dear := *order
+	dear.ID = 2
+	dear.TotalPrice = domain.NewMoney(900000, order.TotalPrice.Currency)
+	rows := sqlmock.NewRows(orderColumns)
+	addOrderRow(rows, dear)
+	addOrderRow(rows, *order)
+
+	query := `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at FROM orders WHERE user_id = \? AND status = \? ORDER BY total_price DESC, id DESC LIMIT \?`
+	mock.ExpectQuery(query).WithArgs(order.UserID, "paid", int64(2)).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	byPrice := domain.Sort{Field: "total_price", Desc: true}
+	list, info, err := a.FetchByUser(context.TODO(), order.UserID, domain.PageRequest{
+		Limit:   1,
+		Sort:    byPrice,
+		Filters: []domain.Filter{{Field: "status", Op: domain.FilterEq, Value: "paid"}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, &domain.Cursor{Sort: byPrice, Key: int64(900000), ID: 2}, info.Next)
+	assert.Nil(t, info.Prev)
+}
+
+func TestGetByID(t *testing.T) {
+	db, mock := NewMock()
+	rows := addOrderRow(sqlmock.NewRows(orderColumns), *order)
+
+	query := `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at FROM orders WHERE id = \?`
+	mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	res, err := a.GetByID(context.TODO(), order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, *order, res)
+}
+
+func TestGetByIDForUpdate(t *testing.T) {
+	db, mock := NewMock()
+	rows := addOrderRow(sqlmock.NewRows(orderColumns), *order)
+
+	query := `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at FROM orders WHERE id = \? FOR UPDATE`
+	mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	res, err := a.GetByIDForUpdate(context.TODO(), order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, *order, res)
+}
+
+func TestGetByIDWithExchangeRate(t *testing.T) {
+	db, mock := NewMock()
+	locked := *order
+	locked.LockExchangeRate("USD", 6154)
+	rows := addOrderRow(sqlmock.NewRows(orderColumns), locked)
+
+	query := `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at FROM orders WHERE id = \?`
+	mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	res, err := a.GetByID(context.TODO(), order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Rate(6154), res.ExchangeRate)
+	assert.Equal(t, domain.NewMoney(985, "USD"), *res.DisplayTotalPrice)
+}
+
+func TestGetByIDNotFound(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `SELECT id, user_id, pay_method, tax_price, shipping_price, total_price, refunded_total, currency, display_currency, exchange_rate, status, paid_at, delivered_at, updated_at, created_at FROM orders WHERE id = \?`
+	mock.ExpectQuery(query).WithArgs(int64(9)).WillReturnRows(sqlmock.NewRows(orderColumns))
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	_, err := a.GetByID(context.TODO(), int64(9))
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestStore(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  orders SET user_id=\\? , pay_method=\\? , tax_price=\\? , shipping_price=\\? , total_price=\\? , currency=\\? , display_currency=\\? , exchange_rate=\\? , status=\\? , paid_at=\\? , delivered_at=\\? , updated_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(order.UserID, order.PayMethod, order.TaxPrice, order.ShippingPrice, order.TotalPrice, order.TotalPrice.Currency, order.DisplayCurrency, order.ExchangeRate, order.Status, order.PaidAt, order.DeliveredAt, order.UpdatedAt, order.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	tmp := *order
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), tmp.ID)
+}
+
+func TestUpdate(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  orders SET pay_method=\\? , tax_price=\\? , shipping_price=\\? , total_price=\\? , currency=\\? , updated_at=\\? WHERE id=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(order.PayMethod, order.TaxPrice, order.ShippingPrice, order.TotalPrice, order.TotalPrice.Currency, order.UpdatedAt, order.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	err := a.Update(context.TODO(), order)
+	assert.NoError(t, err)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	query := "UPDATE  orders SET status=\\? , paid_at=\\? , delivered_at=\\? , updated_at=\\? WHERE id=\\? AND status=\\?"
+	paid := *order
+	paid.Status = domain.OrderStatusPaid
+	paid.PaidAt = &now
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(paid.Status, paid.PaidAt, paid.DeliveredAt, paid.UpdatedAt, paid.ID, domain.OrderStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		a := orderMysqlRepo.NewMysqlOrderRepo(db)
+		err := a.UpdateStatus(context.TODO(), &paid, domain.OrderStatusPending)
+		assert.NoError(t, err)
+	})
+
+	t.Run("changed-concurrently", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(paid.Status, paid.PaidAt, paid.DeliveredAt, paid.UpdatedAt, paid.ID, domain.OrderStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		a := orderMysqlRepo.NewMysqlOrderRepo(db)
+		err := a.UpdateStatus(context.TODO(), &paid, domain.OrderStatusPending)
+		assert.Equal(t, domain.ErrConflict, err)
+	})
+}
+
+func TestAddRefunded(t *testing.T) {
+	query := "UPDATE  orders SET refunded_total = refunded_total \\+ \\? WHERE id = \\? AND currency = \\? AND refunded_total \\+ \\? <= total_price"
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(int64(5000000), order.ID, "IDR", int64(5000000)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		a := orderMysqlRepo.NewMysqlOrderRepo(db)
+		err := a.AddRefunded(context.TODO(), order.ID, domain.NewMoney(5000000, "IDR"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("exceeds-total", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(int64(99999900), order.ID, "IDR", int64(99999900)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		a := orderMysqlRepo.NewMysqlOrderRepo(db)
+		err := a.AddRefunded(context.TODO(), order.ID, domain.NewMoney(99999900, "IDR"))
+		assert.Equal(t, domain.ErrRefundExceedsBalance, err)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "DELETE FROM orders WHERE id = \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(order.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := orderMysqlRepo.NewMysqlOrderRepo(db)
+	err := a.Delete(context.TODO(), order.ID)
+	assert.NoError(t, err)
+}
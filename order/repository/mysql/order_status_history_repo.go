@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlOrderStatusHistoryRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlOrderStatusHistoryRepo will create an object that represent the domain.OrderStatusHistoryRepository interface
+func NewMysqlOrderStatusHistoryRepo(DB *sql.DB) domain.OrderStatusHistoryRepository {
+	return &mysqlOrderStatusHistoryRepo{DB: DB}
+}
+
+func (m *mysqlOrderStatusHistoryRepo) FetchByOrder(ctx context.Context, orderID int64) (result []domain.OrderStatusHistory, err error) {
+	query := `SELECT id, order_id, from_status, to_status, changed_by, reason, created_at FROM order_status_history WHERE order_id = ? ORDER BY id`
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, orderID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.OrderStatusHistory, 0)
+	for rows.Next() {
+		t := domain.OrderStatusHistory{}
+		var changedBy sql.NullInt64
+		err = rows.Scan(
+			&t.ID,
+			&t.OrderID,
+			&t.FromStatus,
+			&t.ToStatus,
+			&changedBy,
+			&t.Reason,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.ChangedBy = changedBy.Int64
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlOrderStatusHistoryRepo) Store(ctx context.Context, h *domain.OrderStatusHistory) (err error) {
+	query := `INSERT  order_status_history SET order_id=? , from_status=? , to_status=? , changed_by=? , reason=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	changedBy := sql.NullInt64{Int64: h.ChangedBy, Valid: h.ChangedBy != 0}
+	res, err := stmt.ExecContext(ctx, h.OrderID, h.FromStatus, h.ToStatus, changedBy, h.Reason, h.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	h.ID = lastID
+	return
+}
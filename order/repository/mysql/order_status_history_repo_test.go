@@ -0,0 +1,60 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	orderMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/order/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var historyColumns = []string{"id", "order_id", "from_status", "to_status", "changed_by", "reason", "created_at"}
+
+func TestFetchStatusHistory(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows(historyColumns).
+		AddRow(1, order.ID, domain.OrderStatusPending, domain.OrderStatusPaid, nil, "", now).
+		AddRow(2, order.ID, domain.OrderStatusPaid, domain.OrderStatusShipped, 7, "JNE 123", now)
+
+	query := `SELECT id, order_id, from_status, to_status, changed_by, reason, created_at FROM order_status_history WHERE order_id = \? ORDER BY id`
+	mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlOrderStatusHistoryRepo(db)
+	list, err := a.FetchByOrder(context.TODO(), order.ID)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, int64(0), list[0].ChangedBy)
+	assert.Equal(t, int64(7), list[1].ChangedBy)
+	assert.Equal(t, domain.OrderStatusShipped, list[1].ToStatus)
+}
+
+func TestStoreStatusHistory(t *testing.T) {
+	query := "INSERT  order_status_history SET order_id=\\? , from_status=\\? , to_status=\\? , changed_by=\\? , reason=\\? , created_at=\\?"
+
+	t.Run("by-user", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(order.ID, domain.OrderStatusPaid, domain.OrderStatusShipped, int64(7), "JNE 123", now).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		a := orderMysqlRepo.NewMysqlOrderStatusHistoryRepo(db)
+		h := domain.OrderStatusHistory{OrderID: order.ID, FromStatus: domain.OrderStatusPaid, ToStatus: domain.OrderStatusShipped, ChangedBy: 7, Reason: "JNE 123", CreatedAt: now}
+		err := a.Store(context.TODO(), &h)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), h.ID)
+	})
+
+	t.Run("by-system", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(order.ID, domain.OrderStatusPending, domain.OrderStatusPaid, nil, "", now).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+
+		a := orderMysqlRepo.NewMysqlOrderStatusHistoryRepo(db)
+		h := domain.OrderStatusHistory{OrderID: order.ID, FromStatus: domain.OrderStatusPending, ToStatus: domain.OrderStatusPaid, CreatedAt: now}
+		err := a.Store(context.TODO(), &h)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlShippingAddressRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlShippingAddressRepo will create an object that represent the domain.ShippingAddressRepository interface
+func NewMysqlShippingAddressRepo(DB *sql.DB) domain.ShippingAddressRepository {
+	return &mysqlShippingAddressRepo{DB: DB}
+}
+
+func (m *mysqlShippingAddressRepo) GetByOrderID(ctx context.Context, orderID int64) (res domain.ShippingAddress, err error) {
+	query := `SELECT id, order_id, address, city, postal_code, country, shipping_method, shipping_price, currency FROM shipping_address WHERE order_id = ?`
+
+	err = transaction.Conn(ctx, m.DB).QueryRowContext(ctx, query, orderID).Scan(
+		&res.ID,
+		&res.OrderID,
+		&res.Address,
+		&res.City,
+		&res.PostalCode,
+		&res.Country,
+		&res.ShippingMethod,
+		&res.ShippingPrice,
+		&res.ShippingPrice.Currency,
+	)
+	if err == sql.ErrNoRows {
+		return domain.ShippingAddress{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.ShippingAddress{}, err
+	}
+	return
+}
+
+func (m *mysqlShippingAddressRepo) Store(ctx context.Context, a *domain.ShippingAddress) (err error) {
+	query := `INSERT  shipping_address SET order_id=? , address=? , city=? , postal_code=? , country=? , shipping_method=? , shipping_price=? , currency=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.OrderID, a.Address, a.City, a.PostalCode, a.Country, a.ShippingMethod, a.ShippingPrice, a.ShippingPrice.Currency)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	return
+}
@@ -0,0 +1,62 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	orderMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/order/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var shippingAddress = &domain.ShippingAddress{
+	ID:             1,
+	OrderID:        order.ID,
+	Address:        "Jl. Merdeka 1",
+	City:           "Bandung",
+	PostalCode:     "40111",
+	Country:        "Indonesia",
+	ShippingMethod: domain.ShippingMethodStandard,
+	ShippingPrice:  domain.NewMoney(1000000, "IDR"),
+}
+
+func TestGetShippingAddressByOrderID(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"id", "order_id", "address", "city", "postal_code", "country", "shipping_method", "shipping_price", "currency"}).
+		AddRow(shippingAddress.ID, shippingAddress.OrderID, shippingAddress.Address, shippingAddress.City, shippingAddress.PostalCode, shippingAddress.Country, shippingAddress.ShippingMethod, shippingAddress.ShippingPrice.Amount, shippingAddress.ShippingPrice.Currency)
+
+	query := `SELECT id, order_id, address, city, postal_code, country, shipping_method, shipping_price, currency FROM shipping_address WHERE order_id = \?`
+	mock.ExpectQuery(query).WithArgs(order.ID).WillReturnRows(rows)
+
+	a := orderMysqlRepo.NewMysqlShippingAddressRepo(db)
+	res, err := a.GetByOrderID(context.TODO(), order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, *shippingAddress, res)
+}
+
+func TestGetShippingAddressByOrderIDNotFound(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `SELECT id, order_id, address, city, postal_code, country, shipping_method, shipping_price, currency FROM shipping_address WHERE order_id = \?`
+	mock.ExpectQuery(query).WithArgs(int64(9)).WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "address", "city", "postal_code", "country", "shipping_method", "shipping_price", "currency"}))
+
+	a := orderMysqlRepo.NewMysqlShippingAddressRepo(db)
+	_, err := a.GetByOrderID(context.TODO(), int64(9))
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestStoreShippingAddress(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  shipping_address SET order_id=\\? , address=\\? , city=\\? , postal_code=\\? , country=\\? , shipping_method=\\? , shipping_price=\\? , currency=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(shippingAddress.OrderID, shippingAddress.Address, shippingAddress.City, shippingAddress.PostalCode, shippingAddress.Country, shippingAddress.ShippingMethod, shippingAddress.ShippingPrice, shippingAddress.ShippingPrice.Currency).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	a := orderMysqlRepo.NewMysqlShippingAddressRepo(db)
+	tmp := *shippingAddress
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), tmp.ID)
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type orderUsecase struct {
+	orderRepo      domain.OrderRepository
+	orderItemRepo  domain.OrderItemRepository
+	addressRepo    domain.ShippingAddressRepository
+	historyRepo    domain.OrderStatusHistoryRepository
+	productRepo    domain.ProductRepository
+	variantRepo    domain.VariantRepository
+	transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// orderTransitions is the lifecycle of an order: the statuses each status may
+// move to. Cancelled and refunded orders are final.
+var orderTransitions = map[domain.OrderStatus][]domain.OrderStatus{
+	domain.OrderStatusPending:   {domain.OrderStatusPaid, domain.OrderStatusCancelled},
+	domain.OrderStatusPaid:      {domain.OrderStatusShipped, domain.OrderStatusRefunded},
+	domain.OrderStatusShipped:   {domain.OrderStatusDelivered, domain.OrderStatusReturned},
+	domain.OrderStatusDelivered: {domain.OrderStatusReturned, domain.OrderStatusRefunded},
+	domain.OrderStatusReturned:  {domain.OrderStatusRefunded},
+}
+
+func canTransition(from, to domain.OrderStatus) bool {
+	for _, s := range orderTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NewOrderUsecase will create an object that represent the domain.OrderUsecase interface
+func NewOrderUsecase(o domain.OrderRepository, oi domain.OrderItemRepository, sa domain.ShippingAddressRepository, oh domain.OrderStatusHistoryRepository, p domain.ProductRepository, v domain.VariantRepository, tx domain.Transactor, timeout time.Duration) domain.OrderUsecase {
+	return &orderUsecase{
+		orderRepo:      o,
+		orderItemRepo:  oi,
+		addressRepo:    sa,
+		historyRepo:    oh,
+		productRepo:    p,
+		variantRepo:    v,
+		transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (m *orderUsecase) Fetch(ctx context.Context, page domain.PageRequest) (res []domain.Order, info domain.PageInfo, err error) {
+	if page.Limit == 0 {
+		page.Limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, info, err = m.orderRepo.Fetch(ctx, page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return
+}
+
+func (m *orderUsecase) FetchByUser(ctx context.Context, userID int64, page domain.PageRequest) (res []domain.Order, info domain.PageInfo, err error) {
+	if page.Limit == 0 {
+		page.Limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, info, err = m.orderRepo.FetchByUser(ctx, userID, page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return
+}
+
+func (m *orderUsecase) GetByID(ctx context.Context, id int64) (domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return m.withDetails(ctx, res)
+}
+
+// withDetails attaches the lines and the shipping address of the order.
+// Orders not placed through the cart checkout have no shipping address.
+func (m *orderUsecase) withDetails(ctx context.Context, o domain.Order) (domain.Order, error) {
+	items, err := m.orderItemRepo.FetchByOrder(ctx, o.ID)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	o.Items = items
+
+	address, err := m.addressRepo.GetByOrderID(ctx, o.ID)
+	if err == domain.ErrNotFound {
+		return o, nil
+	}
+	if err != nil {
+		return domain.Order{}, err
+	}
+	o.ShippingAddress = &address
+	return o, nil
+}
+
+// GetUserOrder returns the order only when it belongs to userID. Orders of
+// other users are reported as not found so their ids can not be probed.
+func (m *orderUsecase) GetUserOrder(ctx context.Context, userID int64, id int64) (domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if res.UserID != userID {
+		return domain.Order{}, domain.ErrNotFound
+	}
+	return m.withDetails(ctx, res)
+}
+
+// Transition moves the order along its lifecycle and records the change in
+// the status history, both in one transaction. A cancelled order puts the
+// units taken out of the stock at checkout back.
+func (m *orderUsecase) Transition(ctx context.Context, id int64, to domain.OrderStatus, reason string) (domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.transition(ctx, id, to, reason, func(domain.Order) error { return nil })
+}
+
+func (m *orderUsecase) CancelUserOrder(ctx context.Context, userID int64, id int64, reason string) (domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.transition(ctx, id, domain.OrderStatusCancelled, reason, func(o domain.Order) error {
+		if o.UserID != userID {
+			return domain.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// transition runs check on the current order before moving it, so callers
+// can add their own rules to the transition table
+func (m *orderUsecase) transition(ctx context.Context, id int64, to domain.OrderStatus, reason string, check func(domain.Order) error) (res domain.Order, err error) {
+	var changedBy int64
+	if auth, ok := domain.AuthFromContext(ctx); ok {
+		changedBy = auth.UserID
+	}
+
+	err = m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		res, err = m.orderRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = check(res); err != nil {
+			return err
+		}
+		from := res.Status
+		if !canTransition(from, to) {
+			return &domain.TransitionError{From: from, To: to}
+		}
+
+		now := time.Now()
+		res.Status = to
+		res.UpdatedAt = now
+		switch to {
+		case domain.OrderStatusPaid:
+			res.PaidAt = &now
+		case domain.OrderStatusDelivered:
+			res.DeliveredAt = &now
+		}
+		if err = m.orderRepo.UpdateStatus(ctx, &res, from); err != nil {
+			return err
+		}
+		if to == domain.OrderStatusCancelled {
+			if err = m.restock(ctx, id); err != nil {
+				return err
+			}
+		}
+		return m.historyRepo.Store(ctx, &domain.OrderStatusHistory{
+			OrderID:    id,
+			FromStatus: from,
+			ToStatus:   to,
+			ChangedBy:  changedBy,
+			Reason:     reason,
+			CreatedAt:  now,
+		})
+	})
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return res, nil
+}
+
+// restock puts the units of every line of the order back into the stock of
+// its variant, when it has one, and of its product. Products and variants
+// removed from the catalog have no stock to go back to.
+func (m *orderUsecase) restock(ctx context.Context, id int64) error {
+	items, err := m.orderItemRepo.FetchByOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.VariantID != 0 {
+			err = m.variantRepo.IncrementStock(ctx, item.VariantID, item.Qty)
+			if err == domain.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		err = m.productRepo.IncrementStock(ctx, item.ProductID, item.Qty)
+		if err != nil && err != domain.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *orderUsecase) FetchHistory(ctx context.Context, id int64) ([]domain.OrderStatusHistory, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if _, err := m.orderRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return m.historyRepo.FetchByOrder(ctx, id)
+}
+
+// Update changes the payment details of an order. The status is left alone,
+// it only moves through Transition. The prices of a pending order are worked
+// out again from its lines, the tax is never taken from the caller. Once the
+// order is no longer pending they are what the customer was charged and can
+// not change.
+func (m *orderUsecase) Update(ctx context.Context, o *domain.Order) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err = domain.SameCurrency(o.ShippingPrice, o.TotalPrice); err != nil {
+		return
+	}
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// the lock keeps a refund from working on prices about to change
+		existed, err := m.orderRepo.GetByIDForUpdate(ctx, o.ID)
+		if err != nil {
+			return err
+		}
+		if existed.Status != domain.OrderStatusPending {
+			if o.ShippingPrice != existed.ShippingPrice || o.TotalPrice != existed.TotalPrice {
+				return domain.ErrOrderPricesLocked
+			}
+			o.TaxPrice = existed.TaxPrice
+		} else if err = m.reprice(ctx, o); err != nil {
+			return err
+		}
+
+		o.UserID = existed.UserID
+		o.Status = existed.Status
+		o.PaidAt = existed.PaidAt
+		o.DeliveredAt = existed.DeliveredAt
+		o.CreatedAt = existed.CreatedAt
+		o.UpdatedAt = time.Now()
+		return m.orderRepo.Update(ctx, o)
+	})
+}
+
+// reprice sets the tax of the order to the tax of its lines and the total to
+// its lines and shipping. An order without lines, made by staff, keeps the
+// total it is given and has no tax.
+func (m *orderUsecase) reprice(ctx context.Context, o *domain.Order) error {
+	items, err := m.orderItemRepo.FetchByOrder(ctx, o.ID)
+	if err != nil {
+		return err
+	}
+	o.TaxPrice = domain.NewMoney(0, o.TotalPrice.Currency)
+	if len(items) == 0 {
+		return nil
+	}
+
+	o.TaxPrice = domain.NewMoney(0, items[0].Price.Currency)
+	o.TotalPrice = o.ShippingPrice
+	for _, item := range items {
+		if o.TotalPrice, err = o.TotalPrice.Add(item.Charged(item.Qty)); err != nil {
+			return err
+		}
+		if o.TaxPrice, err = o.TaxPrice.Add(item.TaxAmount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *orderUsecase) Store(ctx context.Context, o *domain.Order) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err = domain.SameCurrency(o.TaxPrice, o.ShippingPrice, o.TotalPrice); err != nil {
+		return
+	}
+	o.Status = domain.OrderStatusPending
+	o.PaidAt = nil
+	o.DeliveredAt = nil
+	o.CreatedAt = time.Now()
+	o.UpdatedAt = o.CreatedAt
+	return m.orderRepo.Store(ctx, o)
+}
+
+func (m *orderUsecase) Delete(ctx context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	_, err = m.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	return m.orderRepo.Delete(ctx, id)
+}
@@ -0,0 +1,350 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	ucase "github.com/alfathaulia/ca_ecommerce_api/order/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFetchByUser(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockHistoryRepo := new(mocks.OrderStatusHistoryRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockTransactor := new(mocks.Transactor)
+	mockListOrder := []domain.Order{{ID: 1, UserID: 2}}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo.On("FetchByUser", mock.Anything, int64(2), domain.PageRequest{Limit: 10}).Return(mockListOrder, domain.PageInfo{}, nil).Once()
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		list, _, err := u.FetchByUser(context.TODO(), 2, domain.PageRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockOrderRepo.On("FetchByUser", mock.Anything, int64(2), domain.PageRequest{Limit: 5}).Return(nil, domain.PageInfo{}, errors.New("Unexpexted Error")).Once()
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		list, _, err := u.FetchByUser(context.TODO(), 2, domain.PageRequest{Limit: 5})
+		assert.Error(t, err)
+		assert.Len(t, list, 0)
+		mockOrderRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetUserOrder(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockHistoryRepo := new(mocks.OrderStatusHistoryRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockTransactor := new(mocks.Transactor)
+	mockOrder := domain.Order{ID: 1, UserID: 2}
+
+	t.Run("success", func(t *testing.T) {
+		items := []domain.OrderItem{{ID: 1, OrderID: mockOrder.ID, ProductID: 4, Qty: 2}}
+		mockOrderRepo.On("GetByID", mock.Anything, mockOrder.ID).Return(mockOrder, nil).Once()
+		mockOrderItemRepo.On("FetchByOrder", mock.Anything, mockOrder.ID).Return(items, nil).Once()
+		mockAddressRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return(domain.ShippingAddress{}, domain.ErrNotFound).Once()
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		res, err := u.GetUserOrder(context.TODO(), mockOrder.UserID, mockOrder.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, mockOrder.ID, res.ID)
+		assert.Equal(t, items, res.Items)
+		assert.Nil(t, res.ShippingAddress)
+		mockOrderRepo.AssertExpectations(t)
+		mockOrderItemRepo.AssertExpectations(t)
+		mockAddressRepo.AssertExpectations(t)
+	})
+
+	t.Run("with-shipping-address", func(t *testing.T) {
+		address := domain.ShippingAddress{ID: 3, OrderID: mockOrder.ID, Address: "Jl. Merdeka 1", City: "Bandung", PostalCode: "40111", Country: "Indonesia"}
+		mockOrderRepo.On("GetByID", mock.Anything, mockOrder.ID).Return(mockOrder, nil).Once()
+		mockOrderItemRepo.On("FetchByOrder", mock.Anything, mockOrder.ID).Return([]domain.OrderItem{}, nil).Once()
+		mockAddressRepo.On("GetByOrderID", mock.Anything, mockOrder.ID).Return(address, nil).Once()
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		res, err := u.GetUserOrder(context.TODO(), mockOrder.UserID, mockOrder.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, &address, res.ShippingAddress)
+		mockAddressRepo.AssertExpectations(t)
+	})
+
+	t.Run("other-users-order", func(t *testing.T) {
+		mockOrderRepo.On("GetByID", mock.Anything, mockOrder.ID).Return(mockOrder, nil).Once()
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		_, err := u.GetUserOrder(context.TODO(), 99, mockOrder.ID)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+}
+
+func idr(amount int64) domain.Money {
+	return domain.NewMoney(amount, "IDR")
+}
+
+func TestStore(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockHistoryRepo := new(mocks.OrderStatusHistoryRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockTransactor := new(mocks.Transactor)
+
+	t.Run("success", func(t *testing.T) {
+		paidAt := time.Now()
+		o := domain.Order{UserID: 2, PayMethod: "transfer", TaxPrice: idr(0), ShippingPrice: idr(0), TotalPrice: idr(15000000), Status: domain.OrderStatusPaid, PaidAt: &paidAt}
+		mockOrderRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		err := u.Store(context.TODO(), &o)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusPending, o.Status)
+		assert.Nil(t, o.PaidAt)
+		assert.False(t, o.CreatedAt.IsZero())
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("mixed-currencies", func(t *testing.T) {
+		o := domain.Order{UserID: 2, PayMethod: "transfer", TaxPrice: idr(0), ShippingPrice: domain.NewMoney(500, "USD"), TotalPrice: idr(15000000)}
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		err := u.Store(context.TODO(), &o)
+		assert.Equal(t, domain.ErrCurrencyMismatch, err)
+		mockOrderRepo.AssertNotCalled(t, "Store", mock.Anything, &o)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockHistoryRepo := new(mocks.OrderStatusHistoryRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	existing := domain.Order{ID: 1, UserID: 2, PayMethod: "transfer", TaxPrice: idr(0), ShippingPrice: idr(0), TotalPrice: idr(15000000),
+		Status: domain.OrderStatusShipped, CreatedAt: time.Now().Add(-time.Hour)}
+	newUsecase := func(orderRepo *mocks.OrderRepository, itemRepo *mocks.OrderItemRepository) domain.OrderUsecase {
+		transactor := new(mocks.Transactor)
+		transactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Maybe()
+		return ucase.NewOrderUsecase(orderRepo, itemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, transactor, time.Second*2)
+	}
+
+	t.Run("keeps-status", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		o := domain.Order{ID: 1, UserID: 99, PayMethod: "cod", ShippingPrice: idr(0), TotalPrice: idr(15000000), Status: domain.OrderStatusDelivered}
+		mockOrderRepo.On("GetByIDForUpdate", mock.Anything, existing.ID).Return(existing, nil).Once()
+		mockOrderRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Order")).Return(nil).Once()
+		u := newUsecase(mockOrderRepo, new(mocks.OrderItemRepository))
+		err := u.Update(context.TODO(), &o)
+		assert.NoError(t, err)
+		assert.Equal(t, existing.UserID, o.UserID)
+		assert.Equal(t, domain.OrderStatusShipped, o.Status)
+		assert.Equal(t, "cod", o.PayMethod)
+		assert.Nil(t, o.DeliveredAt)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("prices-locked-after-pending", func(t *testing.T) {
+		for _, status := range []domain.OrderStatus{domain.OrderStatusPaid, domain.OrderStatusShipped, domain.OrderStatusRefunded} {
+			mockOrderRepo := new(mocks.OrderRepository)
+			locked := existing
+			locked.Status = status
+			o := domain.Order{ID: 1, PayMethod: "cod", ShippingPrice: idr(0), TotalPrice: idr(100)}
+			mockOrderRepo.On("GetByIDForUpdate", mock.Anything, existing.ID).Return(locked, nil).Once()
+			u := newUsecase(mockOrderRepo, new(mocks.OrderItemRepository))
+			err := u.Update(context.TODO(), &o)
+			assert.Equal(t, domain.ErrOrderPricesLocked, err)
+			mockOrderRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("pending-is-repriced-from-its-lines", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockOrderItemRepo := new(mocks.OrderItemRepository)
+		pending := existing
+		pending.Status = domain.OrderStatusPending
+		mockOrderRepo.On("GetByIDForUpdate", mock.Anything, existing.ID).Return(pending, nil).Once()
+		mockOrderItemRepo.On("FetchByOrder", mock.Anything, existing.ID).Return([]domain.OrderItem{
+			{ProductID: 4, Qty: 2, Price: idr(5000000), TaxRate: 1100, TaxAmount: idr(1100000)},
+			{ProductID: 5, Qty: 1, Price: idr(2000000), TaxRate: 1100, TaxInclusive: true, TaxAmount: idr(198198)},
+		}, nil).Once()
+		mockOrderRepo.On("Update", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.TaxPrice == idr(1298198) && o.TotalPrice == idr(13600000)
+		})).Return(nil).Once()
+
+		// the total and tax sent are not taken
+		o := domain.Order{ID: 1, PayMethod: "cod", TaxPrice: idr(0), ShippingPrice: idr(500000), TotalPrice: idr(100)}
+		u := newUsecase(mockOrderRepo, mockOrderItemRepo)
+		err := u.Update(context.TODO(), &o)
+		assert.NoError(t, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockOrderRepo := new(mocks.OrderRepository)
+		o := domain.Order{ID: 9, ShippingPrice: idr(0), TotalPrice: idr(0)}
+		mockOrderRepo.On("GetByIDForUpdate", mock.Anything, o.ID).Return(domain.Order{}, domain.ErrNotFound).Once()
+		u := newUsecase(mockOrderRepo, new(mocks.OrderItemRepository))
+		err := u.Update(context.TODO(), &o)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockHistoryRepo := new(mocks.OrderStatusHistoryRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockTransactor := new(mocks.Transactor)
+	mockOrder := domain.Order{ID: 1, UserID: 2}
+
+	t.Run("success", func(t *testing.T) {
+		mockOrderRepo.On("GetByID", mock.Anything, mockOrder.ID).Return(mockOrder, nil).Once()
+		mockOrderRepo.On("Delete", mock.Anything, mockOrder.ID).Return(nil).Once()
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		err := u.Delete(context.TODO(), mockOrder.ID)
+		assert.NoError(t, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+}
+
+// runInTransaction makes the Transactor mock call the unit of work directly
+func runInTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestTransition(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockHistoryRepo := new(mocks.OrderStatusHistoryRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockTransactor := new(mocks.Transactor)
+	staff := domain.NewContextWithAuth(context.TODO(), &domain.TokenPayload{UserID: 7, Role: domain.RolesTypeStaff})
+
+	t.Run("success", func(t *testing.T) {
+		paid := domain.Order{ID: 1, UserID: 2, Status: domain.OrderStatusPaid}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, paid.ID).Return(paid, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+			return o.Status == domain.OrderStatusShipped
+		}), domain.OrderStatusPaid).Return(nil).Once()
+		mockHistoryRepo.On("Store", mock.Anything, mock.MatchedBy(func(h *domain.OrderStatusHistory) bool {
+			return h.OrderID == paid.ID && h.FromStatus == domain.OrderStatusPaid && h.ToStatus == domain.OrderStatusShipped &&
+				h.ChangedBy == 7 && h.Reason == "JNE 123"
+		})).Return(nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		res, err := u.Transition(staff, paid.ID, domain.OrderStatusShipped, "JNE 123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusShipped, res.Status)
+		mockTransactor.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+		mockHistoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("sets-paid-at", func(t *testing.T) {
+		pending := domain.Order{ID: 1, UserID: 2, Status: domain.OrderStatusPending}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, pending.ID).Return(pending, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*domain.Order"), domain.OrderStatusPending).Return(nil).Once()
+		mockHistoryRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.OrderStatusHistory")).Return(nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		res, err := u.Transition(staff, pending.ID, domain.OrderStatusPaid, "")
+		assert.NoError(t, err)
+		assert.NotNil(t, res.PaidAt)
+		assert.Nil(t, res.DeliveredAt)
+	})
+
+	t.Run("illegal-transition", func(t *testing.T) {
+		delivered := domain.Order{ID: 1, UserID: 2, Status: domain.OrderStatusDelivered}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, delivered.ID).Return(delivered, nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		_, err := u.Transition(staff, delivered.ID, domain.OrderStatusCancelled, "")
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+		assert.Equal(t, &domain.TransitionError{From: domain.OrderStatusDelivered, To: domain.OrderStatusCancelled}, err)
+		mockOrderRepo.AssertExpectations(t)
+	})
+
+	t.Run("final-status", func(t *testing.T) {
+		refunded := domain.Order{ID: 1, UserID: 2, Status: domain.OrderStatusRefunded}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, refunded.ID).Return(refunded, nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		_, err := u.Transition(staff, refunded.ID, domain.OrderStatusPaid, "")
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	})
+}
+
+func TestCancelUserOrder(t *testing.T) {
+	mockOrderRepo := new(mocks.OrderRepository)
+	mockOrderItemRepo := new(mocks.OrderItemRepository)
+	mockAddressRepo := new(mocks.ShippingAddressRepository)
+	mockHistoryRepo := new(mocks.OrderStatusHistoryRepository)
+	mockProductRepo := new(mocks.ProductRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	mockTransactor := new(mocks.Transactor)
+
+	t.Run("success", func(t *testing.T) {
+		pending := domain.Order{ID: 1, UserID: 2, Status: domain.OrderStatusPending}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, pending.ID).Return(pending, nil).Once()
+		mockOrderRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*domain.Order"), domain.OrderStatusPending).Return(nil).Once()
+		mockOrderItemRepo.On("FetchByOrder", mock.Anything, pending.ID).Return([]domain.OrderItem{
+			{ID: 3, OrderID: 1, ProductID: 4, Qty: 2},
+			{ID: 4, OrderID: 1, ProductID: 5, VariantID: 7, Qty: 1},
+			{ID: 5, OrderID: 1, ProductID: 6, VariantID: 9, Qty: 1},
+		}, nil).Once()
+		mockProductRepo.On("IncrementStock", mock.Anything, int64(4), 2).Return(nil).Once()
+		mockVariantRepo.On("IncrementStock", mock.Anything, int64(7), 1).Return(nil).Once()
+		mockProductRepo.On("IncrementStock", mock.Anything, int64(5), 1).Return(nil).Once()
+		// the variant was deleted since, its product is left alone
+		mockVariantRepo.On("IncrementStock", mock.Anything, int64(9), 1).Return(domain.ErrNotFound).Once()
+		mockHistoryRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.OrderStatusHistory")).Return(nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		res, err := u.CancelUserOrder(context.TODO(), pending.UserID, pending.ID, "changed my mind")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusCancelled, res.Status)
+		mockOrderRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+		mockVariantRepo.AssertExpectations(t)
+		mockProductRepo.AssertNotCalled(t, "IncrementStock", mock.Anything, int64(6), mock.Anything)
+	})
+
+	t.Run("other-users-order", func(t *testing.T) {
+		pending := domain.Order{ID: 1, UserID: 2, Status: domain.OrderStatusPending}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, pending.ID).Return(pending, nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		_, err := u.CancelUserOrder(context.TODO(), 99, pending.ID, "")
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("already-paid", func(t *testing.T) {
+		paid := domain.Order{ID: 1, UserID: 2, Status: domain.OrderStatusPaid}
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockOrderRepo.On("GetByID", mock.Anything, paid.ID).Return(paid, nil).Once()
+
+		u := ucase.NewOrderUsecase(mockOrderRepo, mockOrderItemRepo, mockAddressRepo, mockHistoryRepo, mockProductRepo, mockVariantRepo, mockTransactor, time.Second*2)
+		_, err := u.CancelUserOrder(context.TODO(), paid.UserID, paid.ID, "")
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	})
+}
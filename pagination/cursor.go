@@ -0,0 +1,129 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+const (
+	cursorVersion = "v2"
+	forward       = "n"
+	backward      = "p"
+)
+
+// Codec turns cursors into opaque tokens signed with HMAC-SHA256, so that
+// clients can neither read nor forge a position in a list
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec will create a Codec signing with secret
+func NewCodec(secret string) *Codec {
+	return &Codec{secret: []byte(secret)}
+}
+
+func (c *Codec) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// Encode returns the token of cur
+func (c *Codec) Encode(cur domain.Cursor) string {
+	dir := forward
+	if cur.Backward {
+		dir = backward
+	}
+	payload := strings.Join([]string{
+		cursorVersion,
+		cur.Sort.String(),
+		encodeKey(cur.Key),
+		strconv.FormatInt(cur.ID, 10),
+		dir,
+	}, ":")
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+// Decode verifies token and returns its cursor, domain.ErrBadParamInput is
+// returned for anything that was not produced by Encode with the same secret
+func (c *Codec) Decode(token string) (domain.Cursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return domain.Cursor{}, domain.ErrBadParamInput
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return domain.Cursor{}, domain.ErrBadParamInput
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, c.sign(string(payload))) {
+		return domain.Cursor{}, domain.ErrBadParamInput
+	}
+
+	fields := strings.Split(string(payload), ":")
+	if len(fields) != 5 || fields[0] != cursorVersion || (fields[4] != forward && fields[4] != backward) {
+		return domain.Cursor{}, domain.ErrBadParamInput
+	}
+	sort := domain.ParseSort(fields[1])
+	if sort.Field == "" {
+		return domain.Cursor{}, domain.ErrBadParamInput
+	}
+	key, err := decodeKey(fields[2])
+	if err != nil {
+		return domain.Cursor{}, domain.ErrBadParamInput
+	}
+	id, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return domain.Cursor{}, domain.ErrBadParamInput
+	}
+	return domain.Cursor{
+		Sort:     sort,
+		Key:      key,
+		ID:       id,
+		Backward: fields[4] == backward,
+	}, nil
+}
+
+// encodeKey writes a sort key as its type, one letter, and its value
+func encodeKey(key interface{}) string {
+	switch v := key.(type) {
+	case time.Time:
+		return "t" + strconv.FormatInt(v.UnixNano(), 10)
+	case int64:
+		return "i" + strconv.FormatInt(v, 10)
+	case bool:
+		return "b" + strconv.FormatBool(v)
+	case string:
+		return "s" + base64.RawURLEncoding.EncodeToString([]byte(v))
+	}
+	panic(fmt.Sprintf("pagination: unsupported sort key %T", key))
+}
+
+func decodeKey(s string) (interface{}, error) {
+	if s == "" {
+		return nil, domain.ErrBadParamInput
+	}
+	switch s[0] {
+	case 't':
+		nanos, err := strconv.ParseInt(s[1:], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return time.Unix(0, nanos).UTC(), nil
+	case 'i':
+		return strconv.ParseInt(s[1:], 10, 64)
+	case 'b':
+		return strconv.ParseBool(s[1:])
+	case 's':
+		b, err := base64.RawURLEncoding.DecodeString(s[1:])
+		return string(b), err
+	}
+	return nil, domain.ErrBadParamInput
+}
@@ -0,0 +1,51 @@
+package pagination_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec(t *testing.T) {
+	codec := pagination.NewCodec("secret")
+	cur := domain.Cursor{Sort: domain.Sort{Field: "created_at"}, Key: time.Date(2021, 3, 4, 5, 6, 7, 890, time.UTC), ID: 42, Backward: true}
+
+	t.Run("round-trip", func(t *testing.T) {
+		token := codec.Encode(cur)
+		assert.NotContains(t, token, "42")
+
+		res, err := codec.Decode(token)
+		require.NoError(t, err)
+		assert.Equal(t, cur, res)
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		for _, key := range []interface{}{int64(-1500), "Kopi: Arabica / 250g", "", true} {
+			want := domain.Cursor{Sort: domain.Sort{Field: "name", Desc: true}, Key: key, ID: 7}
+			res, err := codec.Decode(codec.Encode(want))
+			require.NoError(t, err)
+			assert.Equal(t, want, res)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		token := codec.Encode(cur)
+		other := codec.Encode(domain.Cursor{Sort: cur.Sort, Key: cur.Key, ID: 43})
+		forged := strings.Split(other, ".")[0] + "." + strings.Split(token, ".")[1]
+
+		for _, tok := range []string{"", "2", "abc.def", token + "x", forged} {
+			_, err := codec.Decode(tok)
+			assert.Equal(t, domain.ErrBadParamInput, err, tok)
+		}
+	})
+
+	t.Run("other-secret", func(t *testing.T) {
+		_, err := pagination.NewCodec("another").Decode(codec.Encode(cur))
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
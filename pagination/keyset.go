@@ -0,0 +1,158 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+var filterOperators = map[domain.FilterOp]string{
+	domain.FilterEq:  "=",
+	domain.FilterGt:  ">",
+	domain.FilterLt:  "<",
+	domain.FilterGte: ">=",
+	domain.FilterLte: "<=",
+}
+
+// Keyset pages the rows of a table ordered by one of its columns and the id.
+// Columns maps the fields of the list schema to the columns holding them, a
+// field missing from it is never written into a statement.
+type Keyset struct {
+	IDColumn string
+	Columns  map[string]string
+	Default  domain.Sort
+}
+
+// NewKeyset will create a Keyset on the id column, ordered by def unless the
+// page asks for another sort
+func NewKeyset(columns map[string]string, def domain.Sort) Keyset {
+	return Keyset{IDColumn: "id", Columns: columns, Default: def}
+}
+
+// Sort returns the order page is read in
+func (k Keyset) Sort(page domain.PageRequest) domain.Sort {
+	if page.Sort.Field == "" {
+		return k.Default
+	}
+	return page.Sort
+}
+
+// Query returns the statement reading page from sel, a SELECT without WHERE,
+// ORDER BY or LIMIT, and the args it adds after the ones of filter. filter is
+// the condition the list always has, e.g. "user_id = ?", and may be empty.
+// One row more than the limit is read to know whether another page follows,
+// the rows always come in the order of the sort. domain.ErrBadParamInput is
+// returned for a field that has no column.
+func (k Keyset) Query(sel string, filter string, page domain.PageRequest) (string, []interface{}, error) {
+	var conds []string
+	if filter != "" {
+		conds = append(conds, filter)
+	}
+	filters, args, err := k.Conditions(page.Filters)
+	if err != nil {
+		return "", nil, err
+	}
+	conds = append(conds, filters...)
+
+	sort := k.Sort(page)
+	col, ok := k.Columns[sort.Field]
+	if !ok {
+		return "", nil, domain.ErrBadParamInput
+	}
+	// the rows before a cursor are read in the reverse order, nearest first
+	backward := page.Cursor != nil && page.Cursor.Backward
+	desc := sort.Desc != backward
+	if page.Cursor != nil {
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		conds = append(conds, fmt.Sprintf("(%[1]s %[3]s ? OR (%[1]s = ? AND %[2]s %[3]s ?))", col, k.IDColumn, op))
+		args = append(args, page.Cursor.Key, page.Cursor.Key, page.Cursor.ID)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, page.Limit+1)
+
+	query := fmt.Sprintf("%s%s ORDER BY %s LIMIT ?", sel, where, k.orderBy(col, desc))
+	if backward {
+		query = fmt.Sprintf("SELECT * FROM (%s) AS page ORDER BY %s", query, k.orderBy(col, sort.Desc))
+	}
+	return query, args, nil
+}
+
+// Conditions returns the SQL conditions of filters and their args, for the
+// statements that read the list without paging it, e.g. to count its rows
+func (k Keyset) Conditions(filters []domain.Filter) ([]string, []interface{}, error) {
+	var conds []string
+	var args []interface{}
+	for _, f := range filters {
+		col, ok := k.Columns[f.Field]
+		if !ok {
+			return nil, nil, domain.ErrBadParamInput
+		}
+		if f.Op == domain.FilterIn {
+			values, ok := f.Value.([]interface{})
+			if !ok || len(values) == 0 {
+				return nil, nil, domain.ErrBadParamInput
+			}
+			conds = append(conds, fmt.Sprintf("%s IN (%s)", col, strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")))
+			args = append(args, values...)
+			continue
+		}
+		op, ok := filterOperators[f.Op]
+		if !ok {
+			return nil, nil, domain.ErrBadParamInput
+		}
+		conds = append(conds, fmt.Sprintf("%s %s ?", col, op))
+		args = append(args, f.Value)
+	}
+	return conds, args, nil
+}
+
+func (k Keyset) orderBy(col string, desc bool) string {
+	if desc {
+		return fmt.Sprintf("%s DESC, %s DESC", col, k.IDColumn)
+	}
+	return fmt.Sprintf("%s, %s", col, k.IDColumn)
+}
+
+// Window returns the bounds of the page within the n rows read with Query
+// and the cursors around it. key returns the value of the sort field and the
+// id of row i.
+func (k Keyset) Window(page domain.PageRequest, n int, key func(i int, field string) (interface{}, int64)) (from int, to int, info domain.PageInfo) {
+	limit := int(page.Limit)
+	sort := k.Sort(page)
+	at := func(i int, backward bool) *domain.Cursor {
+		v, id := key(i, sort.Field)
+		return &domain.Cursor{Sort: sort, Key: v, ID: id, Backward: backward}
+	}
+
+	if page.Cursor != nil && page.Cursor.Backward {
+		// the extra row, if any, is the first one
+		from, to = 0, n
+		if n > limit {
+			from = n - limit
+			info.Prev = at(from, true)
+		}
+		if to > from {
+			info.Next = at(to-1, false)
+		}
+		return
+	}
+
+	from, to = 0, n
+	if n > limit {
+		to = limit
+	}
+	if n > limit && to > 0 {
+		info.Next = at(to-1, false)
+	}
+	if page.Cursor != nil && to > from {
+		info.Prev = at(from, true)
+	}
+	return
+}
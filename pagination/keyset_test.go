@@ -0,0 +1,128 @@
+package pagination_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var keyset = pagination.NewKeyset(map[string]string{
+	"status":      "status",
+	"total_price": "total_price",
+	"created_at":  "created_at",
+}, domain.Sort{Field: "created_at"})
+
+func TestQuery(t *testing.T) {
+	at := time.Unix(1600000000, 0).UTC()
+	byPrice := domain.Sort{Field: "total_price", Desc: true}
+
+	t.Run("first-page", func(t *testing.T) {
+		query, args, err := keyset.Query("SELECT id FROM orders", "user_id = ?", domain.PageRequest{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM orders WHERE user_id = ? ORDER BY created_at, id LIMIT ?", query)
+		assert.Equal(t, []interface{}{int64(3)}, args)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		query, args, err := keyset.Query("SELECT id FROM orders", "", domain.PageRequest{
+			Limit: 2,
+			Sort:  byPrice,
+			Filters: []domain.Filter{
+				{Field: "status", Op: domain.FilterIn, Value: []interface{}{"paid", "shipped"}},
+				{Field: "created_at", Op: domain.FilterGt, Value: at},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM orders WHERE status IN (?, ?) AND created_at > ? ORDER BY total_price DESC, id DESC LIMIT ?", query)
+		assert.Equal(t, []interface{}{"paid", "shipped", at, int64(3)}, args)
+	})
+
+	t.Run("after", func(t *testing.T) {
+		query, args, err := keyset.Query("SELECT id FROM orders", "", domain.PageRequest{Cursor: &domain.Cursor{Key: at, ID: 5}, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM orders WHERE (created_at > ? OR (created_at = ? AND id > ?)) ORDER BY created_at, id LIMIT ?", query)
+		assert.Equal(t, []interface{}{at, at, int64(5), int64(3)}, args)
+	})
+
+	t.Run("after-descending", func(t *testing.T) {
+		query, args, err := keyset.Query("SELECT id FROM orders", "", domain.PageRequest{
+			Cursor: &domain.Cursor{Sort: byPrice, Key: int64(900), ID: 5},
+			Limit:  2,
+			Sort:   byPrice,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM orders WHERE (total_price < ? OR (total_price = ? AND id < ?)) ORDER BY total_price DESC, id DESC LIMIT ?", query)
+		assert.Equal(t, []interface{}{int64(900), int64(900), int64(5), int64(3)}, args)
+	})
+
+	t.Run("before", func(t *testing.T) {
+		query, _, err := keyset.Query("SELECT id FROM orders", "", domain.PageRequest{Cursor: &domain.Cursor{Key: at, ID: 5, Backward: true}, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM (SELECT id FROM orders WHERE (created_at < ? OR (created_at = ? AND id < ?)) "+
+			"ORDER BY created_at DESC, id DESC LIMIT ?) AS page ORDER BY created_at, id", query)
+	})
+
+	t.Run("before-descending", func(t *testing.T) {
+		query, _, err := keyset.Query("SELECT id FROM orders", "", domain.PageRequest{
+			Cursor: &domain.Cursor{Sort: byPrice, Key: int64(900), ID: 5, Backward: true},
+			Limit:  2,
+			Sort:   byPrice,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM (SELECT id FROM orders WHERE (total_price > ? OR (total_price = ? AND id > ?)) "+
+			"ORDER BY total_price, id LIMIT ?) AS page ORDER BY total_price DESC, id DESC", query)
+	})
+
+	t.Run("unknown-field", func(t *testing.T) {
+		_, _, err := keyset.Query("SELECT id FROM orders", "", domain.PageRequest{
+			Limit:   2,
+			Filters: []domain.Filter{{Field: "1=1; DROP TABLE orders", Op: domain.FilterEq, Value: "x"}},
+		})
+		assert.Equal(t, domain.ErrBadParamInput, err)
+
+		_, _, err = keyset.Query("SELECT id FROM orders", "", domain.PageRequest{Limit: 2, Sort: domain.Sort{Field: "user_id"}})
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestWindow(t *testing.T) {
+	at := time.Unix(1600000000, 0).UTC()
+	def := domain.Sort{Field: "created_at"}
+	// every row shares the same key, the id alone orders them
+	ids := []int64{4, 5, 6}
+	key := func(i int, field string) (interface{}, int64) {
+		assert.Equal(t, "created_at", field)
+		return at, ids[i]
+	}
+
+	tests := []struct {
+		name     string
+		page     domain.PageRequest
+		n        int
+		from, to int
+		info     domain.PageInfo
+	}{
+		{"first-of-many", domain.PageRequest{Limit: 2}, 3, 0, 2,
+			domain.PageInfo{Next: &domain.Cursor{Sort: def, Key: at, ID: 5}}},
+		{"only", domain.PageRequest{Limit: 5}, 3, 0, 3, domain.PageInfo{}},
+		{"last", domain.PageRequest{Cursor: &domain.Cursor{Sort: def, Key: at, ID: 3}, Limit: 5}, 3, 0, 3,
+			domain.PageInfo{Prev: &domain.Cursor{Sort: def, Key: at, ID: 4, Backward: true}}},
+		{"backward-more", domain.PageRequest{Cursor: &domain.Cursor{Sort: def, Key: at, ID: 7, Backward: true}, Limit: 2}, 3, 1, 3,
+			domain.PageInfo{Next: &domain.Cursor{Sort: def, Key: at, ID: 6}, Prev: &domain.Cursor{Sort: def, Key: at, ID: 5, Backward: true}}},
+		{"backward-first", domain.PageRequest{Cursor: &domain.Cursor{Sort: def, Key: at, ID: 7, Backward: true}, Limit: 5}, 3, 0, 3,
+			domain.PageInfo{Next: &domain.Cursor{Sort: def, Key: at, ID: 6}}},
+		{"empty", domain.PageRequest{Cursor: &domain.Cursor{Sort: def, Key: at, ID: 9}, Limit: 2}, 0, 0, 0, domain.PageInfo{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, info := keyset.Window(tt.page, tt.n, key)
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+			assert.Equal(t, tt.info, info)
+		})
+	}
+}
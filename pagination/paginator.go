@@ -0,0 +1,135 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// CursorHeader carries the cursor of the next page, empty on the last one
+	CursorHeader = "X-Cursor"
+	// PrevCursorHeader carries the cursor of the previous page, empty on the first one
+	PrevCursorHeader = "X-Prev-Cursor"
+
+	cursorParam = "cursor"
+	sizeParam   = "num"
+	sortParam   = "sort"
+)
+
+// Paginator reads the page asked by a list request, the cursor, num and sort
+// query params and the filters, and writes where the neighbouring pages are. Every list
+// endpoint answers with the same headers:
+//
+//	X-Cursor:      the cursor of the next page
+//	X-Prev-Cursor: the cursor of the previous page
+//	Link:          <...?cursor=...>; rel="next", <...?cursor=...>; rel="prev"
+type Paginator struct {
+	codec       *Codec
+	defaultSize int64
+	maxSize     int64
+}
+
+// NewPaginator will create a Paginator signing cursors with secret. Pages
+// hold defaultSize rows unless num asks for another size, which is capped
+// at maxSize.
+func NewPaginator(secret string, defaultSize int64, maxSize int64) *Paginator {
+	if maxSize < defaultSize {
+		maxSize = defaultSize
+	}
+	return &Paginator{
+		codec:       NewCodec(secret),
+		defaultSize: defaultSize,
+		maxSize:     maxSize,
+	}
+}
+
+// Page returns the page asked by the request of a list of schema: its size,
+// cursor, sort and filters. params are the other query params the endpoint
+// reads, e.g. currency, any param left is rejected. A *domain.QueryError
+// listing every problem is returned when the query is not valid.
+func (p *Paginator) Page(c echo.Context, schema domain.ListSchema, params ...string) (domain.PageRequest, error) {
+	var problems []domain.QueryProblem
+	res := domain.PageRequest{Limit: p.defaultSize, Sort: schema.DefaultSort}
+	if s := c.QueryParam(sizeParam); s != "" {
+		num, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || num < 0 {
+			problems = append(problems, domain.QueryProblem{Param: sizeParam, Reason: "must be a positive number"})
+		}
+		if num > 0 {
+			res.Limit = num
+		}
+	}
+	if res.Limit > p.maxSize {
+		res.Limit = p.maxSize
+	}
+
+	query := c.QueryParams()
+	sort, sortProblem := parseSort(schema, query[sortParam])
+	if sortProblem != "" {
+		problems = append(problems, domain.QueryProblem{Param: sortParam, Reason: sortProblem})
+	} else if sort != nil {
+		res.Sort = *sort
+	}
+
+	if token := c.QueryParam(cursorParam); token != "" {
+		cur, err := p.codec.Decode(token)
+		switch {
+		case err != nil || !keyFits(schema, cur):
+			problems = append(problems, domain.QueryProblem{Param: cursorParam, Reason: "is not a cursor of this list"})
+		case sort != nil && *sort != cur.Sort:
+			problems = append(problems, domain.QueryProblem{Param: cursorParam, Reason: "was issued for sort=" + cur.Sort.String()})
+		default:
+			// the cursor carries the sort of the pages it links
+			res.Cursor = &cur
+			res.Sort = cur.Sort
+		}
+	}
+
+	reserved := map[string]bool{sizeParam: true, sortParam: true, cursorParam: true}
+	for _, param := range params {
+		reserved[param] = true
+	}
+	filters, filterProblems := parseFilters(schema, query, reserved)
+	res.Filters = filters
+	problems = append(problems, filterProblems...)
+
+	if len(problems) > 0 {
+		return domain.PageRequest{}, &domain.QueryError{Problems: problems}
+	}
+	return res, nil
+}
+
+// SetHeaders writes the cursors of info to the response
+func (p *Paginator) SetHeaders(c echo.Context, info domain.PageInfo) {
+	header := c.Response().Header()
+	var links []string
+	next, prev := "", ""
+	if info.Next != nil {
+		next = p.codec.Encode(*info.Next)
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, p.pageURL(c, next)))
+	}
+	if info.Prev != nil {
+		prev = p.codec.Encode(*info.Prev)
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, p.pageURL(c, prev)))
+	}
+	header.Set(CursorHeader, next)
+	header.Set(PrevCursorHeader, prev)
+	if len(links) > 0 {
+		header.Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageURL is the request URL with its cursor replaced, the other query
+// params such as num or currency are kept
+func (p *Paginator) pageURL(c echo.Context, token string) string {
+	u := url.URL{Path: c.Request().URL.Path}
+	query := c.Request().URL.Query()
+	query.Set(cursorParam, token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
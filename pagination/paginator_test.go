@@ -0,0 +1,160 @@
+package pagination_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var schema = domain.ListSchema{
+	Fields: []domain.ListField{
+		{Name: "username", Type: domain.FieldString, Sortable: true, Filterable: true},
+		{Name: "role", Type: domain.FieldString, Filterable: true},
+		{Name: "is_verified", Type: domain.FieldBool, Filterable: true},
+		{Name: "rating", Type: domain.FieldInt, Sortable: true, Filterable: true},
+		{Name: "name", Type: domain.FieldString, Sortable: true},
+		{Name: "created_at", Type: domain.FieldTime, Sortable: true, Filterable: true},
+	},
+	DefaultSort: domain.Sort{Field: "created_at"},
+}
+
+func newContext(target string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(echo.GET, target, nil)
+	w := httptest.NewRecorder()
+	return echo.New().NewContext(req, w), w
+}
+
+func TestPage(t *testing.T) {
+	p := pagination.NewPaginator("secret", 10, 50)
+	def := domain.Sort{Field: "created_at"}
+	byName := domain.Sort{Field: "username", Desc: true}
+	cur := domain.Cursor{Sort: def, Key: time.Unix(1600000000, 0).UTC(), ID: 7}
+	nameCur := domain.Cursor{Sort: byName, Key: "budi", ID: 7}
+	codec := pagination.NewCodec("secret")
+	token := codec.Encode(cur)
+	since := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		target string
+		want   domain.PageRequest
+	}{
+		{"default", "/users", domain.PageRequest{Limit: 10, Sort: def}},
+		{"zero", "/users?num=0", domain.PageRequest{Limit: 10, Sort: def}},
+		{"size", "/users?num=20", domain.PageRequest{Limit: 20, Sort: def}},
+		{"capped", "/users?num=500", domain.PageRequest{Limit: 50, Sort: def}},
+		{"cursor", "/users?num=5&cursor=" + token, domain.PageRequest{Cursor: &cur, Limit: 5, Sort: def}},
+		{"sort", "/users?sort=-username", domain.PageRequest{Limit: 10, Sort: byName}},
+		{"cursor-sort", "/users?cursor=" + codec.Encode(nameCur), domain.PageRequest{Cursor: &nameCur, Limit: 10, Sort: byName}},
+		{"cursor-same-sort", "/users?sort=-username&cursor=" + codec.Encode(nameCur), domain.PageRequest{Cursor: &nameCur, Limit: 10, Sort: byName}},
+		{"filters", "/users?role=staff&is_verified=true&created_after=2021-01-02&rating_min=3&currency=EUR", domain.PageRequest{
+			Limit: 10,
+			Sort:  def,
+			Filters: []domain.Filter{
+				{Field: "created_at", Op: domain.FilterGt, Value: since},
+				{Field: "is_verified", Op: domain.FilterEq, Value: true},
+				{Field: "rating", Op: domain.FilterGte, Value: int64(3)},
+				{Field: "role", Op: domain.FilterEq, Value: "staff"},
+			},
+		}},
+		{"in", "/users?role=staff&role=admin&created_before=2021-01-02T07:00:00%2B07:00", domain.PageRequest{
+			Limit: 10,
+			Sort:  def,
+			Filters: []domain.Filter{
+				{Field: "created_at", Op: domain.FilterLt, Value: since},
+				{Field: "role", Op: domain.FilterIn, Value: []interface{}{"staff", "admin"}},
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newContext(tt.target)
+			res, err := p.Page(c, schema, "currency")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, res)
+		})
+	}
+}
+
+func TestPageProblems(t *testing.T) {
+	p := pagination.NewPaginator("secret", 10, 50)
+	nameCur := pagination.NewCodec("secret").Encode(domain.Cursor{Sort: domain.Sort{Field: "username"}, Key: "budi", ID: 7})
+	otherList := pagination.NewCodec("secret").Encode(domain.Cursor{Sort: domain.Sort{Field: "price"}, Key: int64(100), ID: 7})
+
+	tests := []struct {
+		name   string
+		target string
+		want   []domain.QueryProblem
+	}{
+		{"bad-num", "/users?num=ten", []domain.QueryProblem{{Param: "num", Reason: "must be a positive number"}}},
+		{"negative-num", "/users?num=-1", []domain.QueryProblem{{Param: "num", Reason: "must be a positive number"}}},
+		{"bad-cursor", "/users?cursor=2", []domain.QueryProblem{{Param: "cursor", Reason: "is not a cursor of this list"}}},
+		{"cursor-of-another-list", "/users?cursor=" + otherList, []domain.QueryProblem{{Param: "cursor", Reason: "is not a cursor of this list"}}},
+		{"cursor-other-sort", "/users?sort=rating&cursor=" + nameCur, []domain.QueryProblem{{Param: "cursor", Reason: "was issued for sort=username"}}},
+		{"unknown-sort", "/users?sort=password", []domain.QueryProblem{{Param: "sort", Reason: `unknown field "password"`}}},
+		{"unsortable", "/users?sort=-role", []domain.QueryProblem{{Param: "sort", Reason: `can not sort on "role"`}}},
+		{"two-sorts", "/users?sort=username,-rating", []domain.QueryProblem{{Param: "sort", Reason: "lists sort on one field"}}},
+		{"unknown-filter", "/users?hashed_password=x", []domain.QueryProblem{{Param: "hashed_password", Reason: "is not a filter of this list"}}},
+		{"unfilterable", "/users?name=x", []domain.QueryProblem{{Param: "name", Reason: `can not filter on "name"`}}},
+		{"range-of-string", "/users?username_min=a", []domain.QueryProblem{{Param: "username_min", Reason: "is not a filter of this list"}}},
+		{"every-problem", "/users?is_verified=maybe&rating_max=high&created_after=yesterday&created_after=today&num=x", []domain.QueryProblem{
+			{Param: "num", Reason: "must be a positive number"},
+			{Param: "created_after", Reason: "is given more than once"},
+			{Param: "is_verified", Reason: "must be true or false"},
+			{Param: "rating_max", Reason: "must be a whole number"},
+		}},
+		{"bad-time", "/users?created_before=02/01/2021", []domain.QueryProblem{{Param: "created_before", Reason: "must be a date, 2006-01-02, or an RFC 3339 time"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newContext(tt.target)
+			res, err := p.Page(c, schema)
+			assert.ErrorIs(t, err, domain.ErrBadParamInput)
+			var queryErr *domain.QueryError
+			require.ErrorAs(t, err, &queryErr)
+			assert.Equal(t, tt.want, queryErr.Problems)
+			assert.Equal(t, domain.PageRequest{}, res)
+		})
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	p := pagination.NewPaginator("secret", 10, 50)
+	codec := pagination.NewCodec("secret")
+	def := domain.Sort{Field: "created_at"}
+	next := domain.Cursor{Sort: def, Key: time.Unix(1600000000, 0).UTC(), ID: 9}
+	prev := domain.Cursor{Sort: def, Key: time.Unix(1500000000, 0).UTC(), ID: 3, Backward: true}
+
+	t.Run("both", func(t *testing.T) {
+		c, w := newContext("/products?num=2&currency=EUR&cursor=old")
+		p.SetHeaders(c, domain.PageInfo{Next: &next, Prev: &prev})
+
+		assert.Equal(t, codec.Encode(next), w.Header().Get(pagination.CursorHeader))
+		assert.Equal(t, codec.Encode(prev), w.Header().Get(pagination.PrevCursorHeader))
+		assert.Equal(t, `</products?currency=EUR&cursor=`+codec.Encode(next)+`&num=2>; rel="next", `+
+			`</products?currency=EUR&cursor=`+codec.Encode(prev)+`&num=2>; rel="prev"`, w.Header().Get("Link"))
+	})
+
+	t.Run("single-page", func(t *testing.T) {
+		c, w := newContext("/products")
+		p.SetHeaders(c, domain.PageInfo{})
+
+		assert.Empty(t, w.Header().Get(pagination.CursorHeader))
+		assert.Empty(t, w.Header().Get(pagination.PrevCursorHeader))
+		_, ok := w.Header()["Link"]
+		assert.False(t, ok)
+	})
+
+	t.Run("follows", func(t *testing.T) {
+		c, _ := newContext("/users?cursor=" + codec.Encode(next))
+		res, err := p.Page(c, schema)
+		require.NoError(t, err)
+		assert.Equal(t, &next, res.Cursor)
+	})
+}
@@ -0,0 +1,164 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+// rangeFilter is a filter param named after a field with a suffix, e.g.
+// created_after for created_at or price_min for price
+type rangeFilter struct {
+	suffix    string
+	fieldType domain.FieldType
+	// fieldSuffix replaces suffix to name the field
+	fieldSuffix string
+	op          domain.FilterOp
+}
+
+var rangeFilters = []rangeFilter{
+	{suffix: "_after", fieldType: domain.FieldTime, fieldSuffix: "_at", op: domain.FilterGt},
+	{suffix: "_before", fieldType: domain.FieldTime, fieldSuffix: "_at", op: domain.FilterLt},
+	{suffix: "_min", fieldType: domain.FieldInt, op: domain.FilterGte},
+	{suffix: "_max", fieldType: domain.FieldInt, op: domain.FilterLte},
+}
+
+// parseSort reads the sort param, nil when it is not set. A sort is one
+// field, prefixed with - for the descending order.
+func parseSort(schema domain.ListSchema, values []string) (*domain.Sort, string) {
+	if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+		return nil, ""
+	}
+	if len(values) > 1 || strings.Contains(values[0], ",") {
+		return nil, "lists sort on one field"
+	}
+	res := domain.ParseSort(values[0])
+	field, ok := schema.Field(res.Field)
+	if !ok {
+		return nil, fmt.Sprintf("unknown field %q", res.Field)
+	}
+	if !field.Sortable {
+		return nil, fmt.Sprintf("can not sort on %q", res.Field)
+	}
+	return &res, ""
+}
+
+// parseFilters reads every param of query that is not reserved as a filter,
+// in the order of the param names
+func parseFilters(schema domain.ListSchema, query url.Values, reserved map[string]bool) ([]domain.Filter, []domain.QueryProblem) {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		if !reserved[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var res []domain.Filter
+	var problems []domain.QueryProblem
+	for _, name := range names {
+		field, op, reason := resolveFilter(schema, name)
+		if reason != "" {
+			problems = append(problems, domain.QueryProblem{Param: name, Reason: reason})
+			continue
+		}
+
+		values := query[name]
+		if len(values) > 1 && (op != domain.FilterEq || field.Type == domain.FieldBool || field.Type == domain.FieldTime) {
+			problems = append(problems, domain.QueryProblem{Param: name, Reason: "is given more than once"})
+			continue
+		}
+		parsed := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			value, reason := parseValue(field.Type, v)
+			if reason != "" {
+				problems = append(problems, domain.QueryProblem{Param: name, Reason: reason})
+				break
+			}
+			parsed = append(parsed, value)
+		}
+		if len(parsed) != len(values) {
+			continue
+		}
+
+		if len(parsed) > 1 {
+			res = append(res, domain.Filter{Field: field.Name, Op: domain.FilterIn, Value: parsed})
+		} else {
+			res = append(res, domain.Filter{Field: field.Name, Op: op, Value: parsed[0]})
+		}
+	}
+	return res, problems
+}
+
+// resolveFilter returns the field and the comparison of the filter param
+// name, or why there is none
+func resolveFilter(schema domain.ListSchema, name string) (domain.ListField, domain.FilterOp, string) {
+	if field, ok := schema.Field(name); ok {
+		if !field.Filterable {
+			return domain.ListField{}, "", fmt.Sprintf("can not filter on %q", name)
+		}
+		return field, domain.FilterEq, ""
+	}
+	for _, r := range rangeFilters {
+		if !strings.HasSuffix(name, r.suffix) {
+			continue
+		}
+		field, ok := schema.Field(strings.TrimSuffix(name, r.suffix) + r.fieldSuffix)
+		if ok && field.Filterable && field.Type == r.fieldType {
+			return field, r.op, ""
+		}
+	}
+	return domain.ListField{}, "", "is not a filter of this list"
+}
+
+// parseValue reads v as a value of a field of type t, or says why it can not
+func parseValue(t domain.FieldType, v string) (interface{}, string) {
+	switch t {
+	case domain.FieldInt:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, "must be a whole number"
+		}
+		return n, ""
+	case domain.FieldBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, "must be true or false"
+		}
+		return b, ""
+	case domain.FieldTime:
+		if at, err := time.Parse(time.RFC3339, v); err == nil {
+			return at.UTC(), ""
+		}
+		if at, err := time.Parse("2006-01-02", v); err == nil {
+			return at, ""
+		}
+		return nil, "must be a date, 2006-01-02, or an RFC 3339 time"
+	}
+	return v, ""
+}
+
+// keyFits reports whether cur was issued for a sort of schema, a cursor of
+// another list is refused even though it verifies
+func keyFits(schema domain.ListSchema, cur domain.Cursor) bool {
+	field, ok := schema.Field(cur.Sort.Field)
+	if !ok || !field.Sortable {
+		return false
+	}
+	switch cur.Key.(type) {
+	case string:
+		return field.Type == domain.FieldString
+	case int64:
+		return field.Type == domain.FieldInt
+	case bool:
+		return field.Type == domain.FieldBool
+	case time.Time:
+		return field.Type == domain.FieldTime
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"reflect"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// amounts are validated on their minor units, e.g. validate:"gte=0"
+	v.RegisterCustomTypeFunc(moneyAmount, domain.Money{})
+	return v
+}
+
+func moneyAmount(field reflect.Value) interface{} {
+	return field.Interface().(domain.Money).Amount
+}
+
+type payRequest struct {
+	OrderID int64 `json:"order_id" validate:"required"`
+}
+
+type refundItemRequest struct {
+	OrderItemID int64 `json:"order_item_id" validate:"required"`
+	Qty         int   `json:"qty" validate:"required,min=1"`
+	Restock     bool  `json:"restock"`
+}
+
+// refundRequest refunds either items or an amount in the currency of the
+// order, leaving both out refunds everything not refunded yet
+type refundRequest struct {
+	Items  []refundItemRequest `json:"items" validate:"omitempty,dive"`
+	Amount domain.Money        `json:"amount" validate:"gte=0"`
+	Reason string              `json:"reason" validate:"required,oneof=customer_request damaged wrong_item not_delivered other"`
+	Note   string              `json:"note" validate:"max=255"`
+}
+
+func (r refundRequest) toRefundRequest() domain.RefundRequest {
+	res := domain.RefundRequest{
+		Amount: r.Amount,
+		Reason: domain.RefundReason(r.Reason),
+		Note:   r.Note,
+	}
+	for _, item := range r.Items {
+		res.Items = append(res.Items, domain.RefundItemRequest{
+			OrderItemID: item.OrderItemID,
+			Qty:         item.Qty,
+			Restock:     item.Restock,
+		})
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// maxWebhookSize bounds the body read from a payment notification
+const maxWebhookSize = 1 << 16
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+type PaymentHandler struct {
+	PUsecase domain.PaymentUsecase
+}
+
+func NewPaymentHandler(e *echo.Echo, pucase domain.PaymentUsecase, mw *middleware.GoMiddleware) {
+	handler := &PaymentHandler{
+		PUsecase: pucase,
+	}
+	e.POST("/payments", handler.Pay, mw.Auth, mw.RequireVerified, mw.Idempotent)
+	e.POST("/payments/webhook", handler.Webhook)
+	e.GET("/orders/:id/payments", handler.FetchByOrder, mw.Auth, mw.RequirePermission(domain.PermissionOrderRead))
+}
+
+// Pay will start a payment of a pending order of the authenticated user
+func (h *PaymentHandler) Pay(c echo.Context) (err error) {
+	var req payRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	payment, err := h.PUsecase.Pay(ctx, auth.UserID, req.OrderID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, payment)
+}
+
+// Webhook receives the notifications of the payment provider, the body is
+// passed on untouched because the signature covers the raw bytes
+func (h *PaymentHandler) Webhook(c echo.Context) error {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Response(), c.Request().Body, maxWebhookSize))
+	if err != nil {
+		return c.JSON(http.StatusRequestEntityTooLarge, ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	err = h.PUsecase.HandleWebhook(ctx, payload, c.Request().Header)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// FetchByOrder will list the payment attempts of an order
+func (h *PaymentHandler) FetchByOrder(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	payments, err := h.PUsecase.FetchByOrder(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, payments)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		return http.StatusConflict
+	}
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict, domain.ErrRefundExceedsBalance:
+		return http.StatusConflict
+	case domain.ErrBadParamInput, domain.ErrCurrencyMismatch, domain.ErrInvalidCurrency:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized, domain.ErrInvalidSignature:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden, domain.ErrUnverified:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
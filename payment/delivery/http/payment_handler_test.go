@@ -0,0 +1,84 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	paymentHttp "github.com/alfathaulia/ca_ecommerce_api/payment/delivery/http"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPay(t *testing.T) {
+	mockUcase := new(mocks.PaymentUsecase)
+	payment := domain.Payment{ID: 1, OrderID: 8, Provider: "fake", ProviderRef: "fake_pi_1", ClientSecret: "fake_pi_1_secret_1", Status: domain.PaymentStatusPending}
+	mockUcase.On("Pay", mock.Anything, int64(3), int64(8)).Return(payment, nil).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/payments", strings.NewReader(`{"order_id":8}`))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, IsVerified: true}))
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	handler := paymentHttp.PaymentHandler{
+		PUsecase: mockUcase,
+	}
+
+	err = handler.Pay(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"client_secret":"fake_pi_1_secret_1"`)
+	mockUcase.AssertExpectations(t)
+}
+
+func TestWebhook(t *testing.T) {
+	mockUcase := new(mocks.PaymentUsecase)
+	body := `{"id":"evt_1","type":"payment.succeeded","provider_ref":"fake_pi_1"}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUcase.On("HandleWebhook", mock.Anything, []byte(body), mock.AnythingOfType("http.Header")).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/payments/webhook", strings.NewReader(body))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := paymentHttp.PaymentHandler{
+			PUsecase: mockUcase,
+		}
+
+		err = handler.Webhook(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-signature", func(t *testing.T) {
+		mockUcase.On("HandleWebhook", mock.Anything, []byte(body), mock.AnythingOfType("http.Header")).Return(domain.ErrInvalidSignature).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/payments/webhook", strings.NewReader(body))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := paymentHttp.PaymentHandler{
+			PUsecase: mockUcase,
+		}
+
+		err = handler.Webhook(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+type RefundHandler struct {
+	RUsecase domain.RefundUsecase
+}
+
+func NewRefundHandler(e *echo.Echo, rucase domain.RefundUsecase, mw *middleware.GoMiddleware) {
+	handler := &RefundHandler{
+		RUsecase: rucase,
+	}
+	e.POST("/orders/:id/refunds", handler.Refund, mw.Auth, mw.RequirePermission(domain.PermissionOrderRefund), mw.Idempotent)
+	e.POST("/orders/:id/refunds/:refund_id/retry", handler.Retry, mw.Auth, mw.RequirePermission(domain.PermissionOrderRefund), mw.Idempotent)
+	e.GET("/orders/:id/refunds", handler.FetchByOrder, mw.Auth, mw.RequirePermission(domain.PermissionOrderRead))
+}
+
+// Refund will refund a paid order fully, by items or by amount
+func (h *RefundHandler) Refund(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req refundRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	refund, err := h.RUsecase.Refund(ctx, int64(idP), req.toRefundRequest())
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, refund)
+}
+
+// Retry will ask the payment provider again for a refund it refused
+func (h *RefundHandler) Retry(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	refundID, err := strconv.Atoi(c.Param("refund_id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	refund, err := h.RUsecase.Retry(ctx, int64(idP), int64(refundID))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, refund)
+}
+
+// FetchByOrder will list the refunds of an order
+func (h *RefundHandler) FetchByOrder(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	refunds, err := h.RUsecase.FetchByOrder(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, refunds)
+}
@@ -0,0 +1,113 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	paymentHttp "github.com/alfathaulia/ca_ecommerce_api/payment/delivery/http"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefund(t *testing.T) {
+	mockUcase := new(mocks.RefundUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		want := domain.RefundRequest{
+			Items:  []domain.RefundItemRequest{{OrderItemID: 3, Qty: 1, Restock: true}},
+			Reason: domain.RefundReasonDamaged,
+		}
+		mockUcase.On("Refund", mock.Anything, int64(8), want).Return(domain.Refund{ID: 1, OrderID: 8, Amount: domain.NewMoney(15000000, "IDR")}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/orders/8/refunds", strings.NewReader(`{"items":[{"order_item_id":3,"qty":1,"restock":true}],"reason":"damaged"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("orders/:id/refunds")
+		c.SetParamNames("id")
+		c.SetParamValues("8")
+		handler := paymentHttp.RefundHandler{
+			RUsecase: mockUcase,
+		}
+
+		err = handler.Refund(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("unknown-reason", func(t *testing.T) {
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/orders/8/refunds", strings.NewReader(`{"reason":"bored"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("orders/:id/refunds")
+		c.SetParamNames("id")
+		c.SetParamValues("8")
+		handler := paymentHttp.RefundHandler{
+			RUsecase: mockUcase,
+		}
+
+		err = handler.Refund(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("exceeds-balance", func(t *testing.T) {
+		mockUcase.On("Refund", mock.Anything, int64(8), mock.AnythingOfType("domain.RefundRequest")).Return(domain.Refund{}, domain.ErrRefundExceedsBalance).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/orders/8/refunds", strings.NewReader(`{"amount":{"amount":99999900,"currency":"IDR"},"reason":"other"}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("orders/:id/refunds")
+		c.SetParamNames("id")
+		c.SetParamValues("8")
+		handler := paymentHttp.RefundHandler{
+			RUsecase: mockUcase,
+		}
+
+		err = handler.Refund(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+}
+
+func TestRetryRefund(t *testing.T) {
+	mockUcase := new(mocks.RefundUsecase)
+	mockUcase.On("Retry", mock.Anything, int64(8), int64(5)).Return(domain.Refund{}, domain.ErrConflict).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/orders/8/refunds/5/retry", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	c.SetPath("orders/:id/refunds/:refund_id/retry")
+	c.SetParamNames("id", "refund_id")
+	c.SetParamValues("8", "5")
+	handler := paymentHttp.RefundHandler{
+		RUsecase: mockUcase,
+	}
+
+	err = handler.Retry(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUcase.AssertExpectations(t)
+}
@@ -0,0 +1,122 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+const selectPayment = `SELECT id, order_id, provider, provider_ref, amount, currency, status, failure_reason, updated_at, created_at
+  						FROM payment`
+
+type mysqlPaymentRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlPaymentRepo will create an object that represent the domain.PaymentRepository interface
+func NewMysqlPaymentRepo(DB *sql.DB) domain.PaymentRepository {
+	return &mysqlPaymentRepo{DB: DB}
+}
+
+func (m *mysqlPaymentRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Payment, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Payment, 0)
+	for rows.Next() {
+		t := domain.Payment{}
+		err = rows.Scan(
+			&t.ID,
+			&t.OrderID,
+			&t.Provider,
+			&t.ProviderRef,
+			&t.Amount,
+			&t.Amount.Currency,
+			&t.Status,
+			&t.FailureReason,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlPaymentRepo) GetByProviderRef(ctx context.Context, provider string, providerRef string) (res domain.Payment, err error) {
+	query := selectPayment + ` WHERE provider = ? AND provider_ref = ?`
+
+	list, err := m.fetch(ctx, query, provider, providerRef)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlPaymentRepo) FetchByOrder(ctx context.Context, orderID int64) ([]domain.Payment, error) {
+	query := selectPayment + ` WHERE order_id = ? ORDER BY id`
+	return m.fetch(ctx, query, orderID)
+}
+
+func (m *mysqlPaymentRepo) Store(ctx context.Context, p *domain.Payment) (err error) {
+	query := `INSERT  payment SET order_id=? , provider=? , provider_ref=? , amount=? , currency=? , status=? , failure_reason=? , updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, p.OrderID, p.Provider, p.ProviderRef, p.Amount, p.Amount.Currency, p.Status, p.FailureReason, p.UpdatedAt, p.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	p.ID = lastID
+	return
+}
+
+func (m *mysqlPaymentRepo) UpdateStatus(ctx context.Context, p *domain.Payment, from domain.PaymentStatus) (err error) {
+	query := `UPDATE  payment SET status=? , failure_reason=? , updated_at=? WHERE id=? AND status=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, p.Status, p.FailureReason, p.UpdatedAt, p.ID, from)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
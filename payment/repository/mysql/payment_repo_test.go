@@ -0,0 +1,109 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	paymentMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/payment/repository/mysql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+var payment = &domain.Payment{
+	ID:          1,
+	OrderID:     8,
+	Provider:    "fake",
+	ProviderRef: "fake_pi_1",
+	Amount:      domain.NewMoney(15000000, "IDR"),
+	Status:      domain.PaymentStatusPending,
+	UpdatedAt:   now,
+	CreatedAt:   now,
+}
+
+var paymentColumns = []string{"id", "order_id", "provider", "provider_ref", "amount", "currency", "status", "failure_reason", "updated_at", "created_at"}
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func addPaymentRow(rows *sqlmock.Rows, p domain.Payment) *sqlmock.Rows {
+	return rows.AddRow(p.ID, p.OrderID, p.Provider, p.ProviderRef, p.Amount.Amount, p.Amount.Currency, p.Status, p.FailureReason, p.UpdatedAt, p.CreatedAt)
+}
+
+func TestGetByProviderRef(t *testing.T) {
+	db, mock := NewMock()
+	rows := addPaymentRow(sqlmock.NewRows(paymentColumns), *payment)
+
+	query := `SELECT id, order_id, provider, provider_ref, amount, currency, status, failure_reason, updated_at, created_at FROM payment WHERE provider = \? AND provider_ref = \?`
+	mock.ExpectQuery(query).WithArgs(payment.Provider, payment.ProviderRef).WillReturnRows(rows)
+
+	a := paymentMysqlRepo.NewMysqlPaymentRepo(db)
+	res, err := a.GetByProviderRef(context.TODO(), payment.Provider, payment.ProviderRef)
+	assert.NoError(t, err)
+	assert.Equal(t, *payment, res)
+}
+
+func TestGetByProviderRefNotFound(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `SELECT id, order_id, provider, provider_ref, amount, currency, status, failure_reason, updated_at, created_at FROM payment WHERE provider = \? AND provider_ref = \?`
+	mock.ExpectQuery(query).WithArgs("fake", "nope").WillReturnRows(sqlmock.NewRows(paymentColumns))
+
+	a := paymentMysqlRepo.NewMysqlPaymentRepo(db)
+	_, err := a.GetByProviderRef(context.TODO(), "fake", "nope")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestStore(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  payment SET order_id=\\? , provider=\\? , provider_ref=\\? , amount=\\? , currency=\\? , status=\\? , failure_reason=\\? , updated_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(payment.OrderID, payment.Provider, payment.ProviderRef, payment.Amount, payment.Amount.Currency, payment.Status, payment.FailureReason, payment.UpdatedAt, payment.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	a := paymentMysqlRepo.NewMysqlPaymentRepo(db)
+	tmp := *payment
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), tmp.ID)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	query := "UPDATE  payment SET status=\\? , failure_reason=\\? , updated_at=\\? WHERE id=\\? AND status=\\?"
+	succeeded := *payment
+	succeeded.Status = domain.PaymentStatusSucceeded
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(succeeded.Status, "", succeeded.UpdatedAt, succeeded.ID, domain.PaymentStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		a := paymentMysqlRepo.NewMysqlPaymentRepo(db)
+		err := a.UpdateStatus(context.TODO(), &succeeded, domain.PaymentStatusPending)
+		assert.NoError(t, err)
+	})
+
+	t.Run("already-settled", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(succeeded.Status, "", succeeded.UpdatedAt, succeeded.ID, domain.PaymentStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		a := paymentMysqlRepo.NewMysqlPaymentRepo(db)
+		err := a.UpdateStatus(context.TODO(), &succeeded, domain.PaymentStatusPending)
+		assert.Equal(t, domain.ErrConflict, err)
+	})
+}
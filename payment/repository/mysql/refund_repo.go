@@ -0,0 +1,215 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlRefundRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlRefundRepo will create an object that represent the domain.RefundRepository interface
+func NewMysqlRefundRepo(DB *sql.DB) domain.RefundRepository {
+	return &mysqlRefundRepo{DB: DB}
+}
+
+const selectRefund = `SELECT id, order_id, payment_id, provider_ref, status, failure_reason, amount, currency, reason, note, created_by, created_at FROM refund`
+
+func (m *mysqlRefundRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Refund, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Refund, 0)
+	for rows.Next() {
+		t := domain.Refund{}
+		var paymentID sql.NullInt64
+		err = rows.Scan(
+			&t.ID,
+			&t.OrderID,
+			&paymentID,
+			&t.ProviderRef,
+			&t.Status,
+			&t.FailureReason,
+			&t.Amount,
+			&t.Amount.Currency,
+			&t.Reason,
+			&t.Note,
+			&t.CreatedBy,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.PaymentID = paymentID.Int64
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// addItems fills the items of the refunds, all of the order orderID
+func (m *mysqlRefundRepo) addItems(ctx context.Context, orderID int64, refunds []domain.Refund) error {
+	if len(refunds) == 0 {
+		return nil
+	}
+	index := map[int64]int{}
+	for i, r := range refunds {
+		index[r.ID] = i
+	}
+	items, err := m.fetchItems(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if i, ok := index[item.RefundID]; ok {
+			refunds[i].Items = append(refunds[i].Items, item)
+		}
+	}
+	return nil
+}
+
+func (m *mysqlRefundRepo) FetchByOrder(ctx context.Context, orderID int64) ([]domain.Refund, error) {
+	result, err := m.fetch(ctx, selectRefund+` WHERE order_id = ? ORDER BY id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err = m.addItems(ctx, orderID, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (m *mysqlRefundRepo) GetByID(ctx context.Context, id int64) (domain.Refund, error) {
+	list, err := m.fetch(ctx, selectRefund+` WHERE id = ?`, id)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if len(list) == 0 {
+		return domain.Refund{}, domain.ErrNotFound
+	}
+	if err = m.addItems(ctx, list[0].OrderID, list); err != nil {
+		return domain.Refund{}, err
+	}
+	return list[0], nil
+}
+
+func (m *mysqlRefundRepo) fetchItems(ctx context.Context, orderID int64) (result []domain.RefundItem, err error) {
+	query := `SELECT ri.id, ri.refund_id, ri.order_item_id, ri.qty, ri.amount, r.currency, ri.restocked
+  						FROM refund_item ri JOIN refund r ON r.id = ri.refund_id WHERE r.order_id = ? ORDER BY ri.id`
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, orderID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.RefundItem, 0)
+	for rows.Next() {
+		t := domain.RefundItem{}
+		err = rows.Scan(
+			&t.ID,
+			&t.RefundID,
+			&t.OrderItemID,
+			&t.Qty,
+			&t.Amount,
+			&t.Amount.Currency,
+			&t.Restocked,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// Store saves the refund and its items, callers keep them consistent by
+// running it inside a transaction
+func (m *mysqlRefundRepo) Store(ctx context.Context, r *domain.Refund) (err error) {
+	query := `INSERT  refund SET order_id=? , payment_id=? , provider_ref=? , status=? , failure_reason=? , amount=? , currency=? , reason=? , note=? , created_by=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	paymentID := sql.NullInt64{Int64: r.PaymentID, Valid: r.PaymentID != 0}
+	res, err := stmt.ExecContext(ctx, r.OrderID, paymentID, r.ProviderRef, r.Status, r.FailureReason, r.Amount, r.Amount.Currency, r.Reason, r.Note, r.CreatedBy, r.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	r.ID = lastID
+
+	for i := range r.Items {
+		r.Items[i].RefundID = r.ID
+		if err = m.storeItem(ctx, &r.Items[i]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m *mysqlRefundRepo) storeItem(ctx context.Context, item *domain.RefundItem) (err error) {
+	query := `INSERT  refund_item SET refund_id=? , order_item_id=? , qty=? , amount=? , restocked=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, item.RefundID, item.OrderItemID, item.Qty, item.Amount, item.Restocked)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	item.ID = lastID
+	return
+}
+
+func (m *mysqlRefundRepo) UpdateStatus(ctx context.Context, r *domain.Refund, from domain.RefundStatus) (err error) {
+	query := `UPDATE  refund SET provider_ref=? , status=? , failure_reason=? WHERE id=? AND status=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, r.ProviderRef, r.Status, r.FailureReason, r.ID, from)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
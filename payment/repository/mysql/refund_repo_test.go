@@ -0,0 +1,112 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	paymentMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/payment/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var refundColumns = []string{"id", "order_id", "payment_id", "provider_ref", "status", "failure_reason", "amount", "currency", "reason", "note", "created_by", "created_at"}
+
+func TestFetchRefundsByOrder(t *testing.T) {
+	db, mock := NewMock()
+	refundRows := sqlmock.NewRows(refundColumns).
+		AddRow(1, 8, 1, "fake_re_1", domain.RefundStatusSucceeded, "", 15000000, "IDR", domain.RefundReasonDamaged, "", 7, now).
+		AddRow(2, 8, nil, "", domain.RefundStatusSucceeded, "", 500000, "IDR", domain.RefundReasonOther, "goodwill", 7, now)
+	itemRows := sqlmock.NewRows([]string{"id", "refund_id", "order_item_id", "qty", "amount", "currency", "restocked"}).
+		AddRow(1, 1, 3, 1, 15000000, "IDR", true)
+
+	mock.ExpectQuery(`SELECT id, order_id, payment_id, provider_ref, status, failure_reason, amount, currency, reason, note, created_by, created_at FROM refund WHERE order_id = \? ORDER BY id`).
+		WithArgs(int64(8)).WillReturnRows(refundRows)
+	mock.ExpectQuery(`SELECT ri.id, ri.refund_id, ri.order_item_id, ri.qty, ri.amount, r.currency, ri.restocked FROM refund_item ri JOIN refund r ON r.id = ri.refund_id WHERE r.order_id = \? ORDER BY ri.id`).
+		WithArgs(int64(8)).WillReturnRows(itemRows)
+
+	a := paymentMysqlRepo.NewMysqlRefundRepo(db)
+	list, err := a.FetchByOrder(context.TODO(), 8)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, int64(1), list[0].PaymentID)
+	assert.Equal(t, []domain.RefundItem{{ID: 1, RefundID: 1, OrderItemID: 3, Qty: 1, Amount: domain.NewMoney(15000000, "IDR"), Restocked: true}}, list[0].Items)
+	assert.Equal(t, int64(0), list[1].PaymentID)
+	assert.Empty(t, list[1].Items)
+}
+
+func TestStoreRefund(t *testing.T) {
+	db, mock := NewMock()
+	refund := domain.Refund{
+		OrderID:   8,
+		Amount:    domain.NewMoney(15000000, "IDR"),
+		Reason:    domain.RefundReasonDamaged,
+		Status:    domain.RefundStatusPending,
+		CreatedBy: 7,
+		Items:     []domain.RefundItem{{OrderItemID: 3, Qty: 1, Amount: domain.NewMoney(15000000, "IDR"), Restocked: true}},
+		CreatedAt: now,
+	}
+
+	prep := mock.ExpectPrepare("INSERT  refund SET order_id=\\? , payment_id=\\? , provider_ref=\\? , status=\\? , failure_reason=\\? , amount=\\? , currency=\\? , reason=\\? , note=\\? , created_by=\\? , created_at=\\?")
+	prep.ExpectExec().WithArgs(refund.OrderID, nil, "", domain.RefundStatusPending, "", refund.Amount, refund.Amount.Currency, refund.Reason, "", refund.CreatedBy, refund.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	prep = mock.ExpectPrepare("INSERT  refund_item SET refund_id=\\? , order_item_id=\\? , qty=\\? , amount=\\? , restocked=\\?")
+	prep.ExpectExec().WithArgs(int64(5), int64(3), 1, int64(15000000), true).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+
+	a := paymentMysqlRepo.NewMysqlRefundRepo(db)
+	err := a.Store(context.TODO(), &refund)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), refund.ID)
+	assert.Equal(t, int64(5), refund.Items[0].RefundID)
+	assert.Equal(t, int64(9), refund.Items[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRefundByID(t *testing.T) {
+	db, mock := NewMock()
+	refundRows := sqlmock.NewRows(refundColumns).
+		AddRow(2, 8, 1, "", domain.RefundStatusFailed, "card expired", 500000, "IDR", domain.RefundReasonOther, "", 7, now)
+	itemRows := sqlmock.NewRows([]string{"id", "refund_id", "order_item_id", "qty", "amount", "currency", "restocked"}).
+		AddRow(1, 1, 3, 1, 15000000, "IDR", true).
+		AddRow(2, 2, 4, 1, 500000, "IDR", false)
+
+	mock.ExpectQuery(`SELECT id, order_id, payment_id, provider_ref, status, failure_reason, amount, currency, reason, note, created_by, created_at FROM refund WHERE id = \?`).
+		WithArgs(int64(2)).WillReturnRows(refundRows)
+	mock.ExpectQuery(`SELECT ri.id, ri.refund_id, ri.order_item_id, ri.qty, ri.amount, r.currency, ri.restocked FROM refund_item ri JOIN refund r ON r.id = ri.refund_id WHERE r.order_id = \? ORDER BY ri.id`).
+		WithArgs(int64(8)).WillReturnRows(itemRows)
+
+	a := paymentMysqlRepo.NewMysqlRefundRepo(db)
+	res, err := a.GetByID(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RefundStatusFailed, res.Status)
+	assert.Equal(t, "card expired", res.FailureReason)
+	assert.Equal(t, []domain.RefundItem{{ID: 2, RefundID: 2, OrderItemID: 4, Qty: 1, Amount: domain.NewMoney(500000, "IDR")}}, res.Items)
+}
+
+func TestUpdateRefundStatus(t *testing.T) {
+	query := "UPDATE  refund SET provider_ref=\\? , status=\\? , failure_reason=\\? WHERE id=\\? AND status=\\?"
+	refund := domain.Refund{ID: 5, ProviderRef: "fake_re_1", Status: domain.RefundStatusSucceeded}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs("fake_re_1", domain.RefundStatusSucceeded, "", refund.ID, domain.RefundStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		a := paymentMysqlRepo.NewMysqlRefundRepo(db)
+		err := a.UpdateStatus(context.TODO(), &refund, domain.RefundStatusPending)
+		assert.NoError(t, err)
+	})
+
+	t.Run("changed-meanwhile", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs("fake_re_1", domain.RefundStatusSucceeded, "", refund.ID, domain.RefundStatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		a := paymentMysqlRepo.NewMysqlRefundRepo(db)
+		err := a.UpdateStatus(context.TODO(), &refund, domain.RefundStatusPending)
+		assert.Equal(t, domain.ErrConflict, err)
+	})
+}
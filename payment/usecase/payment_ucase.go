@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type paymentUsecase struct {
+	paymentRepo    domain.PaymentRepository
+	orderUsecase   domain.OrderUsecase
+	provider       domain.PaymentProvider
+	transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewPaymentUsecase will create an object that represent the domain.PaymentUsecase interface
+func NewPaymentUsecase(p domain.PaymentRepository, ou domain.OrderUsecase, pp domain.PaymentProvider, tx domain.Transactor, timeout time.Duration) domain.PaymentUsecase {
+	return &paymentUsecase{
+		paymentRepo:    p,
+		orderUsecase:   ou,
+		provider:       pp,
+		transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (m *paymentUsecase) Pay(ctx context.Context, userID int64, orderID int64) (domain.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	order, err := m.orderUsecase.GetUserOrder(ctx, userID, orderID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if order.Status != domain.OrderStatusPending {
+		return domain.Payment{}, &domain.TransitionError{From: order.Status, To: domain.OrderStatusPaid}
+	}
+
+	intent, err := m.provider.CreateIntent(ctx, order.ID, order.TotalPrice)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	now := time.Now()
+	res := domain.Payment{
+		OrderID:      order.ID,
+		Provider:     m.provider.Name(),
+		ProviderRef:  intent.ProviderRef,
+		Amount:       order.TotalPrice,
+		Status:       domain.PaymentStatusPending,
+		ClientSecret: intent.ClientSecret,
+		UpdatedAt:    now,
+		CreatedAt:    now,
+	}
+	if err = m.paymentRepo.Store(ctx, &res); err != nil {
+		return domain.Payment{}, err
+	}
+	return res, nil
+}
+
+func (m *paymentUsecase) FetchByOrder(ctx context.Context, orderID int64) ([]domain.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if _, err := m.orderUsecase.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return m.paymentRepo.FetchByOrder(ctx, orderID)
+}
+
+// HandleWebhook settles the payment attempt the notification is about. Only
+// pending attempts are settled, and the settlement is a compare-and-set on
+// the attempt status, so a notification delivered twice, even concurrently,
+// marks the order paid once. An attempt whose amount or currency differs from
+// the one asked for is flagged for review instead of being settled.
+func (m *paymentUsecase) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	event, err := m.provider.VerifyWebhook(payload, header)
+	if err != nil {
+		return err
+	}
+	payment, err := m.paymentRepo.GetByProviderRef(ctx, m.provider.Name(), event.ProviderRef)
+	if err != nil {
+		return err
+	}
+	if payment.Status != domain.PaymentStatusPending {
+		logrus.Infof("payment %s is already %s, ignoring %s", payment.ProviderRef, payment.Status, event.Type)
+		return nil
+	}
+
+	if (event.Type == domain.PaymentEventAuthorized || event.Type == domain.PaymentEventSucceeded) && event.Amount != payment.Amount {
+		logrus.Errorf("payment %s reported %s for %s, flagging it for review", payment.ProviderRef, event.Amount, payment.Amount)
+		payment.Status = domain.PaymentStatusReview
+		payment.FailureReason = fmt.Sprintf("amount mismatch: provider reported %s, expected %s", event.Amount, payment.Amount)
+		payment.UpdatedAt = time.Now()
+		err = m.paymentRepo.UpdateStatus(ctx, &payment, domain.PaymentStatusPending)
+		if err == domain.ErrConflict {
+			return nil
+		}
+		return err
+	}
+
+	switch event.Type {
+	case domain.PaymentEventAuthorized:
+		if err = m.provider.Capture(ctx, payment.ProviderRef); err != nil {
+			return err
+		}
+		err = m.succeed(ctx, payment)
+	case domain.PaymentEventSucceeded:
+		err = m.succeed(ctx, payment)
+	case domain.PaymentEventFailed:
+		payment.Status = domain.PaymentStatusFailed
+		payment.FailureReason = event.FailureReason
+		payment.UpdatedAt = time.Now()
+		err = m.paymentRepo.UpdateStatus(ctx, &payment, domain.PaymentStatusPending)
+	default:
+		logrus.Infof("ignoring payment event %s", event.Type)
+	}
+	if err == domain.ErrConflict {
+		return nil
+	}
+	return err
+}
+
+func (m *paymentUsecase) succeed(ctx context.Context, payment domain.Payment) error {
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		payment.Status = domain.PaymentStatusSucceeded
+		payment.UpdatedAt = time.Now()
+		if err := m.paymentRepo.UpdateStatus(ctx, &payment, domain.PaymentStatusPending); err != nil {
+			return err
+		}
+
+		_, err := m.orderUsecase.Transition(ctx, payment.OrderID, domain.OrderStatusPaid, "payment "+payment.ProviderRef)
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			// the money is taken but the order moved on, e.g. it was cancelled
+			// or paid by another attempt: keep the payment for a refund
+			logrus.Warnf("payment %s succeeded for order %d: %s", payment.ProviderRef, payment.OrderID, err)
+			return nil
+		}
+		return err
+	})
+}
@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/alfathaulia/ca_ecommerce_api/gateway"
+	ucase "github.com/alfathaulia/ca_ecommerce_api/payment/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// runInTransaction makes the Transactor mock call the unit of work directly
+func runInTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestPay(t *testing.T) {
+	mockPaymentRepo := new(mocks.PaymentRepository)
+	mockOrderUcase := new(mocks.OrderUsecase)
+	mockTransactor := new(mocks.Transactor)
+	provider := gateway.NewFakeProvider("secret")
+
+	t.Run("success", func(t *testing.T) {
+		order := domain.Order{ID: 8, UserID: 3, TotalPrice: idr(15000000), Status: domain.OrderStatusPending}
+		mockOrderUcase.On("GetUserOrder", mock.Anything, order.UserID, order.ID).Return(order, nil).Once()
+		mockPaymentRepo.On("Store", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+			return p.OrderID == order.ID && p.Provider == "fake" && p.Amount == order.TotalPrice && p.Status == domain.PaymentStatusPending
+		})).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+		res, err := u.Pay(context.TODO(), order.UserID, order.ID)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.ProviderRef)
+		assert.NotEmpty(t, res.ClientSecret)
+		mockOrderUcase.AssertExpectations(t)
+		mockPaymentRepo.AssertExpectations(t)
+	})
+
+	t.Run("order-not-pending", func(t *testing.T) {
+		order := domain.Order{ID: 8, UserID: 3, Status: domain.OrderStatusPaid}
+		mockOrderUcase.On("GetUserOrder", mock.Anything, order.UserID, order.ID).Return(order, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+		_, err := u.Pay(context.TODO(), order.UserID, order.ID)
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+		mockOrderUcase.AssertExpectations(t)
+	})
+}
+
+func TestHandleWebhook(t *testing.T) {
+	provider := gateway.NewFakeProvider("secret")
+	intent, err := provider.CreateIntent(context.TODO(), 8, idr(15000000))
+	require.NoError(t, err)
+	pending := domain.Payment{ID: 1, OrderID: 8, Provider: "fake", ProviderRef: intent.ProviderRef, Amount: idr(15000000), Status: domain.PaymentStatusPending}
+
+	t.Run("succeeded-marks-order-paid", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderUcase := new(mocks.OrderUsecase)
+		mockTransactor := new(mocks.Transactor)
+		payload, header, err := provider.SignedEvent(intent.ProviderRef, domain.PaymentEventSucceeded)
+		require.NoError(t, err)
+
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, "fake", intent.ProviderRef).Return(pending, nil).Once()
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+			return p.Status == domain.PaymentStatusSucceeded
+		}), domain.PaymentStatusPending).Return(nil).Once()
+		mockOrderUcase.On("Transition", mock.Anything, pending.OrderID, domain.OrderStatusPaid, "payment "+intent.ProviderRef).
+			Return(domain.Order{ID: 8, Status: domain.OrderStatusPaid}, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+		err = u.HandleWebhook(context.TODO(), payload, header)
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderUcase.AssertExpectations(t)
+	})
+
+	t.Run("duplicate-callback", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderUcase := new(mocks.OrderUsecase)
+		mockTransactor := new(mocks.Transactor)
+		payload, header, err := provider.SignedEvent(intent.ProviderRef, domain.PaymentEventSucceeded)
+		require.NoError(t, err)
+
+		succeeded := pending
+		succeeded.Status = domain.PaymentStatusSucceeded
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, "fake", intent.ProviderRef).Return(succeeded, nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+		err = u.HandleWebhook(context.TODO(), payload, header)
+		assert.NoError(t, err)
+		mockOrderUcase.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent-duplicate", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderUcase := new(mocks.OrderUsecase)
+		mockTransactor := new(mocks.Transactor)
+		payload, header, err := provider.SignedEvent(intent.ProviderRef, domain.PaymentEventSucceeded)
+		require.NoError(t, err)
+
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, "fake", intent.ProviderRef).Return(pending, nil).Once()
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*domain.Payment"), domain.PaymentStatusPending).Return(domain.ErrConflict).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+		err = u.HandleWebhook(context.TODO(), payload, header)
+		assert.NoError(t, err)
+		mockOrderUcase.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderUcase := new(mocks.OrderUsecase)
+		mockTransactor := new(mocks.Transactor)
+		payload, header, err := provider.SignedEvent(intent.ProviderRef, domain.PaymentEventFailed)
+		require.NoError(t, err)
+
+		mockPaymentRepo.On("GetByProviderRef", mock.Anything, "fake", intent.ProviderRef).Return(pending, nil).Once()
+		mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+			return p.Status == domain.PaymentStatusFailed && p.FailureReason == "card_declined"
+		}), domain.PaymentStatusPending).Return(nil).Once()
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+		err = u.HandleWebhook(context.TODO(), payload, header)
+		assert.NoError(t, err)
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderUcase.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("other-amount-is-flagged", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderUcase := new(mocks.OrderUsecase)
+		mockTransactor := new(mocks.Transactor)
+		for _, amount := range []domain.Money{idr(10000000), domain.NewMoney(15000000, "USD")} {
+			other, err := provider.CreateIntent(context.TODO(), 8, amount)
+			require.NoError(t, err)
+			payload, header, err := provider.SignedEvent(other.ProviderRef, domain.PaymentEventSucceeded)
+			require.NoError(t, err)
+
+			attempt := pending
+			attempt.ProviderRef = other.ProviderRef
+			mockPaymentRepo.On("GetByProviderRef", mock.Anything, "fake", other.ProviderRef).Return(attempt, nil).Once()
+			mockPaymentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.ProviderRef == other.ProviderRef && p.Status == domain.PaymentStatusReview && p.FailureReason != ""
+			}), domain.PaymentStatusPending).Return(nil).Once()
+
+			u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+			err = u.HandleWebhook(context.TODO(), payload, header)
+			assert.NoError(t, err)
+		}
+		mockPaymentRepo.AssertExpectations(t)
+		mockOrderUcase.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid-signature", func(t *testing.T) {
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderUcase := new(mocks.OrderUsecase)
+		mockTransactor := new(mocks.Transactor)
+		payload, _, err := provider.SignedEvent(intent.ProviderRef, domain.PaymentEventSucceeded)
+		require.NoError(t, err)
+		header := http.Header{}
+		header.Set(gateway.FakeSignatureHeader, "00")
+
+		u := ucase.NewPaymentUsecase(mockPaymentRepo, mockOrderUcase, provider, mockTransactor, time.Second*2)
+		err = u.HandleWebhook(context.TODO(), payload, header)
+		assert.Equal(t, domain.ErrInvalidSignature, err)
+		mockPaymentRepo.AssertNotCalled(t, "GetByProviderRef", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
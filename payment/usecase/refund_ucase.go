@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type refundUsecase struct {
+	refundRepo     domain.RefundRepository
+	paymentRepo    domain.PaymentRepository
+	orderRepo      domain.OrderRepository
+	productRepo    domain.ProductRepository
+	variantRepo    domain.VariantRepository
+	orderUsecase   domain.OrderUsecase
+	provider       domain.PaymentProvider
+	transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewRefundUsecase will create an object that represent the domain.RefundUsecase interface
+func NewRefundUsecase(r domain.RefundRepository, p domain.PaymentRepository, o domain.OrderRepository, pr domain.ProductRepository, v domain.VariantRepository, ou domain.OrderUsecase, pp domain.PaymentProvider, tx domain.Transactor, timeout time.Duration) domain.RefundUsecase {
+	return &refundUsecase{
+		refundRepo:     r,
+		paymentRepo:    p,
+		orderRepo:      o,
+		productRepo:    pr,
+		variantRepo:    v,
+		orderUsecase:   ou,
+		provider:       pp,
+		transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func isRefundable(status domain.OrderStatus) bool {
+	switch status {
+	case domain.OrderStatusPaid, domain.OrderStatusShipped, domain.OrderStatusDelivered, domain.OrderStatusReturned:
+		return true
+	}
+	return false
+}
+
+func validateRefundRequest(req domain.RefundRequest) error {
+	if !req.Reason.IsValid() || req.Amount.IsNegative() {
+		return domain.ErrBadParamInput
+	}
+	if len(req.Items) > 0 && req.Amount.IsPositive() {
+		return domain.ErrBadParamInput
+	}
+	for _, item := range req.Items {
+		if item.Qty < 1 {
+			return domain.ErrBadParamInput
+		}
+	}
+	return nil
+}
+
+// Refund gives back money of a paid order. The refund, the new refunded total
+// of the order and the restocking are saved in one transaction; the order
+// becomes refunded once nothing is left. An order paid through the payment
+// provider is refunded there only after the commit, so a rollback never
+// leaves money returned without a record: the refund is saved pending, its
+// amount held in the refunded total, then marked succeeded or failed with the
+// answer of the provider. A failed refund gives its amount back to the
+// balance, the order becomes refunded only once every refund succeeded.
+func (m *refundUsecase) Refund(ctx context.Context, orderID int64, req domain.RefundRequest) (res domain.Refund, err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err = validateRefundRequest(req); err != nil {
+		return domain.Refund{}, err
+	}
+	res = domain.Refund{OrderID: orderID, Reason: req.Reason, Note: req.Note}
+	if auth, ok := domain.AuthFromContext(ctx); ok {
+		res.CreatedBy = auth.UserID
+	}
+
+	var payment domain.Payment
+	err = m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// concurrent refunds of the order wait here, so each one sees the
+		// refunds committed before it
+		if _, err := m.orderRepo.GetByIDForUpdate(ctx, orderID); err != nil {
+			return err
+		}
+		order, err := m.orderUsecase.GetByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if !isRefundable(order.Status) {
+			return &domain.TransitionError{From: order.Status, To: domain.OrderStatusRefunded}
+		}
+
+		switch {
+		case len(req.Items) > 0:
+			res.Items, err = m.refundItems(ctx, order, req.Items)
+			if err != nil {
+				return err
+			}
+			res.Amount = domain.NewMoney(0, order.TotalPrice.Currency)
+			for _, item := range res.Items {
+				if res.Amount, err = res.Amount.Add(item.Amount); err != nil {
+					return err
+				}
+			}
+		case req.Amount.IsPositive():
+			res.Amount = req.Amount
+		default:
+			res.Amount = order.RemainingBalance
+		}
+		remaining, err := order.RemainingBalance.Sub(res.Amount)
+		if err != nil {
+			return err
+		}
+		if !res.Amount.IsPositive() || remaining.IsNegative() {
+			return domain.ErrRefundExceedsBalance
+		}
+		if err = m.orderRepo.AddRefunded(ctx, orderID, res.Amount); err != nil {
+			return err
+		}
+
+		payment, err = m.capturedPayment(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		res.Status = domain.RefundStatusSucceeded
+		if payment.ID != 0 {
+			res.PaymentID = payment.ID
+			res.Status = domain.RefundStatusPending
+		}
+		res.CreatedAt = time.Now()
+		if err = m.refundRepo.Store(ctx, &res); err != nil {
+			return err
+		}
+
+		if remaining.IsPositive() || res.Status == domain.RefundStatusPending {
+			return nil
+		}
+		return m.markRefunded(ctx, orderID, req.Reason)
+	})
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if res.Status == domain.RefundStatusPending {
+		err = m.refundAtProvider(ctx, &res, payment)
+	}
+	return res, err
+}
+
+// refundItems prices the requested lines and puts the returned units back
+// in stock. A line can not be refunded for more units than were ordered,
+// counting the earlier refunds.
+func (m *refundUsecase) refundItems(ctx context.Context, order domain.Order, req []domain.RefundItemRequest) ([]domain.RefundItem, error) {
+	previous, err := m.refundRepo.FetchByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	refunded := map[int64]int{}
+	for _, r := range previous {
+		for _, item := range r.Items {
+			refunded[item.OrderItemID] += item.Qty
+		}
+	}
+
+	lines := map[int64]domain.OrderItem{}
+	for _, line := range order.Items {
+		lines[line.ID] = line
+	}
+
+	res := make([]domain.RefundItem, 0, len(req))
+	for _, r := range req {
+		line, ok := lines[r.OrderItemID]
+		if !ok {
+			return nil, domain.ErrNotFound
+		}
+		if refunded[line.ID]+r.Qty > line.Qty {
+			return nil, domain.ErrRefundExceedsBalance
+		}
+		// the exclusive tax is refunded with the units, taking the difference
+		// of the cumulated amounts keeps the rounding from drifting
+		before := line.Charged(refunded[line.ID])
+		refunded[line.ID] += r.Qty
+		amount, err := line.Charged(refunded[line.ID]).Sub(before)
+		if err != nil {
+			return nil, err
+		}
+
+		item := domain.RefundItem{OrderItemID: line.ID, Qty: r.Qty, Amount: amount}
+		if r.Restock {
+			err = m.restock(ctx, line, r.Qty)
+			if err != nil && err != domain.ErrNotFound {
+				return nil, err
+			}
+			// a product or variant removed from the catalog has no stock to go back to
+			item.Restocked = err == nil
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// restock puts qty of the line back into the stock of its variant, when it
+// has one, and of its product which counts the stock of its variants
+func (m *refundUsecase) restock(ctx context.Context, line domain.OrderItem, qty int) error {
+	if line.VariantID != 0 {
+		if err := m.variantRepo.IncrementStock(ctx, line.VariantID, qty); err != nil {
+			return err
+		}
+	}
+	return m.productRepo.IncrementStock(ctx, line.ProductID, qty)
+}
+
+// capturedPayment returns the payment through which the order was paid, a
+// zero Payment for orders marked paid by staff which are refunded by hand
+func (m *refundUsecase) capturedPayment(ctx context.Context, orderID int64) (domain.Payment, error) {
+	payments, err := m.paymentRepo.FetchByOrder(ctx, orderID)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	for _, p := range payments {
+		if p.Status == domain.PaymentStatusSucceeded {
+			return p, nil
+		}
+	}
+	return domain.Payment{}, nil
+}
+
+// markRefunded moves the order to refunded
+func (m *refundUsecase) markRefunded(ctx context.Context, orderID int64, reason domain.RefundReason) error {
+	_, err := m.orderUsecase.Transition(ctx, orderID, domain.OrderStatusRefunded, string(reason))
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		// e.g. a shipped order refunded in full stays shipped until it is returned
+		return nil
+	}
+	return err
+}
+
+// refundAtProvider asks the provider to refund r on payment and records the
+// answer. A refusal is not an error of the request, the refund is marked
+// failed with the reason and can be retried.
+func (m *refundUsecase) refundAtProvider(ctx context.Context, r *domain.Refund, payment domain.Payment) error {
+	from := r.Status
+	ref, err := m.provider.Refund(ctx, payment.ProviderRef, r.Amount)
+	if err != nil {
+		logrus.Error(err)
+		r.Status = domain.RefundStatusFailed
+		r.FailureReason = err.Error()
+	} else {
+		r.Status = domain.RefundStatusSucceeded
+		r.ProviderRef = ref
+		r.FailureReason = ""
+	}
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := m.orderRepo.GetByIDForUpdate(ctx, r.OrderID)
+		if err != nil {
+			return err
+		}
+		if err = m.refundRepo.UpdateStatus(ctx, r, from); err != nil {
+			return err
+		}
+		if r.Status == domain.RefundStatusFailed {
+			// the money never left, it is back in the balance of the order
+			return m.orderRepo.AddRefunded(ctx, r.OrderID, r.Amount.Mul(-1))
+		}
+		if order.RemainingBalance.IsPositive() {
+			return nil
+		}
+		refunds, err := m.refundRepo.FetchByOrder(ctx, r.OrderID)
+		if err != nil {
+			return err
+		}
+		for _, other := range refunds {
+			if other.Status != domain.RefundStatusSucceeded {
+				return nil
+			}
+		}
+		return m.markRefunded(ctx, r.OrderID, r.Reason)
+	})
+}
+
+// Retry refuses a refund still pending within the timeout of a request, the
+// request that recorded it may still be waiting for the provider. A failed
+// refund holds its amount in the refunded total again before the provider is
+// asked, ErrRefundExceedsBalance is returned when the balance was refunded
+// otherwise meanwhile.
+func (m *refundUsecase) Retry(ctx context.Context, orderID int64, id int64) (domain.Refund, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.refundRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if res.OrderID != orderID {
+		return domain.Refund{}, domain.ErrNotFound
+	}
+	if res.Status == domain.RefundStatusSucceeded || res.PaymentID == 0 {
+		return domain.Refund{}, domain.ErrConflict
+	}
+	if res.Status == domain.RefundStatusPending && time.Since(res.CreatedAt) < m.contextTimeout {
+		return domain.Refund{}, domain.ErrConflict
+	}
+	payments, err := m.paymentRepo.FetchByOrder(ctx, orderID)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	for _, p := range payments {
+		if p.ID != res.PaymentID {
+			continue
+		}
+		if res.Status == domain.RefundStatusFailed {
+			if err = m.reserve(ctx, &res); err != nil {
+				return domain.Refund{}, err
+			}
+		}
+		err = m.refundAtProvider(ctx, &res, p)
+		return res, err
+	}
+	return domain.Refund{}, domain.ErrNotFound
+}
+
+// reserve moves the failed refund r back to pending, adding its amount to the
+// refunded total of the order again
+func (m *refundUsecase) reserve(ctx context.Context, r *domain.Refund) error {
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := m.orderRepo.GetByIDForUpdate(ctx, r.OrderID); err != nil {
+			return err
+		}
+		if err := m.orderRepo.AddRefunded(ctx, r.OrderID, r.Amount); err != nil {
+			return err
+		}
+		r.Status = domain.RefundStatusPending
+		return m.refundRepo.UpdateStatus(ctx, r, domain.RefundStatusFailed)
+	})
+}
+
+func (m *refundUsecase) FetchByOrder(ctx context.Context, orderID int64) ([]domain.Refund, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if _, err := m.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return m.refundRepo.FetchByOrder(ctx, orderID)
+}
@@ -0,0 +1,364 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	ucase "github.com/alfathaulia/ca_ecommerce_api/payment/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func idr(amount int64) domain.Money {
+	return domain.NewMoney(amount, "IDR")
+}
+
+func TestRefund(t *testing.T) {
+	staff := domain.NewContextWithAuth(context.TODO(), &domain.TokenPayload{UserID: 7, Role: domain.RolesTypeAdmin})
+	paidOrder := domain.Order{
+		ID:               8,
+		Status:           domain.OrderStatusDelivered,
+		TotalPrice:       idr(35000000),
+		RemainingBalance: idr(35000000),
+		Items: []domain.OrderItem{
+			{ID: 3, OrderID: 8, ProductID: 4, Qty: 2, Price: idr(15000000)},
+			{ID: 4, OrderID: 8, ProductID: 5, Qty: 1, Price: idr(5000000)},
+		},
+	}
+	captured := domain.Payment{ID: 1, OrderID: 8, Provider: "fake", ProviderRef: "fake_pi_1", Status: domain.PaymentStatusSucceeded}
+
+	type deps struct {
+		refundRepo  *mocks.RefundRepository
+		paymentRepo *mocks.PaymentRepository
+		orderRepo   *mocks.OrderRepository
+		productRepo *mocks.ProductRepository
+		variantRepo *mocks.VariantRepository
+		orderUcase  *mocks.OrderUsecase
+		provider    *mocks.PaymentProvider
+		transactor  *mocks.Transactor
+	}
+	newUsecase := func() (domain.RefundUsecase, deps) {
+		d := deps{
+			refundRepo:  new(mocks.RefundRepository),
+			paymentRepo: new(mocks.PaymentRepository),
+			orderRepo:   new(mocks.OrderRepository),
+			productRepo: new(mocks.ProductRepository),
+			variantRepo: new(mocks.VariantRepository),
+			orderUcase:  new(mocks.OrderUsecase),
+			provider:    new(mocks.PaymentProvider),
+			transactor:  new(mocks.Transactor),
+		}
+		d.transactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Maybe()
+		d.orderRepo.On("GetByIDForUpdate", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Maybe()
+		u := ucase.NewRefundUsecase(d.refundRepo, d.paymentRepo, d.orderRepo, d.productRepo, d.variantRepo, d.orderUcase, d.provider, d.transactor, time.Second*2)
+		return u, d
+	}
+
+	t.Run("partial-by-items-with-restock", func(t *testing.T) {
+		u, d := newUsecase()
+		d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Once()
+		d.refundRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Refund{}, nil).Once()
+		d.productRepo.On("IncrementStock", mock.Anything, int64(4), 1).Return(nil).Once()
+		d.orderRepo.On("AddRefunded", mock.Anything, paidOrder.ID, idr(15000000)).Return(nil).Once()
+		d.paymentRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Payment{captured}, nil).Once()
+		committed := false
+		d.refundRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.Refund) bool {
+			return r.Amount == idr(15000000) && r.PaymentID == captured.ID && r.Status == domain.RefundStatusPending && r.CreatedBy == 7 &&
+				len(r.Items) == 1 && r.Items[0].Restocked
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Refund).ID = 5
+		}).Once()
+		d.transactor.ExpectedCalls = nil
+		d.transactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			err := fn(ctx)
+			committed = err == nil
+			return err
+		})
+		// the provider is only asked once the refund is committed
+		d.provider.On("Refund", mock.Anything, captured.ProviderRef, idr(15000000)).Return("fake_re_1", nil).Run(func(mock.Arguments) {
+			assert.True(t, committed)
+		}).Once()
+		d.refundRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Refund) bool {
+			return r.ID == 5 && r.Status == domain.RefundStatusSucceeded && r.ProviderRef == "fake_re_1"
+		}), domain.RefundStatusPending).Return(nil).Once()
+
+		res, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{
+			Items:  []domain.RefundItemRequest{{OrderItemID: 3, Qty: 1, Restock: true}},
+			Reason: domain.RefundReasonDamaged,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, idr(15000000), res.Amount)
+		assert.Equal(t, domain.RefundStatusSucceeded, res.Status)
+		d.orderRepo.AssertCalled(t, "GetByIDForUpdate", mock.Anything, paidOrder.ID)
+		d.orderUcase.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		d.productRepo.AssertExpectations(t)
+		d.orderRepo.AssertExpectations(t)
+		d.provider.AssertExpectations(t)
+		d.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("provider-refuses", func(t *testing.T) {
+		u, d := newUsecase()
+		refunded := idr(0)
+		d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Once()
+		d.orderRepo.On("AddRefunded", mock.Anything, paidOrder.ID, mock.AnythingOfType("domain.Money")).Return(nil).Run(func(args mock.Arguments) {
+			refunded, _ = refunded.Add(args.Get(2).(domain.Money))
+		}).Twice()
+		d.paymentRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Payment{captured}, nil).Once()
+		d.refundRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Refund")).Return(nil).Once()
+		d.provider.On("Refund", mock.Anything, captured.ProviderRef, idr(35000000)).Return("", errors.New("card expired")).Once()
+		d.refundRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Refund) bool {
+			return r.Status == domain.RefundStatusFailed && r.FailureReason == "card expired"
+		}), domain.RefundStatusPending).Return(nil).Once()
+
+		res, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{Reason: domain.RefundReasonCustomerRequest})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RefundStatusFailed, res.Status)
+		// nothing left the shop, the order keeps its balance and its status
+		assert.Equal(t, idr(0), refunded)
+		d.orderRepo.AssertCalled(t, "AddRefunded", mock.Anything, paidOrder.ID, idr(-35000000))
+		d.orderUcase.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		d.orderRepo.AssertExpectations(t)
+		d.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("full-refund-at-provider", func(t *testing.T) {
+		refundedOrder := paidOrder
+		refundedOrder.RefundedTotal = idr(35000000)
+		refundedOrder.RemainingBalance = idr(0)
+		for name, tc := range map[string]struct {
+			others     []domain.Refund
+			transition bool
+		}{
+			"marks-order-refunded": {others: []domain.Refund{{ID: 5, Status: domain.RefundStatusSucceeded}}, transition: true},
+			"other-refund-pending": {others: []domain.Refund{{ID: 4, Status: domain.RefundStatusPending}, {ID: 5, Status: domain.RefundStatusSucceeded}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				u, d := newUsecase()
+				d.orderRepo.ExpectedCalls = nil
+				d.orderRepo.On("GetByIDForUpdate", mock.Anything, paidOrder.ID).Return(refundedOrder, nil).Twice()
+				d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Once()
+				d.orderRepo.On("AddRefunded", mock.Anything, paidOrder.ID, idr(35000000)).Return(nil).Once()
+				d.paymentRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Payment{captured}, nil).Once()
+				d.refundRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Refund")).Return(nil).Once()
+				d.provider.On("Refund", mock.Anything, captured.ProviderRef, idr(35000000)).Return("fake_re_1", nil).Once()
+				d.refundRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*domain.Refund"), domain.RefundStatusPending).Return(nil).Once()
+				d.refundRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return(tc.others, nil).Once()
+				if tc.transition {
+					// the order is only refunded once the provider gave the money back
+					d.orderUcase.On("Transition", mock.Anything, paidOrder.ID, domain.OrderStatusRefunded, "customer_request").
+						Return(domain.Order{}, nil).Run(func(mock.Arguments) {
+						d.provider.AssertExpectations(t)
+					}).Once()
+				}
+
+				res, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{Reason: domain.RefundReasonCustomerRequest})
+				assert.NoError(t, err)
+				assert.Equal(t, domain.RefundStatusSucceeded, res.Status)
+				if !tc.transition {
+					d.orderUcase.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				}
+				d.orderUcase.AssertExpectations(t)
+				d.orderRepo.AssertExpectations(t)
+				d.refundRepo.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("restocks-variant", func(t *testing.T) {
+		u, d := newUsecase()
+		variantOrder := paidOrder
+		variantOrder.Items = []domain.OrderItem{
+			{ID: 3, OrderID: 8, ProductID: 4, VariantID: 7, SKU: "SHIRT-XL", Qty: 2, Price: idr(15000000)},
+			{ID: 4, OrderID: 8, ProductID: 5, VariantID: 9, SKU: "CAP-RED", Qty: 1, Price: idr(5000000)},
+		}
+		d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(variantOrder, nil).Once()
+		d.refundRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Refund{}, nil).Once()
+		d.variantRepo.On("IncrementStock", mock.Anything, int64(7), 1).Return(nil).Once()
+		d.productRepo.On("IncrementStock", mock.Anything, int64(4), 1).Return(nil).Once()
+		// the variant was deleted since, its product is left alone
+		d.variantRepo.On("IncrementStock", mock.Anything, int64(9), 1).Return(domain.ErrNotFound).Once()
+		d.orderRepo.On("AddRefunded", mock.Anything, paidOrder.ID, idr(20000000)).Return(nil).Once()
+		d.paymentRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Payment{}, nil).Once()
+		d.refundRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.Refund) bool {
+			return len(r.Items) == 2 && r.Items[0].Restocked && !r.Items[1].Restocked
+		})).Return(nil).Once()
+
+		_, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{
+			Items: []domain.RefundItemRequest{
+				{OrderItemID: 3, Qty: 1, Restock: true},
+				{OrderItemID: 4, Qty: 1, Restock: true},
+			},
+			Reason: domain.RefundReasonDamaged,
+		})
+		assert.NoError(t, err)
+		d.variantRepo.AssertExpectations(t)
+		d.productRepo.AssertExpectations(t)
+		d.productRepo.AssertNotCalled(t, "IncrementStock", mock.Anything, int64(5), mock.Anything)
+		d.refundRepo.AssertExpectations(t)
+	})
+
+	t.Run("full-refund-marks-order-refunded", func(t *testing.T) {
+		u, d := newUsecase()
+		d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Once()
+		d.orderRepo.On("AddRefunded", mock.Anything, paidOrder.ID, idr(35000000)).Return(nil).Once()
+		d.paymentRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Payment{}, nil).Once()
+		d.refundRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.Refund) bool {
+			return r.Amount == idr(35000000) && r.PaymentID == 0 && r.Status == domain.RefundStatusSucceeded
+		})).Return(nil).Once()
+		d.orderUcase.On("Transition", mock.Anything, paidOrder.ID, domain.OrderStatusRefunded, "customer_request").
+			Return(domain.Order{}, nil).Once()
+
+		res, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{Reason: domain.RefundReasonCustomerRequest})
+		assert.NoError(t, err)
+		assert.Equal(t, idr(35000000), res.Amount)
+		d.orderUcase.AssertExpectations(t)
+		d.refundRepo.AssertExpectations(t)
+		d.provider.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("more-units-than-ordered", func(t *testing.T) {
+		u, d := newUsecase()
+		d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Once()
+		d.refundRepo.On("FetchByOrder", mock.Anything, paidOrder.ID).Return([]domain.Refund{
+			{ID: 1, Items: []domain.RefundItem{{OrderItemID: 3, Qty: 2}}},
+		}, nil).Once()
+
+		_, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{
+			Items:  []domain.RefundItemRequest{{OrderItemID: 3, Qty: 1}},
+			Reason: domain.RefundReasonDamaged,
+		})
+		assert.Equal(t, domain.ErrRefundExceedsBalance, err)
+		d.orderRepo.AssertNotCalled(t, "AddRefunded", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("amount-above-balance", func(t *testing.T) {
+		u, d := newUsecase()
+		d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Once()
+
+		_, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{Amount: idr(99999900), Reason: domain.RefundReasonOther})
+		assert.Equal(t, domain.ErrRefundExceedsBalance, err)
+		d.orderRepo.AssertNotCalled(t, "AddRefunded", mock.Anything, mock.Anything, mock.Anything)
+		d.provider.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("amount-in-other-currency", func(t *testing.T) {
+		u, d := newUsecase()
+		d.orderUcase.On("GetByID", mock.Anything, paidOrder.ID).Return(paidOrder, nil).Once()
+
+		_, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{Amount: domain.NewMoney(1000, "USD"), Reason: domain.RefundReasonOther})
+		assert.Equal(t, domain.ErrCurrencyMismatch, err)
+		d.orderRepo.AssertNotCalled(t, "AddRefunded", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unpaid-order", func(t *testing.T) {
+		u, d := newUsecase()
+		pending := paidOrder
+		pending.Status = domain.OrderStatusPending
+		d.orderUcase.On("GetByID", mock.Anything, pending.ID).Return(pending, nil).Once()
+
+		_, err := u.Refund(staff, pending.ID, domain.RefundRequest{Reason: domain.RefundReasonOther})
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	})
+
+	t.Run("invalid-reason", func(t *testing.T) {
+		u, _ := newUsecase()
+		_, err := u.Refund(staff, paidOrder.ID, domain.RefundRequest{Reason: "bored"})
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestRetryRefund(t *testing.T) {
+	captured := domain.Payment{ID: 1, OrderID: 8, ProviderRef: "fake_pi_1", Status: domain.PaymentStatusSucceeded}
+	failed := domain.Refund{ID: 5, OrderID: 8, PaymentID: 1, Status: domain.RefundStatusFailed, FailureReason: "card expired",
+		Amount: idr(5000000), CreatedAt: time.Now().Add(-time.Hour)}
+	order := domain.Order{ID: 8, Status: domain.OrderStatusDelivered, TotalPrice: idr(35000000), RemainingBalance: idr(30000000)}
+
+	t.Run("success", func(t *testing.T) {
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockProvider := new(mocks.PaymentProvider)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction)
+		mockRefundRepo.On("GetByID", mock.Anything, failed.ID).Return(failed, nil).Once()
+		mockPaymentRepo.On("FetchByOrder", mock.Anything, failed.OrderID).Return([]domain.Payment{captured}, nil).Once()
+		mockOrderRepo.On("GetByIDForUpdate", mock.Anything, failed.OrderID).Return(order, nil).Twice()
+		// the failed refund holds its amount again before the provider is asked
+		mockOrderRepo.On("AddRefunded", mock.Anything, failed.OrderID, failed.Amount).Return(nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Refund) bool {
+			return r.Status == domain.RefundStatusPending
+		}), domain.RefundStatusFailed).Return(nil).Once()
+		mockProvider.On("Refund", mock.Anything, captured.ProviderRef, failed.Amount).Return("fake_re_2", nil).Once()
+		mockRefundRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(r *domain.Refund) bool {
+			return r.Status == domain.RefundStatusSucceeded && r.ProviderRef == "fake_re_2" && r.FailureReason == ""
+		}), domain.RefundStatusPending).Return(nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockPaymentRepo, mockOrderRepo, new(mocks.ProductRepository), new(mocks.VariantRepository), new(mocks.OrderUsecase), mockProvider, mockTransactor, time.Second*2)
+		res, err := u.Retry(context.TODO(), failed.OrderID, failed.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RefundStatusSucceeded, res.Status)
+		mockRefundRepo.AssertExpectations(t)
+		mockOrderRepo.AssertExpectations(t)
+		mockProvider.AssertExpectations(t)
+	})
+
+	t.Run("balance-refunded-meanwhile", func(t *testing.T) {
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockPaymentRepo := new(mocks.PaymentRepository)
+		mockOrderRepo := new(mocks.OrderRepository)
+		mockProvider := new(mocks.PaymentProvider)
+		mockTransactor := new(mocks.Transactor)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction)
+		mockRefundRepo.On("GetByID", mock.Anything, failed.ID).Return(failed, nil).Once()
+		mockPaymentRepo.On("FetchByOrder", mock.Anything, failed.OrderID).Return([]domain.Payment{captured}, nil).Once()
+		mockOrderRepo.On("GetByIDForUpdate", mock.Anything, failed.OrderID).Return(order, nil).Once()
+		mockOrderRepo.On("AddRefunded", mock.Anything, failed.OrderID, failed.Amount).Return(domain.ErrRefundExceedsBalance).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, mockPaymentRepo, mockOrderRepo, new(mocks.ProductRepository), new(mocks.VariantRepository), new(mocks.OrderUsecase), mockProvider, mockTransactor, time.Second*2)
+		_, err := u.Retry(context.TODO(), failed.OrderID, failed.ID)
+		assert.Equal(t, domain.ErrRefundExceedsBalance, err)
+		mockRefundRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		mockProvider.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("already-succeeded", func(t *testing.T) {
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockProvider := new(mocks.PaymentProvider)
+		done := failed
+		done.Status = domain.RefundStatusSucceeded
+		mockRefundRepo.On("GetByID", mock.Anything, failed.ID).Return(done, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, new(mocks.PaymentRepository), new(mocks.OrderRepository), new(mocks.ProductRepository), new(mocks.VariantRepository), new(mocks.OrderUsecase), mockProvider, new(mocks.Transactor), time.Second*2)
+		_, err := u.Retry(context.TODO(), failed.OrderID, failed.ID)
+		assert.Equal(t, domain.ErrConflict, err)
+		mockProvider.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("pending-in-flight", func(t *testing.T) {
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockProvider := new(mocks.PaymentProvider)
+		pending := failed
+		pending.Status = domain.RefundStatusPending
+		pending.CreatedAt = time.Now()
+		mockRefundRepo.On("GetByID", mock.Anything, failed.ID).Return(pending, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, new(mocks.PaymentRepository), new(mocks.OrderRepository), new(mocks.ProductRepository), new(mocks.VariantRepository), new(mocks.OrderUsecase), mockProvider, new(mocks.Transactor), time.Second*2)
+		_, err := u.Retry(context.TODO(), failed.OrderID, failed.ID)
+		assert.Equal(t, domain.ErrConflict, err)
+		mockProvider.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("refund-of-other-order", func(t *testing.T) {
+		mockRefundRepo := new(mocks.RefundRepository)
+		mockRefundRepo.On("GetByID", mock.Anything, failed.ID).Return(failed, nil).Once()
+
+		u := ucase.NewRefundUsecase(mockRefundRepo, new(mocks.PaymentRepository), new(mocks.OrderRepository), new(mocks.ProductRepository), new(mocks.VariantRepository), new(mocks.OrderUsecase), new(mocks.PaymentProvider), new(mocks.Transactor), time.Second*2)
+		_, err := u.Retry(context.TODO(), 9, failed.ID)
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"reflect"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// amounts are validated on their minor units, e.g. validate:"gte=0"
+	v.RegisterCustomTypeFunc(moneyAmount, domain.Money{})
+	return v
+}
+
+func moneyAmount(field reflect.Value) interface{} {
+	return field.Interface().(domain.Money).Amount
+}
+
+// productRequest is the body of create and update, rating and num_reviews
+// come from the reviews and are never set by the client
+type productRequest struct {
+	Image        string       `json:"image" validate:"required"`
+	Name         string       `json:"name" validate:"required,max=255"`
+	Brand        string       `json:"brand" validate:"required,max=255"`
+	CategoryID   int64        `json:"category_id" validate:"required,gt=0"`
+	Description  string       `json:"description" validate:"required"`
+	Price        domain.Money `json:"price" validate:"gte=0"`
+	TaxCategory  string       `json:"tax_category" validate:"max=64"`
+	CountInStock int          `json:"count_in_stock" validate:"gte=0"`
+	WeightGrams  int          `json:"weight_grams" validate:"gte=0"`
+	LengthMM     int          `json:"length_mm" validate:"gte=0"`
+	WidthMM      int          `json:"width_mm" validate:"gte=0"`
+	HeightMM     int          `json:"height_mm" validate:"gte=0"`
+}
+
+func (r productRequest) toProduct() domain.Product {
+	return domain.Product{
+		Image:        r.Image,
+		Name:         r.Name,
+		Brand:        r.Brand,
+		CategoryID:   r.CategoryID,
+		Description:  r.Description,
+		Price:        r.Price,
+		TaxCategory:  domain.TaxCategory(r.TaxCategory),
+		CountInStock: r.CountInStock,
+		WeightGrams:  r.WeightGrams,
+		LengthMM:     r.LengthMM,
+		WidthMM:      r.WidthMM,
+		HeightMM:     r.HeightMM,
+	}
+}
+
+// optionRequest is the body of adding an option, values in the order they
+// are offered
+type optionRequest struct {
+	Name     string   `json:"name" validate:"required,max=64"`
+	Position int      `json:"position" validate:"gte=0"`
+	Values   []string `json:"values" validate:"required,min=1,dive,required,max=64"`
+}
+
+func (r optionRequest) toOption() domain.ProductOption {
+	values := make([]domain.OptionValue, len(r.Values))
+	for i, v := range r.Values {
+		values[i] = domain.OptionValue{Value: v}
+	}
+	return domain.ProductOption{
+		Name:     r.Name,
+		Position: r.Position,
+		Values:   values,
+	}
+}
+
+// variantRequest is the body of create and update of a variant, one value of
+// each option of the product is chosen by its id
+type variantRequest struct {
+	SKU            string       `json:"sku" validate:"required,max=64"`
+	Price          domain.Money `json:"price" validate:"gte=0"`
+	CountInStock   int          `json:"count_in_stock" validate:"gte=0"`
+	Image          string       `json:"image" validate:"max=512"`
+	Barcode        string       `json:"barcode" validate:"max=64"`
+	OptionValueIDs []int64      `json:"option_value_ids" validate:"required,min=1,dive,gt=0"`
+}
+
+func (r variantRequest) toVariant() domain.Variant {
+	options := make([]domain.OptionValue, len(r.OptionValueIDs))
+	for i, id := range r.OptionValueIDs {
+		options[i] = domain.OptionValue{ID: id}
+	}
+	return domain.Variant{
+		SKU:          r.SKU,
+		Price:        r.Price,
+		CountInStock: r.CountInStock,
+		Image:        r.Image,
+		Barcode:      r.Barcode,
+		Options:      options,
+	}
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// acceptCurrencyHeaderKey asks for prices in a currency when the currency query param is not set
+	acceptCurrencyHeaderKey = "Accept-Currency"
+	// searchParam carries the words of a catalog search
+	searchParam = "q"
+	// maxSearchLength is the longest search accepted, in characters
+	maxSearchLength = 200
+)
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// queryErrorResponse answers a list request with query params that were rejected
+type queryErrorResponse struct {
+	Message string                `json:"message"`
+	Details []domain.QueryProblem `json:"details"`
+}
+
+type ProductHandler struct {
+	PUsecase  domain.ProductUsecase
+	RUsecase  domain.ExchangeRateUsecase
+	Paginator *pagination.Paginator
+}
+
+func NewProductHandler(e *echo.Echo, pucase domain.ProductUsecase, rucase domain.ExchangeRateUsecase, pg *pagination.Paginator, mw *middleware.GoMiddleware) {
+	handler := &ProductHandler{
+		PUsecase:  pucase,
+		RUsecase:  rucase,
+		Paginator: pg,
+	}
+	e.GET("/products", handler.FetchProduct)
+	e.GET("/products/search", handler.Search)
+	e.GET("/products/:id", handler.GetByID)
+	e.GET("/categories/:id/products", handler.FetchByCategory)
+	e.POST("/products", handler.Store, mw.Auth, mw.RequirePermission(domain.PermissionProductWrite))
+	e.PUT("/products/:id", handler.Update, mw.Auth, mw.RequirePermission(domain.PermissionProductWrite))
+	e.DELETE("/products/:id", handler.Delete, mw.Auth, mw.RequirePermission(domain.PermissionProductDelete))
+}
+
+// FetchProduct will list the catalog one page at a time
+func (p *ProductHandler) FetchProduct(c echo.Context) error {
+	page, err := p.Paginator.Page(c, domain.ProductListSchema, "currency")
+	var queryErr *domain.QueryError
+	if errors.As(err, &queryErr) {
+		return c.JSON(http.StatusBadRequest, queryErrorResponse{Message: domain.ErrBadParamInput.Error(), Details: queryErr.Problems})
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+
+	listProduct, info, err := p.PUsecase.Fetch(ctx, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if err = p.displayPrices(ctx, listProduct, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	p.Paginator.SetHeaders(c, info)
+	return c.JSON(http.StatusOK, listProduct)
+}
+
+// FetchByCategory will list the products of the category and its
+// subcategories, filtered, sorted and paged like FetchProduct
+func (p *ProductHandler) FetchByCategory(c echo.Context) error {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	page, err := p.Paginator.Page(c, domain.ProductListSchema, "currency")
+	var queryErr *domain.QueryError
+	if errors.As(err, &queryErr) {
+		return c.JSON(http.StatusBadRequest, queryErrorResponse{Message: domain.ErrBadParamInput.Error(), Details: queryErr.Problems})
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+
+	listProduct, info, err := p.PUsecase.FetchInCategory(ctx, categoryID, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if err = p.displayPrices(ctx, listProduct, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	p.Paginator.SetHeaders(c, info)
+	return c.JSON(http.StatusOK, listProduct)
+}
+
+// Search will look the catalog up by the q query param, filtered, sorted and
+// paged like FetchProduct, with the facets of the search
+func (p *ProductHandler) Search(c echo.Context) error {
+	var problems []domain.QueryProblem
+	query := strings.TrimSpace(c.QueryParam(searchParam))
+	switch {
+	case query == "":
+		problems = append(problems, domain.QueryProblem{Param: searchParam, Reason: "is required"})
+	case utf8.RuneCountInString(query) > maxSearchLength:
+		problems = append(problems, domain.QueryProblem{Param: searchParam, Reason: fmt.Sprintf("is longer than %d characters", maxSearchLength)})
+	}
+	page, err := p.Paginator.Page(c, domain.ProductSearchSchema, searchParam, "currency")
+	var queryErr *domain.QueryError
+	if errors.As(err, &queryErr) {
+		problems = append(problems, queryErr.Problems...)
+	} else if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if len(problems) > 0 {
+		return c.JSON(http.StatusBadRequest, queryErrorResponse{Message: domain.ErrBadParamInput.Error(), Details: problems})
+	}
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+
+	res, info, err := p.PUsecase.Search(ctx, query, page)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if err = p.displayPrices(ctx, res.Products, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	p.Paginator.SetHeaders(c, info)
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetByID will get product by given id
+func (p *ProductHandler) GetByID(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	currency, err := displayCurrency(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	ctx := c.Request().Context()
+	product, err := p.PUsecase.GetByID(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	products := []domain.Product{product}
+	if err = p.displayPrices(ctx, products, currency); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, products[0])
+}
+
+// Store will store the product by given request body
+func (p *ProductHandler) Store(c echo.Context) (err error) {
+	var req productRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	product := req.toProduct()
+	product.UserID = auth.UserID
+	err = p.PUsecase.Store(ctx, &product)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, product)
+}
+
+// Update will replace the catalog fields of the product by given param
+func (p *ProductHandler) Update(c echo.Context) (err error) {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req productRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	product := req.toProduct()
+	product.ID = int64(idP)
+	ctx := c.Request().Context()
+	err = p.PUsecase.Update(ctx, &product)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+// Delete will delete product by given param
+func (p *ProductHandler) Delete(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = p.PUsecase.Delete(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// displayCurrency is the currency asked for by the currency query param or
+// the Accept-Currency header, empty when prices are shown as stored
+func displayCurrency(c echo.Context) (domain.Currency, error) {
+	code := c.QueryParam("currency")
+	if code == "" {
+		code = c.Request().Header.Get(acceptCurrencyHeaderKey)
+	}
+	if code == "" {
+		return "", nil
+	}
+	currency := domain.Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !currency.IsValid() {
+		return "", domain.ErrInvalidCurrency
+	}
+	return currency, nil
+}
+
+// displayPrices fills DisplayPrice of the products and their variants
+// converted to currency, the rate of every currency is looked up once
+func (p *ProductHandler) displayPrices(ctx context.Context, products []domain.Product, currency domain.Currency) error {
+	if currency == "" {
+		return nil
+	}
+	rates := make(map[domain.Currency]domain.Rate)
+	convert := func(price domain.Money) (*domain.Money, error) {
+		rate, ok := rates[price.Currency]
+		if !ok {
+			var err error
+			rate, err = p.RUsecase.Rate(ctx, price.Currency, currency)
+			if err != nil {
+				return nil, err
+			}
+			rates[price.Currency] = rate
+		}
+		display := rate.Convert(price, currency)
+		return &display, nil
+	}
+	for i := range products {
+		var err error
+		if products[i].DisplayPrice, err = convert(products[i].Price); err != nil {
+			return err
+		}
+		for j := range products[i].Variants {
+			if products[i].Variants[j].DisplayPrice, err = convert(products[i].Variants[j].Price); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict, domain.ErrVariantsExist:
+		return http.StatusConflict
+	case domain.ErrBadParamInput, domain.ErrCurrencyMismatch, domain.ErrInvalidCurrency, domain.ErrNoExchangeRate, domain.ErrUnknownCategory:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,388 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	productHttp "github.com/alfathaulia/ca_ecommerce_api/product/delivery/http"
+	"github.com/bxcodec/faker"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const cursorSecret = "cursor-secret"
+
+var paginator = pagination.NewPaginator(cursorSecret, 10, 100)
+
+func TestFetch(t *testing.T) {
+	var mockProduct domain.Product
+	err := faker.FakeData(&mockProduct)
+	assert.NoError(t, err)
+	codec := pagination.NewCodec(cursorSecret)
+
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		mockListProduct := []domain.Product{mockProduct}
+		byPrice := domain.Sort{Field: "price", Desc: true}
+		cursor := domain.Cursor{Sort: byPrice, Key: int64(5000), ID: 2}
+		next := &domain.Cursor{Sort: byPrice, Key: int64(4000), ID: 3}
+		mockUcase.On("Fetch", mock.Anything, domain.PageRequest{
+			Cursor:  &cursor,
+			Limit:   1,
+			Sort:    byPrice,
+			Filters: []domain.Filter{{Field: "category_id", Op: domain.FilterEq, Value: int64(4)}},
+		}).Return(mockListProduct, domain.PageInfo{Next: next}, nil)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products?num=1&category_id=4&cursor="+codec.Encode(cursor), strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.FetchProduct(c)
+		require.NoError(t, err)
+		assert.Equal(t, codec.Encode(*next), w.Header().Get("X-Cursor"))
+		assert.Empty(t, w.Header().Get("X-Prev-Cursor"))
+		assert.Equal(t, `</products?category_id=4&cursor=`+codec.Encode(*next)+`&num=1>; rel="next"`, w.Header().Get("Link"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("forged-cursor", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		forged := pagination.NewCodec("another-secret").Encode(domain.Cursor{Sort: domain.ProductListSchema.DefaultSort, Key: time.Now(), ID: 2})
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products?cursor="+forged, strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.FetchProduct(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchDisplayCurrency(t *testing.T) {
+	shirt := domain.Product{ID: 4, Name: "Shirt", Price: domain.NewMoney(16250000, "IDR")}
+	hat := domain.Product{ID: 5, Name: "Hat", Price: domain.NewMoney(8125000, "IDR")}
+
+	t.Run("converts-prices", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		mockUcase.On("Fetch", mock.Anything, domain.PageRequest{Limit: 10, Sort: domain.ProductListSchema.DefaultSort}).Return([]domain.Product{shirt, hat}, domain.PageInfo{}, nil).Once()
+		mockRateUcase := new(mocks.ExchangeRateUsecase)
+		mockRateUcase.On("Rate", mock.Anything, domain.Currency("IDR"), domain.Currency("USD")).Return(domain.Rate(6154), nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products", strings.NewReader(""))
+		assert.NoError(t, err)
+		req.Header.Set("Accept-Currency", "usd")
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			RUsecase:  mockRateUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.FetchProduct(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"price":{"amount":16250000,"currency":"IDR"},"display_price":{"amount":1000,"currency":"USD"}`)
+		assert.Contains(t, w.Body.String(), `"display_price":{"amount":500,"currency":"USD"}`)
+		mockRateUcase.AssertExpectations(t)
+	})
+
+	t.Run("no-rate", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		mockUcase.On("Fetch", mock.Anything, domain.PageRequest{Limit: 10, Sort: domain.ProductListSchema.DefaultSort}).Return([]domain.Product{shirt}, domain.PageInfo{}, nil).Once()
+		mockRateUcase := new(mocks.ExchangeRateUsecase)
+		mockRateUcase.On("Rate", mock.Anything, domain.Currency("IDR"), domain.Currency("EUR")).Return(domain.Rate(0), domain.ErrNoExchangeRate).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products?currency=EUR", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			RUsecase:  mockRateUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.FetchProduct(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unsupported-currency", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products?currency=XYZ", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.FetchProduct(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchByCategory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		mockUcase.On("FetchInCategory", mock.Anything, int64(4), domain.PageRequest{Limit: 10, Sort: domain.ProductListSchema.DefaultSort}).
+			Return([]domain.Product{{ID: 1, CategoryID: 7, Price: domain.NewMoney(15000000, "IDR")}}, domain.PageInfo{}, nil)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/categories/4/products", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("categories/:id/products")
+		c.SetParamNames("id")
+		c.SetParamValues("4")
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.FetchByCategory(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("unknown-category", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		mockUcase.On("FetchInCategory", mock.Anything, int64(9), mock.Anything).Return(nil, domain.PageInfo{}, domain.ErrNotFound)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/categories/9/products", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("categories/:id/products")
+		c.SetParamNames("id")
+		c.SetParamValues("9")
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.FetchByCategory(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	shoe := domain.Product{ID: 4, Name: "Running Shoe", Brand: "Nike", Price: domain.NewMoney(16250000, "IDR")}
+
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		next := &domain.Cursor{Sort: domain.ProductSearchSchema.DefaultSort, Key: int64(1200000), ID: 4}
+		mockUcase.On("Search", mock.Anything, "running shoe", domain.PageRequest{
+			Limit: 1,
+			Sort:  domain.ProductSearchSchema.DefaultSort,
+			Filters: []domain.Filter{
+				{Field: "brand", Op: domain.FilterIn, Value: []interface{}{"Nike", "Adidas"}},
+				{Field: "in_stock", Op: domain.FilterEq, Value: true},
+				{Field: "price", Op: domain.FilterLte, Value: int64(20000000)},
+			},
+		}).Return(domain.ProductSearchResult{
+			Products: []domain.Product{shoe},
+			Facets: domain.ProductFacets{
+				Brands:     []domain.FacetCount{{Value: "Nike", Count: 2}, {Value: "Adidas", Count: 1}},
+				Categories: []domain.FacetCount{{Value: "Shoes", Count: 3}},
+				Prices:     []domain.PriceBucket{{Min: 10000000, Count: 3}},
+			},
+			Suggestions: []string{},
+		}, domain.PageInfo{Next: next}, nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products/search?q=running+shoe&brand=Nike&brand=Adidas&in_stock=true&price_max=20000000&num=1", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.Search(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Header().Get("X-Cursor"))
+		assert.Contains(t, w.Header().Get("Link"), "q=running+shoe")
+		assert.Contains(t, w.Body.String(), `"facets":{"brands":[{"value":"Nike","count":2},{"value":"Adidas","count":1}],"categories":[{"value":"Shoes","count":3}],"prices":[{"min":10000000,"count":3}]}`)
+		assert.Contains(t, w.Body.String(), `"suggestions":[]`)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products/search?rating_min=good&count_in_stock=1", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase:  mockUcase,
+			Paginator: paginator,
+		}
+
+		err = handler.Search(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{
+			"message": "given param is not valid",
+			"details": [
+				{"param": "q", "reason": "is required"},
+				{"param": "count_in_stock", "reason": "is not a filter of this list"},
+				{"param": "rating_min", "reason": "must be a whole number"}
+			]
+		}`, w.Body.String())
+		mockUcase.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	t.Run("variant-display-prices", func(t *testing.T) {
+		shirt := domain.Product{ID: 4, Name: "Shirt", Price: domain.NewMoney(16250000, "IDR"), Variants: []domain.Variant{
+			{ID: 7, ProductID: 4, SKU: "SHIRT-XL", Price: domain.NewMoney(32500000, "IDR")},
+		}}
+		mockUcase := new(mocks.ProductUsecase)
+		mockUcase.On("GetByID", mock.Anything, int64(4)).Return(shirt, nil).Once()
+		mockRateUcase := new(mocks.ExchangeRateUsecase)
+		mockRateUcase.On("Rate", mock.Anything, domain.Currency("IDR"), domain.Currency("USD")).Return(domain.Rate(6154), nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products/4?currency=USD", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("products/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("4")
+		handler := productHttp.ProductHandler{
+			PUsecase: mockUcase,
+			RUsecase: mockRateUcase,
+		}
+
+		err = handler.GetByID(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"sku":"SHIRT-XL","price":{"amount":32500000,"currency":"IDR"},"display_price":{"amount":2000,"currency":"USD"}`)
+		mockRateUcase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		mockUcase.On("GetByID", mock.Anything, int64(9)).Return(domain.Product{}, domain.ErrNotFound)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/products/9", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("products/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("9")
+		handler := productHttp.ProductHandler{
+			PUsecase: mockUcase,
+		}
+
+		err = handler.GetByID(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+}
+
+func TestStore(t *testing.T) {
+	body := `{"image":"/images/shirt.jpg","name":"Shirt","brand":"Acme","category_id":4,"description":"A plain shirt","price":{"amount":15000000,"currency":"IDR"},"count_in_stock":5}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+		mockUcase.On("Store", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "Shirt" && p.UserID == 3 && p.Price == domain.NewMoney(15000000, "IDR")
+		})).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/products", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, Role: domain.RolesTypeStaff}))
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase: mockUcase,
+		}
+
+		err = handler.Store(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-body", func(t *testing.T) {
+		mockUcase := new(mocks.ProductUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/products", strings.NewReader(`{"name":"Shirt","price":{"amount":-1,"currency":"IDR"}}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = req.WithContext(domain.NewContextWithAuth(context.Background(), &domain.TokenPayload{UserID: 3, Role: domain.RolesTypeStaff}))
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := productHttp.ProductHandler{
+			PUsecase: mockUcase,
+		}
+
+		err = handler.Store(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+type VariantHandler struct {
+	VUsecase domain.VariantUsecase
+}
+
+func NewVariantHandler(e *echo.Echo, vucase domain.VariantUsecase, mw *middleware.GoMiddleware) {
+	handler := &VariantHandler{
+		VUsecase: vucase,
+	}
+	e.GET("/products/:id/options", handler.FetchOptions)
+	e.POST("/products/:id/options", handler.StoreOption, mw.Auth, mw.RequirePermission(domain.PermissionProductWrite))
+	e.DELETE("/products/:id/options/:option_id", handler.DeleteOption, mw.Auth, mw.RequirePermission(domain.PermissionProductWrite))
+	e.GET("/products/:id/variants", handler.FetchVariants)
+	e.POST("/products/:id/variants", handler.StoreVariant, mw.Auth, mw.RequirePermission(domain.PermissionProductWrite))
+	e.PUT("/products/:id/variants/:variant_id", handler.UpdateVariant, mw.Auth, mw.RequirePermission(domain.PermissionProductWrite))
+	e.DELETE("/products/:id/variants/:variant_id", handler.DeleteVariant, mw.Auth, mw.RequirePermission(domain.PermissionProductWrite))
+}
+
+// FetchOptions will list the options of a product with their values
+func (h *VariantHandler) FetchOptions(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	options, err := h.VUsecase.FetchOptions(ctx, productID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, options)
+}
+
+// StoreOption will add an option with its values to a product without variants
+func (h *VariantHandler) StoreOption(c echo.Context) (err error) {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req optionRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	option := req.toOption()
+	option.ProductID = productID
+	ctx := c.Request().Context()
+	err = h.VUsecase.StoreOption(ctx, &option)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, option)
+}
+
+// DeleteOption will remove an option of a product without variants
+func (h *VariantHandler) DeleteOption(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	optionID, err := strconv.ParseInt(c.Param("option_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = h.VUsecase.DeleteOption(ctx, productID, optionID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// FetchVariants will list the variants of a product
+func (h *VariantHandler) FetchVariants(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	variants, err := h.VUsecase.FetchVariants(ctx, productID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, variants)
+}
+
+// StoreVariant will create a variant of a product from one value of each of its options
+func (h *VariantHandler) StoreVariant(c echo.Context) (err error) {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req variantRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	variant := req.toVariant()
+	variant.ProductID = productID
+	ctx := c.Request().Context()
+	err = h.VUsecase.StoreVariant(ctx, &variant)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, variant)
+}
+
+// UpdateVariant will replace the variant by given param
+func (h *VariantHandler) UpdateVariant(c echo.Context) (err error) {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	variantID, err := strconv.ParseInt(c.Param("variant_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req variantRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	variant := req.toVariant()
+	variant.ID = variantID
+	variant.ProductID = productID
+	ctx := c.Request().Context()
+	err = h.VUsecase.UpdateVariant(ctx, &variant)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, variant)
+}
+
+// DeleteVariant will delete the variant by given param
+func (h *VariantHandler) DeleteVariant(c echo.Context) error {
+	productID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+	variantID, err := strconv.ParseInt(c.Param("variant_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = h.VUsecase.DeleteVariant(ctx, productID, variantID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,118 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	productHttp "github.com/alfathaulia/ca_ecommerce_api/product/delivery/http"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreOption(t *testing.T) {
+	t.Run("variants-exist", func(t *testing.T) {
+		mockUcase := new(mocks.VariantUsecase)
+		mockUcase.On("StoreOption", mock.Anything, mock.MatchedBy(func(o *domain.ProductOption) bool {
+			return o.ProductID == 1 && o.Name == "Colour" && len(o.Values) == 2 && o.Values[1].Value == "Blue"
+		})).Return(domain.ErrVariantsExist).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/products/1/options", strings.NewReader(`{"name":"Colour","values":["Red","Blue"]}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("products/:id/options")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		handler := productHttp.VariantHandler{
+			VUsecase: mockUcase,
+		}
+
+		err = handler.StoreOption(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("no-values", func(t *testing.T) {
+		mockUcase := new(mocks.VariantUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/products/1/options", strings.NewReader(`{"name":"Colour","values":[]}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		c.SetPath("products/:id/options")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		handler := productHttp.VariantHandler{
+			VUsecase: mockUcase,
+		}
+
+		err = handler.StoreOption(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "StoreOption", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreVariant(t *testing.T) {
+	mockUcase := new(mocks.VariantUsecase)
+	mockUcase.On("StoreVariant", mock.Anything, mock.MatchedBy(func(v *domain.Variant) bool {
+		return v.ProductID == 1 && v.SKU == "SHIRT-M-RED" && v.Price == domain.NewMoney(16000000, "IDR") &&
+			v.CountInStock == 3 && v.Barcode == "8991234567890" && len(v.Options) == 2 && v.Options[0].ID == 5
+	})).Return(nil).Once()
+
+	e := echo.New()
+	body := `{"sku":"SHIRT-M-RED","price":{"amount":16000000,"currency":"IDR"},"count_in_stock":3,"barcode":"8991234567890","option_value_ids":[5,8]}`
+	req, err := http.NewRequest(echo.POST, "/products/1/variants", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	c.SetPath("products/:id/variants")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	handler := productHttp.VariantHandler{
+		VUsecase: mockUcase,
+	}
+
+	err = handler.StoreVariant(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockUcase.AssertExpectations(t)
+}
+
+func TestDeleteVariant(t *testing.T) {
+	mockUcase := new(mocks.VariantUsecase)
+	mockUcase.On("DeleteVariant", mock.Anything, int64(1), int64(7)).Return(domain.ErrNotFound).Once()
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.DELETE, "/products/1/variants/7", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	c.SetPath("products/:id/variants/:variant_id")
+	c.SetParamNames("id", "variant_id")
+	c.SetParamValues("1", "7")
+	handler := productHttp.VariantHandler{
+		VUsecase: mockUcase,
+	}
+
+	err = handler.DeleteVariant(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUcase.AssertExpectations(t)
+}
@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlProductOptionRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlProductOptionRepo will create an object that represent the domain.ProductOptionRepository interface
+func NewMysqlProductOptionRepo(DB *sql.DB) domain.ProductOptionRepository {
+	return &mysqlProductOptionRepo{DB: DB}
+}
+
+// FetchByProduct reads the options and their values in one query, an option
+// without values comes with a row of NULL values
+func (m *mysqlProductOptionRepo) FetchByProduct(ctx context.Context, productID int64) (result []domain.ProductOption, err error) {
+	query := `SELECT o.id, o.product_id, o.name, o.position, v.id, v.value, v.position
+  						FROM product_option o LEFT JOIN product_option_value v ON v.option_id = o.id
+  						WHERE o.product_id = ? ORDER BY o.position, o.id, v.position, v.id`
+
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, productID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.ProductOption, 0)
+	for rows.Next() {
+		t := domain.ProductOption{}
+		var valueID sql.NullInt64
+		var value sql.NullString
+		var valuePosition sql.NullInt64
+		err = rows.Scan(
+			&t.ID,
+			&t.ProductID,
+			&t.Name,
+			&t.Position,
+			&valueID,
+			&value,
+			&valuePosition,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if n := len(result); n == 0 || result[n-1].ID != t.ID {
+			t.Values = make([]domain.OptionValue, 0)
+			result = append(result, t)
+		}
+		if valueID.Valid {
+			last := &result[len(result)-1]
+			last.Values = append(last.Values, domain.OptionValue{
+				ID:       valueID.Int64,
+				OptionID: t.ID,
+				Value:    value.String,
+				Position: int(valuePosition.Int64),
+			})
+		}
+	}
+	return result, nil
+}
+
+func (m *mysqlProductOptionRepo) Store(ctx context.Context, o *domain.ProductOption) (err error) {
+	query := `INSERT  product_option SET product_id=? , name=? , position=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, o.ProductID, o.Name, o.Position)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	o.ID = lastID
+
+	query = `INSERT  product_option_value SET option_id=? , value=? , position=?`
+	stmt, err = transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	for i := range o.Values {
+		o.Values[i].OptionID = o.ID
+		res, err = stmt.ExecContext(ctx, o.ID, o.Values[i].Value, o.Values[i].Position)
+		if err != nil {
+			return
+		}
+		if o.Values[i].ID, err = res.LastInsertId(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// DeleteByProduct removes the values first, it only makes sense inside a
+// transaction
+func (m *mysqlProductOptionRepo) DeleteByProduct(ctx context.Context, productID int64) (err error) {
+	query := "DELETE FROM product_option_value WHERE option_id IN (SELECT id FROM product_option WHERE product_id = ?)"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	if _, err = stmt.ExecContext(ctx, productID); err != nil {
+		return
+	}
+
+	query = "DELETE FROM product_option WHERE product_id = ?"
+	stmt, err = transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, productID)
+	return
+}
+
+func (m *mysqlProductOptionRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM product_option_value WHERE option_id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	if _, err = stmt.ExecContext(ctx, id); err != nil {
+		return
+	}
+
+	query = "DELETE FROM product_option WHERE id = ?"
+	stmt, err = transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", rowsAfected)
+		return
+	}
+	return
+}
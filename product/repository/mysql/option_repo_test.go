@@ -0,0 +1,81 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	productMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/product/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+func TestFetchOptions(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"id", "product_id", "name", "position", "value_id", "value", "value_position"}).
+		AddRow(2, 1, "Size", 0, 5, "M", 0).
+		AddRow(2, 1, "Size", 0, 6, "L", 1).
+		AddRow(3, 1, "Colour", 1, nil, nil, nil)
+
+	query := `SELECT o.id, o.product_id, o.name, o.position, v.id, v.value, v.position
+  						FROM product_option o LEFT JOIN product_option_value v ON v.option_id = o.id
+  						WHERE o.product_id = \? ORDER BY o.position, o.id, v.position, v.id`
+	mock.ExpectQuery(query).WithArgs(int64(1)).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductOptionRepo(db)
+	list, err := a.FetchByProduct(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ProductOption{
+		{ID: 2, ProductID: 1, Name: "Size", Position: 0, Values: []domain.OptionValue{
+			{ID: 5, OptionID: 2, Value: "M", Position: 0},
+			{ID: 6, OptionID: 2, Value: "L", Position: 1},
+		}},
+		{ID: 3, ProductID: 1, Name: "Colour", Position: 1, Values: []domain.OptionValue{}},
+	}, list)
+}
+
+func TestStoreOption(t *testing.T) {
+	db, mock := NewMock()
+
+	prep := mock.ExpectPrepare("INSERT  product_option SET product_id=\\? , name=\\? , position=\\?")
+	prep.ExpectExec().WithArgs(int64(1), "Size", 0).WillReturnResult(sqlmock.NewResult(2, 1))
+	prep = mock.ExpectPrepare("INSERT  product_option_value SET option_id=\\? , value=\\? , position=\\?")
+	prep.ExpectExec().WithArgs(int64(2), "M", 0).WillReturnResult(sqlmock.NewResult(5, 1))
+	prep.ExpectExec().WithArgs(int64(2), "L", 1).WillReturnResult(sqlmock.NewResult(6, 1))
+
+	a := productMysqlRepo.NewMysqlProductOptionRepo(db)
+	o := domain.ProductOption{ProductID: 1, Name: "Size", Values: []domain.OptionValue{{Value: "M"}, {Value: "L", Position: 1}}}
+	err := a.Store(context.TODO(), &o)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), o.ID)
+	assert.Equal(t, domain.OptionValue{ID: 6, OptionID: 2, Value: "L", Position: 1}, o.Values[1])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOption(t *testing.T) {
+	db, mock := NewMock()
+
+	prep := mock.ExpectPrepare("DELETE FROM product_option_value WHERE option_id = \\?")
+	prep.ExpectExec().WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 2))
+	prep = mock.ExpectPrepare("DELETE FROM product_option WHERE id = \\?")
+	prep.ExpectExec().WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := productMysqlRepo.NewMysqlProductOptionRepo(db)
+	err := a.Delete(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOptionsByProduct(t *testing.T) {
+	db, mock := NewMock()
+
+	prep := mock.ExpectPrepare("DELETE FROM product_option_value WHERE option_id IN \\(SELECT id FROM product_option WHERE product_id = \\?\\)")
+	prep.ExpectExec().WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 5))
+	prep = mock.ExpectPrepare("DELETE FROM product_option WHERE product_id = \\?")
+	prep.ExpectExec().WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	a := productMysqlRepo.NewMysqlProductOptionRepo(db)
+	err := a.DeleteByProduct(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,546 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+// productKeyset holds the columns of the fields of domain.ProductListSchema
+var productKeyset = pagination.NewKeyset(map[string]string{
+	"name":        "name",
+	"brand":       "brand",
+	"category_id": "category_id",
+	"price":       "price",
+	"rating":      "rating",
+	"updated_at":  "updated_at",
+	"created_at":  "created_at",
+}, domain.ProductListSchema.DefaultSort)
+
+// productHits are the products matching a search, by their own text or by
+// the name of their category or of a category above it. It takes the search
+// four times as args, see hitsArgs. MATCH uses the FULLTEXT indexes of
+// migrations 020 and 025.
+const productHits = `(SELECT p.id, p.user_id, p.image, p.name, p.brand, p.category_id, p.description, p.rating, p.num_reviews, p.price, p.currency, p.tax_category, p.count_in_stock, p.weight_grams, p.length_mm, p.width_mm, p.height_mm, p.updated_at, p.created_at,
+  							p.count_in_stock > 0 AS in_stock,
+  							ROUND((MATCH(p.name, p.brand, p.description) AGAINST (?) + COALESCE(c.relevance, 0)) * 1000000) AS relevance
+  						FROM product p LEFT JOIN (SELECT sub.id, MAX(MATCH(cat.name) AGAINST (?)) AS relevance
+  							FROM category cat JOIN category sub ON sub.path LIKE CONCAT(cat.path, '%')
+  							WHERE MATCH(cat.name) AGAINST (?) GROUP BY sub.id) AS c ON c.id = p.category_id
+  						WHERE MATCH(p.name, p.brand, p.description) AGAINST (?) OR c.id IS NOT NULL) AS hit`
+
+// hitsArgs returns the args of productHits
+func hitsArgs(query string) []interface{} {
+	return []interface{}{query, query, query, query}
+}
+
+// maxFacetValues is the number of brands or categories counted in a facet
+const maxFacetValues = 20
+
+// maxTerms is the number of terms the spelling suggestions are made from
+const maxTerms = 20000
+
+// productSearchKeyset holds the columns of the fields of domain.ProductSearchSchema
+var productSearchKeyset = pagination.NewKeyset(map[string]string{
+	"relevance":   "relevance",
+	"name":        "name",
+	"brand":       "brand",
+	"category_id": "category_id",
+	"price":       "price",
+	"rating":      "rating",
+	"in_stock":    "in_stock",
+	"created_at":  "created_at",
+}, domain.ProductSearchSchema.DefaultSort)
+
+type mysqlProductRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlProductRepo will create an object that represent the domain.ProductRepository interface
+func NewMysqlProductRepo(DB *sql.DB) domain.ProductRepository {
+	return &mysqlProductRepo{DB: DB}
+}
+
+func (m *mysqlProductRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Product, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Product, 0)
+	for rows.Next() {
+		t := domain.Product{}
+		err = scanProduct(rows, &t)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// scanProduct reads the product columns of the row into t, then the columns
+// selected after them into extra
+func scanProduct(rows *sql.Rows, t *domain.Product, extra ...interface{}) error {
+	return rows.Scan(append([]interface{}{
+		&t.ID,
+		&t.UserID,
+		&t.Image,
+		&t.Name,
+		&t.Brand,
+		&t.CategoryID,
+		&t.Description,
+		&t.Rating,
+		&t.NumReviews,
+		&t.Price,
+		&t.Price.Currency,
+		&t.TaxCategory,
+		&t.CountInStock,
+		&t.WeightGrams,
+		&t.LengthMM,
+		&t.WidthMM,
+		&t.HeightMM,
+		&t.UpdatedAt,
+		&t.CreatedAt,
+	}, extra...)...)
+}
+
+func (m *mysqlProductRepo) fetchPage(ctx context.Context, filter string, page domain.PageRequest, args ...interface{}) ([]domain.Product, domain.PageInfo, error) {
+	query, pageArgs, err := productKeyset.Query(`SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at
+  						FROM product`, filter, page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	res, err := m.fetch(ctx, query, append(args, pageArgs...)...)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	from, to, info := productKeyset.Window(page, len(res), func(i int, field string) (interface{}, int64) {
+		return productSortKey(res[i], field), res[i].ID
+	})
+	return res[from:to], info, nil
+}
+
+func (m *mysqlProductRepo) Fetch(ctx context.Context, page domain.PageRequest) ([]domain.Product, domain.PageInfo, error) {
+	return m.fetchPage(ctx, "", page)
+}
+
+// FetchInCategory reads the categories of the subtree by their path, see
+// domain.Category
+func (m *mysqlProductRepo) FetchInCategory(ctx context.Context, root domain.Category, page domain.PageRequest) ([]domain.Product, domain.PageInfo, error) {
+	return m.fetchPage(ctx, "category_id IN (SELECT id FROM category WHERE path LIKE ?)", page, root.Path+"%")
+}
+
+// productSortKey is the value of the sortable field of p
+func productSortKey(p domain.Product, field string) interface{} {
+	switch field {
+	case "name":
+		return p.Name
+	case "price":
+		return p.Price.Amount
+	case "rating":
+		return int64(p.Rating)
+	case "updated_at":
+		return p.UpdatedAt
+	}
+	return p.CreatedAt
+}
+
+// Search ranks the products on MATCH ... AGAINST, the relevance is scaled to
+// an integer so that cursors compare it exactly
+func (m *mysqlProductRepo) Search(ctx context.Context, query string, page domain.PageRequest) (res []domain.Product, info domain.PageInfo, err error) {
+	stmt, args, err := productSearchKeyset.Query(`SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at, relevance
+  						FROM `+productHits, "", page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, stmt, append(hitsArgs(query), args...)...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, domain.PageInfo{}, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res = make([]domain.Product, 0)
+	relevance := make([]int64, 0)
+	for rows.Next() {
+		t := domain.Product{}
+		var r int64
+		if err = scanProduct(rows, &t, &r); err != nil {
+			logrus.Error(err)
+			return nil, domain.PageInfo{}, err
+		}
+		res = append(res, t)
+		relevance = append(relevance, r)
+	}
+
+	from, to, info := productSearchKeyset.Window(page, len(res), func(i int, field string) (interface{}, int64) {
+		if field == "relevance" {
+			return relevance[i], res[i].ID
+		}
+		return productSortKey(res[i], field), res[i].ID
+	})
+	return res[from:to], info, nil
+}
+
+// Facets counts the brands and categories of the hits, the most common
+// first, and their prices by bucket
+func (m *mysqlProductRepo) Facets(ctx context.Context, query string, filters []domain.Filter, priceBounds []int64) (res domain.ProductFacets, err error) {
+	res.Brands, err = m.facetCounts(ctx, "brand", query, filtersWithout(filters, "brand"))
+	if err != nil {
+		return domain.ProductFacets{}, err
+	}
+	res.Categories, err = m.categoryCounts(ctx, query, filtersWithout(filters, "category_id"))
+	if err != nil {
+		return domain.ProductFacets{}, err
+	}
+	res.Prices, err = m.priceBuckets(ctx, query, filtersWithout(filters, "price"), priceBounds)
+	if err != nil {
+		return domain.ProductFacets{}, err
+	}
+	return
+}
+
+// hitsWhere returns the hits matching query and filters and their args
+func hitsWhere(query string, filters []domain.Filter) (string, []interface{}, error) {
+	conds, args, err := productSearchKeyset.Conditions(filters)
+	if err != nil {
+		return "", nil, err
+	}
+	stmt := productHits
+	if len(conds) > 0 {
+		stmt += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return stmt, append(hitsArgs(query), args...), nil
+}
+
+func (m *mysqlProductRepo) facetCounts(ctx context.Context, column string, query string, filters []domain.Filter) ([]domain.FacetCount, error) {
+	hits, args, err := hitsWhere(query, filters)
+	if err != nil {
+		return nil, err
+	}
+	stmt := fmt.Sprintf(`SELECT %[1]s, COUNT(*) FROM %[2]s GROUP BY %[1]s ORDER BY COUNT(*) DESC, %[1]s LIMIT ?`, column, hits)
+
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, stmt, append(args, maxFacetValues)...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res := make([]domain.FacetCount, 0)
+	for rows.Next() {
+		t := domain.FacetCount{}
+		if err = rows.Scan(&t.Value, &t.Count); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// categoryCounts is facetCounts of the categories, with their names
+func (m *mysqlProductRepo) categoryCounts(ctx context.Context, query string, filters []domain.Filter) ([]domain.FacetCount, error) {
+	hits, args, err := hitsWhere(query, filters)
+	if err != nil {
+		return nil, err
+	}
+	stmt := fmt.Sprintf(`SELECT category.id, category.name, COUNT(*) FROM (SELECT category_id FROM %s) AS f
+  						JOIN category ON category.id = f.category_id
+  						GROUP BY category.id, category.name ORDER BY COUNT(*) DESC, category.name LIMIT ?`, hits)
+
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, stmt, append(args, maxFacetValues)...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res := make([]domain.FacetCount, 0)
+	for rows.Next() {
+		t := domain.FacetCount{}
+		if err = rows.Scan(&t.ID, &t.Value, &t.Count); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// priceBuckets counts the hits in the buckets bounded by bounds, which are
+// ascending. Empty buckets are left out.
+func (m *mysqlProductRepo) priceBuckets(ctx context.Context, query string, filters []domain.Filter, bounds []int64) ([]domain.PriceBucket, error) {
+	res := make([]domain.PriceBucket, 0)
+	if len(bounds) == 0 {
+		return res, nil
+	}
+	hits, args, err := hitsWhere(query, filters)
+	if err != nil {
+		return nil, err
+	}
+	bucket := "CASE" + strings.Repeat(" WHEN price < ? THEN ?", len(bounds)) + " ELSE ? END"
+	var bucketArgs []interface{}
+	for i, b := range bounds {
+		bucketArgs = append(bucketArgs, b, i)
+	}
+	bucketArgs = append(bucketArgs, len(bounds))
+	stmt := fmt.Sprintf(`SELECT %s AS bucket, COUNT(*) FROM %s GROUP BY bucket ORDER BY bucket`, bucket, hits)
+
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, stmt, append(bucketArgs, args...)...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	for rows.Next() {
+		var i int
+		var count int64
+		if err = rows.Scan(&i, &count); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t := domain.PriceBucket{Count: count}
+		if i > 0 {
+			t.Min = bounds[i-1]
+		}
+		if i < len(bounds) {
+			max := bounds[i]
+			t.Max = &max
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func filtersWithout(filters []domain.Filter, field string) []domain.Filter {
+	res := make([]domain.Filter, 0, len(filters))
+	for _, f := range filters {
+		if f.Field != field {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Terms reads at most maxTerms terms, the newest products first
+func (m *mysqlProductRepo) Terms(ctx context.Context) ([]string, error) {
+	query := `SELECT name FROM category
+  						UNION SELECT brand FROM (SELECT brand FROM product ORDER BY created_at DESC LIMIT ?) AS b
+  						UNION SELECT name FROM (SELECT name FROM product ORDER BY created_at DESC LIMIT ?) AS n
+  						LIMIT ?`
+
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, maxTerms, maxTerms, maxTerms)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	res := make([]string, 0)
+	for rows.Next() {
+		var t string
+		if err = rows.Scan(&t); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func (m *mysqlProductRepo) GetByID(ctx context.Context, id int64) (res domain.Product, err error) {
+	query := `SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at
+  						FROM product WHERE id = ?`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+// GetByIDForUpdate is GetByID holding a write lock on the row, it only makes
+// sense inside a transaction where the lock lasts until commit or rollback
+func (m *mysqlProductRepo) GetByIDForUpdate(ctx context.Context, id int64) (res domain.Product, err error) {
+	query := `SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at
+  						FROM product WHERE id = ? FOR UPDATE`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+// DecrementStock never lets count_in_stock go below zero, when there is not
+// enough stock no row is touched and ErrInsufficientStock is returned
+func (m *mysqlProductRepo) DecrementStock(ctx context.Context, id int64, qty int) (err error) {
+	query := `UPDATE  product SET count_in_stock = count_in_stock - ? WHERE id = ? AND count_in_stock >= ?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, qty, id, qty)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrInsufficientStock
+		return
+	}
+	return
+}
+
+func (m *mysqlProductRepo) IncrementStock(ctx context.Context, id int64, qty int) (err error) {
+	query := `UPDATE  product SET count_in_stock = count_in_stock + ? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, qty, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrNotFound
+		return
+	}
+	return
+}
+
+func (m *mysqlProductRepo) Store(ctx context.Context, p *domain.Product) (err error) {
+	query := `INSERT  product SET user_id=? , image=? , name=? , brand=? , category_id=? , description=? , rating=? , num_reviews=? , price=? , currency=? , tax_category=? , count_in_stock=? , weight_grams=? , length_mm=? , width_mm=? , height_mm=? , updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, p.UserID, p.Image, p.Name, p.Brand, p.CategoryID, p.Description, p.Rating, p.NumReviews, p.Price, p.Price.Currency, p.TaxCategory, p.CountInStock, p.WeightGrams, p.LengthMM, p.WidthMM, p.HeightMM, p.UpdatedAt, p.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	p.ID = lastID
+	return
+}
+
+// Update changes the catalog fields of a product, rating and num_reviews are
+// maintained by the reviews
+func (m *mysqlProductRepo) Update(ctx context.Context, p *domain.Product) (err error) {
+	query := `UPDATE  product SET image=? , name=? , brand=? , category_id=? , description=? , price=? , currency=? , tax_category=? , count_in_stock=? , weight_grams=? , length_mm=? , width_mm=? , height_mm=? , updated_at=? WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, p.Image, p.Name, p.Brand, p.CategoryID, p.Description, p.Price, p.Price.Currency, p.TaxCategory, p.CountInStock, p.WeightGrams, p.LengthMM, p.WidthMM, p.HeightMM, p.UpdatedAt, p.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
+
+func (m *mysqlProductRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM product WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", rowsAfected)
+		return
+	}
+	return
+}
@@ -0,0 +1,358 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	productMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/product/repository/mysql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+var product = &domain.Product{
+	ID:           1,
+	UserID:       2,
+	Image:        "/images/shirt.jpg",
+	Name:         "Shirt",
+	Brand:        "Acme",
+	CategoryID:   4,
+	Description:  "A plain shirt",
+	Rating:       4,
+	NumReviews:   10,
+	Price:        domain.NewMoney(15000000, "IDR"),
+	CountInStock: 5,
+	WeightGrams:  250,
+	LengthMM:     300,
+	WidthMM:      250,
+	HeightMM:     20,
+	UpdatedAt:    now,
+	CreatedAt:    now,
+}
+
+var productColumns = []string{"id", "user_id", "image", "name", "brand", "category_id", "description", "rating", "num_reviews", "price", "currency", "tax_category", "count_in_stock", "weight_grams", "length_mm", "width_mm", "height_mm", "updated_at", "created_at"}
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func productValues(p domain.Product) []driver.Value {
+	return []driver.Value{p.ID, p.UserID, p.Image, p.Name, p.Brand, p.CategoryID, p.Description, p.Rating, p.NumReviews, p.Price.Amount, p.Price.Currency, p.TaxCategory, p.CountInStock, p.WeightGrams, p.LengthMM, p.WidthMM, p.HeightMM, p.UpdatedAt, p.CreatedAt}
+}
+
+func addProductRow(rows *sqlmock.Rows, p domain.Product) *sqlmock.Rows {
+	return rows.AddRow(productValues(p)...)
+}
+
+func TestFetch(t *testing.T) {
+	db, mock := NewMock()
+
+	second := *product
+	second.ID = 2
+	second.CreatedAt = now.Add(time.Second)
+	rows := sqlmock.NewRows(productColumns)
+	addProductRow(rows, *product)
+	addProductRow(rows, second)
+
+	query := `SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at FROM product ORDER BY created_at, id LIMIT \?`
+	mock.ExpectQuery(query).WithArgs(int64(2)).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	list, info, err := a.Fetch(context.TODO(), domain.PageRequest{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Cursor{Sort: domain.Sort{Field: "created_at"}, Key: product.CreatedAt, ID: product.ID}, info.Next)
+	assert.Nil(t, info.Prev)
+	assert.Len(t, list, 1)
+	assert.Equal(t, product.Name, list[0].Name)
+	assert.Equal(t, product.UserID, list[0].UserID)
+	assert.Equal(t, product.Price, list[0].Price)
+}
+
+func TestFetchBackward(t *testing.T) {
+	db, mock := NewMock()
+
+	second := *product
+	second.ID = 2
+	rows := sqlmock.NewRows(productColumns)
+	addProductRow(rows, *product)
+	addProductRow(rows, second)
+
+	cursor := &domain.Cursor{Sort: domain.Sort{Field: "created_at"}, Key: now, ID: 3, Backward: true}
+	query := `SELECT \* FROM \(SELECT id, user_id, .* FROM product WHERE \(created_at < \? OR \(created_at = \? AND id < \?\)\) ORDER BY created_at DESC, id DESC LIMIT \?\) AS page ORDER BY created_at, id`
+	mock.ExpectQuery(query).WithArgs(now, now, int64(3), int64(11)).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	list, info, err := a.Fetch(context.TODO(), domain.PageRequest{Cursor: cursor, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Nil(t, info.Prev)
+	assert.Equal(t, &domain.Cursor{Sort: cursor.Sort, Key: now, ID: 2}, info.Next)
+}
+
+func TestFetchFiltered(t *testing.T) {
+	db, mock := NewMock()
+	rows := addProductRow(sqlmock.NewRows(productColumns), *product)
+
+	byPrice := domain.Sort{Field: "price"}
+	cursor := &domain.Cursor{Sort: byPrice, Key: int64(1000), ID: 3}
+	query := `SELECT id, user_id, .* FROM product WHERE brand = \? AND price <= \? AND \(price > \? OR \(price = \? AND id > \?\)\) ORDER BY price, id LIMIT \?`
+	mock.ExpectQuery(query).WithArgs("Apple", int64(500000), int64(1000), int64(1000), int64(3), int64(11)).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	list, info, err := a.Fetch(context.TODO(), domain.PageRequest{
+		Cursor: cursor,
+		Limit:  10,
+		Sort:   byPrice,
+		Filters: []domain.Filter{
+			{Field: "brand", Op: domain.FilterEq, Value: "Apple"},
+			{Field: "price", Op: domain.FilterLte, Value: int64(500000)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Nil(t, info.Next)
+	assert.Equal(t, &domain.Cursor{Sort: byPrice, Key: product.Price.Amount, ID: product.ID, Backward: true}, info.Prev)
+}
+
+func TestFetchInCategory(t *testing.T) {
+	db, mock := NewMock()
+	rows := addProductRow(sqlmock.NewRows(productColumns), *product)
+
+	query := `SELECT id, user_id, .* FROM product WHERE category_id IN \(SELECT id FROM category WHERE path LIKE \?\) AND price <= \? ORDER BY created_at, id LIMIT \?`
+	mock.ExpectQuery(query).WithArgs("/1/4/%", int64(500000), int64(11)).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	list, info, err := a.FetchInCategory(context.TODO(), domain.Category{ID: 4, Path: "/1/4/"}, domain.PageRequest{
+		Limit:   10,
+		Filters: []domain.Filter{{Field: "price", Op: domain.FilterLte, Value: int64(500000)}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Nil(t, info.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// hitsQuery matches the products of a search, by their own text or by the
+// category they are in
+const hitsQuery = `\(SELECT p.id, .*, p.count_in_stock > 0 AS in_stock, ` +
+	`ROUND\(\(MATCH\(p.name, p.brand, p.description\) AGAINST \(\?\) \+ COALESCE\(c.relevance, 0\)\) \* 1000000\) AS relevance ` +
+	`FROM product p LEFT JOIN \(SELECT sub.id, MAX\(MATCH\(cat.name\) AGAINST \(\?\)\) AS relevance ` +
+	`FROM category cat JOIN category sub ON sub.path LIKE CONCAT\(cat.path, '%'\) ` +
+	`WHERE MATCH\(cat.name\) AGAINST \(\?\) GROUP BY sub.id\) AS c ON c.id = p.category_id ` +
+	`WHERE MATCH\(p.name, p.brand, p.description\) AGAINST \(\?\) OR c.id IS NOT NULL\) AS hit`
+
+func TestSearch(t *testing.T) {
+	db, mock := NewMock()
+	second := *product
+	second.ID = 2
+	rows := sqlmock.NewRows(append(productColumns, "relevance")).
+		AddRow(append(productValues(*product), int64(2500000))...).
+		AddRow(append(productValues(second), int64(1250000))...)
+
+	query := `SELECT id, user_id, .*, relevance FROM ` + hitsQuery + ` WHERE in_stock = \? AND price >= \? ORDER BY relevance DESC, id DESC LIMIT \?`
+	mock.ExpectQuery(query).WithArgs("shirt", "shirt", "shirt", "shirt", true, int64(10000), int64(2)).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	list, info, err := a.Search(context.TODO(), "shirt", domain.PageRequest{
+		Limit: 1,
+		Filters: []domain.Filter{
+			{Field: "in_stock", Op: domain.FilterEq, Value: true},
+			{Field: "price", Op: domain.FilterGte, Value: int64(10000)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Product{*product}, list)
+	assert.Equal(t, &domain.Cursor{Sort: domain.ProductSearchSchema.DefaultSort, Key: int64(2500000), ID: product.ID}, info.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchByCategory(t *testing.T) {
+	db, mock := NewMock()
+	// the product text does not mention shoes, its category does
+	boot := *product
+	boot.Name = "Hiking boot"
+	boot.CategoryID = 4
+	rows := sqlmock.NewRows(append(productColumns, "relevance")).
+		AddRow(append(productValues(boot), int64(800000))...)
+
+	mock.ExpectQuery(`SELECT id, user_id, .*, relevance FROM `+hitsQuery+` ORDER BY relevance DESC, id DESC LIMIT \?`).
+		WithArgs("shoes", "shoes", "shoes", "shoes", int64(11)).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	list, _, err := a.Search(context.TODO(), "shoes", domain.PageRequest{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Product{boot}, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFacets(t *testing.T) {
+	db, mock := NewMock()
+	hits := hitsQuery
+	filters := []domain.Filter{
+		{Field: "brand", Op: domain.FilterIn, Value: []interface{}{"Acme", "Zeta"}},
+		{Field: "price", Op: domain.FilterLte, Value: int64(20000000)},
+	}
+
+	// each facet leaves its own filter out
+	mock.ExpectQuery(`SELECT brand, COUNT\(\*\) FROM `+hits+` WHERE price <= \? GROUP BY brand ORDER BY COUNT\(\*\) DESC, brand LIMIT \?`).
+		WithArgs("shirt", "shirt", "shirt", "shirt", int64(20000000), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"brand", "count"}).AddRow("Acme", 3).AddRow("Other", 1))
+	mock.ExpectQuery(`SELECT category.id, category.name, COUNT\(\*\) FROM \(SELECT category_id FROM `+hits+` WHERE brand IN \(\?, \?\) AND price <= \?\) AS f `+
+		`JOIN category ON category.id = f.category_id GROUP BY category.id, category.name`).
+		WithArgs("shirt", "shirt", "shirt", "shirt", "Acme", "Zeta", int64(20000000), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).AddRow(4, "Clothing", 3))
+	mock.ExpectQuery(`SELECT CASE WHEN price < \? THEN \? WHEN price < \? THEN \? ELSE \? END AS bucket, COUNT\(\*\) FROM `+hits+` WHERE brand IN \(\?, \?\) GROUP BY bucket ORDER BY bucket`).
+		WithArgs(int64(10000000), 0, int64(50000000), 1, 2, "shirt", "shirt", "shirt", "shirt", "Acme", "Zeta").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(1, 2).AddRow(2, 1))
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	res, err := a.Facets(context.TODO(), "shirt", filters, []int64{10000000, 50000000})
+	assert.NoError(t, err)
+	max := int64(50000000)
+	assert.Equal(t, domain.ProductFacets{
+		Brands:     []domain.FacetCount{{Value: "Acme", Count: 3}, {Value: "Other", Count: 1}},
+		Categories: []domain.FacetCount{{ID: 4, Value: "Clothing", Count: 3}},
+		Prices: []domain.PriceBucket{
+			{Min: 10000000, Max: &max, Count: 2},
+			{Min: 50000000, Count: 1},
+		},
+	}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTerms(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"name"}).AddRow("Shirt").AddRow("Acme").AddRow("Clothing")
+	mock.ExpectQuery(`SELECT name FROM category UNION SELECT brand FROM \(SELECT brand FROM product ORDER BY created_at DESC LIMIT \?\) AS b `+
+		`UNION SELECT name FROM \(SELECT name FROM product ORDER BY created_at DESC LIMIT \?\) AS n LIMIT \?`).
+		WithArgs(20000, 20000, 20000).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	res, err := a.Terms(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Shirt", "Acme", "Clothing"}, res)
+}
+
+func TestGetByID(t *testing.T) {
+	db, mock := NewMock()
+	rows := addProductRow(sqlmock.NewRows(productColumns), *product)
+
+	query := `SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at FROM product WHERE id = \?`
+	mock.ExpectQuery(query).WithArgs(product.ID).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	res, err := a.GetByID(context.TODO(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, *product, res)
+}
+
+func TestGetByIDNotFound(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at FROM product WHERE id = \?`
+	mock.ExpectQuery(query).WithArgs(int64(9)).WillReturnRows(sqlmock.NewRows(productColumns))
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	_, err := a.GetByID(context.TODO(), int64(9))
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestGetByIDForUpdate(t *testing.T) {
+	db, mock := NewMock()
+	rows := addProductRow(sqlmock.NewRows(productColumns), *product)
+
+	query := `SELECT id, user_id, image, name, brand, category_id, description, rating, num_reviews, price, currency, tax_category, count_in_stock, weight_grams, length_mm, width_mm, height_mm, updated_at, created_at FROM product WHERE id = \? FOR UPDATE`
+	mock.ExpectQuery(query).WithArgs(product.ID).WillReturnRows(rows)
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	res, err := a.GetByIDForUpdate(context.TODO(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, *product, res)
+}
+
+func TestDecrementStock(t *testing.T) {
+	query := "UPDATE  product SET count_in_stock = count_in_stock - \\? WHERE id = \\? AND count_in_stock >= \\?"
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(2, product.ID, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		a := productMysqlRepo.NewMysqlProductRepo(db)
+		err := a.DecrementStock(context.TODO(), product.ID, 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("insufficient-stock", func(t *testing.T) {
+		db, mock := NewMock()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(99, product.ID, 99).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		a := productMysqlRepo.NewMysqlProductRepo(db)
+		err := a.DecrementStock(context.TODO(), product.ID, 99)
+		assert.Equal(t, domain.ErrInsufficientStock, err)
+	})
+}
+
+func TestIncrementStock(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  product SET count_in_stock = count_in_stock \\+ \\? WHERE id = \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(2, product.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	err := a.IncrementStock(context.TODO(), product.ID, 2)
+	assert.NoError(t, err)
+}
+
+func TestStore(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  product SET user_id=\\? , image=\\? , name=\\? , brand=\\? , category_id=\\? , description=\\? , rating=\\? , num_reviews=\\? , price=\\? , currency=\\? , tax_category=\\? , count_in_stock=\\? , weight_grams=\\? , length_mm=\\? , width_mm=\\? , height_mm=\\? , updated_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(product.UserID, product.Image, product.Name, product.Brand, product.CategoryID, product.Description, product.Rating, product.NumReviews, product.Price, product.Price.Currency, product.TaxCategory, product.CountInStock, product.WeightGrams, product.LengthMM, product.WidthMM, product.HeightMM, product.UpdatedAt, product.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	tmp := *product
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), tmp.ID)
+}
+
+func TestUpdate(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  product SET image=\\? , name=\\? , brand=\\? , category_id=\\? , description=\\? , price=\\? , currency=\\? , tax_category=\\? , count_in_stock=\\? , weight_grams=\\? , length_mm=\\? , width_mm=\\? , height_mm=\\? , updated_at=\\? WHERE id=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(product.Image, product.Name, product.Brand, product.CategoryID, product.Description, product.Price, product.Price.Currency, product.TaxCategory, product.CountInStock, product.WeightGrams, product.LengthMM, product.WidthMM, product.HeightMM, product.UpdatedAt, product.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	err := a.Update(context.TODO(), product)
+	assert.NoError(t, err)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "DELETE FROM product WHERE id = \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(product.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := productMysqlRepo.NewMysqlProductRepo(db)
+	err := a.Delete(context.TODO(), product.ID)
+	assert.NoError(t, err)
+}
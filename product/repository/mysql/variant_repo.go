@@ -0,0 +1,326 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+const selectVariant = `SELECT id, product_id, sku, price, currency, count_in_stock, image, barcode, updated_at, created_at FROM product_variant`
+
+type mysqlVariantRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlVariantRepo will create an object that represent the domain.VariantRepository interface
+func NewMysqlVariantRepo(DB *sql.DB) domain.VariantRepository {
+	return &mysqlVariantRepo{DB: DB}
+}
+
+func (m *mysqlVariantRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Variant, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Variant, 0)
+	for rows.Next() {
+		t := domain.Variant{}
+		err = rows.Scan(
+			&t.ID,
+			&t.ProductID,
+			&t.SKU,
+			&t.Price,
+			&t.Price.Currency,
+			&t.CountInStock,
+			&t.Image,
+			&t.Barcode,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		t.Options = make([]domain.OptionValue, 0)
+		result = append(result, t)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if err = m.fetchOptions(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// fetchOptions fills the values the variants are made of, in the order of
+// the options of their product
+func (m *mysqlVariantRepo) fetchOptions(ctx context.Context, variants []domain.Variant) (err error) {
+	if len(variants) == 0 {
+		return nil
+	}
+	index := make(map[int64]int, len(variants))
+	args := make([]interface{}, len(variants))
+	for i, v := range variants {
+		index[v.ID] = i
+		args[i] = v.ID
+	}
+	query := fmt.Sprintf(`SELECT vv.variant_id, v.id, v.option_id, v.value, v.position
+  						FROM product_variant_value vv
+  						JOIN product_option_value v ON v.id = vv.option_value_id
+  						JOIN product_option o ON o.id = v.option_id
+  						WHERE vv.variant_id IN (%s) ORDER BY o.position, o.id`, strings.TrimSuffix(strings.Repeat("?, ", len(variants)), ", "))
+
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	for rows.Next() {
+		var variantID int64
+		t := domain.OptionValue{}
+		if err = rows.Scan(&variantID, &t.ID, &t.OptionID, &t.Value, &t.Position); err != nil {
+			logrus.Error(err)
+			return err
+		}
+		if i, ok := index[variantID]; ok {
+			variants[i].Options = append(variants[i].Options, t)
+		}
+	}
+	return nil
+}
+
+func (m *mysqlVariantRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Variant, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Variant{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlVariantRepo) FetchByProduct(ctx context.Context, productID int64) ([]domain.Variant, error) {
+	return m.fetch(ctx, selectVariant+` WHERE product_id = ? ORDER BY id`, productID)
+}
+
+func (m *mysqlVariantRepo) GetByID(ctx context.Context, id int64) (domain.Variant, error) {
+	return m.getOne(ctx, selectVariant+` WHERE id = ?`, id)
+}
+
+// GetByIDForUpdate is GetByID holding a write lock on the row, it only makes
+// sense inside a transaction where the lock lasts until commit or rollback
+func (m *mysqlVariantRepo) GetByIDForUpdate(ctx context.Context, id int64) (domain.Variant, error) {
+	return m.getOne(ctx, selectVariant+` WHERE id = ? FOR UPDATE`, id)
+}
+
+func (m *mysqlVariantRepo) GetBySKU(ctx context.Context, sku string) (domain.Variant, error) {
+	return m.getOne(ctx, selectVariant+` WHERE sku = ?`, sku)
+}
+
+// storeOptions links the variant to the values in v.Options
+func (m *mysqlVariantRepo) storeOptions(ctx context.Context, v *domain.Variant) (err error) {
+	query := `INSERT  product_variant_value SET variant_id=? , option_value_id=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	for _, o := range v.Options {
+		if _, err = stmt.ExecContext(ctx, v.ID, o.ID); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m *mysqlVariantRepo) deleteOptions(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM product_variant_value WHERE variant_id = ?"
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, id)
+	return
+}
+
+// Store writes the variant and its values in several statements, it only
+// makes sense inside a transaction
+func (m *mysqlVariantRepo) Store(ctx context.Context, v *domain.Variant) (err error) {
+	query := `INSERT  product_variant SET product_id=? , sku=? , price=? , currency=? , count_in_stock=? , image=? , barcode=? , updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, v.ProductID, v.SKU, v.Price, v.Price.Currency, v.CountInStock, v.Image, v.Barcode, v.UpdatedAt, v.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	v.ID = lastID
+	return m.storeOptions(ctx, v)
+}
+
+// Update replaces the values of the variant, it only makes sense inside a
+// transaction
+func (m *mysqlVariantRepo) Update(ctx context.Context, v *domain.Variant) (err error) {
+	query := `UPDATE  product_variant SET sku=? , price=? , currency=? , count_in_stock=? , image=? , barcode=? , updated_at=? WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, v.SKU, v.Price, v.Price.Currency, v.CountInStock, v.Image, v.Barcode, v.UpdatedAt, v.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	if err = m.deleteOptions(ctx, v.ID); err != nil {
+		return
+	}
+	return m.storeOptions(ctx, v)
+}
+
+func (m *mysqlVariantRepo) Delete(ctx context.Context, id int64) (err error) {
+	if err = m.deleteOptions(ctx, id); err != nil {
+		return
+	}
+	query := "DELETE FROM product_variant WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", rowsAfected)
+		return
+	}
+	return
+}
+
+// DeleteByProduct removes the values first, it only makes sense inside a
+// transaction
+func (m *mysqlVariantRepo) DeleteByProduct(ctx context.Context, productID int64) (err error) {
+	query := "DELETE FROM product_variant_value WHERE variant_id IN (SELECT id FROM product_variant WHERE product_id = ?)"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	if _, err = stmt.ExecContext(ctx, productID); err != nil {
+		return
+	}
+
+	query = "DELETE FROM product_variant WHERE product_id = ?"
+	stmt, err = transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, productID)
+	return
+}
+
+// DecrementStock never lets count_in_stock go below zero, when there is not
+// enough stock no row is touched and ErrInsufficientStock is returned
+func (m *mysqlVariantRepo) DecrementStock(ctx context.Context, id int64, qty int) (err error) {
+	query := `UPDATE  product_variant SET count_in_stock = count_in_stock - ? WHERE id = ? AND count_in_stock >= ?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, qty, id, qty)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrInsufficientStock
+		return
+	}
+	return
+}
+
+func (m *mysqlVariantRepo) IncrementStock(ctx context.Context, id int64, qty int) (err error) {
+	query := `UPDATE  product_variant SET count_in_stock = count_in_stock + ? WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, qty, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrNotFound
+		return
+	}
+	return
+}
+
+func (m *mysqlVariantRepo) SyncProductStock(ctx context.Context, productID int64) (err error) {
+	query := `UPDATE  product SET count_in_stock = (SELECT COALESCE(SUM(count_in_stock), 0) FROM product_variant WHERE product_id = ?) WHERE id = ?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	_, err = stmt.ExecContext(ctx, productID, productID)
+	return
+}
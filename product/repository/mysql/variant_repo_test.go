@@ -0,0 +1,144 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	productMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/product/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var variant = domain.Variant{
+	ID:           7,
+	ProductID:    1,
+	SKU:          "SHIRT-M-RED",
+	Price:        domain.NewMoney(16000000, "IDR"),
+	CountInStock: 3,
+	Image:        "/images/shirt-red.jpg",
+	Barcode:      "8991234567890",
+	Options: []domain.OptionValue{
+		{ID: 5, OptionID: 2, Value: "M"},
+		{ID: 8, OptionID: 3, Value: "Red"},
+	},
+	UpdatedAt: now,
+	CreatedAt: now,
+}
+
+var variantColumns = []string{"id", "product_id", "sku", "price", "currency", "count_in_stock", "image", "barcode", "updated_at", "created_at"}
+
+const selectVariantQuery = `SELECT id, product_id, sku, price, currency, count_in_stock, image, barcode, updated_at, created_at FROM product_variant`
+
+func expectVariantOptions(mock sqlmock.Sqlmock, v domain.Variant) {
+	rows := sqlmock.NewRows([]string{"variant_id", "id", "option_id", "value", "position"})
+	for _, o := range v.Options {
+		rows.AddRow(v.ID, o.ID, o.OptionID, o.Value, o.Position)
+	}
+	mock.ExpectQuery(`SELECT vv.variant_id, v.id, v.option_id, v.value, v.position
+  						FROM product_variant_value vv
+  						JOIN product_option_value v ON v.id = vv.option_value_id
+  						JOIN product_option o ON o.id = v.option_id
+  						WHERE vv.variant_id IN \(\?\) ORDER BY o.position, o.id`).WithArgs(v.ID).WillReturnRows(rows)
+}
+
+func TestFetchVariants(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows(variantColumns).
+		AddRow(variant.ID, variant.ProductID, variant.SKU, variant.Price.Amount, variant.Price.Currency, variant.CountInStock, variant.Image, variant.Barcode, variant.UpdatedAt, variant.CreatedAt)
+	mock.ExpectQuery(selectVariantQuery + ` WHERE product_id = \? ORDER BY id`).WithArgs(int64(1)).WillReturnRows(rows)
+	expectVariantOptions(mock, variant)
+
+	a := productMysqlRepo.NewMysqlVariantRepo(db)
+	list, err := a.FetchByProduct(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Variant{variant}, list)
+	assert.Equal(t, "M / Red", list[0].Title())
+}
+
+func TestGetVariantByIDNotFound(t *testing.T) {
+	db, mock := NewMock()
+	mock.ExpectQuery(selectVariantQuery + ` WHERE id = \?`).WithArgs(int64(9)).WillReturnRows(sqlmock.NewRows(variantColumns))
+
+	a := productMysqlRepo.NewMysqlVariantRepo(db)
+	_, err := a.GetByID(context.TODO(), 9)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestStoreVariant(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  product_variant SET product_id=\\? , sku=\\? , price=\\? , currency=\\? , count_in_stock=\\? , image=\\? , barcode=\\? , updated_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(variant.ProductID, variant.SKU, variant.Price, variant.Price.Currency, variant.CountInStock, variant.Image, variant.Barcode, variant.UpdatedAt, variant.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	prep = mock.ExpectPrepare("INSERT  product_variant_value SET variant_id=\\? , option_value_id=\\?")
+	prep.ExpectExec().WithArgs(int64(7), int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WithArgs(int64(7), int64(8)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := productMysqlRepo.NewMysqlVariantRepo(db)
+	tmp := variant
+	tmp.ID = 0
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), tmp.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateVariant(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  product_variant SET sku=\\? , price=\\? , currency=\\? , count_in_stock=\\? , image=\\? , barcode=\\? , updated_at=\\? WHERE id=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(variant.SKU, variant.Price, variant.Price.Currency, variant.CountInStock, variant.Image, variant.Barcode, variant.UpdatedAt, variant.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prep = mock.ExpectPrepare("DELETE FROM product_variant_value WHERE variant_id = \\?")
+	prep.ExpectExec().WithArgs(variant.ID).WillReturnResult(sqlmock.NewResult(0, 2))
+	prep = mock.ExpectPrepare("INSERT  product_variant_value SET variant_id=\\? , option_value_id=\\?")
+	prep.ExpectExec().WithArgs(variant.ID, int64(5)).WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WithArgs(variant.ID, int64(8)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := productMysqlRepo.NewMysqlVariantRepo(db)
+	tmp := variant
+	err := a.Update(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDecrementVariantStock(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  product_variant SET count_in_stock = count_in_stock - \\? WHERE id = \\? AND count_in_stock >= \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(5, variant.ID, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := productMysqlRepo.NewMysqlVariantRepo(db)
+	err := a.DecrementStock(context.TODO(), variant.ID, 5)
+	assert.Equal(t, domain.ErrInsufficientStock, err)
+}
+
+func TestDeleteVariantsByProduct(t *testing.T) {
+	db, mock := NewMock()
+
+	prep := mock.ExpectPrepare("DELETE FROM product_variant_value WHERE variant_id IN \\(SELECT id FROM product_variant WHERE product_id = \\?\\)")
+	prep.ExpectExec().WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 4))
+	prep = mock.ExpectPrepare("DELETE FROM product_variant WHERE product_id = \\?")
+	prep.ExpectExec().WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	a := productMysqlRepo.NewMysqlVariantRepo(db)
+	err := a.DeleteByProduct(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSyncProductStock(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  product SET count_in_stock = \\(SELECT COALESCE\\(SUM\\(count_in_stock\\), 0\\) FROM product_variant WHERE product_id = \\?\\) WHERE id = \\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(int64(1), int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := productMysqlRepo.NewMysqlVariantRepo(db)
+	err := a.SyncProductStock(context.TODO(), 1)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type productUsecase struct {
+	productRepo    domain.ProductRepository
+	categoryRepo   domain.CategoryRepository
+	optionRepo     domain.ProductOptionRepository
+	variantRepo    domain.VariantRepository
+	transactor     domain.Transactor
+	priceBounds    []int64
+	vocabulary     *vocabularyCache
+	contextTimeout time.Duration
+}
+
+// NewProductUsecase will create an object that represent the domain.ProductUsecase interface.
+// priceBounds split the prices of the search facets into buckets, in minor units.
+func NewProductUsecase(p domain.ProductRepository, c domain.CategoryRepository, o domain.ProductOptionRepository, v domain.VariantRepository, tx domain.Transactor, priceBounds []int64, timeout time.Duration) domain.ProductUsecase {
+	bounds := append([]int64(nil), priceBounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+	return &productUsecase{
+		productRepo:    p,
+		categoryRepo:   c,
+		optionRepo:     o,
+		variantRepo:    v,
+		transactor:     tx,
+		priceBounds:    bounds,
+		vocabulary:     &vocabularyCache{},
+		contextTimeout: timeout,
+	}
+}
+
+func (m *productUsecase) Fetch(ctx context.Context, page domain.PageRequest) (res []domain.Product, info domain.PageInfo, err error) {
+	if page.Limit == 0 {
+		page.Limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, info, err = m.productRepo.Fetch(ctx, page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return
+}
+
+func (m *productUsecase) FetchInCategory(ctx context.Context, categoryID int64, page domain.PageRequest) (res []domain.Product, info domain.PageInfo, err error) {
+	if page.Limit == 0 {
+		page.Limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	category, err := m.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	res, info, err = m.productRepo.FetchInCategory(ctx, category, page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return
+}
+
+func (m *productUsecase) Search(ctx context.Context, query string, page domain.PageRequest) (res domain.ProductSearchResult, info domain.PageInfo, err error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return domain.ProductSearchResult{}, domain.PageInfo{}, domain.ErrBadParamInput
+	}
+	if page.Limit == 0 {
+		page.Limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res.Products, info, err = m.productRepo.Search(ctx, query, page)
+	if err != nil {
+		return domain.ProductSearchResult{}, domain.PageInfo{}, err
+	}
+	res.Facets, err = m.productRepo.Facets(ctx, query, page.Filters, m.priceBounds)
+	if err != nil {
+		return domain.ProductSearchResult{}, domain.PageInfo{}, err
+	}
+
+	res.Suggestions = []string{}
+	if len(res.Products) == 0 && page.Cursor == nil {
+		vocabulary, err := m.vocabulary.get(ctx, m.productRepo.Terms)
+		if err != nil {
+			return domain.ProductSearchResult{}, domain.PageInfo{}, err
+		}
+		res.Suggestions = suggest(query, vocabulary)
+	}
+	return
+}
+
+func (m *productUsecase) GetByID(ctx context.Context, id int64) (res domain.Product, err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err = m.productRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if res.Options, err = m.optionRepo.FetchByProduct(ctx, id); err != nil {
+		return domain.Product{}, err
+	}
+	if res.Variants, err = m.variantRepo.FetchByProduct(ctx, id); err != nil {
+		return domain.Product{}, err
+	}
+	return
+}
+
+// checkCategory refuses a product in a category that does not exist
+func (m *productUsecase) checkCategory(ctx context.Context, p *domain.Product) error {
+	_, err := m.categoryRepo.GetByID(ctx, p.CategoryID)
+	if err == domain.ErrNotFound {
+		return domain.ErrUnknownCategory
+	}
+	return err
+}
+
+func (m *productUsecase) Update(ctx context.Context, p *domain.Product) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if !p.Price.Currency.IsValid() {
+		return domain.ErrInvalidCurrency
+	}
+	existed, err := m.productRepo.GetByID(ctx, p.ID)
+	if err != nil {
+		return
+	}
+	if err = m.checkCategory(ctx, p); err != nil {
+		return
+	}
+	if p.TaxCategory == "" {
+		p.TaxCategory = domain.TaxCategoryStandard
+	}
+	// the stock of a product with variants is theirs and only follows them,
+	// and they stay priced in the currency of the product
+	variants, err := m.variantRepo.FetchByProduct(ctx, p.ID)
+	if err != nil {
+		return
+	}
+	if len(variants) > 0 {
+		if p.Price.Currency != existed.Price.Currency {
+			return domain.ErrVariantsExist
+		}
+		p.CountInStock = existed.CountInStock
+	}
+	p.UserID = existed.UserID
+	p.Rating = existed.Rating
+	p.NumReviews = existed.NumReviews
+	p.CreatedAt = existed.CreatedAt
+	p.UpdatedAt = time.Now()
+	return m.productRepo.Update(ctx, p)
+}
+
+func (m *productUsecase) Store(ctx context.Context, p *domain.Product) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if !p.Price.Currency.IsValid() {
+		return domain.ErrInvalidCurrency
+	}
+	if err = m.checkCategory(ctx, p); err != nil {
+		return
+	}
+	if p.TaxCategory == "" {
+		p.TaxCategory = domain.TaxCategoryStandard
+	}
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = p.CreatedAt
+	return m.productRepo.Store(ctx, p)
+}
+
+// Delete removes the product with its variants and options in one transaction
+func (m *productUsecase) Delete(ctx context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := m.productRepo.GetByIDForUpdate(ctx, id); err != nil {
+			return err
+		}
+		if err := m.variantRepo.DeleteByProduct(ctx, id); err != nil {
+			return err
+		}
+		if err := m.optionRepo.DeleteByProduct(ctx, id); err != nil {
+			return err
+		}
+		return m.productRepo.Delete(ctx, id)
+	})
+}
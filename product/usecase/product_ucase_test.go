@@ -0,0 +1,301 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	ucase "github.com/alfathaulia/ca_ecommerce_api/product/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFetch(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	mockProduct := domain.Product{ID: 1, Name: "Shirt", Price: domain.NewMoney(15000000, "IDR")}
+	mockListProduct := []domain.Product{mockProduct}
+
+	t.Run("success", func(t *testing.T) {
+		next := &domain.Cursor{Key: time.Now(), ID: 1}
+		mockProductRepo.On("Fetch", mock.Anything, domain.PageRequest{Limit: 10}).Return(mockListProduct, domain.PageInfo{Next: next}, nil).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		list, info, err := u.Fetch(context.TODO(), domain.PageRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, next, info.Next)
+		assert.Len(t, list, 1)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockProductRepo.On("Fetch", mock.Anything, domain.PageRequest{Limit: 1}).Return(nil, domain.PageInfo{}, errors.New("Unexpexted Error")).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		list, info, err := u.Fetch(context.TODO(), domain.PageRequest{Limit: 1})
+		assert.Error(t, err)
+		assert.Nil(t, info.Next)
+		assert.Len(t, list, 0)
+		mockProductRepo.AssertExpectations(t)
+	})
+}
+
+func TestFetchInCategory(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	shoes := domain.Category{ID: 4, Name: "Shoes", Slug: "shoes", Path: "/1/4/"}
+
+	t.Run("success", func(t *testing.T) {
+		mockCategoryRepo.On("GetByID", mock.Anything, shoes.ID).Return(shoes, nil).Once()
+		mockProductRepo.On("FetchInCategory", mock.Anything, shoes, domain.PageRequest{Limit: 10}).
+			Return([]domain.Product{{ID: 1, CategoryID: 7}}, domain.PageInfo{}, nil).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, mockCategoryRepo, new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		list, _, err := u.FetchInCategory(context.TODO(), shoes.ID, domain.PageRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		mockProductRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-category", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Category{}, domain.ErrNotFound).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, mockCategoryRepo, new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		_, _, err := u.FetchInCategory(context.TODO(), int64(9), domain.PageRequest{})
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockProductRepo.AssertNotCalled(t, "FetchInCategory", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestStore(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	mockCategoryRepo := new(mocks.CategoryRepository)
+
+	t.Run("success", func(t *testing.T) {
+		p := domain.Product{Name: "Shirt", UserID: 2, CategoryID: 4, Price: domain.NewMoney(15000000, "IDR")}
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(domain.Category{ID: 4, Path: "/4/"}, nil).Once()
+		mockProductRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, mockCategoryRepo, new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		err := u.Store(context.TODO(), &p)
+		assert.NoError(t, err)
+		assert.False(t, p.CreatedAt.IsZero())
+		assert.Equal(t, p.CreatedAt, p.UpdatedAt)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("unsupported-currency", func(t *testing.T) {
+		p := domain.Product{Name: "Shirt", UserID: 2, Price: domain.NewMoney(100, "XYZ")}
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		err := u.Store(context.TODO(), &p)
+		assert.Equal(t, domain.ErrInvalidCurrency, err)
+		mockProductRepo.AssertNotCalled(t, "Store", mock.Anything, &p)
+	})
+
+	t.Run("unknown-category", func(t *testing.T) {
+		p := domain.Product{Name: "Shirt", UserID: 2, CategoryID: 9, Price: domain.NewMoney(15000000, "IDR")}
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Category{}, domain.ErrNotFound).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, mockCategoryRepo, new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		err := u.Store(context.TODO(), &p)
+		assert.Equal(t, domain.ErrUnknownCategory, err)
+		mockProductRepo.AssertNotCalled(t, "Store", mock.Anything, &p)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	mockCategoryRepo := new(mocks.CategoryRepository)
+	existing := domain.Product{ID: 1, UserID: 2, Name: "Shirt", Rating: 4, NumReviews: 10, CreatedAt: time.Now().Add(-time.Hour)}
+
+	t.Run("success", func(t *testing.T) {
+		p := domain.Product{ID: 1, Name: "Blue shirt", CategoryID: 4, Price: domain.NewMoney(15000000, "IDR"), Rating: 5, NumReviews: 99}
+		mockProductRepo.On("GetByID", mock.Anything, existing.ID).Return(existing, nil).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(domain.Category{ID: 4, Path: "/4/"}, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockVariantRepo := new(mocks.VariantRepository)
+		mockVariantRepo.On("FetchByProduct", mock.Anything, existing.ID).Return([]domain.Variant{}, nil).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, mockCategoryRepo, new(mocks.ProductOptionRepository), mockVariantRepo, new(mocks.Transactor), nil, time.Second*2)
+		err := u.Update(context.TODO(), &p)
+		assert.NoError(t, err)
+		assert.Equal(t, "Blue shirt", p.Name)
+		assert.Equal(t, existing.Rating, p.Rating)
+		assert.Equal(t, existing.NumReviews, p.NumReviews)
+		assert.Equal(t, existing.UserID, p.UserID)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("stock-follows-variants", func(t *testing.T) {
+		withStock := existing
+		withStock.Price = domain.NewMoney(15000000, "IDR")
+		withStock.CountInStock = 7
+		p := domain.Product{ID: 1, Name: "Shirt", CategoryID: 4, Price: domain.NewMoney(15000000, "IDR"), CountInStock: 100}
+		mockProductRepo.On("GetByID", mock.Anything, existing.ID).Return(withStock, nil).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(domain.Category{ID: 4, Path: "/4/"}, nil).Once()
+		mockProductRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).Return(nil).Once()
+		mockVariantRepo := new(mocks.VariantRepository)
+		mockVariantRepo.On("FetchByProduct", mock.Anything, existing.ID).Return([]domain.Variant{{ID: 3, ProductID: 1, CountInStock: 7}}, nil).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, mockCategoryRepo, new(mocks.ProductOptionRepository), mockVariantRepo, new(mocks.Transactor), nil, time.Second*2)
+		err := u.Update(context.TODO(), &p)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, p.CountInStock)
+		mockVariantRepo.AssertExpectations(t)
+	})
+
+	t.Run("currency-change-with-variants", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		inIDR := existing
+		inIDR.Price = domain.NewMoney(15000000, "IDR")
+		p := domain.Product{ID: 1, Name: "Shirt", CategoryID: 4, Price: domain.NewMoney(1000, "USD")}
+		mockProductRepo.On("GetByID", mock.Anything, existing.ID).Return(inIDR, nil).Once()
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(4)).Return(domain.Category{ID: 4, Path: "/4/"}, nil).Once()
+		mockVariantRepo := new(mocks.VariantRepository)
+		mockVariantRepo.On("FetchByProduct", mock.Anything, existing.ID).Return([]domain.Variant{{ID: 3, ProductID: 1}}, nil).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, mockCategoryRepo, new(mocks.ProductOptionRepository), mockVariantRepo, new(mocks.Transactor), nil, time.Second*2)
+		err := u.Update(context.TODO(), &p)
+		assert.Equal(t, domain.ErrVariantsExist, err)
+		mockProductRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		p := domain.Product{ID: 9, Price: domain.NewMoney(15000000, "IDR")}
+		mockProductRepo.On("GetByID", mock.Anything, p.ID).Return(domain.Product{}, domain.ErrNotFound).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), nil, time.Second*2)
+		err := u.Update(context.TODO(), &p)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockProductRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	mockOptionRepo := new(mocks.ProductOptionRepository)
+	mockVariantRepo := new(mocks.VariantRepository)
+	size := domain.ProductOption{ID: 2, ProductID: 1, Name: "Size", Values: []domain.OptionValue{{ID: 5, OptionID: 2, Value: "M"}}}
+	variant := domain.Variant{ID: 3, ProductID: 1, SKU: "SHIRT-M", Options: []domain.OptionValue{size.Values[0]}}
+	mockProductRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Product{ID: 1, Name: "Shirt"}, nil).Once()
+	mockOptionRepo.On("FetchByProduct", mock.Anything, int64(1)).Return([]domain.ProductOption{size}, nil).Once()
+	mockVariantRepo.On("FetchByProduct", mock.Anything, int64(1)).Return([]domain.Variant{variant}, nil).Once()
+
+	u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), mockOptionRepo, mockVariantRepo, new(mocks.Transactor), nil, time.Second*2)
+	res, err := u.GetByID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ProductOption{size}, res.Options)
+	assert.Equal(t, []domain.Variant{variant}, res.Variants)
+	mockOptionRepo.AssertExpectations(t)
+	mockVariantRepo.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockProductRepo := new(mocks.ProductRepository)
+	mockTransactor := new(mocks.Transactor)
+	mockProduct := domain.Product{ID: 1, Name: "Shirt"}
+
+	t.Run("success", func(t *testing.T) {
+		mockOptionRepo := new(mocks.ProductOptionRepository)
+		mockVariantRepo := new(mocks.VariantRepository)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, mockProduct.ID).Return(mockProduct, nil).Once()
+		mockVariantRepo.On("DeleteByProduct", mock.Anything, mockProduct.ID).Return(nil).Once()
+		mockOptionRepo.On("DeleteByProduct", mock.Anything, mockProduct.ID).Return(nil).Once()
+		mockProductRepo.On("Delete", mock.Anything, mockProduct.ID).Return(nil).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), mockOptionRepo, mockVariantRepo, mockTransactor, nil, time.Second*2)
+		err := u.Delete(context.TODO(), mockProduct.ID)
+		assert.NoError(t, err)
+		mockProductRepo.AssertExpectations(t)
+		mockOptionRepo.AssertExpectations(t)
+		mockVariantRepo.AssertExpectations(t)
+		mockTransactor.AssertExpectations(t)
+	})
+
+	t.Run("product-is-not-exist", func(t *testing.T) {
+		mockVariantRepo := new(mocks.VariantRepository)
+		mockTransactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Once()
+		mockProductRepo.On("GetByIDForUpdate", mock.Anything, int64(9)).Return(domain.Product{}, domain.ErrNotFound).Once()
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), mockVariantRepo, mockTransactor, nil, time.Second*2)
+		err := u.Delete(context.TODO(), int64(9))
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockProductRepo.AssertExpectations(t)
+		mockVariantRepo.AssertNotCalled(t, "DeleteByProduct", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mockProduct := domain.Product{ID: 1, Name: "Running Shoe", Brand: "Nike", CategoryID: 4, Price: domain.NewMoney(15000000, "IDR")}
+	bounds := []int64{10000000, 5000000}
+	page := domain.PageRequest{
+		Limit:   10,
+		Sort:    domain.ProductSearchSchema.DefaultSort,
+		Filters: []domain.Filter{{Field: "brand", Op: domain.FilterEq, Value: "Nike"}},
+	}
+	facets := domain.ProductFacets{
+		Brands:     []domain.FacetCount{{Value: "Nike", Count: 1}, {Value: "Adidas", Count: 4}},
+		Categories: []domain.FacetCount{{Value: "Shoes", Count: 1}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("Search", mock.Anything, "running shoe", page).Return([]domain.Product{mockProduct}, domain.PageInfo{}, nil).Once()
+		mockProductRepo.On("Facets", mock.Anything, "running shoe", page.Filters, []int64{5000000, 10000000}).Return(facets, nil).Once()
+
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), bounds, time.Second*2)
+		res, _, err := u.Search(context.TODO(), "  running shoe ", page)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Product{mockProduct}, res.Products)
+		assert.Equal(t, facets, res.Facets)
+		assert.Empty(t, res.Suggestions)
+		mockProductRepo.AssertExpectations(t)
+		mockProductRepo.AssertNotCalled(t, "Terms", mock.Anything)
+	})
+
+	t.Run("suggestions", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("Search", mock.Anything, "runing sheos", page).Return([]domain.Product{}, domain.PageInfo{}, nil).Once()
+		mockProductRepo.On("Facets", mock.Anything, "runing sheos", page.Filters, mock.Anything).Return(domain.ProductFacets{}, nil).Once()
+		mockProductRepo.On("Terms", mock.Anything).Return([]string{"Running Shoe", "Nike", "Shoes", "Sneakers", "Shop"}, nil).Once()
+
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), bounds, time.Second*2)
+		res, _, err := u.Search(context.TODO(), "runing sheos", page)
+		assert.NoError(t, err)
+		assert.Empty(t, res.Products)
+		assert.Equal(t, []string{"running shoes", "running shoe", "running shop"}, res.Suggestions)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("terms-are-read-once", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("Search", mock.Anything, mock.AnythingOfType("string"), page).Return([]domain.Product{}, domain.PageInfo{}, nil).Twice()
+		mockProductRepo.On("Facets", mock.Anything, mock.AnythingOfType("string"), page.Filters, mock.Anything).Return(domain.ProductFacets{}, nil).Twice()
+		mockProductRepo.On("Terms", mock.Anything).Return([]string{"Running Shoe", "Nike"}, nil).Once()
+
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), bounds, time.Second*2)
+		res, _, err := u.Search(context.TODO(), "runing", page)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"running"}, res.Suggestions)
+		res, _, err = u.Search(context.TODO(), "nikee", page)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"nike"}, res.Suggestions)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("no-suggestion-past-first-page", func(t *testing.T) {
+		next := page
+		next.Cursor = &domain.Cursor{Sort: page.Sort, Key: int64(1500), ID: 9}
+		mockProductRepo := new(mocks.ProductRepository)
+		mockProductRepo.On("Search", mock.Anything, "runing", next).Return([]domain.Product{}, domain.PageInfo{}, nil).Once()
+		mockProductRepo.On("Facets", mock.Anything, "runing", page.Filters, mock.Anything).Return(domain.ProductFacets{}, nil).Once()
+
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), bounds, time.Second*2)
+		res, _, err := u.Search(context.TODO(), "runing", next)
+		assert.NoError(t, err)
+		assert.Empty(t, res.Suggestions)
+		mockProductRepo.AssertNotCalled(t, "Terms", mock.Anything)
+	})
+
+	t.Run("empty-query", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		u := ucase.NewProductUsecase(mockProductRepo, new(mocks.CategoryRepository), new(mocks.ProductOptionRepository), new(mocks.VariantRepository), new(mocks.Transactor), bounds, time.Second*2)
+		_, _, err := u.Search(context.TODO(), "   ", page)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		mockProductRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
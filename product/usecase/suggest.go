@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// maxSuggestions is the number of spellings suggested for a search
+	maxSuggestions = 3
+	// vocabularyTTL is how long the words of the catalog are kept before
+	// they are read again
+	vocabularyTTL = 10 * time.Minute
+)
+
+// vocabularyCache keeps the words of the catalog so that a search without
+// hits does not read the whole catalog
+type vocabularyCache struct {
+	mu       sync.Mutex
+	words    map[string]bool
+	loadedAt time.Time
+}
+
+// get returns the cached words, load is called when they are missing or
+// older than vocabularyTTL. Concurrent searches wait for one load.
+func (c *vocabularyCache) get(ctx context.Context, load func(ctx context.Context) ([]string, error)) (map[string]bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.words != nil && time.Since(c.loadedAt) < vocabularyTTL {
+		return c.words, nil
+	}
+	terms, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.words = vocabularyOf(terms)
+	c.loadedAt = time.Now()
+	return c.words, nil
+}
+
+// vocabularyOf returns the words of terms
+func vocabularyOf(terms []string) map[string]bool {
+	vocabulary := map[string]bool{}
+	for _, t := range terms {
+		for _, w := range words(t) {
+			vocabulary[w] = true
+		}
+	}
+	return vocabulary
+}
+
+// suggest returns up to maxSuggestions spellings of query where the words
+// that are not in the vocabulary are replaced by the nearest words of it. The
+// first suggestion takes the nearest word for each, the next ones the
+// runners-up. Nothing is suggested when every word is known or too far from
+// any.
+func suggest(query string, vocabulary map[string]bool) []string {
+	queryWords := words(query)
+	candidates := make([][]string, len(queryWords))
+	corrected := false
+	for i, w := range queryWords {
+		if vocabulary[w] {
+			candidates[i] = []string{w}
+			continue
+		}
+		candidates[i] = nearest(w, vocabulary)
+		if len(candidates[i]) == 0 {
+			candidates[i] = []string{w}
+			continue
+		}
+		corrected = true
+	}
+
+	res := []string{}
+	if !corrected {
+		return res
+	}
+	seen := map[string]bool{}
+	for k := 0; k < maxSuggestions; k++ {
+		spelling := make([]string, len(candidates))
+		more := false
+		for i, c := range candidates {
+			spelling[i] = c[0]
+			if k < len(c) {
+				spelling[i] = c[k]
+				more = more || k > 0
+			}
+		}
+		if k > 0 && !more {
+			break
+		}
+		s := strings.Join(spelling, " ")
+		if !seen[s] {
+			seen[s] = true
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// words splits s into lower case words of letters and digits
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// nearest returns the words of vocabulary close enough to w to be a typo of
+// it, the nearest first. Short words tolerate one edit, longer ones two, and
+// words of less than three letters are not corrected.
+func nearest(w string, vocabulary map[string]bool) []string {
+	n := len([]rune(w))
+	if n < 3 {
+		return nil
+	}
+	maxEdits := 1
+	if n > 4 {
+		maxEdits = 2
+	}
+
+	type candidate struct {
+		word     string
+		distance int
+	}
+	var found []candidate
+	for v := range vocabulary {
+		if d := editDistance(w, v); d <= maxEdits {
+			found = append(found, candidate{word: v, distance: d})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].distance != found[j].distance {
+			return found[i].distance < found[j].distance
+		}
+		return found[i].word < found[j].word
+	})
+
+	res := make([]string, 0, maxSuggestions)
+	for i := 0; i < len(found) && i < maxSuggestions; i++ {
+		res = append(res, found[i].word)
+	}
+	return res
+}
+
+// editDistance is the number of insertions, deletions, substitutions and
+// swaps of adjacent letters turning a into b
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func min(first int, rest ...int) int {
+	res := first
+	for _, v := range rest {
+		if v < res {
+			res = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type variantUsecase struct {
+	productRepo    domain.ProductRepository
+	optionRepo     domain.ProductOptionRepository
+	variantRepo    domain.VariantRepository
+	transactor     domain.Transactor
+	contextTimeout time.Duration
+}
+
+// NewVariantUsecase will create an object that represent the domain.VariantUsecase interface
+func NewVariantUsecase(p domain.ProductRepository, o domain.ProductOptionRepository, v domain.VariantRepository, tx domain.Transactor, timeout time.Duration) domain.VariantUsecase {
+	return &variantUsecase{
+		productRepo:    p,
+		optionRepo:     o,
+		variantRepo:    v,
+		transactor:     tx,
+		contextTimeout: timeout,
+	}
+}
+
+func (m *variantUsecase) FetchOptions(ctx context.Context, productID int64) ([]domain.ProductOption, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if _, err := m.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return m.optionRepo.FetchByProduct(ctx, productID)
+}
+
+// checkOption trims the name and values of o and refuses an option without
+// values or with the same value twice
+func checkOption(o *domain.ProductOption) error {
+	o.Name = strings.TrimSpace(o.Name)
+	if o.Name == "" || len(o.Values) == 0 {
+		return domain.ErrBadParamInput
+	}
+	seen := map[string]bool{}
+	for i := range o.Values {
+		o.Values[i].Value = strings.TrimSpace(o.Values[i].Value)
+		key := strings.ToLower(o.Values[i].Value)
+		if key == "" || seen[key] {
+			return domain.ErrBadParamInput
+		}
+		seen[key] = true
+		o.Values[i].Position = i
+	}
+	return nil
+}
+
+// noVariants refuses to change the options of a product that has variants,
+// they would be left without a value of an option or with a value gone
+func (m *variantUsecase) noVariants(ctx context.Context, productID int64) error {
+	variants, err := m.variantRepo.FetchByProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if len(variants) > 0 {
+		return domain.ErrVariantsExist
+	}
+	return nil
+}
+
+func (m *variantUsecase) StoreOption(ctx context.Context, o *domain.ProductOption) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err := checkOption(o); err != nil {
+		return err
+	}
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := m.productRepo.GetByIDForUpdate(ctx, o.ProductID); err != nil {
+			return err
+		}
+		if err := m.noVariants(ctx, o.ProductID); err != nil {
+			return err
+		}
+		options, err := m.optionRepo.FetchByProduct(ctx, o.ProductID)
+		if err != nil {
+			return err
+		}
+		for _, existing := range options {
+			if strings.EqualFold(existing.Name, o.Name) {
+				return domain.ErrConflict
+			}
+		}
+		return m.optionRepo.Store(ctx, o)
+	})
+}
+
+func (m *variantUsecase) DeleteOption(ctx context.Context, productID int64, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := m.productRepo.GetByIDForUpdate(ctx, productID); err != nil {
+			return err
+		}
+		options, err := m.optionRepo.FetchByProduct(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if !hasOption(options, id) {
+			return domain.ErrNotFound
+		}
+		if err = m.noVariants(ctx, productID); err != nil {
+			return err
+		}
+		return m.optionRepo.Delete(ctx, id)
+	})
+}
+
+func hasOption(options []domain.ProductOption, id int64) bool {
+	for _, o := range options {
+		if o.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *variantUsecase) FetchVariants(ctx context.Context, productID int64) ([]domain.Variant, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if _, err := m.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return m.variantRepo.FetchByProduct(ctx, productID)
+}
+
+// resolveOptions replaces the value ids of v.Options with the values, one of
+// each option of the product in their order, and refuses a SKU or a
+// combination of values another variant of the catalog already has
+func (m *variantUsecase) resolveOptions(ctx context.Context, v *domain.Variant) error {
+	options, err := m.optionRepo.FetchByProduct(ctx, v.ProductID)
+	if err != nil {
+		return err
+	}
+	if len(options) == 0 || len(v.Options) != len(options) {
+		return domain.ErrBadParamInput
+	}
+	chosen := make([]*domain.OptionValue, len(options))
+	for _, picked := range v.Options {
+		found := false
+		for i := range options {
+			for j := range options[i].Values {
+				if options[i].Values[j].ID == picked.ID && chosen[i] == nil {
+					chosen[i] = &options[i].Values[j]
+					found = true
+				}
+			}
+		}
+		if !found {
+			return domain.ErrBadParamInput
+		}
+	}
+	v.Options = make([]domain.OptionValue, len(chosen))
+	for i, c := range chosen {
+		v.Options[i] = *c
+	}
+
+	existing, err := m.variantRepo.GetBySKU(ctx, v.SKU)
+	if err == nil && existing.ID != v.ID {
+		return domain.ErrConflict
+	}
+	if err != nil && err != domain.ErrNotFound {
+		return err
+	}
+	variants, err := m.variantRepo.FetchByProduct(ctx, v.ProductID)
+	if err != nil {
+		return err
+	}
+	for _, other := range variants {
+		if other.ID != v.ID && other.Title() == v.Title() {
+			return domain.ErrConflict
+		}
+	}
+	return nil
+}
+
+// checkVariant refuses a variant without a SKU, with a negative stock or an
+// unknown currency, a variant is priced in the currency of its product
+func checkVariant(v *domain.Variant) error {
+	v.SKU = strings.TrimSpace(v.SKU)
+	if v.SKU == "" || v.CountInStock < 0 {
+		return domain.ErrBadParamInput
+	}
+	if !v.Price.Currency.IsValid() {
+		return domain.ErrInvalidCurrency
+	}
+	return nil
+}
+
+func (m *variantUsecase) StoreVariant(ctx context.Context, v *domain.Variant) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err := checkVariant(v); err != nil {
+		return err
+	}
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := m.productRepo.GetByIDForUpdate(ctx, v.ProductID)
+		if err != nil {
+			return err
+		}
+		if v.Price.Currency != product.Price.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+		if err = m.resolveOptions(ctx, v); err != nil {
+			return err
+		}
+		v.CreatedAt = time.Now()
+		v.UpdatedAt = v.CreatedAt
+		if err = m.variantRepo.Store(ctx, v); err != nil {
+			return err
+		}
+		return m.variantRepo.SyncProductStock(ctx, v.ProductID)
+	})
+}
+
+func (m *variantUsecase) UpdateVariant(ctx context.Context, v *domain.Variant) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err := checkVariant(v); err != nil {
+		return err
+	}
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := m.productRepo.GetByIDForUpdate(ctx, v.ProductID)
+		if err != nil {
+			return err
+		}
+		if v.Price.Currency != product.Price.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+		existed, err := m.variantRepo.GetByID(ctx, v.ID)
+		if err != nil {
+			return err
+		}
+		if existed.ProductID != v.ProductID {
+			return domain.ErrNotFound
+		}
+		if err = m.resolveOptions(ctx, v); err != nil {
+			return err
+		}
+		v.CreatedAt = existed.CreatedAt
+		v.UpdatedAt = time.Now()
+		if err = m.variantRepo.Update(ctx, v); err != nil {
+			return err
+		}
+		return m.variantRepo.SyncProductStock(ctx, v.ProductID)
+	})
+}
+
+func (m *variantUsecase) DeleteVariant(ctx context.Context, productID int64, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := m.productRepo.GetByIDForUpdate(ctx, productID); err != nil {
+			return err
+		}
+		existed, err := m.variantRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if existed.ProductID != productID {
+			return domain.ErrNotFound
+		}
+		if err = m.variantRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return m.variantRepo.SyncProductStock(ctx, productID)
+	})
+}
@@ -0,0 +1,191 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	ucase "github.com/alfathaulia/ca_ecommerce_api/product/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// runInTransaction makes the Transactor mock call the unit of work directly
+func runInTransaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+var (
+	shirt  = domain.Product{ID: 1, Name: "Shirt", Price: domain.NewMoney(15000000, "IDR")}
+	size   = domain.ProductOption{ID: 2, ProductID: 1, Name: "Size", Values: []domain.OptionValue{{ID: 5, OptionID: 2, Value: "M"}, {ID: 6, OptionID: 2, Value: "L", Position: 1}}}
+	colour = domain.ProductOption{ID: 3, ProductID: 1, Name: "Colour", Position: 1, Values: []domain.OptionValue{{ID: 8, OptionID: 3, Value: "Red"}}}
+)
+
+type variantDeps struct {
+	productRepo *mocks.ProductRepository
+	optionRepo  *mocks.ProductOptionRepository
+	variantRepo *mocks.VariantRepository
+	transactor  *mocks.Transactor
+}
+
+func newVariantUsecase() (domain.VariantUsecase, variantDeps) {
+	d := variantDeps{
+		productRepo: new(mocks.ProductRepository),
+		optionRepo:  new(mocks.ProductOptionRepository),
+		variantRepo: new(mocks.VariantRepository),
+		transactor:  new(mocks.Transactor),
+	}
+	d.transactor.On("WithinTransaction", mock.Anything, mock.Anything).Return(runInTransaction).Maybe()
+	return ucase.NewVariantUsecase(d.productRepo, d.optionRepo, d.variantRepo, d.transactor, time.Second*2), d
+}
+
+func TestStoreOption(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		d.variantRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.Variant{}, nil).Once()
+		d.optionRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.ProductOption{size}, nil).Once()
+		d.optionRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.ProductOption")).Return(nil).Once()
+
+		o := domain.ProductOption{ProductID: shirt.ID, Name: " Colour ", Values: []domain.OptionValue{{Value: "Red"}, {Value: " Blue"}}}
+		err := u.StoreOption(context.TODO(), &o)
+		assert.NoError(t, err)
+		assert.Equal(t, "Colour", o.Name)
+		assert.Equal(t, domain.OptionValue{Value: "Blue", Position: 1}, o.Values[1])
+		d.optionRepo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate-value", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		o := domain.ProductOption{ProductID: shirt.ID, Name: "Colour", Values: []domain.OptionValue{{Value: "Red"}, {Value: "red"}}}
+		err := u.StoreOption(context.TODO(), &o)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		d.optionRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("same-name", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		d.variantRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.Variant{}, nil).Once()
+		d.optionRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.ProductOption{size}, nil).Once()
+
+		o := domain.ProductOption{ProductID: shirt.ID, Name: "size", Values: []domain.OptionValue{{Value: "XL"}}}
+		err := u.StoreOption(context.TODO(), &o)
+		assert.Equal(t, domain.ErrConflict, err)
+		d.optionRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("variants-exist", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		d.variantRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.Variant{{ID: 7, ProductID: shirt.ID}}, nil).Once()
+
+		o := domain.ProductOption{ProductID: shirt.ID, Name: "Colour", Values: []domain.OptionValue{{Value: "Red"}}}
+		err := u.StoreOption(context.TODO(), &o)
+		assert.Equal(t, domain.ErrVariantsExist, err)
+		d.optionRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteOptionOfOtherProduct(t *testing.T) {
+	u, d := newVariantUsecase()
+	d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+	d.optionRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.ProductOption{size}, nil).Once()
+
+	err := u.DeleteOption(context.TODO(), shirt.ID, 99)
+	assert.Equal(t, domain.ErrNotFound, err)
+	d.optionRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestStoreVariant(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		d.optionRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.ProductOption{size, colour}, nil).Once()
+		d.variantRepo.On("GetBySKU", mock.Anything, "SHIRT-L-RED").Return(domain.Variant{}, domain.ErrNotFound).Once()
+		d.variantRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.Variant{
+			{ID: 7, ProductID: shirt.ID, Options: []domain.OptionValue{size.Values[0], colour.Values[0]}},
+		}, nil).Once()
+		d.variantRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Variant")).Return(nil).Once()
+		d.variantRepo.On("SyncProductStock", mock.Anything, shirt.ID).Return(nil).Once()
+
+		// the values may come in any order, they are kept in the order of the options
+		v := domain.Variant{ProductID: shirt.ID, SKU: " SHIRT-L-RED ", Price: domain.NewMoney(16000000, "IDR"), CountInStock: 4,
+			Options: []domain.OptionValue{{ID: 8}, {ID: 6}}}
+		err := u.StoreVariant(context.TODO(), &v)
+		assert.NoError(t, err)
+		assert.Equal(t, "SHIRT-L-RED", v.SKU)
+		assert.Equal(t, "L / Red", v.Title())
+		assert.Equal(t, v.CreatedAt, v.UpdatedAt)
+		d.variantRepo.AssertExpectations(t)
+	})
+
+	t.Run("same-combination", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		d.optionRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.ProductOption{size, colour}, nil).Once()
+		d.variantRepo.On("GetBySKU", mock.Anything, "SHIRT-M-RED-2").Return(domain.Variant{}, domain.ErrNotFound).Once()
+		d.variantRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.Variant{
+			{ID: 7, ProductID: shirt.ID, Options: []domain.OptionValue{size.Values[0], colour.Values[0]}},
+		}, nil).Once()
+
+		v := domain.Variant{ProductID: shirt.ID, SKU: "SHIRT-M-RED-2", Price: shirt.Price, Options: []domain.OptionValue{{ID: 5}, {ID: 8}}}
+		err := u.StoreVariant(context.TODO(), &v)
+		assert.Equal(t, domain.ErrConflict, err)
+		d.variantRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("two-values-of-one-option", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		d.optionRepo.On("FetchByProduct", mock.Anything, shirt.ID).Return([]domain.ProductOption{size, colour}, nil).Once()
+
+		v := domain.Variant{ProductID: shirt.ID, SKU: "SHIRT-ML", Price: shirt.Price, Options: []domain.OptionValue{{ID: 5}, {ID: 6}}}
+		err := u.StoreVariant(context.TODO(), &v)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		d.variantRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid-currency", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		v := domain.Variant{ProductID: shirt.ID, SKU: "SHIRT-M", Price: domain.NewMoney(100, "XYZ"), Options: []domain.OptionValue{{ID: 5}}}
+		err := u.StoreVariant(context.TODO(), &v)
+		assert.Equal(t, domain.ErrInvalidCurrency, err)
+		d.transactor.AssertNotCalled(t, "WithinTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("other-currency-than-product", func(t *testing.T) {
+		u, d := newVariantUsecase()
+		d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+
+		v := domain.Variant{ProductID: shirt.ID, SKU: "SHIRT-M", Price: domain.NewMoney(1000, "USD"), Options: []domain.OptionValue{{ID: 5}}}
+		err := u.StoreVariant(context.TODO(), &v)
+		assert.Equal(t, domain.ErrCurrencyMismatch, err)
+		d.variantRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateVariantOfOtherProduct(t *testing.T) {
+	u, d := newVariantUsecase()
+	d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+	d.variantRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Variant{ID: 9, ProductID: 2}, nil).Once()
+
+	v := domain.Variant{ID: 9, ProductID: shirt.ID, SKU: "HAT-M", Price: shirt.Price, Options: []domain.OptionValue{{ID: 5}}}
+	err := u.UpdateVariant(context.TODO(), &v)
+	assert.Equal(t, domain.ErrNotFound, err)
+	d.variantRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeleteVariant(t *testing.T) {
+	u, d := newVariantUsecase()
+	d.productRepo.On("GetByIDForUpdate", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+	d.variantRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Variant{ID: 7, ProductID: shirt.ID}, nil).Once()
+	d.variantRepo.On("Delete", mock.Anything, int64(7)).Return(nil).Once()
+	d.variantRepo.On("SyncProductStock", mock.Anything, shirt.ID).Return(nil).Once()
+
+	err := u.DeleteVariant(context.TODO(), shirt.ID, 7)
+	assert.NoError(t, err)
+	d.variantRepo.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var validate = validator.New()
+
+// destinationRequest is the part of the address shipping is priced on
+type destinationRequest struct {
+	City       string `json:"city" validate:"max=128"`
+	PostalCode string `json:"postal_code" validate:"required,max=32"`
+	Country    string `json:"country" validate:"required,max=128"`
+}
+
+type quoteItemRequest struct {
+	ProductID int64 `json:"product_id" validate:"required"`
+	VariantID int64 `json:"variant_id"`
+	Qty       int   `json:"qty" validate:"required,min=1"`
+}
+
+// quoteRequest asks the price of shipping items, e.g. the lines of the cart
+type quoteRequest struct {
+	ShippingAddress destinationRequest `json:"shipping_address" validate:"required"`
+	Items           []quoteItemRequest `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+func (r quoteRequest) toAddress() domain.ShippingAddress {
+	return domain.ShippingAddress{
+		City:       r.ShippingAddress.City,
+		PostalCode: r.ShippingAddress.PostalCode,
+		Country:    r.ShippingAddress.Country,
+	}
+}
+
+func (r quoteRequest) toItems() []domain.CartItem {
+	res := make([]domain.CartItem, len(r.Items))
+	for i, item := range r.Items {
+		res[i] = domain.CartItem{ProductID: item.ProductID, VariantID: item.VariantID, Qty: item.Qty}
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+type ShippingHandler struct {
+	SUsecase domain.ShippingUsecase
+}
+
+func NewShippingHandler(e *echo.Echo, sucase domain.ShippingUsecase) {
+	handler := &ShippingHandler{
+		SUsecase: sucase,
+	}
+	e.POST("/shipping/quote", handler.Quote)
+}
+
+// Quote will list the shipping methods and their prices for the items shipped to the address
+func (h *ShippingHandler) Quote(c echo.Context) (err error) {
+	var req quoteRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	quotes, err := h.SUsecase.Quote(ctx, req.toAddress(), req.toItems())
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, quotes)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrBadParamInput, domain.ErrCurrencyMismatch, domain.ErrShippingUnavailable:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,86 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	shippingHttp "github.com/alfathaulia/ca_ecommerce_api/shipping/delivery/http"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.ShippingUsecase)
+		quotes := []domain.ShippingQuote{{Carrier: "local", Method: domain.ShippingMethodStandard, Service: "Regular", Price: domain.NewMoney(1500000, "IDR"), EstimatedDays: 5}}
+		mockUcase.On("Quote", mock.Anything, domain.ShippingAddress{PostalCode: "40111", Country: "Indonesia"}, []domain.CartItem{{ProductID: 4, Qty: 2}}).
+			Return(quotes, nil).Once()
+
+		e := echo.New()
+		body := `{"shipping_address":{"postal_code":"40111","country":"Indonesia"},"items":[{"product_id":4,"qty":2}]}`
+		req, err := http.NewRequest(echo.POST, "/shipping/quote", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := shippingHttp.ShippingHandler{
+			SUsecase: mockUcase,
+		}
+
+		err = handler.Quote(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"carrier":"local","method":"standard","service":"Regular","price":{"amount":1500000,"currency":"IDR"},"estimated_days":5}]`, w.Body.String())
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		mockUcase := new(mocks.ShippingUsecase)
+		mockUcase.On("Quote", mock.Anything, mock.AnythingOfType("domain.ShippingAddress"), mock.AnythingOfType("[]domain.CartItem")).
+			Return(nil, domain.ErrShippingUnavailable).Once()
+
+		e := echo.New()
+		body := `{"shipping_address":{"postal_code":"50000","country":"Malaysia"},"items":[{"product_id":4,"qty":1}]}`
+		req, err := http.NewRequest(echo.POST, "/shipping/quote", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := shippingHttp.ShippingHandler{
+			SUsecase: mockUcase,
+		}
+
+		err = handler.Quote(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("no-items", func(t *testing.T) {
+		mockUcase := new(mocks.ShippingUsecase)
+
+		e := echo.New()
+		body := `{"shipping_address":{"postal_code":"40111","country":"Indonesia"},"items":[]}`
+		req, err := http.NewRequest(echo.POST, "/shipping/quote", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := shippingHttp.ShippingHandler{
+			SUsecase: mockUcase,
+		}
+
+		err = handler.Quote(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "Quote", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
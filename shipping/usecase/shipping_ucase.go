@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type shippingUsecase struct {
+	productRepo    domain.ProductRepository
+	variantRepo    domain.VariantRepository
+	carrier        domain.ShippingCarrier
+	contextTimeout time.Duration
+}
+
+// NewShippingUsecase will create an object that represent the domain.ShippingUsecase interface
+func NewShippingUsecase(p domain.ProductRepository, v domain.VariantRepository, c domain.ShippingCarrier, timeout time.Duration) domain.ShippingUsecase {
+	return &shippingUsecase{
+		productRepo:    p,
+		variantRepo:    v,
+		carrier:        c,
+		contextTimeout: timeout,
+	}
+}
+
+func (s *shippingUsecase) Quote(ctx context.Context, address domain.ShippingAddress, items []domain.CartItem) ([]domain.ShippingQuote, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	if len(items) == 0 {
+		return nil, domain.ErrBadParamInput
+	}
+	var parcel domain.Parcel
+	for _, item := range items {
+		if item.Qty < 1 {
+			return nil, domain.ErrBadParamInput
+		}
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		price := product.Price
+		if item.VariantID != 0 {
+			variant, err := s.variantRepo.GetByID(ctx, item.VariantID)
+			if err != nil {
+				return nil, err
+			}
+			if variant.ProductID != product.ID {
+				return nil, domain.ErrNotFound
+			}
+			price = variant.Price
+		}
+		if err = parcel.Add(product, price, item.Qty); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := s.carrier.Quote(ctx, address, parcel)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, domain.ErrShippingUnavailable
+	}
+	return res, nil
+}
+
+func (s *shippingUsecase) Price(ctx context.Context, address domain.ShippingAddress, parcel domain.Parcel, method domain.ShippingMethod) (domain.ShippingQuote, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.contextTimeout)
+	defer cancel()
+
+	quotes, err := s.carrier.Quote(ctx, address, parcel)
+	if err != nil {
+		return domain.ShippingQuote{}, err
+	}
+
+	var res domain.ShippingQuote
+	found := false
+	for _, q := range quotes {
+		if q.Method != method {
+			continue
+		}
+		if found && q.Price.Amount >= res.Price.Amount {
+			continue
+		}
+		res, found = q, true
+	}
+	if !found {
+		return domain.ShippingQuote{}, domain.ErrShippingUnavailable
+	}
+	return res, nil
+}
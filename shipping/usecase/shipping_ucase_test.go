@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	ucase "github.com/alfathaulia/ca_ecommerce_api/shipping/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	address = domain.ShippingAddress{City: "Bandung", PostalCode: "40111", Country: "Indonesia"}
+	shirt   = domain.Product{ID: 4, Name: "Shirt", Price: domain.NewMoney(15000000, "IDR"), WeightGrams: 250, LengthMM: 300, WidthMM: 250, HeightMM: 20}
+	regular = domain.ShippingQuote{Carrier: "local", Method: domain.ShippingMethodStandard, Service: "Regular", Price: domain.NewMoney(1500000, "IDR")}
+)
+
+func TestQuote(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockCarrier := new(mocks.ShippingCarrier)
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockCarrier.On("Quote", mock.Anything, address, domain.Parcel{
+			WeightGrams: 750,
+			VolumeMM3:   3 * 300 * 250 * 20,
+			LongestMM:   300,
+			Value:       domain.NewMoney(45000000, "IDR"),
+		}).Return([]domain.ShippingQuote{regular}, nil).Once()
+
+		u := ucase.NewShippingUsecase(mockProductRepo, new(mocks.VariantRepository), mockCarrier, time.Second*2)
+		quotes, err := u.Quote(context.TODO(), address, []domain.CartItem{{ProductID: shirt.ID, Qty: 3}})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ShippingQuote{regular}, quotes)
+		mockProductRepo.AssertExpectations(t)
+		mockCarrier.AssertExpectations(t)
+	})
+
+	t.Run("variant-priced", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockVariantRepo := new(mocks.VariantRepository)
+		mockCarrier := new(mocks.ShippingCarrier)
+		shirtXL := domain.Variant{ID: 7, ProductID: shirt.ID, Price: domain.NewMoney(17500000, "IDR")}
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockVariantRepo.On("GetByID", mock.Anything, shirtXL.ID).Return(shirtXL, nil).Once()
+		mockCarrier.On("Quote", mock.Anything, address, mock.MatchedBy(func(p domain.Parcel) bool {
+			return p.Value == domain.NewMoney(35000000, "IDR") && p.WeightGrams == 500
+		})).Return([]domain.ShippingQuote{regular}, nil).Once()
+
+		u := ucase.NewShippingUsecase(mockProductRepo, mockVariantRepo, mockCarrier, time.Second*2)
+		_, err := u.Quote(context.TODO(), address, []domain.CartItem{{ProductID: shirt.ID, VariantID: shirtXL.ID, Qty: 2}})
+		require.NoError(t, err)
+		mockCarrier.AssertExpectations(t)
+	})
+
+	t.Run("product-not-found", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockCarrier := new(mocks.ShippingCarrier)
+		mockProductRepo.On("GetByID", mock.Anything, int64(9)).Return(domain.Product{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewShippingUsecase(mockProductRepo, new(mocks.VariantRepository), mockCarrier, time.Second*2)
+		_, err := u.Quote(context.TODO(), address, []domain.CartItem{{ProductID: 9, Qty: 1}})
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockCarrier.AssertNotCalled(t, "Quote", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("nothing-ships-there", func(t *testing.T) {
+		mockProductRepo := new(mocks.ProductRepository)
+		mockCarrier := new(mocks.ShippingCarrier)
+		mockProductRepo.On("GetByID", mock.Anything, shirt.ID).Return(shirt, nil).Once()
+		mockCarrier.On("Quote", mock.Anything, address, mock.AnythingOfType("domain.Parcel")).Return([]domain.ShippingQuote{}, nil).Once()
+
+		u := ucase.NewShippingUsecase(mockProductRepo, new(mocks.VariantRepository), mockCarrier, time.Second*2)
+		_, err := u.Quote(context.TODO(), address, []domain.CartItem{{ProductID: shirt.ID, Qty: 1}})
+		assert.Equal(t, domain.ErrShippingUnavailable, err)
+	})
+}
+
+func TestPrice(t *testing.T) {
+	parcel := domain.Parcel{WeightGrams: 250, Value: shirt.Price}
+	cheaper := regular
+	cheaper.Service = "Economy"
+	cheaper.Price = domain.NewMoney(1200000, "IDR")
+	express := domain.ShippingQuote{Carrier: "local", Method: domain.ShippingMethodExpress, Price: domain.NewMoney(3000000, "IDR")}
+
+	mockCarrier := new(mocks.ShippingCarrier)
+	mockCarrier.On("Quote", mock.Anything, address, parcel).Return([]domain.ShippingQuote{regular, express, cheaper}, nil)
+	u := ucase.NewShippingUsecase(new(mocks.ProductRepository), new(mocks.VariantRepository), mockCarrier, time.Second*2)
+
+	t.Run("cheapest-of-method", func(t *testing.T) {
+		res, err := u.Price(context.TODO(), address, parcel, domain.ShippingMethodStandard)
+		require.NoError(t, err)
+		assert.Equal(t, cheaper, res)
+	})
+
+	t.Run("unavailable-method", func(t *testing.T) {
+		_, err := u.Price(context.TODO(), address, parcel, domain.ShippingMethodPickup)
+		assert.Equal(t, domain.ErrShippingUnavailable, err)
+	})
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+var validate = validator.New()
+
+// taxRuleRequest is the body of create and update, rate is in basis points
+type taxRuleRequest struct {
+	Name         string `json:"name" validate:"required,max=128"`
+	Country      string `json:"country" validate:"required,max=128"`
+	PostalPrefix string `json:"postal_prefix" validate:"max=32"`
+	Category     string `json:"category" validate:"max=64"`
+	Rate         int64  `json:"rate" validate:"gte=0,lte=10000"`
+	Inclusive    bool   `json:"inclusive"`
+}
+
+func (r taxRuleRequest) toTaxRule() domain.TaxRule {
+	return domain.TaxRule{
+		Name:         r.Name,
+		Country:      r.Country,
+		PostalPrefix: r.PostalPrefix,
+		Category:     domain.TaxCategory(r.Category),
+		Rate:         r.Rate,
+		Inclusive:    r.Inclusive,
+	}
+}
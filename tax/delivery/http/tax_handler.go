@@ -0,0 +1,145 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+type TaxHandler struct {
+	TUsecase domain.TaxUsecase
+}
+
+func NewTaxHandler(e *echo.Echo, tucase domain.TaxUsecase, mw *middleware.GoMiddleware) {
+	handler := &TaxHandler{
+		TUsecase: tucase,
+	}
+	e.GET("/tax-rules", handler.Fetch)
+	e.GET("/tax-rules/:id", handler.GetByID)
+	e.POST("/tax-rules", handler.Store, mw.Auth, mw.RequirePermission(domain.PermissionTaxWrite))
+	e.PUT("/tax-rules/:id", handler.Update, mw.Auth, mw.RequirePermission(domain.PermissionTaxWrite))
+	e.DELETE("/tax-rules/:id", handler.Delete, mw.Auth, mw.RequirePermission(domain.PermissionTaxWrite))
+}
+
+// Fetch will list all tax rules
+func (h *TaxHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+	rules, err := h.TUsecase.Fetch(ctx)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+// GetByID will get tax rule by given id
+func (h *TaxHandler) GetByID(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	rule, err := h.TUsecase.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// Store will create a tax rule by given request body
+func (h *TaxHandler) Store(c echo.Context) (err error) {
+	var req taxRuleRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	rule := req.toTaxRule()
+	ctx := c.Request().Context()
+	err = h.TUsecase.Store(ctx, &rule)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// Update will replace the tax rule by given param
+func (h *TaxHandler) Update(c echo.Context) (err error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	var req taxRuleRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	rule := req.toTaxRule()
+	rule.ID = id
+	ctx := c.Request().Context()
+	err = h.TUsecase.Update(ctx, &rule)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// Delete will delete tax rule by given param
+func (h *TaxHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = h.TUsecase.Delete(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	logrus.Error(err)
+	switch err {
+	case domain.ErrInternalServerError:
+		return http.StatusInternalServerError
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,81 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	taxHttp "github.com/alfathaulia/ca_ecommerce_api/tax/delivery/http"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.TaxUsecase)
+		mockUcase.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.TaxRule) bool {
+			return r.Country == "Indonesia" && r.Category == "food" && r.Rate == 0
+		})).Return(nil).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/tax-rules", strings.NewReader(`{"name":"Food","country":"Indonesia","category":"food","rate":0}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := taxHttp.TaxHandler{
+			TUsecase: mockUcase,
+		}
+
+		err = handler.Store(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		mockUcase := new(mocks.TaxUsecase)
+		mockUcase.On("Store", mock.Anything, mock.AnythingOfType("*domain.TaxRule")).Return(domain.ErrConflict).Once()
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/tax-rules", strings.NewReader(`{"name":"PPN","country":"Indonesia","rate":1100}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := taxHttp.TaxHandler{
+			TUsecase: mockUcase,
+		}
+
+		err = handler.Store(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("rate-out-of-range", func(t *testing.T) {
+		mockUcase := new(mocks.TaxUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.POST, "/tax-rules", strings.NewReader(`{"name":"PPN","country":"Indonesia","rate":12000}`))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := taxHttp.TaxHandler{
+			TUsecase: mockUcase,
+		}
+
+		err = handler.Store(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,147 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/sirupsen/logrus"
+)
+
+const selectTaxRule = `SELECT id, name, country, postal_prefix, category, rate, inclusive, updated_at, created_at FROM tax_rule`
+
+type mysqlTaxRuleRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlTaxRuleRepo will create an object that represent the domain.TaxRuleRepository interface
+func NewMysqlTaxRuleRepo(DB *sql.DB) domain.TaxRuleRepository {
+	return &mysqlTaxRuleRepo{DB: DB}
+}
+
+func (m *mysqlTaxRuleRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.TaxRule, err error) {
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.TaxRule, 0)
+	for rows.Next() {
+		t := domain.TaxRule{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Country,
+			&t.PostalPrefix,
+			&t.Category,
+			&t.Rate,
+			&t.Inclusive,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlTaxRuleRepo) Fetch(ctx context.Context) ([]domain.TaxRule, error) {
+	return m.fetch(ctx, selectTaxRule+` ORDER BY country, postal_prefix, category`)
+}
+
+func (m *mysqlTaxRuleRepo) FetchByCountry(ctx context.Context, country string) ([]domain.TaxRule, error) {
+	return m.fetch(ctx, selectTaxRule+` WHERE country = ? ORDER BY id`, country)
+}
+
+func (m *mysqlTaxRuleRepo) GetByID(ctx context.Context, id int64) (res domain.TaxRule, err error) {
+	list, err := m.fetch(ctx, selectTaxRule+` WHERE id = ?`, id)
+	if err != nil {
+		return domain.TaxRule{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlTaxRuleRepo) Store(ctx context.Context, r *domain.TaxRule) (err error) {
+	query := `INSERT  tax_rule SET name=? , country=? , postal_prefix=? , category=? , rate=? , inclusive=? , updated_at=? , created_at=?`
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, r.Name, r.Country, r.PostalPrefix, r.Category, r.Rate, r.Inclusive, r.UpdatedAt, r.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	r.ID = lastID
+	return
+}
+
+func (m *mysqlTaxRuleRepo) Update(ctx context.Context, r *domain.TaxRule) (err error) {
+	query := `UPDATE  tax_rule SET name=? , country=? , postal_prefix=? , category=? , rate=? , inclusive=? , updated_at=? WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, r.Name, r.Country, r.PostalPrefix, r.Category, r.Rate, r.Inclusive, r.UpdatedAt, r.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
+
+func (m *mysqlTaxRuleRepo) Delete(ctx context.Context, id int64) (err error) {
+	query := "DELETE FROM tax_rule WHERE id = ?"
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", rowsAfected)
+		return
+	}
+	return
+}
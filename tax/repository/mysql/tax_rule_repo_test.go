@@ -0,0 +1,119 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	taxMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/tax/repository/mysql"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var now = time.Now()
+
+var rule = &domain.TaxRule{
+	ID:        1,
+	Name:      "PPN",
+	Country:   "Indonesia",
+	Category:  "",
+	Rate:      1100,
+	UpdatedAt: now,
+	CreatedAt: now,
+}
+
+var ruleColumns = []string{"id", "name", "country", "postal_prefix", "category", "rate", "inclusive", "updated_at", "created_at"}
+
+func NewMock() (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		logrus.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return db, mock
+}
+
+func addRuleRow(rows *sqlmock.Rows, r domain.TaxRule) *sqlmock.Rows {
+	return rows.AddRow(r.ID, r.Name, r.Country, r.PostalPrefix, r.Category, r.Rate, r.Inclusive, r.UpdatedAt, r.CreatedAt)
+}
+
+func TestFetchByCountry(t *testing.T) {
+	db, mock := NewMock()
+	food := *rule
+	food.ID = 2
+	food.Category = "food"
+	food.Rate = 0
+	rows := addRuleRow(sqlmock.NewRows(ruleColumns), *rule)
+	addRuleRow(rows, food)
+
+	query := `SELECT id, name, country, postal_prefix, category, rate, inclusive, updated_at, created_at FROM tax_rule WHERE country = \? ORDER BY id`
+	mock.ExpectQuery(query).WithArgs("Indonesia").WillReturnRows(rows)
+
+	r := taxMysqlRepo.NewMysqlTaxRuleRepo(db)
+	list, err := r.FetchByCountry(context.TODO(), "Indonesia")
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.TaxRule{*rule, food}, list)
+}
+
+func TestGetByID(t *testing.T) {
+	query := `SELECT id, name, country, postal_prefix, category, rate, inclusive, updated_at, created_at FROM tax_rule WHERE id = \?`
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := NewMock()
+		mock.ExpectQuery(query).WithArgs(rule.ID).WillReturnRows(addRuleRow(sqlmock.NewRows(ruleColumns), *rule))
+
+		r := taxMysqlRepo.NewMysqlTaxRuleRepo(db)
+		res, err := r.GetByID(context.TODO(), rule.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, *rule, res)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		db, mock := NewMock()
+		mock.ExpectQuery(query).WithArgs(int64(9)).WillReturnRows(sqlmock.NewRows(ruleColumns))
+
+		r := taxMysqlRepo.NewMysqlTaxRuleRepo(db)
+		_, err := r.GetByID(context.TODO(), int64(9))
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+}
+
+func TestStore(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  tax_rule SET name=\\? , country=\\? , postal_prefix=\\? , category=\\? , rate=\\? , inclusive=\\? , updated_at=\\? , created_at=\\?"
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(rule.Name, rule.Country, rule.PostalPrefix, rule.Category, rule.Rate, rule.Inclusive, rule.UpdatedAt, rule.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	r := taxMysqlRepo.NewMysqlTaxRuleRepo(db)
+	tmp := *rule
+	err := r.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), tmp.ID)
+}
+
+func TestUpdate(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  tax_rule SET name=\\? , country=\\? , postal_prefix=\\? , category=\\? , rate=\\? , inclusive=\\? , updated_at=\\? WHERE id=\\?"
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(rule.Name, rule.Country, rule.PostalPrefix, rule.Category, rule.Rate, rule.Inclusive, rule.UpdatedAt, rule.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := taxMysqlRepo.NewMysqlTaxRuleRepo(db)
+	err := r.Update(context.TODO(), rule)
+	assert.NoError(t, err)
+}
+
+func TestDelete(t *testing.T) {
+	db, mock := NewMock()
+	mock.ExpectPrepare("DELETE FROM tax_rule WHERE id = \\?").ExpectExec().WithArgs(rule.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	r := taxMysqlRepo.NewMysqlTaxRuleRepo(db)
+	err := r.Delete(context.TODO(), rule.ID)
+	assert.NoError(t, err)
+}
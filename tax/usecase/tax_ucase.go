@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type taxUsecase struct {
+	ruleRepo       domain.TaxRuleRepository
+	contextTimeout time.Duration
+}
+
+// NewTaxUsecase will create an object that represent the domain.TaxUsecase interface
+func NewTaxUsecase(r domain.TaxRuleRepository, timeout time.Duration) domain.TaxUsecase {
+	return &taxUsecase{
+		ruleRepo:       r,
+		contextTimeout: timeout,
+	}
+}
+
+func (m *taxUsecase) Fetch(ctx context.Context) ([]domain.TaxRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.ruleRepo.Fetch(ctx)
+}
+
+func (m *taxUsecase) GetByID(ctx context.Context, id int64) (domain.TaxRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.ruleRepo.GetByID(ctx, id)
+}
+
+// checkScope refuses a second rule for the same country, postal prefix and category
+func (m *taxUsecase) checkScope(ctx context.Context, r *domain.TaxRule) error {
+	r.Country = strings.TrimSpace(r.Country)
+	if r.Country == "" || r.Rate < 0 || r.Rate > domain.TaxRateScale {
+		return domain.ErrBadParamInput
+	}
+	rules, err := m.ruleRepo.FetchByCountry(ctx, r.Country)
+	if err != nil {
+		return err
+	}
+	for _, existing := range rules {
+		if existing.ID != r.ID && existing.PostalPrefix == r.PostalPrefix && existing.Category == r.Category {
+			return domain.ErrConflict
+		}
+	}
+	return nil
+}
+
+func (m *taxUsecase) Store(ctx context.Context, r *domain.TaxRule) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if err := m.checkScope(ctx, r); err != nil {
+		return err
+	}
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = r.CreatedAt
+	return m.ruleRepo.Store(ctx, r)
+}
+
+func (m *taxUsecase) Update(ctx context.Context, r *domain.TaxRule) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	existed, err := m.ruleRepo.GetByID(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	if err = m.checkScope(ctx, r); err != nil {
+		return err
+	}
+	r.CreatedAt = existed.CreatedAt
+	r.UpdatedAt = time.Now()
+	return m.ruleRepo.Update(ctx, r)
+}
+
+func (m *taxUsecase) Delete(ctx context.Context, id int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	_, err = m.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return
+	}
+	return m.ruleRepo.Delete(ctx, id)
+}
+
+func (m *taxUsecase) Apply(ctx context.Context, address domain.ShippingAddress, items []domain.OrderItem) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	rules, err := m.ruleRepo.FetchByCountry(ctx, strings.TrimSpace(address.Country))
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		if items[i].TaxCategory == "" {
+			items[i].TaxCategory = domain.TaxCategoryStandard
+		}
+		var best *domain.TaxRule
+		for j, rule := range rules {
+			if rule.Matches(address, items[i].TaxCategory) && (best == nil || rule.MoreSpecific(*best)) {
+				best = &rules[j]
+			}
+		}
+
+		items[i].TaxRate = 0
+		items[i].TaxInclusive = false
+		items[i].TaxAmount = domain.NewMoney(0, items[i].Price.Currency)
+		if best != nil {
+			items[i].TaxRate = best.Rate
+			items[i].TaxInclusive = best.Inclusive
+			items[i].TaxAmount = best.Tax(items[i].Subtotal())
+		}
+	}
+	return nil
+}
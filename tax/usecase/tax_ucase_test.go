@@ -0,0 +1,126 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	ucase "github.com/alfathaulia/ca_ecommerce_api/tax/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var indonesia = []domain.TaxRule{
+	{ID: 1, Name: "PPN", Country: "Indonesia", Rate: 1100},
+	{ID: 2, Name: "Food", Country: "Indonesia", Category: "food", Rate: 0},
+	{ID: 3, Name: "Batam free trade zone", Country: "Indonesia", PostalPrefix: "29", Rate: 0},
+	{ID: 4, Name: "Batam luxury", Country: "Indonesia", PostalPrefix: "29", Category: "luxury", Rate: 2000, Inclusive: true},
+}
+
+func TestApply(t *testing.T) {
+	shirt := domain.OrderItem{ProductID: 4, Qty: 2, Price: domain.NewMoney(15000000, "IDR")}
+	rice := domain.OrderItem{ProductID: 5, Qty: 1, Price: domain.NewMoney(7000000, "IDR"), TaxCategory: "food"}
+	watch := domain.OrderItem{ProductID: 6, Qty: 1, Price: domain.NewMoney(120000000, "IDR"), TaxCategory: "luxury"}
+
+	tests := []struct {
+		name    string
+		address domain.ShippingAddress
+		items   []domain.OrderItem
+		want    []domain.OrderItem
+	}{
+		{
+			name:    "country-rule-and-category-rule",
+			address: domain.ShippingAddress{Country: "indonesia ", PostalCode: "40111"},
+			items:   []domain.OrderItem{shirt, rice, watch},
+			want: []domain.OrderItem{
+				{ProductID: 4, Qty: 2, Price: shirt.Price, TaxCategory: domain.TaxCategoryStandard, TaxRate: 1100, TaxAmount: domain.NewMoney(3300000, "IDR")},
+				{ProductID: 5, Qty: 1, Price: rice.Price, TaxCategory: "food", TaxRate: 0, TaxAmount: domain.NewMoney(0, "IDR")},
+				{ProductID: 6, Qty: 1, Price: watch.Price, TaxCategory: "luxury", TaxRate: 1100, TaxAmount: domain.NewMoney(13200000, "IDR")},
+			},
+		},
+		{
+			name:    "postal-prefix-wins",
+			address: domain.ShippingAddress{Country: "Indonesia", PostalCode: "29432"},
+			items:   []domain.OrderItem{shirt, watch},
+			want: []domain.OrderItem{
+				{ProductID: 4, Qty: 2, Price: shirt.Price, TaxCategory: domain.TaxCategoryStandard, TaxRate: 0, TaxAmount: domain.NewMoney(0, "IDR")},
+				{ProductID: 6, Qty: 1, Price: watch.Price, TaxCategory: "luxury", TaxRate: 2000, TaxInclusive: true, TaxAmount: domain.NewMoney(20000000, "IDR")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRuleRepo := new(mocks.TaxRuleRepository)
+			mockRuleRepo.On("FetchByCountry", mock.Anything, "indonesia").Return(indonesia, nil).Maybe()
+			mockRuleRepo.On("FetchByCountry", mock.Anything, "Indonesia").Return(indonesia, nil).Maybe()
+
+			u := ucase.NewTaxUsecase(mockRuleRepo, time.Second*2)
+			err := u.Apply(context.TODO(), tt.address, tt.items)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.items)
+		})
+	}
+
+	t.Run("no-rule", func(t *testing.T) {
+		mockRuleRepo := new(mocks.TaxRuleRepository)
+		mockRuleRepo.On("FetchByCountry", mock.Anything, "Singapore").Return([]domain.TaxRule{}, nil).Once()
+
+		items := []domain.OrderItem{shirt}
+		u := ucase.NewTaxUsecase(mockRuleRepo, time.Second*2)
+		err := u.Apply(context.TODO(), domain.ShippingAddress{Country: "Singapore", PostalCode: "018956"}, items)
+		require.NoError(t, err)
+		assert.Equal(t, domain.NewMoney(0, "IDR"), items[0].TaxAmount)
+		assert.Equal(t, int64(0), items[0].TaxRate)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRuleRepo := new(mocks.TaxRuleRepository)
+		mockRuleRepo.On("FetchByCountry", mock.Anything, "Indonesia").Return(indonesia, nil).Once()
+		mockRuleRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.TaxRule) bool {
+			return r.Country == "Indonesia" && !r.CreatedAt.IsZero()
+		})).Return(nil).Once()
+
+		u := ucase.NewTaxUsecase(mockRuleRepo, time.Second*2)
+		err := u.Store(context.TODO(), &domain.TaxRule{Name: "Books", Country: " Indonesia", Category: "books", Rate: 500})
+		assert.NoError(t, err)
+		mockRuleRepo.AssertExpectations(t)
+	})
+
+	t.Run("same-scope", func(t *testing.T) {
+		mockRuleRepo := new(mocks.TaxRuleRepository)
+		mockRuleRepo.On("FetchByCountry", mock.Anything, "Indonesia").Return(indonesia, nil).Once()
+
+		u := ucase.NewTaxUsecase(mockRuleRepo, time.Second*2)
+		err := u.Store(context.TODO(), &domain.TaxRule{Name: "Food again", Country: "Indonesia", Category: "food", Rate: 500})
+		assert.Equal(t, domain.ErrConflict, err)
+		mockRuleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rate-above-100-percent", func(t *testing.T) {
+		mockRuleRepo := new(mocks.TaxRuleRepository)
+
+		u := ucase.NewTaxUsecase(mockRuleRepo, time.Second*2)
+		err := u.Store(context.TODO(), &domain.TaxRule{Name: "Oops", Country: "Indonesia", Rate: 10001})
+		assert.Equal(t, domain.ErrBadParamInput, err)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockRuleRepo := new(mocks.TaxRuleRepository)
+	created := time.Now().Add(-time.Hour)
+	mockRuleRepo.On("GetByID", mock.Anything, int64(2)).Return(domain.TaxRule{ID: 2, CreatedAt: created}, nil).Once()
+	mockRuleRepo.On("FetchByCountry", mock.Anything, "Indonesia").Return(indonesia, nil).Once()
+	mockRuleRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *domain.TaxRule) bool {
+		return r.ID == 2 && r.Rate == 500 && r.CreatedAt.Equal(created)
+	})).Return(nil).Once()
+
+	u := ucase.NewTaxUsecase(mockRuleRepo, time.Second*2)
+	err := u.Update(context.TODO(), &domain.TaxRule{ID: 2, Name: "Food", Country: "Indonesia", Category: "food", Rate: 500})
+	assert.NoError(t, err)
+	mockRuleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,100 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/golang-jwt/jwt"
+)
+
+const minSecretKeySize = 32
+
+type jwtClaims struct {
+	Username   string           `json:"username"`
+	Role       domain.RolesType `json:"role"`
+	IsVerified bool             `json:"is_verified"`
+	Scope      string           `json:"scope"`
+	jwt.StandardClaims
+}
+
+type jwtMaker struct {
+	secretKey []byte
+	duration  time.Duration
+}
+
+// NewJWTMaker will create an object that represent the domain.TokenMaker interface
+func NewJWTMaker(secretKey string, duration time.Duration) (domain.TokenMaker, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+	return &jwtMaker{secretKey: []byte(secretKey), duration: duration}, nil
+}
+
+func (m *jwtMaker) CreateToken(user domain.User) (string, *domain.TokenPayload, error) {
+	return m.CreateScopedToken(user, domain.TokenScopeAccess, m.duration)
+}
+
+func (m *jwtMaker) CreateScopedToken(user domain.User, scope string, duration time.Duration) (string, *domain.TokenPayload, error) {
+	now := time.Now()
+	payload := &domain.TokenPayload{
+		UserID:     user.ID,
+		Username:   user.Username,
+		Role:       user.Role,
+		IsVerified: user.IsVerified,
+		Scope:      scope,
+		IssuedAt:   now,
+		ExpiredAt:  now.Add(duration),
+	}
+	claims := jwtClaims{
+		Username:   payload.Username,
+		Role:       payload.Role,
+		IsVerified: payload.IsVerified,
+		Scope:      payload.Scope,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   strconv.FormatInt(payload.UserID, 10),
+			IssuedAt:  payload.IssuedAt.Unix(),
+			ExpiresAt: payload.ExpiredAt.Unix(),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secretKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, payload, nil
+}
+
+func (m *jwtMaker) VerifyToken(signed string) (*domain.TokenPayload, error) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, domain.ErrInvalidToken
+		}
+		return m.secretKey, nil
+	}
+
+	claims := &jwtClaims{}
+	_, err := jwt.ParseWithClaims(signed, claims, keyFunc)
+	if err != nil {
+		var verr *jwt.ValidationError
+		if errors.As(err, &verr) && verr.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, domain.ErrExpiredToken
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+	return &domain.TokenPayload{
+		UserID:     userID,
+		Username:   claims.Username,
+		Role:       claims.Role,
+		IsVerified: claims.IsVerified,
+		Scope:      claims.Scope,
+		IssuedAt:   time.Unix(claims.IssuedAt, 0),
+		ExpiredAt:  time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/token"
+	util "github.com/alfathaulia/ca_ecommerce_api/user/repository/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTMaker(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+
+	user := domain.User{ID: 7, Username: "user1", Role: "admin", IsVerified: true}
+	signed, payload, err := maker.CreateToken(user)
+	require.NoError(t, err)
+	require.NotEmpty(t, signed)
+	require.Equal(t, user.ID, payload.UserID)
+
+	verified, err := maker.VerifyToken(signed)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, verified.UserID)
+	require.Equal(t, user.Username, verified.Username)
+	require.Equal(t, user.Role, verified.Role)
+	require.True(t, verified.IsVerified)
+	require.Equal(t, domain.TokenScopeAccess, verified.Scope)
+	require.WithinDuration(t, payload.ExpiredAt, verified.ExpiredAt, time.Second)
+}
+
+func TestExpiredJWTToken(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), -time.Minute)
+	require.NoError(t, err)
+
+	signed, _, err := maker.CreateToken(domain.User{ID: 1, Username: "user1"})
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(signed)
+	require.EqualError(t, err, domain.ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidJWTToken(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+	other, err := token.NewJWTMaker(util.RandomString(32), time.Minute)
+	require.NoError(t, err)
+
+	signed, _, err := other.CreateToken(domain.User{ID: 1, Username: "user1"})
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(signed)
+	require.EqualError(t, err, domain.ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	_, err = token.NewJWTMaker("short", time.Minute)
+	require.Error(t, err)
+}
+
+func TestScopedJWTToken(t *testing.T) {
+	maker, err := token.NewJWTMaker(util.RandomString(32), time.Hour)
+	require.NoError(t, err)
+
+	signed, payload, err := maker.CreateScopedToken(domain.User{ID: 1, Username: "user1"}, domain.TokenScopeMFA, time.Minute)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Minute), payload.ExpiredAt, time.Second)
+
+	verified, err := maker.VerifyToken(signed)
+	require.NoError(t, err)
+	require.Equal(t, domain.TokenScopeMFA, verified.Scope)
+}
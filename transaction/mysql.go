@@ -0,0 +1,63 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// DBTX is the part of *sql.DB and *sql.Tx used by the repositories
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+type mysqlTransactor struct {
+	DB *sql.DB
+}
+
+// NewMysqlTransactor will create an object that represent the domain.Transactor interface
+func NewMysqlTransactor(DB *sql.DB) domain.Transactor {
+	return &mysqlTransactor{DB: DB}
+}
+
+// WithinTransaction joins the transaction already carried by ctx, so usecases
+// can be composed without nesting transactions
+func (m *mysqlTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			logrus.Error(errRollback)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Conn returns the transaction carried by ctx, or db when there is none
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
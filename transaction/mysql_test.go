@@ -0,0 +1,67 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+func TestWithinTransaction(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE product").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tr := transaction.NewMysqlTransactor(db)
+		err = tr.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			_, err := transaction.Conn(ctx, db).ExecContext(ctx, "UPDATE product SET count_in_stock = 1")
+			return err
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback-on-error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		failure := errors.New("insufficient stock")
+		tr := transaction.NewMysqlTransactor(db)
+		err = tr.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			return failure
+		})
+		assert.Equal(t, failure, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nested-joins-outer", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		tr := transaction.NewMysqlTransactor(db)
+		err = tr.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			return tr.WithinTransaction(ctx, func(ctx context.Context) error {
+				return nil
+			})
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConnWithoutTransaction(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	assert.Equal(t, db, transaction.Conn(context.TODO(), db))
+}
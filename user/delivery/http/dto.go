@@ -0,0 +1,125 @@
+package http
+
+import (
+	"time"
+	"unicode"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+const (
+	passwordMinLength = 8
+	// bcrypt ignores everything after 72 bytes
+	passwordMaxLength = 72
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation("password", isStrongPassword)
+	return v
+}
+
+// isStrongPassword requires 8 to 72 bytes with at least one lower case
+// letter, one upper case letter and one digit
+func isStrongPassword(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len(password) < passwordMinLength || len(password) > passwordMaxLength {
+		return false
+	}
+
+	var lower, upper, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return lower && upper && digit
+}
+
+// createUserRequest is the body of every endpoint creating an account, the
+// role is decided by the endpoint and never by the client
+type createUserRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+// toUser carries the plaintext password in HashedPassword, the usecase hashes it before storing
+func (r createUserRequest) toUser() domain.User {
+	return domain.User{
+		Username:       r.Username,
+		Email:          r.Email,
+		HashedPassword: r.Password,
+	}
+}
+
+type loginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type updateProfileRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Email    string `json:"email" validate:"required,email"`
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,password"`
+}
+
+type loginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// userResponse is the only representation of a user sent to clients
+type userResponse struct {
+	ID         int64            `json:"id"`
+	Username   string           `json:"username"`
+	Email      string           `json:"email"`
+	IsVerified bool             `json:"is_verified"`
+	Role       domain.RolesType `json:"role"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+func newUserResponse(u domain.User) userResponse {
+	return userResponse{
+		ID:         u.ID,
+		Username:   u.Username,
+		Email:      u.Email,
+		IsVerified: u.IsVerified,
+		Role:       u.Role,
+		UpdatedAt:  u.UpdatedAt,
+		CreatedAt:  u.CreatedAt,
+	}
+}
+
+func newUserListResponse(users []domain.User) []userResponse {
+	res := make([]userResponse, 0, len(users))
+	for _, u := range users {
+		res = append(res, newUserResponse(u))
+	}
+	return res
+}
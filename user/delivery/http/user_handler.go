@@ -1,53 +1,83 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/alfathaulia/ca_ecommerce_api/middleware"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
-	validator "gopkg.in/go-playground/validator.v9"
 )
 
 type ResponseError struct {
 	Message string `json:"message"`
 }
 
+// queryErrorResponse answers a list request with query params that were rejected
+type queryErrorResponse struct {
+	Message string                `json:"message"`
+	Details []domain.QueryProblem `json:"details"`
+}
+
 type UserHandler struct {
-	UUsecase domain.UserUsecase
+	UUsecase  domain.UserUsecase
+	Paginator *pagination.Paginator
 }
 
-func NewUserHandler(e *echo.Echo, uucase domain.UserUsecase) {
+func NewUserHandler(e *echo.Echo, uucase domain.UserUsecase, pg *pagination.Paginator, mw *middleware.GoMiddleware) {
 	handler := &UserHandler{
-		UUsecase: uucase,
+		UUsecase:  uucase,
+		Paginator: pg,
 	}
-	e.GET("/users", handler.FetchUser)
-	e.POST("/users", handler.Store)
-	e.GET("/users/:id", handler.GetByID)
-	e.DELETE("/users/:id", handler.Delete)
-	e.POST("/users/register", handler.Register)
+	e.POST("/users/register", handler.Register, mw.Idempotent)
 	e.POST("/users/login", handler.Login)
-	e.POST("/users/create/admin", handler.CreateAdmin)
-	e.POST("/users/create/staff", handler.CreateStaff)
+	e.POST("/users/login/mfa", handler.LoginMFA)
+	e.POST("/users/mfa/enroll", handler.EnrollMFA, mw.AuthMFAEnrollment)
+	e.POST("/users/mfa/confirm", handler.ConfirmMFA, mw.AuthMFAEnrollment)
+	e.POST("/users/refresh", handler.Refresh)
+	e.POST("/users/logout", handler.Logout)
+	e.GET("/users/verify", handler.Verify)
+	e.POST("/users/verify/resend", handler.ResendVerification, mw.Auth)
+	e.POST("/users/password/forgot", handler.ForgotPassword)
+	e.POST("/users/password/reset", handler.ResetPassword)
+
+	e.GET("/users", handler.FetchUser, mw.Auth, mw.RequirePermission(domain.PermissionUserRead))
+	e.POST("/users", handler.Store, mw.Auth, mw.RequirePermission(domain.PermissionUserWrite))
+	e.GET("/users/:id", handler.GetByID, mw.Auth, mw.RequirePermission(domain.PermissionUserRead))
+	e.DELETE("/users/:id", handler.Delete, mw.Auth, mw.RequirePermission(domain.PermissionUserDelete))
+	e.POST("/users/:id/unlock", handler.Unlock, mw.Auth, mw.RequirePermission(domain.PermissionUserUnlock))
+	e.POST("/users/create/admin", handler.CreateAdmin, mw.Auth, mw.RequirePermission(domain.PermissionAdminCreate))
+	e.POST("/users/create/staff", handler.CreateStaff, mw.Auth, mw.RequirePermission(domain.PermissionStaffCreate))
+	e.PUT("/users/me", handler.UpdateProfile, mw.Auth)
+	e.GET("/users/sessions", handler.FetchSessions, mw.Auth)
+	e.DELETE("/users/sessions/:id", handler.RevokeSession, mw.Auth)
 
 }
 
 func (u *UserHandler) FetchUser(c echo.Context) (err error) {
-	numS := c.QueryParam("num")
-	num, _ := strconv.Atoi(numS)
-	cursor := c.QueryParam("cursor")
+	page, err := u.Paginator.Page(c, domain.UserListSchema)
+	var queryErr *domain.QueryError
+	if errors.As(err, &queryErr) {
+		c.JSON(http.StatusBadRequest, queryErrorResponse{Message: domain.ErrBadParamInput.Error(), Details: queryErr.Problems})
+		return
+	}
+	if err != nil {
+		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return
+	}
 	ctx := c.Request().Context()
 
-	listUser, nextCursor, err := u.UUsecase.Fetch(ctx, cursor, int64(num))
+	listUser, info, err := u.UUsecase.Fetch(ctx, page)
 	if err != nil {
 		c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 		return
 	}
 
-	c.Response().Header().Set(`X-Cursor`, nextCursor)
-	c.JSON(http.StatusOK, listUser)
+	u.Paginator.SetHeaders(c, info)
+	c.JSON(http.StatusOK, newUserListResponse(listUser))
 	return
 
 }
@@ -67,39 +97,53 @@ func (a *UserHandler) GetByID(c echo.Context) error {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, art)
+	return c.JSON(http.StatusOK, newUserResponse(art))
 }
 
-func isRequestValid(m *domain.User) (bool, error) {
-	validate := validator.New()
-	err := validate.Struct(m)
+// Store will store the user by given request body
+func (a *UserHandler) Store(c echo.Context) (err error) {
+	var req createUserRequest
+	err = c.Bind(&req)
 	if err != nil {
-		return false, err
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
 	}
-	return true, nil
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	user := req.toUser()
+	ctx := c.Request().Context()
+	err = a.UUsecase.Store(ctx, &user)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, newUserResponse(user))
 }
 
-// Store will store the user by given request body
-func (a *UserHandler) Store(c echo.Context) (err error) {
-	var user domain.User
-	err = c.Bind(&user)
+// UpdateProfile will change the username and email of the authenticated user
+func (a *UserHandler) UpdateProfile(c echo.Context) (err error) {
+	var req updateProfileRequest
+	err = c.Bind(&req)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, err.Error())
 	}
-
-	var ok bool
-	if ok, err = isRequestValid(&user); !ok {
+	if err = validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
-	user.CreatedAt = time.Now()
-	user.UpdatedAt = time.Now()
+
 	ctx := c.Request().Context()
-	err = a.UUsecase.Store(ctx, &user)
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	user, err := a.UUsecase.UpdateProfile(ctx, auth.UserID, req.Username, req.Email)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, user)
+	return c.JSON(http.StatusOK, newUserResponse(user))
 }
 
 // Delete will delete user by given param
@@ -120,97 +164,321 @@ func (a *UserHandler) Delete(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// Unlock will clear the login lockout of the user by given param
+func (a *UserHandler) Unlock(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = a.UUsecase.Unlock(ctx, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // Register will store the user by given request body
 func (a *UserHandler) Register(c echo.Context) (err error) {
-	var user domain.User
-	err = c.Bind(&user)
+	var req createUserRequest
+	err = c.Bind(&req)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, err.Error())
 	}
-
-	var ok bool
-	if ok, err = isRequestValid(&user); !ok {
+	if err = validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 
+	user := req.toUser()
 	ctx := c.Request().Context()
 	err = a.UUsecase.Register(ctx, &user)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, user)
+	return c.JSON(http.StatusCreated, newUserResponse(user))
 }
 
-// Login will store the user by given request body
+// Verify will mark the email of the user owning the given token as verified
+func (a *UserHandler) Verify(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: domain.ErrBadParamInput.Error()})
+	}
+
+	ctx := c.Request().Context()
+	err := a.UUsecase.Verify(ctx, token)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResendVerification will send a new verification email to the authenticated user
+func (a *UserHandler) ResendVerification(c echo.Context) error {
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	err := a.UUsecase.ResendVerification(ctx, auth.UserID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ForgotPassword will mail a password reset token. The response is the same
+// whether or not the email is registered.
+func (a *UserHandler) ForgotPassword(c echo.Context) (err error) {
+	var req forgotPasswordRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = a.UUsecase.ForgotPassword(ctx, req.Email)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ResetPassword will set a new password using a reset token
+func (a *UserHandler) ResetPassword(c echo.Context) (err error) {
+	var req resetPasswordRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	err = a.UUsecase.ResetPassword(ctx, req.Token, req.Password)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Login will authenticate the user and return a signed access token
 func (a *UserHandler) Login(c echo.Context) (err error) {
+	var req loginRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	res, err := a.UUsecase.Login(ctx, req.Username, req.Password, clientInfo(c))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
 
-	var user domain.User
-	err = c.Bind(&user)
+	return c.JSON(http.StatusOK, res)
+}
+
+// LoginMFA will complete a login challenged for a TOTP or recovery code
+func (a *UserHandler) LoginMFA(c echo.Context) (err error) {
+	var req loginMFARequest
+	err = c.Bind(&req)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, err.Error())
 	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	res, err := a.UUsecase.LoginMFA(ctx, req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// EnrollMFA will start the TOTP enrollment of the authenticated user
+func (a *UserHandler) EnrollMFA(c echo.Context) error {
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	res, err := a.UUsecase.EnrollMFA(ctx, auth.UserID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
 
-	var ok bool
-	if ok, err = isRequestValid(&user); !ok {
+// ConfirmMFA will enable TOTP for the authenticated user and return the recovery codes
+func (a *UserHandler) ConfirmMFA(c echo.Context) (err error) {
+	var req mfaCodeRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 
 	ctx := c.Request().Context()
-	user, err = a.UUsecase.Login(ctx, user.Username, user.HashedPassword)
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	codes, err := a.UUsecase.ConfirmMFA(ctx, auth.UserID, req.Code)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, user)
+	return c.JSON(http.StatusOK, map[string][]string{"recovery_codes": codes})
 }
 
-// CreateAdmin will store the user by given request body
-func (a *UserHandler) CreateAdmin(c echo.Context) (err error) {
-	var user domain.User
-	err = c.Bind(&user)
+// Refresh will rotate the given refresh token and return a new token pair
+func (a *UserHandler) Refresh(c echo.Context) (err error) {
+	var req refreshTokenRequest
+	err = c.Bind(&req)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, err.Error())
 	}
+	if err = validate.Struct(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 
-	var ok bool
-	if ok, err = isRequestValid(&user); !ok {
+	ctx := c.Request().Context()
+	res, err := a.UUsecase.Refresh(ctx, req.RefreshToken, clientInfo(c))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// Logout will revoke the session of the given refresh token
+func (a *UserHandler) Logout(c echo.Context) (err error) {
+	var req refreshTokenRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
-	if user.Role != "admin" {
+
+	ctx := c.Request().Context()
+	err = a.UUsecase.Logout(ctx, req.RefreshToken)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// FetchSessions will list the active sessions of the authenticated user
+func (a *UserHandler) FetchSessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	sessions, err := a.UUsecase.FetchSessions(ctx, auth.UserID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession will revoke one session of the authenticated user
+func (a *UserHandler) RevokeSession(c echo.Context) error {
+	idP, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+	}
+
+	ctx := c.Request().Context()
+	auth, ok := domain.AuthFromContext(ctx)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, ResponseError{Message: domain.ErrUnauthorized.Error()})
+	}
+
+	err = a.UUsecase.RevokeSession(ctx, auth.UserID, int64(idP))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func clientInfo(c echo.Context) domain.ClientInfo {
+	return domain.ClientInfo{
+		Device: c.Request().UserAgent(),
+		IP:     c.RealIP(),
+	}
+}
+
+// CreateAdmin will store the user by given request body
+func (a *UserHandler) CreateAdmin(c echo.Context) (err error) {
+	var req createUserRequest
+	err = c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err = validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
+
+	user := req.toUser()
 	ctx := c.Request().Context()
 	err = a.UUsecase.CreateAdmin(ctx, &user)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, user)
+	return c.JSON(http.StatusCreated, newUserResponse(user))
 }
 
 // CreateStaff will store the user by given request body
 func (a *UserHandler) CreateStaff(c echo.Context) (err error) {
-	var user domain.User
-	err = c.Bind(&user)
+	var req createUserRequest
+	err = c.Bind(&req)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, err.Error())
 	}
-
-	var ok bool
-	if ok, err = isRequestValid(&user); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-	if user.Role != "staff" {
+	if err = validate.Struct(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
+
+	user := req.toUser()
 	ctx := c.Request().Context()
 	err = a.UUsecase.CreateStaff(ctx, &user)
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, user)
+	return c.JSON(http.StatusCreated, newUserResponse(user))
 }
 
 func getStatusCode(err error) int {
@@ -226,6 +494,14 @@ func getStatusCode(err error) int {
 		return http.StatusNotFound
 	case domain.ErrConflict:
 		return http.StatusConflict
+	case domain.ErrBadParamInput:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized, domain.ErrInvalidToken, domain.ErrExpiredToken, domain.ErrInvalidMFACode:
+		return http.StatusUnauthorized
+	case domain.ErrForbidden, domain.ErrUnverified:
+		return http.StatusForbidden
+	case domain.ErrTooManyAttempts:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
@@ -5,9 +5,11 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alfathaulia/ca_ecommerce_api/domain"
 	"github.com/alfathaulia/ca_ecommerce_api/domain/mocks"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
 	userHttp "github.com/alfathaulia/ca_ecommerce_api/user/delivery/http"
 	"github.com/bxcodec/faker"
 	"github.com/labstack/echo/v4"
@@ -23,12 +25,14 @@ func TestFetch(t *testing.T) {
 	mockUcase := new(mocks.UserUsecase)
 	mockListUser := make([]domain.User, 0)
 	mockListUser = append(mockListUser, mockUser)
-	num := 1
-	cursor := "2"
-	mockUcase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListUser, "10", nil)
+	codec := pagination.NewCodec("cursor-secret")
+	def := domain.UserListSchema.DefaultSort
+	cursor := domain.Cursor{Sort: def, Key: time.Unix(1600000000, 0).UTC(), ID: 2}
+	prev := &domain.Cursor{Sort: def, Key: time.Unix(1600000000, 0).UTC(), ID: 3, Backward: true}
+	mockUcase.On("Fetch", mock.Anything, domain.PageRequest{Cursor: &cursor, Limit: 1, Sort: def}).Return(mockListUser, domain.PageInfo{Prev: prev}, nil)
 
 	e := echo.New()
-	req, err := http.NewRequest(echo.GET, "/users?num=1&cursor="+cursor, strings.NewReader("/"))
+	req, err := http.NewRequest(echo.GET, "/users?num=1&cursor="+codec.Encode(cursor), strings.NewReader("/"))
 	assert.NoError(t, err)
 
 	w := httptest.NewRecorder()
@@ -39,14 +43,157 @@ func TestFetch(t *testing.T) {
 	c.QueryParam("cursor")
 
 	handler := userHttp.UserHandler{
-		UUsecase: mockUcase,
+		UUsecase:  mockUcase,
+		Paginator: pagination.NewPaginator("cursor-secret", 10, 100),
 	}
 
 	err = handler.FetchUser(c)
 	require.NoError(t, err)
-	responseCursor := w.Header().Get("X-Cursor")
-	assert.Equal(t, "10", responseCursor)
+	assert.Empty(t, w.Header().Get("X-Cursor"))
+	assert.Equal(t, codec.Encode(*prev), w.Header().Get("X-Prev-Cursor"))
+	assert.Contains(t, w.Header().Get("Link"), `rel="prev"`)
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockUcase.AssertExpectations(t)
 
 }
+
+func TestFetchQuery(t *testing.T) {
+	since := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("filtered", func(t *testing.T) {
+		mockUcase := new(mocks.UserUsecase)
+		mockUcase.On("Fetch", mock.Anything, domain.PageRequest{
+			Limit: 10,
+			Sort:  domain.Sort{Field: "created_at", Desc: true},
+			Filters: []domain.Filter{
+				{Field: "created_at", Op: domain.FilterGt, Value: since},
+				{Field: "is_verified", Op: domain.FilterEq, Value: true},
+				{Field: "role", Op: domain.FilterEq, Value: "staff"},
+			},
+		}).Return([]domain.User{}, domain.PageInfo{}, nil)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/users?sort=-created_at&role=staff&is_verified=true&created_after=2021-06-01", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := userHttp.UserHandler{
+			UUsecase:  mockUcase,
+			Paginator: pagination.NewPaginator("cursor-secret", 10, 100),
+		}
+
+		err = handler.FetchUser(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockUcase := new(mocks.UserUsecase)
+
+		e := echo.New()
+		req, err := http.NewRequest(echo.GET, "/users?sort=hashed_password&is_verified=yes&password=x", strings.NewReader(""))
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := userHttp.UserHandler{
+			UUsecase:  mockUcase,
+			Paginator: pagination.NewPaginator("cursor-secret", 10, 100),
+		}
+
+		handler.FetchUser(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{
+			"message": "given param is not valid",
+			"details": [
+				{"param": "sort", "reason": "unknown field \"hashed_password\""},
+				{"param": "is_verified", "reason": "must be true or false"},
+				{"param": "password", "reason": "is not a filter of this list"}
+			]
+		}`, w.Body.String())
+		mockUcase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	var mockUser domain.User
+	err := faker.FakeData(&mockUser)
+	assert.NoError(t, err)
+	mockUcase := new(mocks.UserUsecase)
+	mockUcase.On("GetByID", mock.Anything, int64(1)).Return(mockUser, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/users/1", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c := e.NewContext(req, w)
+	c.SetPath("users/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	handler := userHttp.UserHandler{
+		UUsecase: mockUcase,
+	}
+
+	err = handler.GetByID(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), mockUser.Username)
+	assert.NotContains(t, w.Body.String(), "password")
+	mockUcase.AssertExpectations(t)
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUcase := new(mocks.UserUsecase)
+		mockUcase.On("Register", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Username == "user1" && u.HashedPassword == "Secret123"
+		})).Return(nil).Run(func(args mock.Arguments) {
+			u := args.Get(1).(*domain.User)
+			u.ID = 1
+			u.HashedPassword = "$2a$10$hash"
+		}).Once()
+
+		e := echo.New()
+		body := `{"username":"user1","email":"user1@gmail.com","password":"Secret123"}`
+		req, err := http.NewRequest(echo.POST, "/users/register", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := userHttp.UserHandler{
+			UUsecase: mockUcase,
+		}
+
+		err = handler.Register(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NotContains(t, w.Body.String(), "password")
+		assert.NotContains(t, w.Body.String(), "$2a$10$hash")
+		mockUcase.AssertExpectations(t)
+	})
+
+	t.Run("weak-password", func(t *testing.T) {
+		mockUcase := new(mocks.UserUsecase)
+
+		e := echo.New()
+		body := `{"username":"user1","email":"user1@gmail.com","password":"secret"}`
+		req, err := http.NewRequest(echo.POST, "/users/register", strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		w := httptest.NewRecorder()
+		c := e.NewContext(req, w)
+		handler := userHttp.UserHandler{
+			UUsecase: mockUcase,
+		}
+
+		err = handler.Register(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUcase.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+	})
+}
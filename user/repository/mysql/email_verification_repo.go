@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlEmailVerificationRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlEmailVerificationRepo will create an object that represent the domain.EmailVerificationRepository interface
+func NewMysqlEmailVerificationRepo(DB *sql.DB) domain.EmailVerificationRepository {
+	return &mysqlEmailVerificationRepo{DB: DB}
+}
+
+func (m *mysqlEmailVerificationRepo) Store(ctx context.Context, v *domain.EmailVerification) (err error) {
+	query := `INSERT  email_verification SET user_id=? , email=? , token_hash=? , is_used=? , expires_at=? , created_at=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, v.UserID, v.Email, v.TokenHash, v.IsUsed, v.ExpiresAt, v.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	v.ID = lastID
+	return
+}
+
+func (m *mysqlEmailVerificationRepo) GetByHash(ctx context.Context, tokenHash string) (res domain.EmailVerification, err error) {
+	query := `SELECT id, user_id, email, token_hash, is_used, expires_at, created_at
+  						FROM email_verification WHERE token_hash = ?`
+
+	err = m.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&res.ID,
+		&res.UserID,
+		&res.Email,
+		&res.TokenHash,
+		&res.IsUsed,
+		&res.ExpiresAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.EmailVerification{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.EmailVerification{}, err
+	}
+	return
+}
+
+// MarkUsed flags the token as consumed. It only succeeds once per token.
+func (m *mysqlEmailVerificationRepo) MarkUsed(ctx context.Context, id int64) (err error) {
+	query := `UPDATE  email_verification SET is_used=1 WHERE id=? AND is_used=0`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
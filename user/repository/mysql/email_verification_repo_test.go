@@ -0,0 +1,67 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	userMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/user/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var emailVerification = &domain.EmailVerification{
+	ID:        1,
+	UserID:    1,
+	Email:     "user1@gmail.com",
+	TokenHash: "hash-1",
+	ExpiresAt: now.Add(time.Hour),
+	CreatedAt: now,
+}
+
+func TestStoreEmailVerification(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  email_verification SET user_id=\\? , email=\\? , token_hash=\\? , is_used=\\? , expires_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(emailVerification.UserID, emailVerification.Email, emailVerification.TokenHash, false, emailVerification.ExpiresAt, emailVerification.CreatedAt).WillReturnResult(sqlmock.NewResult(3, 1))
+
+	a := userMysqlRepo.NewMysqlEmailVerificationRepo(db)
+	tmp := *emailVerification
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tmp.ID)
+}
+
+func TestGetEmailVerificationByHash(t *testing.T) {
+	db, mock := NewMock()
+	columns := []string{"id", "user_id", "email", "token_hash", "is_used", "expires_at", "created_at"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(emailVerification.ID, emailVerification.UserID, emailVerification.Email, emailVerification.TokenHash, false, emailVerification.ExpiresAt, emailVerification.CreatedAt)
+
+	query := `SELECT id, user_id, email, token_hash, is_used, expires_at, created_at FROM email_verification WHERE token_hash = \?`
+	mock.ExpectQuery(query).WithArgs(emailVerification.TokenHash).WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(sqlmock.NewRows(columns))
+
+	a := userMysqlRepo.NewMysqlEmailVerificationRepo(db)
+	res, err := a.GetByHash(context.TODO(), emailVerification.TokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, emailVerification.UserID, res.UserID)
+	assert.Equal(t, emailVerification.Email, res.Email)
+
+	_, err = a.GetByHash(context.TODO(), "missing")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestMarkEmailVerificationUsed(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  email_verification SET is_used=1 WHERE id=\\? AND is_used=0"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(emailVerification.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(emailVerification.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := userMysqlRepo.NewMysqlEmailVerificationRepo(db)
+	assert.NoError(t, a.MarkUsed(context.TODO(), emailVerification.ID))
+	assert.Equal(t, domain.ErrConflict, a.MarkUsed(context.TODO(), emailVerification.ID))
+}
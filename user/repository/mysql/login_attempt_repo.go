@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+)
+
+type mysqlLoginAttemptRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlLoginAttemptRepo will create an object that represent the domain.LoginAttemptRepository interface
+func NewMysqlLoginAttemptRepo(DB *sql.DB) domain.LoginAttemptRepository {
+	return &mysqlLoginAttemptRepo{DB: DB}
+}
+
+func (m *mysqlLoginAttemptRepo) Store(ctx context.Context, a *domain.LoginAttempt) (err error) {
+	query := `INSERT  login_attempt SET username=? , client_ip=? , reason=? , created_at=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, a.Username, a.ClientIP, a.Reason, a.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	a.ID = lastID
+	return
+}
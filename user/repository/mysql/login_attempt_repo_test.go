@@ -0,0 +1,30 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	userMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/user/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+func TestStoreLoginAttempt(t *testing.T) {
+	db, mock := NewMock()
+	attempt := &domain.LoginAttempt{
+		Username:  "user1",
+		ClientIP:  "10.0.0.1",
+		Reason:    domain.LoginFailureWrongPassword,
+		CreatedAt: now,
+	}
+
+	query := "INSERT  login_attempt SET username=\\? , client_ip=\\? , reason=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(attempt.Username, attempt.ClientIP, attempt.Reason, attempt.CreatedAt).WillReturnResult(sqlmock.NewResult(9, 1))
+
+	a := userMysqlRepo.NewMysqlLoginAttemptRepo(db)
+	err := a.Store(context.TODO(), attempt)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), attempt.ID)
+}
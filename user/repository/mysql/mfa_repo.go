@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlMFARepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlMFARepo will create an object that represent the domain.MFARepository interface
+func NewMysqlMFARepo(DB *sql.DB) domain.MFARepository {
+	return &mysqlMFARepo{DB: DB}
+}
+
+func (m *mysqlMFARepo) GetByUserID(ctx context.Context, userID int64) (res domain.MFA, err error) {
+	query := `SELECT user_id, secret, is_enabled, last_used_step, updated_at, created_at
+  						FROM user_mfa WHERE user_id = ?`
+
+	err = m.DB.QueryRowContext(ctx, query, userID).Scan(
+		&res.UserID,
+		&res.Secret,
+		&res.IsEnabled,
+		&res.LastUsedStep,
+		&res.UpdatedAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.MFA{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.MFA{}, err
+	}
+	return
+}
+
+// Upsert replaces the pending secret of the user, re-enrolling disables TOTP until confirmed
+func (m *mysqlMFARepo) Upsert(ctx context.Context, data *domain.MFA) (err error) {
+	query := `INSERT INTO user_mfa (user_id, secret, is_enabled, updated_at, created_at) VALUES (?, ?, ?, ?, ?)
+  						ON DUPLICATE KEY UPDATE secret=VALUES(secret), is_enabled=VALUES(is_enabled), updated_at=VALUES(updated_at)`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	data.UpdatedAt = now
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = now
+	}
+	_, err = stmt.ExecContext(ctx, data.UserID, data.Secret, data.IsEnabled, data.UpdatedAt, data.CreatedAt)
+	return
+}
+
+func (m *mysqlMFARepo) Enable(ctx context.Context, userID int64) (err error) {
+	query := `UPDATE  user_mfa SET is_enabled=1, updated_at=? WHERE user_id=?`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, time.Now(), userID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
+
+// StoreRecoveryCodes replaces every recovery code of the user
+func (m *mysqlMFARepo) StoreRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) (err error) {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				logrus.Error(errRollback)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM mfa_recovery_code WHERE user_id = ?`, userID)
+	if err != nil {
+		return
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT  mfa_recovery_code SET user_id=? , code_hash=? , is_used=0 , created_at=?`)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	for _, hash := range codeHashes {
+		if _, err = stmt.ExecContext(ctx, userID, hash, now); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// UseRecoveryCode consumes one recovery code, it returns domain.ErrNotFound for unknown or used codes
+func (m *mysqlMFARepo) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (err error) {
+	query := `UPDATE  mfa_recovery_code SET is_used=1 WHERE user_id=? AND code_hash=? AND is_used=0`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, userID, codeHash)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrNotFound
+		return
+	}
+	return
+}
+
+// UseStep records the time step of an accepted TOTP code, it returns
+// domain.ErrNotFound when a code of that step or a later one was already used
+func (m *mysqlMFARepo) UseStep(ctx context.Context, userID int64, step int64) (err error) {
+	query := `UPDATE  user_mfa SET last_used_step=? WHERE user_id=? AND last_used_step < ?`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, step, userID, step)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrNotFound
+		return
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	userMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/user/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+func TestGetMFAByUserID(t *testing.T) {
+	db, mock := NewMock()
+	columns := []string{"user_id", "secret", "is_enabled", "last_used_step", "updated_at", "created_at"}
+	rows := sqlmock.NewRows(columns).AddRow(1, "JBSWY3DPEHPK3PXP", true, 56000000, now, now)
+
+	query := `SELECT user_id, secret, is_enabled, last_used_step, updated_at, created_at FROM user_mfa WHERE user_id = \?`
+	mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs(2).WillReturnRows(sqlmock.NewRows(columns))
+
+	a := userMysqlRepo.NewMysqlMFARepo(db)
+	res, err := a.GetByUserID(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.True(t, res.IsEnabled)
+	assert.Equal(t, int64(56000000), res.LastUsedStep)
+
+	_, err = a.GetByUserID(context.TODO(), 2)
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestUpsertMFA(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `INSERT INTO user_mfa \(user_id, secret, is_enabled, updated_at, created_at\) VALUES \(\?, \?, \?, \?, \?\) ON DUPLICATE KEY UPDATE`
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(1, "JBSWY3DPEHPK3PXP", false, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := userMysqlRepo.NewMysqlMFARepo(db)
+	err := a.Upsert(context.TODO(), &domain.MFA{UserID: 1, Secret: "JBSWY3DPEHPK3PXP"})
+	assert.NoError(t, err)
+}
+
+func TestStoreRecoveryCodes(t *testing.T) {
+	db, mock := NewMock()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM mfa_recovery_code WHERE user_id = \?`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 10))
+	prep := mock.ExpectPrepare(`INSERT  mfa_recovery_code SET user_id=\? , code_hash=\? , is_used=0 , created_at=\?`)
+	prep.ExpectExec().WithArgs(1, "hash-1", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	prep.ExpectExec().WithArgs(1, "hash-2", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	a := userMysqlRepo.NewMysqlMFARepo(db)
+	err := a.StoreRecoveryCodes(context.TODO(), 1, []string{"hash-1", "hash-2"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `UPDATE  mfa_recovery_code SET is_used=1 WHERE user_id=\? AND code_hash=\? AND is_used=0`
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(1, "hash-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(1, "hash-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := userMysqlRepo.NewMysqlMFARepo(db)
+	assert.NoError(t, a.UseRecoveryCode(context.TODO(), 1, "hash-1"))
+	assert.Equal(t, domain.ErrNotFound, a.UseRecoveryCode(context.TODO(), 1, "hash-1"))
+}
+
+func TestUseStep(t *testing.T) {
+	db, mock := NewMock()
+
+	query := `UPDATE  user_mfa SET last_used_step=\? WHERE user_id=\? AND last_used_step < \?`
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(56000000, 1, 56000000).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(56000000, 1, 56000000).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := userMysqlRepo.NewMysqlMFARepo(db)
+	assert.NoError(t, a.UseStep(context.TODO(), 1, 56000000))
+	assert.Equal(t, domain.ErrNotFound, a.UseStep(context.TODO(), 1, 56000000))
+}
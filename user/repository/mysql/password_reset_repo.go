@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlPasswordResetRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlPasswordResetRepo will create an object that represent the domain.PasswordResetRepository interface
+func NewMysqlPasswordResetRepo(DB *sql.DB) domain.PasswordResetRepository {
+	return &mysqlPasswordResetRepo{DB: DB}
+}
+
+func (m *mysqlPasswordResetRepo) Store(ctx context.Context, r *domain.PasswordReset) (err error) {
+	query := `INSERT  password_reset SET user_id=? , token_hash=? , is_used=? , expires_at=? , created_at=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, r.UserID, r.TokenHash, r.IsUsed, r.ExpiresAt, r.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	r.ID = lastID
+	return
+}
+
+func (m *mysqlPasswordResetRepo) GetByHash(ctx context.Context, tokenHash string) (res domain.PasswordReset, err error) {
+	query := `SELECT id, user_id, token_hash, is_used, expires_at, created_at
+  						FROM password_reset WHERE token_hash = ?`
+
+	err = m.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&res.ID,
+		&res.UserID,
+		&res.TokenHash,
+		&res.IsUsed,
+		&res.ExpiresAt,
+		&res.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return domain.PasswordReset{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return domain.PasswordReset{}, err
+	}
+	return
+}
+
+// MarkUsed flags the token as consumed. It only succeeds once per token.
+func (m *mysqlPasswordResetRepo) MarkUsed(ctx context.Context, id int64) (err error) {
+	query := `UPDATE  password_reset SET is_used=1 WHERE id=? AND is_used=0`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
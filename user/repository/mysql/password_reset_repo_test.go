@@ -0,0 +1,65 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	userMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/user/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var passwordReset = &domain.PasswordReset{
+	ID:        1,
+	UserID:    1,
+	TokenHash: "hash-1",
+	ExpiresAt: now.Add(time.Hour),
+	CreatedAt: now,
+}
+
+func TestStorePasswordReset(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  password_reset SET user_id=\\? , token_hash=\\? , is_used=\\? , expires_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(passwordReset.UserID, passwordReset.TokenHash, false, passwordReset.ExpiresAt, passwordReset.CreatedAt).WillReturnResult(sqlmock.NewResult(3, 1))
+
+	a := userMysqlRepo.NewMysqlPasswordResetRepo(db)
+	tmp := *passwordReset
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), tmp.ID)
+}
+
+func TestGetPasswordResetByHash(t *testing.T) {
+	db, mock := NewMock()
+	columns := []string{"id", "user_id", "token_hash", "is_used", "expires_at", "created_at"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(passwordReset.ID, passwordReset.UserID, passwordReset.TokenHash, false, passwordReset.ExpiresAt, passwordReset.CreatedAt)
+
+	query := `SELECT id, user_id, token_hash, is_used, expires_at, created_at FROM password_reset WHERE token_hash = \?`
+	mock.ExpectQuery(query).WithArgs(passwordReset.TokenHash).WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(sqlmock.NewRows(columns))
+
+	a := userMysqlRepo.NewMysqlPasswordResetRepo(db)
+	res, err := a.GetByHash(context.TODO(), passwordReset.TokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, passwordReset.UserID, res.UserID)
+
+	_, err = a.GetByHash(context.TODO(), "missing")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestMarkPasswordResetUsed(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  password_reset SET is_used=1 WHERE id=\\? AND is_used=0"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(passwordReset.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(passwordReset.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := userMysqlRepo.NewMysqlPasswordResetRepo(db)
+	assert.NoError(t, a.MarkUsed(context.TODO(), passwordReset.ID))
+	assert.Equal(t, domain.ErrConflict, a.MarkUsed(context.TODO(), passwordReset.ID))
+}
@@ -0,0 +1,163 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	"github.com/sirupsen/logrus"
+)
+
+type mysqlRefreshTokenRepo struct {
+	DB *sql.DB
+}
+
+// NewMysqlRefreshTokenRepo will create an object that represent the domain.RefreshTokenRepository interface
+func NewMysqlRefreshTokenRepo(DB *sql.DB) domain.RefreshTokenRepository {
+	return &mysqlRefreshTokenRepo{DB: DB}
+}
+
+func (m *mysqlRefreshTokenRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.RefreshToken, err error) {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.RefreshToken, 0)
+	for rows.Next() {
+		t := domain.RefreshToken{}
+		err = rows.Scan(
+			&t.ID,
+			&t.UserID,
+			&t.FamilyID,
+			&t.TokenHash,
+			&t.Device,
+			&t.ClientIP,
+			&t.IsUsed,
+			&t.IsRevoked,
+			&t.ExpiresAt,
+			&t.LastSeenAt,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func (m *mysqlRefreshTokenRepo) getOne(ctx context.Context, query string, args ...interface{}) (res domain.RefreshToken, err error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.RefreshToken{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *mysqlRefreshTokenRepo) Store(ctx context.Context, t *domain.RefreshToken) (err error) {
+	query := `INSERT  refresh_token SET user_id=? , family_id=? , token_hash=? , device=? , client_ip=? , is_used=? , is_revoked=? , expires_at=? , last_seen_at=? , created_at=?`
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, t.UserID, t.FamilyID, t.TokenHash, t.Device, t.ClientIP, t.IsUsed, t.IsRevoked, t.ExpiresAt, t.LastSeenAt, t.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	t.ID = lastID
+	return
+}
+
+func (m *mysqlRefreshTokenRepo) GetByID(ctx context.Context, id int64) (domain.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, device, client_ip, is_used, is_revoked, expires_at, last_seen_at, created_at
+  						FROM refresh_token WHERE id = ?`
+	return m.getOne(ctx, query, id)
+}
+
+func (m *mysqlRefreshTokenRepo) GetByHash(ctx context.Context, tokenHash string) (domain.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, device, client_ip, is_used, is_revoked, expires_at, last_seen_at, created_at
+  						FROM refresh_token WHERE token_hash = ?`
+	return m.getOne(ctx, query, tokenHash)
+}
+
+func (m *mysqlRefreshTokenRepo) FetchActiveByUser(ctx context.Context, userID int64) ([]domain.RefreshToken, error) {
+	query := `SELECT id, user_id, family_id, token_hash, device, client_ip, is_used, is_revoked, expires_at, last_seen_at, created_at
+  						FROM refresh_token WHERE user_id = ? AND is_used = 0 AND is_revoked = 0 AND expires_at > NOW() ORDER BY last_seen_at DESC`
+	return m.fetch(ctx, query, userID)
+}
+
+// MarkUsed flags the token as rotated. It only succeeds once per token, so two
+// concurrent refreshes with the same token can not both rotate it.
+func (m *mysqlRefreshTokenRepo) MarkUsed(ctx context.Context, id int64) (err error) {
+	query := `UPDATE  refresh_token SET is_used=1 WHERE id=? AND is_used=0 AND is_revoked=0`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = domain.ErrConflict
+		return
+	}
+	return
+}
+
+func (m *mysqlRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID string) (err error) {
+	query := `UPDATE  refresh_token SET is_revoked=1 WHERE family_id=?`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, familyID)
+	if err != nil {
+		err = fmt.Errorf("revoke refresh token family: %w", err)
+	}
+	return
+}
+
+func (m *mysqlRefreshTokenRepo) RevokeByUser(ctx context.Context, userID int64) (err error) {
+	query := `UPDATE  refresh_token SET is_revoked=1 WHERE user_id=? AND is_revoked=0`
+
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	_, err = stmt.ExecContext(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("revoke refresh tokens of user: %w", err)
+	}
+	return
+}
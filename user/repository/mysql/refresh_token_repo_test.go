@@ -0,0 +1,96 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alfathaulia/ca_ecommerce_api/domain"
+	userMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/user/repository/mysql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v2"
+)
+
+var refreshToken = &domain.RefreshToken{
+	ID:         1,
+	UserID:     1,
+	FamilyID:   "family-1",
+	TokenHash:  "hash-1",
+	Device:     "okhttp/4.9",
+	ClientIP:   "10.0.0.1",
+	ExpiresAt:  now.Add(time.Hour),
+	LastSeenAt: now,
+	CreatedAt:  now,
+}
+
+var refreshTokenColumns = []string{"id", "user_id", "family_id", "token_hash", "device", "client_ip", "is_used", "is_revoked", "expires_at", "last_seen_at", "created_at"}
+
+func TestStoreRefreshToken(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  refresh_token SET user_id=\\? , family_id=\\? , token_hash=\\? , device=\\? , client_ip=\\? , is_used=\\? , is_revoked=\\? , expires_at=\\? , last_seen_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(refreshToken.UserID, refreshToken.FamilyID, refreshToken.TokenHash, refreshToken.Device, refreshToken.ClientIP, false, false, refreshToken.ExpiresAt, refreshToken.LastSeenAt, refreshToken.CreatedAt).WillReturnResult(sqlmock.NewResult(5, 1))
+
+	a := userMysqlRepo.NewMysqlRefreshTokenRepo(db)
+	tmp := *refreshToken
+	err := a.Store(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), tmp.ID)
+}
+
+func TestGetRefreshTokenByHash(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows(refreshTokenColumns).
+		AddRow(refreshToken.ID, refreshToken.UserID, refreshToken.FamilyID, refreshToken.TokenHash, refreshToken.Device, refreshToken.ClientIP, false, false, refreshToken.ExpiresAt, refreshToken.LastSeenAt, refreshToken.CreatedAt)
+
+	query := `SELECT id, user_id, family_id, token_hash, device, client_ip, is_used, is_revoked, expires_at, last_seen_at, created_at FROM refresh_token WHERE token_hash = \?`
+	mock.ExpectQuery(query).WithArgs(refreshToken.TokenHash).WillReturnRows(rows)
+
+	a := userMysqlRepo.NewMysqlRefreshTokenRepo(db)
+	res, err := a.GetByHash(context.TODO(), refreshToken.TokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, refreshToken.FamilyID, res.FamilyID)
+	assert.Equal(t, refreshToken.ClientIP, res.ClientIP)
+
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(sqlmock.NewRows(refreshTokenColumns))
+	_, err = a.GetByHash(context.TODO(), "missing")
+	assert.Equal(t, domain.ErrNotFound, err)
+}
+
+func TestMarkRefreshTokenUsed(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  refresh_token SET is_used=1 WHERE id=\\? AND is_used=0 AND is_revoked=0"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(refreshToken.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(refreshToken.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := userMysqlRepo.NewMysqlRefreshTokenRepo(db)
+	err := a.MarkUsed(context.TODO(), refreshToken.ID)
+	assert.NoError(t, err)
+
+	err = a.MarkUsed(context.TODO(), refreshToken.ID)
+	assert.Equal(t, domain.ErrConflict, err)
+}
+
+func TestRevokeRefreshTokenFamily(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  refresh_token SET is_revoked=1 WHERE family_id=\\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(refreshToken.FamilyID).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	a := userMysqlRepo.NewMysqlRefreshTokenRepo(db)
+	err := a.RevokeFamily(context.TODO(), refreshToken.FamilyID)
+	assert.NoError(t, err)
+}
+
+func TestRevokeRefreshTokensByUser(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  refresh_token SET is_revoked=1 WHERE user_id=\\? AND is_revoked=0"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(refreshToken.UserID).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	a := userMysqlRepo.NewMysqlRefreshTokenRepo(db)
+	err := a.RevokeByUser(context.TODO(), refreshToken.UserID)
+	assert.NoError(t, err)
+}
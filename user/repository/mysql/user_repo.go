@@ -4,12 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/alfathaulia/ca_ecommerce_api/domain"
-	"github.com/alfathaulia/ca_ecommerce_api/user/repository"
+	"github.com/alfathaulia/ca_ecommerce_api/pagination"
+	"github.com/alfathaulia/ca_ecommerce_api/transaction"
 	"github.com/sirupsen/logrus"
 )
 
+// userKeyset holds the columns of the fields of domain.UserListSchema
+var userKeyset = pagination.NewKeyset(map[string]string{
+	"username":    "username",
+	"email":       "email",
+	"role":        "role",
+	"is_verified": "is_verified",
+	"updated_at":  "updated_at",
+	"created_at":  "created_at",
+}, domain.UserListSchema.DefaultSort)
+
 type mysqlUserRepo struct {
 	DB *sql.DB
 }
@@ -20,7 +32,7 @@ func NewMysqlUserRepo(DB *sql.DB) domain.UserRepository {
 }
 
 func (m *mysqlUserRepo) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.User, err error) {
-	rows, err := m.DB.QueryContext(ctx, query, args...)
+	rows, err := transaction.Conn(ctx, m.DB).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -54,22 +66,35 @@ func (m *mysqlUserRepo) fetch(ctx context.Context, query string, args ...interfa
 	return result, nil
 }
 
-func (m *mysqlUserRepo) Fetch(ctx context.Context, cursor string, num int64) (res []domain.User, nextCursor string, err error) {
-	query := `SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at FROM user ORDER BY created_at LIMIT ?`
-
-	decodeCursor, err := repository.DecodeCursor(cursor)
-	if err != nil && cursor != "" {
-		return nil, "", domain.ErrBadParamInput
+func (m *mysqlUserRepo) Fetch(ctx context.Context, page domain.PageRequest) (res []domain.User, info domain.PageInfo, err error) {
+	query, args, err := userKeyset.Query(`SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at FROM user`, "", page)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
 	}
-	res, err = m.fetch(ctx, query, decodeCursor, num)
+
+	res, err = m.fetch(ctx, query, args...)
 	if err != nil {
-		return nil, "", err
+		return nil, domain.PageInfo{}, err
 	}
-	if len(res) == int(num) {
-		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	from, to, info := userKeyset.Window(page, len(res), func(i int, field string) (interface{}, int64) {
+		return userSortKey(res[i], field), res[i].ID
+	})
+	return res[from:to], info, nil
+}
+
+// userSortKey is the value of the sortable field of u
+func userSortKey(u domain.User, field string) interface{} {
+	switch field {
+	case "username":
+		return u.Username
+	case "email":
+		return u.Email
+	case "updated_at":
+		return u.UpdatedAt
 	}
-	return
+	return u.CreatedAt
 }
+
 func (m *mysqlUserRepo) GetByID(ctx context.Context, id int64) (res domain.User, err error) {
 	query := `SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at
   						FROM user WHERE ID = ?`
@@ -103,9 +128,25 @@ func (m *mysqlUserRepo) GetByUsername(ctx context.Context, username string) (res
 	}
 	return
 }
+func (m *mysqlUserRepo) GetByEmail(ctx context.Context, email string) (res domain.User, err error) {
+	query := `SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at
+  						FROM user WHERE email = ?`
+
+	list, err := m.fetch(ctx, query, email)
+	if err != nil {
+		return res, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
 func (m *mysqlUserRepo) Store(ctx context.Context, data *domain.User) (err error) {
 	query := `INSERT  user SET username=? , email=? , hashed_password=?, role=?, is_verified=?, updated_at=? , created_at=?`
-	stmt, err := m.DB.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
@@ -124,7 +165,7 @@ func (m *mysqlUserRepo) Store(ctx context.Context, data *domain.User) (err error
 func (m *mysqlUserRepo) Delete(ctx context.Context, id int64) (err error) {
 	query := "DELETE FROM user WHERE id = ?"
 
-	stmt, err := m.DB.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
@@ -147,14 +188,14 @@ func (m *mysqlUserRepo) Delete(ctx context.Context, id int64) (err error) {
 	return
 }
 func (m *mysqlUserRepo) Update(ctx context.Context, dataUpdate *domain.User) (err error) {
-	query := `UPDATE  user SET username=? , email=? , hashed_password=?, role=?, updated_at=? WHERE id=?`
+	query := `UPDATE  user SET username=? , email=? , hashed_password=?, role=?, is_verified=?, updated_at=? WHERE id=?`
 
-	stmt, err := m.DB.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, dataUpdate.Username, dataUpdate.Email, dataUpdate.HashedPassword, dataUpdate.Role, dataUpdate.UpdatedAt, dataUpdate.ID)
+	res, err := stmt.ExecContext(ctx, dataUpdate.Username, dataUpdate.Email, dataUpdate.HashedPassword, dataUpdate.Role, dataUpdate.IsVerified, dataUpdate.UpdatedAt, dataUpdate.ID)
 	if err != nil {
 		return
 	}
@@ -172,12 +213,12 @@ func (m *mysqlUserRepo) Update(ctx context.Context, dataUpdate *domain.User) (er
 
 func (m *mysqlUserRepo) Register(ctx context.Context, users *domain.User) (err error) {
 	query := `INSERT  user SET username=? , email=? , hashed_password=?, role=?, is_verified=?, updated_at=? , created_at=?`
-	stmt, err := m.DB.PrepareContext(ctx, query)
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, users.Username, users.Email, users.HashedPassword, "user", users.UpdatedAt, users.CreatedAt)
+	res, err := stmt.ExecContext(ctx, users.Username, users.Email, users.HashedPassword, domain.RolesTypeUser, false, users.UpdatedAt, users.CreatedAt)
 	if err != nil {
 		return
 	}
@@ -190,6 +231,52 @@ func (m *mysqlUserRepo) Register(ctx context.Context, users *domain.User) (err e
 	return
 }
 
+func (m *mysqlUserRepo) UpdatePassword(ctx context.Context, id int64, hashedPassword string) (err error) {
+	query := `UPDATE  user SET hashed_password=?, updated_at=? WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, hashedPassword, time.Now(), id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
+
+func (m *mysqlUserRepo) MarkVerified(ctx context.Context, id int64) (err error) {
+	query := `UPDATE  user SET is_verified=1 WHERE id=?`
+
+	stmt, err := transaction.Conn(ctx, m.DB).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect > 1 {
+		err = fmt.Errorf("weird  behavior. total affected: %d", affect)
+		return
+	}
+	return
+}
+
 func (m *mysqlUserRepo) Login(ctx context.Context, username string, password string) (res domain.User, err error) {
 	// query := `SELECT id, username, unit_code, hashed_password, role, updated_at, created_at FROM user WHERE username = ? `
 
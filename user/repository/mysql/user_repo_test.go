@@ -7,13 +7,14 @@ import (
 	"time"
 
 	"github.com/alfathaulia/ca_ecommerce_api/domain"
-	"github.com/alfathaulia/ca_ecommerce_api/user/repository"
 	userMysqlRepo "github.com/alfathaulia/ca_ecommerce_api/user/repository/mysql"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/DATA-DOG/go-sqlmock.v2"
 )
 
+var now = time.Now()
+
 var user = &domain.User{
 	ID:             1,
 	Username:       "user1",
@@ -21,8 +22,8 @@ var user = &domain.User{
 	HashedPassword: "haspass",
 	IsVerified:     true,
 	Role:           "user",
-	UpdatedAt:      time.Now(),
-	CreatedAt:      time.Now(),
+	UpdatedAt:      now,
+	CreatedAt:      now,
 }
 
 func NewMock() (*sql.DB, sqlmock.Sqlmock) {
@@ -42,24 +43,27 @@ func TestFetch(t *testing.T) {
 			ID: user.ID, Username: user.Username, Email: user.Email, HashedPassword: user.HashedPassword, Role: user.Role, IsVerified: user.IsVerified, CreatedAt: user.CreatedAt, UpdatedAt: user.UpdatedAt,
 		},
 		{
-			ID: 2, Username: "User 2", Email: "122456", HashedPassword: "user2", Role: "user", IsVerified: true, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+			ID: 2, Username: "User 2", Email: "122456", HashedPassword: "user2", Role: "user", IsVerified: true, CreatedAt: now, UpdatedAt: now,
+		},
+		{
+			ID: 3, Username: "User 3", Email: "user3@gmail.com", HashedPassword: "user3", Role: "user", IsVerified: true, CreatedAt: now.Add(time.Second), UpdatedAt: now,
 		},
 	}
-	rows := sqlmock.NewRows([]string{"id", "username", "email", "hashed_password", "role", "is_verified", "updated_at", "created_at"}).
-		AddRow(mockUsers[0].ID, mockUsers[0].Username, mockUsers[0].Email, mockUsers[0].HashedPassword, mockUsers[0].Role, mockUsers[0].IsVerified, mockUsers[0].UpdatedAt, mockUsers[0].CreatedAt).
-		AddRow(mockUsers[1].ID, mockUsers[1].Username, mockUsers[1].Email, mockUsers[1].HashedPassword, mockUsers[1].Role, mockUsers[1].IsVerified, mockUsers[1].UpdatedAt, mockUsers[1].CreatedAt)
-
-	query := `SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at FROM user  ORDER BY created_at LIMIT ?`
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "hashed_password", "role", "is_verified", "updated_at", "created_at"})
+	for _, u := range mockUsers {
+		rows.AddRow(u.ID, u.Username, u.Email, u.HashedPassword, u.Role, u.IsVerified, u.UpdatedAt, u.CreatedAt)
+	}
 
-	mock.ExpectQuery(query).WillReturnRows(rows)
+	cursor := &domain.Cursor{Sort: domain.Sort{Field: "created_at"}, Key: now.Add(-time.Hour), ID: 7}
+	query := `SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at FROM user WHERE \(created_at > \? OR \(created_at = \? AND id > \?\)\) ORDER BY created_at, id LIMIT \?`
+	mock.ExpectQuery(query).WithArgs(cursor.Key, cursor.Key, cursor.ID, int64(3)).WillReturnRows(rows)
 
 	a := userMysqlRepo.NewMysqlUserRepo(db)
-	cursor := repository.EncodeCursor(mockUsers[1].CreatedAt)
-	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
+	list, info, err := a.Fetch(context.TODO(), domain.PageRequest{Cursor: cursor, Limit: 2})
 	assert.NoError(t, err)
-	assert.NotEmpty(t, nextCursor)
 	assert.Len(t, list, 2)
+	assert.Equal(t, &domain.Cursor{Sort: cursor.Sort, Key: now, ID: 2}, info.Next)
+	assert.Equal(t, &domain.Cursor{Sort: cursor.Sort, Key: user.CreatedAt, ID: user.ID, Backward: true}, info.Prev)
 
 	assert.Equal(t, user.Username, list[0].Username)
 	assert.Equal(t, user.HashedPassword, list[0].HashedPassword)
@@ -69,7 +73,31 @@ func TestFetch(t *testing.T) {
 	assert.Equal(t, user.CreatedAt, list[0].CreatedAt)
 	assert.Equal(t, user.UpdatedAt, list[0].UpdatedAt)
 	assert.NotZero(t, list[0].CreatedAt)
+}
+
+func TestFetchFiltered(t *testing.T) {
+	db, mock := NewMock()
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "hashed_password", "role", "is_verified", "updated_at", "created_at"}).
+		AddRow(4, "staff", "staff@gmail.com", "staff", "staff", true, now, now)
+
+	since := now.Add(-24 * time.Hour)
+	query := `SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at FROM user WHERE role = \? AND is_verified = \? AND created_at > \? ORDER BY created_at DESC, id DESC LIMIT \?`
+	mock.ExpectQuery(query).WithArgs("staff", true, since, int64(11)).WillReturnRows(rows)
 
+	a := userMysqlRepo.NewMysqlUserRepo(db)
+	list, info, err := a.Fetch(context.TODO(), domain.PageRequest{
+		Limit: 10,
+		Sort:  domain.Sort{Field: "created_at", Desc: true},
+		Filters: []domain.Filter{
+			{Field: "role", Op: domain.FilterEq, Value: "staff"},
+			{Field: "is_verified", Op: domain.FilterEq, Value: true},
+			{Field: "created_at", Op: domain.FilterGt, Value: since},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, domain.PageInfo{}, info)
+	assert.Equal(t, domain.RolesTypeStaff, list[0].Role)
 }
 
 func TestGetByID(t *testing.T) {
@@ -128,9 +156,9 @@ func TestUpdate(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "UPDATE  user SET username=\\? , email=\\? , hashed_password=\\?, role=\\?, updated_at=\\?  WHERE id=\\?"
+	query := "UPDATE  user SET username=\\? , email=\\? , hashed_password=\\?, role=\\?, is_verified=\\?, updated_at=\\?  WHERE id=\\?"
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(user.Username, user.Email, user.HashedPassword, user.Role, user.UpdatedAt, user.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(user.Username, user.Email, user.HashedPassword, user.Role, user.IsVerified, user.UpdatedAt, user.ID).WillReturnResult(sqlmock.NewResult(12, 1))
 
 	a := userMysqlRepo.NewMysqlUserRepo(db)
 
@@ -152,3 +180,54 @@ func TestDelete(t *testing.T) {
 	err := a.Delete(context.TODO(), num)
 	assert.NoError(t, err)
 }
+
+func TestRegister(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "INSERT  user SET username=\\? , email=\\? , hashed_password=\\?, role=\\?, is_verified=\\?, updated_at=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(user.Username, user.Email, user.HashedPassword, domain.RolesTypeUser, false, user.UpdatedAt, user.CreatedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	a := userMysqlRepo.NewMysqlUserRepo(db)
+	tmp := *user
+	err := a.Register(context.TODO(), &tmp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), tmp.ID)
+}
+
+func TestMarkVerified(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  user SET is_verified=1 WHERE id=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(user.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := userMysqlRepo.NewMysqlUserRepo(db)
+	err := a.MarkVerified(context.TODO(), user.ID)
+	assert.NoError(t, err)
+}
+
+func TestGetByEmail(t *testing.T) {
+	db, mock := NewMock()
+
+	rows := sqlmock.NewRows([]string{"id", "username", "email", "hashed_password", "role", "is_verified", "updated_at", "created_at"}).AddRow(1, user.Username, user.Email, user.HashedPassword, user.Role, user.IsVerified, user.UpdatedAt, user.CreatedAt)
+
+	query := `SELECT id, username, email, hashed_password, role, is_verified, updated_at, created_at FROM user WHERE email = \?`
+	mock.ExpectQuery(query).WithArgs(user.Email).WillReturnRows(rows)
+	a := userMysqlRepo.NewMysqlUserRepo(db)
+	res, err := a.GetByEmail(context.TODO(), user.Email)
+	assert.NoError(t, err)
+	assert.Equal(t, user.Username, res.Username)
+}
+
+func TestUpdatePassword(t *testing.T) {
+	db, mock := NewMock()
+
+	query := "UPDATE  user SET hashed_password=\\?, updated_at=\\? WHERE id=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("new-hash", sqlmock.AnyArg(), user.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := userMysqlRepo.NewMysqlUserRepo(db)
+	err := a.UpdatePassword(context.TODO(), user.ID, "new-hash")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomToken(t *testing.T) {
+	token1, err := RandomToken(32)
+	require.NoError(t, err)
+	require.NotEmpty(t, token1)
+
+	token2, err := RandomToken(32)
+	require.NoError(t, err)
+	require.NotEqual(t, token1, token2)
+
+	require.Equal(t, HashToken(token1), HashToken(token1))
+	require.NotEqual(t, HashToken(token1), HashToken(token2))
+	require.Len(t, HashToken(token1), 64)
+}
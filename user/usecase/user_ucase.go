@@ -2,40 +2,67 @@ package usecase
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/alfathaulia/ca_ecommerce_api/domain"
 	util "github.com/alfathaulia/ca_ecommerce_api/user/repository/utils"
+	"github.com/sirupsen/logrus"
 )
 
 const timeout = time.Second * 10
 
+const (
+	tokenType            = "Bearer"
+	refreshTokenDuration = time.Hour * 24 * 30
+	refreshTokenSize     = 32
+	verificationDuration = time.Hour * 24
+	verificationSize     = 32
+	passwordResetTTL     = time.Hour
+	passwordResetSize    = 32
+	mfaTokenDuration     = time.Minute * 5
+	mfaEnrollDuration    = time.Minute * 15
+)
+
 type userUsecase struct {
-	userRepo       domain.UserRepository
-	contextTimeout time.Duration
+	userRepo         domain.UserRepository
+	refreshTokenRepo domain.RefreshTokenRepository
+	verificationRepo domain.EmailVerificationRepository
+	resetRepo        domain.PasswordResetRepository
+	loginLimiter     domain.LoginLimiter
+	mfa              domain.MFAAuthenticator
+	mailer           domain.UserMailer
+	tokenMaker       domain.TokenMaker
+	contextTimeout   time.Duration
 }
 
-func NewUserUsecase(u domain.UserRepository, timeout time.Duration) domain.UserUsecase {
+func NewUserUsecase(u domain.UserRepository, rt domain.RefreshTokenRepository, ev domain.EmailVerificationRepository, pr domain.PasswordResetRepository, ll domain.LoginLimiter, ma domain.MFAAuthenticator, um domain.UserMailer, tm domain.TokenMaker, timeout time.Duration) domain.UserUsecase {
 	return &userUsecase{
-		userRepo:       u,
-		contextTimeout: timeout,
+		userRepo:         u,
+		refreshTokenRepo: rt,
+		verificationRepo: ev,
+		resetRepo:        pr,
+		loginLimiter:     ll,
+		mfa:              ma,
+		mailer:           um,
+		tokenMaker:       tm,
+		contextTimeout:   timeout,
 	}
 }
 
-func (m *userUsecase) Fetch(ctx context.Context, cursor string, num int64) (res []domain.User, nextCursor string, err error) {
-	if num == 0 {
-		num = 10
+func (m *userUsecase) Fetch(ctx context.Context, page domain.PageRequest) (res []domain.User, info domain.PageInfo, err error) {
+	if page.Limit == 0 {
+		page.Limit = 10
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
-	res, nextCursor, err = m.userRepo.Fetch(ctx, cursor, num)
+	res, info, err = m.userRepo.Fetch(ctx, page)
 	if err != nil {
-		return nil, "", err
+		return nil, domain.PageInfo{}, err
 	}
 	return
-
 }
 
 func (m *userUsecase) GetByID(ctx context.Context, id int64) (res domain.User, err error) {
@@ -66,6 +93,55 @@ func (m *userUsecase) Update(ctx context.Context, ar *domain.User) (err error) {
 	return m.userRepo.Update(ctx, ar)
 }
 
+// UpdateProfile changes the username and email of a user. A changed email
+// has to be verified again before it counts as the user's.
+func (m *userUsecase) UpdateProfile(ctx context.Context, id int64, username string, email string) (res domain.User, err error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	res, err = m.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if username != res.Username {
+		existed, err := m.userRepo.GetByUsername(ctx, username)
+		if err != nil && err != domain.ErrNotFound {
+			return domain.User{}, err
+		}
+		if err == nil && existed.ID != id {
+			return domain.User{}, domain.ErrConflict
+		}
+	}
+
+	emailChanged := !strings.EqualFold(email, res.Email)
+	if emailChanged {
+		existed, err := m.userRepo.GetByEmail(ctx, email)
+		if err != nil && err != domain.ErrNotFound {
+			return domain.User{}, err
+		}
+		if err == nil && existed.ID != id {
+			return domain.User{}, domain.ErrConflict
+		}
+		res.IsVerified = false
+	}
+
+	res.Username = username
+	res.Email = email
+	res.UpdatedAt = time.Now()
+	if err = m.userRepo.Update(ctx, &res); err != nil {
+		return domain.User{}, err
+	}
+
+	// the new email is saved at this point, a failed mail can be retried with ResendVerification
+	if emailChanged {
+		if err := m.sendVerification(ctx, res); err != nil {
+			logrus.Error(err)
+		}
+	}
+	return res, nil
+}
+
 func (m *userUsecase) Store(ctx context.Context, a *domain.User) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
@@ -73,8 +149,26 @@ func (m *userUsecase) Store(ctx context.Context, a *domain.User) (err error) {
 	if user != (domain.User{}) {
 		return domain.ErrConflict
 	}
-	err = m.userRepo.Store(ctx, a)
-	return
+	if a.Role == "" {
+		a.Role = domain.RolesTypeUser
+	}
+	return m.storeUser(ctx, a)
+}
+
+// storeUser hashes the plaintext password carried in a.HashedPassword before
+// storing the user
+func (m *userUsecase) storeUser(ctx context.Context, a *domain.User) error {
+	hashPass, err := util.HashPassword(a.HashedPassword)
+	if err != nil {
+		return err
+	}
+	a.HashedPassword = hashPass
+	now := time.Now()
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = now
+	}
+	a.UpdatedAt = now
+	return m.userRepo.Store(ctx, a)
 }
 func (m *userUsecase) Delete(ctx context.Context, id int64) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
@@ -101,25 +195,403 @@ func (m *userUsecase) Register(ctx context.Context, user *domain.User) (err erro
 	if existedArticle != (domain.User{}) {
 		return domain.ErrConflict
 	}
-	return m.userRepo.Register(ctx, user)
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	err = m.userRepo.Register(ctx, user)
+	if err != nil {
+		return err
+	}
+	user.Role = domain.RolesTypeUser
+	user.IsVerified = false
+
+	// the account exists at this point, a failed mail can be retried with ResendVerification
+	if err = m.sendVerification(ctx, *user); err != nil {
+		logrus.Error(err)
+	}
+	return nil
+}
+
+// sendVerification stores a new single-use verification token and mails it to the user
+func (m *userUsecase) sendVerification(ctx context.Context, user domain.User) error {
+	token, err := util.RandomToken(verificationSize)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	v := domain.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: now.Add(verificationDuration),
+		CreatedAt: now,
+	}
+	if err = m.verificationRepo.Store(ctx, &v); err != nil {
+		return err
+	}
+	return m.mailer.SendVerification(ctx, user, token)
+}
+
+func (m *userUsecase) Verify(ctx context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	v, err := m.verificationRepo.GetByHash(ctx, util.HashToken(token))
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if v.IsUsed {
+		return domain.ErrInvalidToken
+	}
+	if time.Now().After(v.ExpiresAt) {
+		return domain.ErrExpiredToken
+	}
+	// a token sent before the email changed does not prove the new address
+	user, err := m.userRepo.GetByID(ctx, v.UserID)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(v.Email, user.Email) {
+		return domain.ErrInvalidToken
+	}
+
+	err = m.verificationRepo.MarkUsed(ctx, v.ID)
+	if err == domain.ErrConflict {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	return m.userRepo.MarkVerified(ctx, v.UserID)
+}
+
+func (m *userUsecase) ResendVerification(ctx context.Context, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	user, err := m.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsVerified {
+		return domain.ErrConflict
+	}
+	return m.sendVerification(ctx, user)
+}
+
+// ForgotPassword mails a reset token when the email belongs to an account. It
+// reports success either way so callers can not probe for registered emails.
+func (m *userUsecase) ForgotPassword(ctx context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	user, err := m.userRepo.GetByEmail(ctx, email)
+	if err == domain.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := util.RandomToken(passwordResetSize)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	r := domain.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: now.Add(passwordResetTTL),
+		CreatedAt: now,
+	}
+	if err = m.resetRepo.Store(ctx, &r); err != nil {
+		return err
+	}
+	if err = m.mailer.SendPasswordReset(ctx, user, token); err != nil {
+		logrus.Error(err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and signs the user out of every session
+func (m *userUsecase) ResetPassword(ctx context.Context, token string, password string) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	r, err := m.resetRepo.GetByHash(ctx, util.HashToken(token))
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if r.IsUsed {
+		return domain.ErrInvalidToken
+	}
+	if time.Now().After(r.ExpiresAt) {
+		return domain.ErrExpiredToken
+	}
+
+	hashPass, err := util.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = m.resetRepo.MarkUsed(ctx, r.ID)
+	if err == domain.ErrConflict {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if err = m.userRepo.UpdatePassword(ctx, r.UserID, hashPass); err != nil {
+		return err
+	}
+	return m.refreshTokenRepo.RevokeByUser(ctx, r.UserID)
 }
 
-func (m *userUsecase) Login(ctx context.Context, username string, password string) (domain.User, error) {
+func (m *userUsecase) Login(ctx context.Context, username string, password string, client domain.ClientInfo) (domain.LoginResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	if err := m.loginLimiter.Allow(ctx, username, client.IP); err != nil {
+		return domain.LoginResponse{}, err
+	}
 	res, err := m.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		return domain.User{}, domain.ErrNotFound
+		m.loginFailed(ctx, username, client.IP, domain.LoginFailureUnknownUser)
+		return domain.LoginResponse{}, domain.ErrNotFound
 	}
 	checkPass := util.CheckPassword(password, res.HashedPassword)
 	if checkPass != nil {
-		return domain.User{}, domain.ErrBadParamInput
+		m.loginFailed(ctx, username, client.IP, domain.LoginFailureWrongPassword)
+		return domain.LoginResponse{}, domain.ErrBadParamInput
 	}
 	if res.Username != username {
-		return domain.User{}, domain.ErrInternalServerError
+		return domain.LoginResponse{}, domain.ErrInternalServerError
+	}
+	if err = m.loginLimiter.Succeed(ctx, username, client.IP); err != nil {
+		logrus.Error(err)
+	}
+
+	enabled, err := m.mfa.IsEnabled(ctx, res.ID)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	if enabled {
+		mfaToken, _, err := m.tokenMaker.CreateScopedToken(res, domain.TokenScopeMFA, mfaTokenDuration)
+		if err != nil {
+			return domain.LoginResponse{}, err
+		}
+		return domain.LoginResponse{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+	if m.mfa.IsRequired(res.Role) {
+		// only good for enrolling, the user logs in again once TOTP is confirmed
+		enrollToken, payload, err := m.tokenMaker.CreateScopedToken(res, domain.TokenScopeMFAEnroll, mfaEnrollDuration)
+		if err != nil {
+			return domain.LoginResponse{}, err
+		}
+		res.HashedPassword = ""
+		return domain.LoginResponse{
+			AccessToken:           enrollToken,
+			TokenType:             tokenType,
+			AccessTokenExpiresAt:  payload.ExpiredAt,
+			MFAEnrollmentRequired: true,
+			User:                  &res,
+		}, nil
+	}
+	return m.startSession(ctx, res, client)
+}
+
+// LoginMFA completes a login that was answered with an MFA challenge
+func (m *userUsecase) LoginMFA(ctx context.Context, mfaToken string, code string, client domain.ClientInfo) (domain.LoginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	payload, err := m.tokenMaker.VerifyToken(mfaToken)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	if payload.Scope != domain.TokenScopeMFA {
+		return domain.LoginResponse{}, domain.ErrInvalidToken
+	}
+	if err = m.loginLimiter.Allow(ctx, payload.Username, client.IP); err != nil {
+		return domain.LoginResponse{}, err
+	}
+
+	err = m.mfa.Verify(ctx, payload.UserID, code)
+	if err == domain.ErrInvalidMFACode {
+		m.loginFailed(ctx, payload.Username, client.IP, domain.LoginFailureWrongMFACode)
+		return domain.LoginResponse{}, err
+	}
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+
+	user, err := m.userRepo.GetByID(ctx, payload.UserID)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	return m.startSession(ctx, user, client)
+}
+
+func (m *userUsecase) EnrollMFA(ctx context.Context, userID int64) (domain.MFAEnrollment, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	user, err := m.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.MFAEnrollment{}, err
+	}
+	return m.mfa.Enroll(ctx, user)
+}
+
+func (m *userUsecase) ConfirmMFA(ctx context.Context, userID int64, code string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.mfa.Confirm(ctx, userID, code)
+}
+
+// startSession issues the tokens of a new session for a fully authenticated user
+func (m *userUsecase) startSession(ctx context.Context, user domain.User, client domain.ClientInfo) (domain.LoginResponse, error) {
+	familyID, err := util.RandomToken(16)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	return m.issueTokens(ctx, user, familyID, client)
+}
+
+func (m *userUsecase) loginFailed(ctx context.Context, username string, ip string, reason string) {
+	if err := m.loginLimiter.Fail(ctx, username, ip, reason); err != nil {
+		logrus.Error(err)
+	}
+}
+
+// Unlock clears the failed login counter of the user
+func (m *userUsecase) Unlock(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	user, err := m.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return m.loginLimiter.Unlock(ctx, user.Username)
+}
+
+// issueTokens mints an access token and a new refresh token in the given family
+func (m *userUsecase) issueTokens(ctx context.Context, user domain.User, familyID string, client domain.ClientInfo) (domain.LoginResponse, error) {
+	accessToken, payload, err := m.tokenMaker.CreateToken(user)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+
+	refreshToken, err := util.RandomToken(refreshTokenSize)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	now := time.Now()
+	stored := domain.RefreshToken{
+		UserID:     user.ID,
+		FamilyID:   familyID,
+		TokenHash:  util.HashToken(refreshToken),
+		Device:     client.Device,
+		ClientIP:   client.IP,
+		ExpiresAt:  now.Add(refreshTokenDuration),
+		LastSeenAt: now,
+		CreatedAt:  now,
 	}
+	if err = m.refreshTokenRepo.Store(ctx, &stored); err != nil {
+		return domain.LoginResponse{}, err
+	}
+	user.HashedPassword = ""
 
-	return res, err
+	return domain.LoginResponse{
+		AccessToken:           accessToken,
+		TokenType:             tokenType,
+		AccessTokenExpiresAt:  payload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
+		User:                  &user,
+	}, nil
+}
+
+// Refresh rotates the given refresh token. Presenting a token that was already
+// rotated or revoked is treated as token theft and revokes the whole family.
+func (m *userUsecase) Refresh(ctx context.Context, refreshToken string, client domain.ClientInfo) (domain.LoginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	current, err := m.refreshTokenRepo.GetByHash(ctx, util.HashToken(refreshToken))
+	if err == domain.ErrNotFound {
+		return domain.LoginResponse{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	if current.IsUsed || current.IsRevoked {
+		if err = m.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return domain.LoginResponse{}, err
+		}
+		return domain.LoginResponse{}, domain.ErrInvalidToken
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return domain.LoginResponse{}, domain.ErrExpiredToken
+	}
+
+	err = m.refreshTokenRepo.MarkUsed(ctx, current.ID)
+	if err == domain.ErrConflict {
+		// lost the race against another refresh with the same token
+		if err = m.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return domain.LoginResponse{}, err
+		}
+		return domain.LoginResponse{}, domain.ErrInvalidToken
+	}
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+
+	user, err := m.userRepo.GetByID(ctx, current.UserID)
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+	return m.issueTokens(ctx, user, current.FamilyID, client)
+}
+
+func (m *userUsecase) Logout(ctx context.Context, refreshToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	current, err := m.refreshTokenRepo.GetByHash(ctx, util.HashToken(refreshToken))
+	if err == domain.ErrNotFound {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	return m.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID)
+}
+
+func (m *userUsecase) FetchSessions(ctx context.Context, userID int64) ([]domain.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	return m.refreshTokenRepo.FetchActiveByUser(ctx, userID)
+}
+
+func (m *userUsecase) RevokeSession(ctx context.Context, userID int64, sessionID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	session, err := m.refreshTokenRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return domain.ErrNotFound
+	}
+	return m.refreshTokenRepo.RevokeFamily(ctx, session.FamilyID)
 }
 
 func (m *userUsecase) CreateAdmin(ctx context.Context, a *domain.User) (err error) {
@@ -130,9 +602,8 @@ func (m *userUsecase) CreateAdmin(ctx context.Context, a *domain.User) (err erro
 	if existedArticle != (domain.User{}) {
 		return domain.ErrConflict
 	}
-	a.Role = "admin"
-	err = m.userRepo.Store(ctx, a)
-	return
+	a.Role = domain.RolesTypeAdmin
+	return m.storeUser(ctx, a)
 }
 
 func (m *userUsecase) CreateStaff(ctx context.Context, a *domain.User) (err error) {
@@ -143,7 +614,6 @@ func (m *userUsecase) CreateStaff(ctx context.Context, a *domain.User) (err erro
 	if existedArticle != (domain.User{}) {
 		return domain.ErrConflict
 	}
-	a.Role = "staff"
-	err = m.userRepo.Store(ctx, a)
-	return
+	a.Role = domain.RolesTypeStaff
+	return m.storeUser(ctx, a)
 }
@@ -16,6 +16,13 @@ import (
 
 func TestFetch(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	mockUser := domain.User{
 		ID:             1,
 		Username:       "user1",
@@ -29,15 +36,13 @@ func TestFetch(t *testing.T) {
 	mockListUser = append(mockListUser, mockUser)
 
 	t.Run("success", func(t *testing.T) {
-		mockUserRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).Return(mockListUser, "next-cursor", nil).Once()
-
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
-		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
-		cursorExpected := "next-cursor"
-		assert.Equal(t, cursorExpected, nextCursor)
-		assert.NotEmpty(t, nextCursor)
+		next := &domain.Cursor{Key: mockUser.CreatedAt, ID: mockUser.ID}
+		mockUserRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.PageRequest")).Return(mockListUser, domain.PageInfo{Next: next}, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		page := domain.PageRequest{Cursor: &domain.Cursor{ID: 12}, Limit: 1}
+		list, info, err := u.Fetch(context.TODO(), page)
+		assert.Equal(t, next, info.Next)
 		assert.NoError(t, err)
 		assert.Len(t, list, len(mockListUser))
 		mockUserRepo.AssertExpectations(t)
@@ -45,13 +50,12 @@ func TestFetch(t *testing.T) {
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockUserRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpected Error")).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
-		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		mockUserRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.PageRequest")).Return(nil, domain.PageInfo{}, errors.New("Unexpected Error")).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		page := domain.PageRequest{Cursor: &domain.Cursor{ID: 12}, Limit: 1}
+		list, info, err := u.Fetch(context.TODO(), page)
 
-		assert.Empty(t, nextCursor)
+		assert.Nil(t, info.Next)
 		assert.Error(t, err)
 		assert.Len(t, list, 0)
 		mockUserRepo.AssertExpectations(t)
@@ -61,6 +65,13 @@ func TestFetch(t *testing.T) {
 
 func TestGetByID(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	mockUser := domain.User{
 		ID:             1,
 		Username:       "user1",
@@ -73,7 +84,7 @@ func TestGetByID(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockUserRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockUser, nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		a, err := u.GetByID(context.TODO(), mockUser.ID)
 
 		assert.NoError(t, err)
@@ -85,7 +96,7 @@ func TestGetByID(t *testing.T) {
 	t.Run("error-failed", func(t *testing.T) {
 		mockUserRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.User{}, errors.New("Unexpected ")).Once()
 
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		a, err := u.GetByID(context.TODO(), mockUser.ID)
 
 		assert.Error(t, err)
@@ -98,6 +109,13 @@ func TestGetByID(t *testing.T) {
 
 func TestStore(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	mockUser := domain.User{
 		ID:             1,
 		Username:       "user1",
@@ -113,17 +131,18 @@ func TestStore(t *testing.T) {
 		tempMockUser.ID = 0
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(domain.User{}, domain.ErrNotFound).Once()
 		mockUserRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Store(context.TODO(), &tempMockUser)
 		assert.NoError(t, err)
 		assert.Equal(t, mockUser.Username, tempMockUser.Username)
+		assert.NoError(t, util.CheckPassword(mockUser.HashedPassword, tempMockUser.HashedPassword))
 		mockUserRepo.AssertExpectations(t)
 	})
 
 	t.Run("existing-username", func(t *testing.T) {
 		existingUser := mockUser
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(existingUser, nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Store(context.TODO(), &mockUser)
 
 		assert.Error(t, err)
@@ -134,6 +153,13 @@ func TestStore(t *testing.T) {
 
 func TestDelete(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	mockUser := domain.User{
 		ID:             1,
 		Username:       "user1",
@@ -148,7 +174,7 @@ func TestDelete(t *testing.T) {
 		mockUserRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockUser, nil).Once()
 		mockUserRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
 
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Delete(context.TODO(), mockUser.ID)
 
 		assert.NoError(t, err)
@@ -156,14 +182,14 @@ func TestDelete(t *testing.T) {
 	})
 	t.Run("user-is-not-exist", func(t *testing.T) {
 		mockUserRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.User{}, nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Delete(context.TODO(), mockUser.ID)
 		assert.Error(t, err)
 		mockUserRepo.AssertExpectations(t)
 	})
 	t.Run("error-happens-in-db", func(t *testing.T) {
 		mockUserRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(domain.User{}, errors.New("Unexpected Error")).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Delete(context.TODO(), mockUser.ID)
 
 		assert.Error(t, err)
@@ -173,6 +199,13 @@ func TestDelete(t *testing.T) {
 
 func TestUpdate(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	mockUser := domain.User{
 		ID:             1,
 		Username:       "user1",
@@ -186,7 +219,7 @@ func TestUpdate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockUserRepo.On("Update", mock.Anything, &mockUser).Once().Return(nil)
 
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 
 		err := u.Update(context.TODO(), &mockUser)
 		assert.NoError(t, err)
@@ -194,8 +227,95 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestUpdateProfile(t *testing.T) {
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockTokenMaker := new(mocks.TokenMaker)
+	mockUser := domain.User{
+		ID:         1,
+		Username:   "user1",
+		Email:      "user1@gmail.com",
+		Role:       "user",
+		IsVerified: true,
+	}
+
+	t.Run("same-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockVerificationRepo := new(mocks.EmailVerificationRepository)
+		mockMailer := new(mocks.UserMailer)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, "user2").Return(domain.User{}, domain.ErrNotFound).Once()
+		mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Username == "user2" && u.IsVerified
+		})).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+
+		res, err := u.UpdateProfile(context.TODO(), mockUser.ID, "user2", "USER1@gmail.com")
+		assert.NoError(t, err)
+		assert.True(t, res.IsVerified)
+		mockUserRepo.AssertExpectations(t)
+		mockMailer.AssertNotCalled(t, "SendVerification", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("new-email", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockVerificationRepo := new(mocks.EmailVerificationRepository)
+		mockMailer := new(mocks.UserMailer)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockUserRepo.On("GetByEmail", mock.Anything, "new@gmail.com").Return(domain.User{}, domain.ErrNotFound).Once()
+		mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "new@gmail.com" && !u.IsVerified
+		})).Return(nil).Once()
+		mockVerificationRepo.On("Store", mock.Anything, mock.MatchedBy(func(v *domain.EmailVerification) bool {
+			return v.Email == "new@gmail.com"
+		})).Return(nil).Once()
+		mockMailer.On("SendVerification", mock.Anything, mock.MatchedBy(func(u domain.User) bool {
+			return u.Email == "new@gmail.com"
+		}), mock.AnythingOfType("string")).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+
+		res, err := u.UpdateProfile(context.TODO(), mockUser.ID, mockUser.Username, "new@gmail.com")
+		assert.NoError(t, err)
+		assert.False(t, res.IsVerified)
+		mockUserRepo.AssertExpectations(t)
+		mockVerificationRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("username-taken", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, "user2").Return(domain.User{ID: 2, Username: "user2"}, nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, new(mocks.EmailVerificationRepository), mockResetRepo, mockLimiter, mockMFA, new(mocks.UserMailer), mockTokenMaker, time.Second*2)
+
+		_, err := u.UpdateProfile(context.TODO(), mockUser.ID, "user2", mockUser.Email)
+		assert.Equal(t, domain.ErrConflict, err)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("email-taken", func(t *testing.T) {
+		mockUserRepo := new(mocks.UserRepository)
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockUserRepo.On("GetByEmail", mock.Anything, "user2@gmail.com").Return(domain.User{ID: 2, Email: "user2@gmail.com"}, nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, new(mocks.EmailVerificationRepository), mockResetRepo, mockLimiter, mockMFA, new(mocks.UserMailer), mockTokenMaker, time.Second*2)
+
+		_, err := u.UpdateProfile(context.TODO(), mockUser.ID, mockUser.Username, "user2@gmail.com")
+		assert.Equal(t, domain.ErrConflict, err)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
 func TestRegister(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	pass := util.RandomString(6)
 	hashPass, err := util.HashPassword(pass)
 	assert.NoError(t, err)
@@ -212,21 +332,32 @@ func TestRegister(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		tempMockUser := mockUser
 		tempMockUser.ID = 0
+		tempMockUser.CreatedAt = time.Time{}
+		tempMockUser.UpdatedAt = time.Time{}
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(domain.User{}, domain.ErrNotFound).Once()
 
-		mockUserRepo.On("Register", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		mockUserRepo.On("Register", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return !u.CreatedAt.IsZero() && !u.UpdatedAt.IsZero()
+		})).Return(nil).Once()
+		mockVerificationRepo.On("Store", mock.Anything, mock.MatchedBy(func(v *domain.EmailVerification) bool {
+			return v.TokenHash != "" && v.ExpiresAt.After(time.Now())
+		})).Return(nil).Once()
+		mockMailer.On("SendVerification", mock.Anything, mock.AnythingOfType("domain.User"), mock.AnythingOfType("string")).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Register(context.TODO(), &tempMockUser)
 		assert.NoError(t, err)
 		assert.Equal(t, mockUser.Username, tempMockUser.Username)
 		assert.NotEqual(t, mockUser.HashedPassword, pass)
+		assert.False(t, tempMockUser.IsVerified)
 		mockUserRepo.AssertExpectations(t)
+		mockVerificationRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
 	})
 
 	t.Run("existing-username", func(t *testing.T) {
 		existingUser := mockUser
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(existingUser, nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Register(context.TODO(), &mockUser)
 
 		assert.Error(t, err)
@@ -235,64 +366,539 @@ func TestRegister(t *testing.T) {
 	})
 }
 
-// func TestLogin(t *testing.T) {
-// 	mockUserRepo := new(mocks.UserRepository)
-// 	pass := util.RandomString(6)
-// 	hashPass, err := util.HashPassword(pass)
-// 	assert.NoError(t, err)
-// 	mockUser := domain.User{
-// 		ID:             1,
-// 		Username:       "user1",
-// 		Email:          "user1@gmail.com",
-// 		HashedPassword: hashPass,
-// 		Role:           "user",
-// 		UpdatedAt:      time.Now(),
-// 		CreatedAt:      time.Now(),
-// 	}
-
-// 	t.Run("success", func(t *testing.T) {
-// 		tempMockUser := mockUser
-// 		tempMockUser.ID = 1
-// 		mockUserRepo.On("GetByUsername", mock.Anything, &mockUser.Username).Once().Return(nil)
-
-// 		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
-// 		user, err := u.Login(context.TODO(), mockUser.Username, mockUser.HashedPassword)
-// 		assert.NoError(t, err)
-// 		assert.NotNil(t, user)
-// 		assert.Equal(t, mockUser.Username, tempMockUser.Username)
-// 		assert.NotEqual(t, mockUser.HashedPassword, user.HashedPassword)
-// 		mockUserRepo.AssertExpectations(t)
-// 	})
-// 	t.Run("user-not-found", func(t *testing.T) {
-// 		tempMockUser := mockUser
-// 		tempMockUser.ID = 0
-// 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(domain.User{}, domain.ErrNotFound).Once()
-
-// 		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
-// 		assert.NoError(t, err)
-// 		assert.NotNil(t, u)
-
-// 		mockUserRepo.AssertExpectations(t)
-// 	})
-
-// 	t.Run("password-wrong", func(t *testing.T) {
-// 		tempMockUser := mockUser
-// 		tempMockUser.ID = 0
-// 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(domain.User{}, domain.ErrBadParamInput).Once()
-
-// 		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
-// 		user, err := u.Login(context.TODO(), mockUser.Username, mockUser.HashedPassword)
-// 		assert.NoError(t, err)
-// 		assert.NotNil(t, user)
-// 		assert.NotEqual(t, tempMockUser.HashedPassword, user.HashedPassword)
-
-// 		mockUserRepo.AssertExpectations(t)
-// 	})
-
-// }
+func TestVerify(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	token := util.RandomString(32)
+	verification := domain.EmailVerification{
+		ID:        5,
+		UserID:    1,
+		Email:     "user1@gmail.com",
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	user := domain.User{ID: 1, Username: "user1", Email: "user1@gmail.com"}
+
+	t.Run("success", func(t *testing.T) {
+		mockVerificationRepo.On("GetByHash", mock.Anything, verification.TokenHash).Return(verification, nil).Once()
+		mockUserRepo.On("GetByID", mock.Anything, verification.UserID).Return(user, nil).Once()
+		mockVerificationRepo.On("MarkUsed", mock.Anything, verification.ID).Return(nil).Once()
+		mockUserRepo.On("MarkVerified", mock.Anything, verification.UserID).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.Verify(context.TODO(), token)
+		assert.NoError(t, err)
+		mockVerificationRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("sent-to-old-email", func(t *testing.T) {
+		userRepo := new(mocks.UserRepository)
+		verificationRepo := new(mocks.EmailVerificationRepository)
+		changed := user
+		changed.Email = "new@gmail.com"
+		verificationRepo.On("GetByHash", mock.Anything, verification.TokenHash).Return(verification, nil).Once()
+		userRepo.On("GetByID", mock.Anything, verification.UserID).Return(changed, nil).Once()
+
+		u := ucase.NewUserUsecase(userRepo, mockRefreshTokenRepo, verificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.Verify(context.TODO(), token)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		verificationRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+		userRepo.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("used-token", func(t *testing.T) {
+		used := verification
+		used.IsUsed = true
+		mockVerificationRepo.On("GetByHash", mock.Anything, verification.TokenHash).Return(used, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.Verify(context.TODO(), token)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		mockVerificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired-token", func(t *testing.T) {
+		expired := verification
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockVerificationRepo.On("GetByHash", mock.Anything, verification.TokenHash).Return(expired, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.Verify(context.TODO(), token)
+		assert.Equal(t, domain.ErrExpiredToken, err)
+		mockVerificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-token", func(t *testing.T) {
+		mockVerificationRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(domain.EmailVerification{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.Verify(context.TODO(), "unknown")
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		mockVerificationRepo.AssertExpectations(t)
+	})
+}
+
+func TestForgotPassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	mockUser := domain.User{ID: 1, Username: "user1", Email: "user1@gmail.com"}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo.On("GetByEmail", mock.Anything, mockUser.Email).Return(mockUser, nil).Once()
+		mockResetRepo.On("Store", mock.Anything, mock.MatchedBy(func(r *domain.PasswordReset) bool {
+			return r.UserID == mockUser.ID && r.TokenHash != "" && r.ExpiresAt.After(time.Now())
+		})).Return(nil).Once()
+		mockMailer.On("SendPasswordReset", mock.Anything, mockUser, mock.AnythingOfType("string")).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.ForgotPassword(context.TODO(), mockUser.Email)
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockResetRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("unknown-email", func(t *testing.T) {
+		mockUserRepo.On("GetByEmail", mock.Anything, "unknown@gmail.com").Return(domain.User{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.ForgotPassword(context.TODO(), "unknown@gmail.com")
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockResetRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	token := util.RandomString(32)
+	reset := domain.PasswordReset{
+		ID:        3,
+		UserID:    1,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	newPassword := "n3w-Passw0rd"
+
+	t.Run("success", func(t *testing.T) {
+		mockResetRepo.On("GetByHash", mock.Anything, reset.TokenHash).Return(reset, nil).Once()
+		mockResetRepo.On("MarkUsed", mock.Anything, reset.ID).Return(nil).Once()
+		mockUserRepo.On("UpdatePassword", mock.Anything, reset.UserID, mock.MatchedBy(func(hash string) bool {
+			return util.CheckPassword(newPassword, hash) == nil
+		})).Return(nil).Once()
+		mockRefreshTokenRepo.On("RevokeByUser", mock.Anything, reset.UserID).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.ResetPassword(context.TODO(), token, newPassword)
+		assert.NoError(t, err)
+		mockResetRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("used-token", func(t *testing.T) {
+		used := reset
+		used.IsUsed = true
+		mockResetRepo.On("GetByHash", mock.Anything, reset.TokenHash).Return(used, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.ResetPassword(context.TODO(), token, newPassword)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		mockResetRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired-token", func(t *testing.T) {
+		expired := reset
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockResetRepo.On("GetByHash", mock.Anything, reset.TokenHash).Return(expired, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.ResetPassword(context.TODO(), token, newPassword)
+		assert.Equal(t, domain.ErrExpiredToken, err)
+		mockResetRepo.AssertExpectations(t)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	pass := util.RandomString(6)
+	hashPass, err := util.HashPassword(pass)
+	assert.NoError(t, err)
+	mockUser := domain.User{
+		ID:             1,
+		Username:       "user1",
+		Email:          "user1@gmail.com",
+		HashedPassword: hashPass,
+		Role:           "user",
+		UpdatedAt:      time.Now(),
+		CreatedAt:      time.Now(),
+	}
+	client := domain.ClientInfo{Device: "okhttp/4.9", IP: "10.0.0.1"}
+
+	t.Run("success", func(t *testing.T) {
+		payload := &domain.TokenPayload{UserID: mockUser.ID, Role: mockUser.Role, ExpiredAt: time.Now().Add(time.Minute)}
+		mockLimiter.On("Allow", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mockUser.Username).Return(mockUser, nil).Once()
+		mockLimiter.On("Succeed", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockMFA.On("IsEnabled", mock.Anything, mockUser.ID).Return(false, nil).Once()
+		mockMFA.On("IsRequired", mockUser.Role).Return(false).Once()
+		mockTokenMaker.On("CreateToken", mock.AnythingOfType("domain.User")).Return("access-token", payload, nil).Once()
+		mockRefreshTokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(rt *domain.RefreshToken) bool {
+			return rt.UserID == mockUser.ID && rt.FamilyID != "" && rt.Device == client.Device && rt.ClientIP == client.IP
+		})).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.Login(context.TODO(), mockUser.Username, pass, client)
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", res.AccessToken)
+		assert.NotEmpty(t, res.RefreshToken)
+		assert.True(t, res.RefreshTokenExpiresAt.After(time.Now()))
+		assert.Equal(t, "Bearer", res.TokenType)
+		assert.Equal(t, payload.ExpiredAt, res.AccessTokenExpiresAt)
+		assert.Equal(t, mockUser.ID, res.User.ID)
+		assert.Empty(t, res.User.HashedPassword)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenMaker.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+		mockLimiter.AssertExpectations(t)
+	})
+
+	t.Run("mfa-challenge", func(t *testing.T) {
+		mockLimiter.On("Allow", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mockUser.Username).Return(mockUser, nil).Once()
+		mockLimiter.On("Succeed", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockMFA.On("IsEnabled", mock.Anything, mockUser.ID).Return(true, nil).Once()
+		mockTokenMaker.On("CreateScopedToken", mock.AnythingOfType("domain.User"), domain.TokenScopeMFA, mock.AnythingOfType("time.Duration")).
+			Return("mfa-token", &domain.TokenPayload{}, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.Login(context.TODO(), mockUser.Username, pass, client)
+		assert.NoError(t, err)
+		assert.True(t, res.MFARequired)
+		assert.Equal(t, "mfa-token", res.MFAToken)
+		assert.Empty(t, res.AccessToken)
+		assert.Empty(t, res.RefreshToken)
+		assert.Nil(t, res.User)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenMaker.AssertExpectations(t)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("mfa-enrollment-required", func(t *testing.T) {
+		mockLimiter.On("Allow", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mockUser.Username).Return(mockUser, nil).Once()
+		mockLimiter.On("Succeed", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockMFA.On("IsEnabled", mock.Anything, mockUser.ID).Return(false, nil).Once()
+		mockMFA.On("IsRequired", mockUser.Role).Return(true).Once()
+		mockTokenMaker.On("CreateScopedToken", mock.AnythingOfType("domain.User"), domain.TokenScopeMFAEnroll, mock.AnythingOfType("time.Duration")).
+			Return("enroll-token", &domain.TokenPayload{Scope: domain.TokenScopeMFAEnroll}, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.Login(context.TODO(), mockUser.Username, pass, client)
+		assert.NoError(t, err)
+		assert.True(t, res.MFAEnrollmentRequired)
+		assert.Equal(t, "enroll-token", res.AccessToken)
+		assert.Empty(t, res.RefreshToken)
+		assert.Empty(t, res.User.HashedPassword)
+		mockTokenMaker.AssertExpectations(t)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("user-not-found", func(t *testing.T) {
+		mockLimiter.On("Allow", mock.Anything, "unknown", client.IP).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(domain.User{}, domain.ErrNotFound).Once()
+		mockLimiter.On("Fail", mock.Anything, "unknown", client.IP, domain.LoginFailureUnknownUser).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.Login(context.TODO(), "unknown", pass, client)
+		assert.Error(t, err)
+		assert.Empty(t, res.AccessToken)
+		mockUserRepo.AssertExpectations(t)
+		mockLimiter.AssertExpectations(t)
+	})
+
+	t.Run("password-wrong", func(t *testing.T) {
+		mockLimiter.On("Allow", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", mock.Anything, mockUser.Username).Return(mockUser, nil).Once()
+		mockLimiter.On("Fail", mock.Anything, mockUser.Username, client.IP, domain.LoginFailureWrongPassword).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.Login(context.TODO(), mockUser.Username, "wrong-password", client)
+		assert.Equal(t, domain.ErrBadParamInput, err)
+		assert.Empty(t, res.AccessToken)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenMaker.AssertExpectations(t)
+		mockLimiter.AssertExpectations(t)
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		mockLimiter.On("Allow", mock.Anything, mockUser.Username, client.IP).Return(domain.ErrTooManyAttempts).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.Login(context.TODO(), mockUser.Username, pass, client)
+		assert.Equal(t, domain.ErrTooManyAttempts, err)
+		assert.Empty(t, res.AccessToken)
+		mockUserRepo.AssertExpectations(t)
+		mockLimiter.AssertExpectations(t)
+	})
+}
+
+func TestLoginMFA(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	mockUser := domain.User{ID: 1, Username: "admin1", Role: domain.RolesTypeAdmin}
+	client := domain.ClientInfo{Device: "okhttp/4.9", IP: "10.0.0.1"}
+	mfaPayload := &domain.TokenPayload{UserID: mockUser.ID, Username: mockUser.Username, Scope: domain.TokenScopeMFA}
+
+	t.Run("success", func(t *testing.T) {
+		payload := &domain.TokenPayload{UserID: mockUser.ID, Scope: domain.TokenScopeAccess, ExpiredAt: time.Now().Add(time.Minute)}
+		mockTokenMaker.On("VerifyToken", "mfa-token").Return(mfaPayload, nil).Once()
+		mockLimiter.On("Allow", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockMFA.On("Verify", mock.Anything, mockUser.ID, "123456").Return(nil).Once()
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockTokenMaker.On("CreateToken", mock.AnythingOfType("domain.User")).Return("access-token", payload, nil).Once()
+		mockRefreshTokenRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.LoginMFA(context.TODO(), "mfa-token", "123456", client)
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", res.AccessToken)
+		assert.NotEmpty(t, res.RefreshToken)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenMaker.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("wrong-code", func(t *testing.T) {
+		mockTokenMaker.On("VerifyToken", "mfa-token").Return(mfaPayload, nil).Once()
+		mockLimiter.On("Allow", mock.Anything, mockUser.Username, client.IP).Return(nil).Once()
+		mockMFA.On("Verify", mock.Anything, mockUser.ID, "000000").Return(domain.ErrInvalidMFACode).Once()
+		mockLimiter.On("Fail", mock.Anything, mockUser.Username, client.IP, domain.LoginFailureWrongMFACode).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.LoginMFA(context.TODO(), "mfa-token", "000000", client)
+		assert.Equal(t, domain.ErrInvalidMFACode, err)
+		assert.Empty(t, res.AccessToken)
+		mockLimiter.AssertExpectations(t)
+		mockMFA.AssertExpectations(t)
+	})
+
+	t.Run("access-token-rejected", func(t *testing.T) {
+		mockTokenMaker.On("VerifyToken", "access-token").
+			Return(&domain.TokenPayload{UserID: mockUser.ID, Scope: domain.TokenScopeAccess}, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		_, err := u.LoginMFA(context.TODO(), "access-token", "123456", client)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		mockTokenMaker.AssertExpectations(t)
+	})
+}
+
+func TestUnlock(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	mockUser := domain.User{ID: 1, Username: "user1"}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockLimiter.On("Unlock", mock.Anything, mockUser.Username).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.Unlock(context.TODO(), mockUser.ID)
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockLimiter.AssertExpectations(t)
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	mockUser := domain.User{ID: 1, Username: "user1", Role: "user"}
+	client := domain.ClientInfo{Device: "okhttp/4.9", IP: "10.0.0.2"}
+	refreshToken := util.RandomString(32)
+	current := domain.RefreshToken{
+		ID:        10,
+		UserID:    mockUser.ID,
+		FamilyID:  "family-1",
+		TokenHash: util.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		payload := &domain.TokenPayload{UserID: mockUser.ID, ExpiredAt: time.Now().Add(time.Minute)}
+		mockRefreshTokenRepo.On("GetByHash", mock.Anything, current.TokenHash).Return(current, nil).Once()
+		mockRefreshTokenRepo.On("MarkUsed", mock.Anything, current.ID).Return(nil).Once()
+		mockUserRepo.On("GetByID", mock.Anything, mockUser.ID).Return(mockUser, nil).Once()
+		mockTokenMaker.On("CreateToken", mockUser).Return("access-token", payload, nil).Once()
+		mockRefreshTokenRepo.On("Store", mock.Anything, mock.MatchedBy(func(rt *domain.RefreshToken) bool {
+			return rt.FamilyID == current.FamilyID && rt.TokenHash != current.TokenHash && rt.ClientIP == client.IP
+		})).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		res, err := u.Refresh(context.TODO(), refreshToken, client)
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", res.AccessToken)
+		assert.NotEqual(t, refreshToken, res.RefreshToken)
+		mockRefreshTokenRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+		mockTokenMaker.AssertExpectations(t)
+	})
+
+	t.Run("reused-token-revokes-family", func(t *testing.T) {
+		used := current
+		used.IsUsed = true
+		mockRefreshTokenRepo.On("GetByHash", mock.Anything, current.TokenHash).Return(used, nil).Once()
+		mockRefreshTokenRepo.On("RevokeFamily", mock.Anything, current.FamilyID).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		_, err := u.Refresh(context.TODO(), refreshToken, client)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("concurrent-rotation-revokes-family", func(t *testing.T) {
+		mockRefreshTokenRepo.On("GetByHash", mock.Anything, current.TokenHash).Return(current, nil).Once()
+		mockRefreshTokenRepo.On("MarkUsed", mock.Anything, current.ID).Return(domain.ErrConflict).Once()
+		mockRefreshTokenRepo.On("RevokeFamily", mock.Anything, current.FamilyID).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		_, err := u.Refresh(context.TODO(), refreshToken, client)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired-token", func(t *testing.T) {
+		expired := current
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockRefreshTokenRepo.On("GetByHash", mock.Anything, current.TokenHash).Return(expired, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		_, err := u.Refresh(context.TODO(), refreshToken, client)
+		assert.Equal(t, domain.ErrExpiredToken, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-token", func(t *testing.T) {
+		mockRefreshTokenRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(domain.RefreshToken{}, domain.ErrNotFound).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		_, err := u.Refresh(context.TODO(), "unknown", client)
+		assert.Equal(t, domain.ErrInvalidToken, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	refreshToken := util.RandomString(32)
+	current := domain.RefreshToken{ID: 10, UserID: 1, FamilyID: "family-1", TokenHash: util.HashToken(refreshToken)}
+
+	t.Run("success", func(t *testing.T) {
+		mockRefreshTokenRepo.On("GetByHash", mock.Anything, current.TokenHash).Return(current, nil).Once()
+		mockRefreshTokenRepo.On("RevokeFamily", mock.Anything, current.FamilyID).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.Logout(context.TODO(), refreshToken)
+		assert.NoError(t, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
+	session := domain.RefreshToken{ID: 10, UserID: 1, FamilyID: "family-1"}
+
+	t.Run("success", func(t *testing.T) {
+		mockRefreshTokenRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil).Once()
+		mockRefreshTokenRepo.On("RevokeFamily", mock.Anything, session.FamilyID).Return(nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.RevokeSession(context.TODO(), session.UserID, session.ID)
+		assert.NoError(t, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("other-users-session", func(t *testing.T) {
+		mockRefreshTokenRepo.On("GetByID", mock.Anything, session.ID).Return(session, nil).Once()
+
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.RevokeSession(context.TODO(), 2, session.ID)
+		assert.Equal(t, domain.ErrNotFound, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+}
 
 func TestCreateAdmin(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	mockUser := domain.User{
 		ID:             1,
 		Username:       "user1",
@@ -308,7 +914,7 @@ func TestCreateAdmin(t *testing.T) {
 		tempMockUser.ID = 0
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(domain.User{}, domain.ErrNotFound).Once()
 		mockUserRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Store(context.TODO(), &tempMockUser)
 		assert.NoError(t, err)
 		assert.Equal(t, mockUser.Username, tempMockUser.Username)
@@ -316,10 +922,23 @@ func TestCreateAdmin(t *testing.T) {
 		mockUserRepo.AssertExpectations(t)
 	})
 
+	t.Run("hashes-password", func(t *testing.T) {
+		tempMockUser := domain.User{Username: "new1", Email: "new1@gmail.com", HashedPassword: "Secret123"}
+		mockUserRepo.On("GetByUsername", mock.Anything, tempMockUser.Username).Return(domain.User{}, domain.ErrNotFound).Once()
+		mockUserRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.CreateAdmin(context.TODO(), &tempMockUser)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RolesTypeAdmin, tempMockUser.Role)
+		assert.NoError(t, util.CheckPassword("Secret123", tempMockUser.HashedPassword))
+		assert.False(t, tempMockUser.CreatedAt.IsZero())
+		mockUserRepo.AssertExpectations(t)
+	})
+
 	t.Run("existing-username", func(t *testing.T) {
 		existingUser := mockUser
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(existingUser, nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Store(context.TODO(), &mockUser)
 
 		assert.Error(t, err)
@@ -331,6 +950,13 @@ func TestCreateAdmin(t *testing.T) {
 
 func TestCreateStaff(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokenRepo := new(mocks.RefreshTokenRepository)
+	mockVerificationRepo := new(mocks.EmailVerificationRepository)
+	mockResetRepo := new(mocks.PasswordResetRepository)
+	mockLimiter := new(mocks.LoginLimiter)
+	mockMFA := new(mocks.MFAAuthenticator)
+	mockMailer := new(mocks.UserMailer)
+	mockTokenMaker := new(mocks.TokenMaker)
 	mockUser := domain.User{
 		ID:             1,
 		Username:       "user1",
@@ -346,7 +972,7 @@ func TestCreateStaff(t *testing.T) {
 		tempMockUser.ID = 0
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(domain.User{}, domain.ErrNotFound).Once()
 		mockUserRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Store(context.TODO(), &tempMockUser)
 		assert.NoError(t, err)
 		assert.Equal(t, mockUser.Username, tempMockUser.Username)
@@ -354,10 +980,23 @@ func TestCreateStaff(t *testing.T) {
 		mockUserRepo.AssertExpectations(t)
 	})
 
+	t.Run("hashes-password", func(t *testing.T) {
+		tempMockUser := domain.User{Username: "new1", Email: "new1@gmail.com", HashedPassword: "Secret123"}
+		mockUserRepo.On("GetByUsername", mock.Anything, tempMockUser.Username).Return(domain.User{}, domain.ErrNotFound).Once()
+		mockUserRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
+		err := u.CreateStaff(context.TODO(), &tempMockUser)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RolesTypeStaff, tempMockUser.Role)
+		assert.NoError(t, util.CheckPassword("Secret123", tempMockUser.HashedPassword))
+		assert.False(t, tempMockUser.CreatedAt.IsZero())
+		mockUserRepo.AssertExpectations(t)
+	})
+
 	t.Run("existing-username", func(t *testing.T) {
 		existingUser := mockUser
 		mockUserRepo.On("GetByUsername", mock.Anything, mock.AnythingOfType("string")).Return(existingUser, nil).Once()
-		u := ucase.NewUserUsecase(mockUserRepo, time.Second*2)
+		u := ucase.NewUserUsecase(mockUserRepo, mockRefreshTokenRepo, mockVerificationRepo, mockResetRepo, mockLimiter, mockMFA, mockMailer, mockTokenMaker, time.Second*2)
 		err := u.Store(context.TODO(), &mockUser)
 
 		assert.Error(t, err)